@@ -0,0 +1,384 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"runtime"
+	"strconv"
+	"time"
+
+	gethutils "github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-mive/mive/cmd/utils"
+	"github.com/ethereum-mive/mive/core"
+	"github.com/ethereum-mive/mive/internal/flags"
+)
+
+var (
+	initCommand = &cli.Command{
+		Action:    initGenesis,
+		Name:      "init",
+		Usage:     "Bootstrap and initialize a new genesis block",
+		ArgsUsage: "<genesisPath>",
+		Flags: flags.Merge([]cli.Flag{
+			utils.MiveEthFlag,
+			utils.MiveEthArchiveFlag,
+			utils.StateSchemeFlag,
+		}, utils.DatabaseFlags),
+		Description: `
+The init command initializes a new genesis block and definition for the network.
+This is a destructive action and changes the network in which you will be
+participating.
+
+It expects the genesis file as argument. The genesis block of a Mive chain is the
+L1 block its configuration starts the chain at, which is retrieved from the L1
+endpoints given with --mive.eth.`,
+	}
+	importCommand = &cli.Command{
+		Action:    importChain,
+		Name:      "import",
+		Usage:     "Import a Mive chain file",
+		ArgsUsage: "<filename> (<filename 2> ... <filename N>) ",
+		Flags: flags.Merge([]cli.Flag{
+			utils.GCModeFlag,
+			utils.SnapshotFlag,
+			utils.StateSchemeFlag,
+			utils.StateHistoryFlag,
+			utils.TxLookupLimitFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+The import command imports Mive blocks, together with their receipts and rejection
+records, from an RLP-encoded file as written by the export command. The database
+must already be initialized for the network, and the imported blocks must extend
+its canonical chain. If only one file is used, an import error results in failure.
+If several files are used, processing proceeds even if an individual file fails to
+import.
+
+Imported blocks are not re-executed: the state of the chain head is only available
+if it is already present in the database.`,
+	}
+	importHistoryCommand = &cli.Command{
+		Action:    importHistory,
+		Name:      "import-history",
+		Usage:     "Import a Mive chain history from Era1 archives",
+		ArgsUsage: "<dir>",
+		Flags: flags.Merge([]cli.Flag{
+			utils.GCModeFlag,
+			utils.SnapshotFlag,
+			utils.StateSchemeFlag,
+			utils.StateHistoryFlag,
+			utils.TxLookupLimitFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+The import-history command imports the Mive blocks, with their receipts and
+rejection records, stored in the Era1 archives of the given directory. The archives
+are verified against the checksums.txt file of the directory and against their
+accumulators. Like the import command, it requires an initialized database and the
+imported blocks to extend its canonical chain.`,
+	}
+	exportHistoryCommand = &cli.Command{
+		Action:    exportHistory,
+		Name:      "export-history",
+		Usage:     "Export the Mive chain history to Era1 archives",
+		ArgsUsage: "<dir> <first> <last>",
+		Flags:     flags.Merge(utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+The export-history command exports the Mive blocks in the given range, along with
+their receipts and rejection records, to Era1 archives of at most 8192 blocks in
+the given directory. The archives are aligned to epochs of 8192 blocks and named
+after the network, their epoch and their accumulator root. A checksums.txt file
+listing the sha256 checksums of the archives is written along with them.`,
+	}
+	exportCommand = &cli.Command{
+		Action:    exportChain,
+		Name:      "export",
+		Usage:     "Export the Mive chain into file",
+		ArgsUsage: "<filename> [<blockNumFirst> <blockNumLast>]",
+		Flags:     flags.Merge(utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+Requires a first argument of the file to write to.
+Optional second and third arguments control the first and
+last block to write. In this mode, the file will be appended
+if already existing. If the file ends with .gz, the output will
+be gzipped.`,
+	}
+	rollbackCommand = &cli.Command{
+		Action:    rollbackChain,
+		Name:      "rollback",
+		Usage:     "Rewind the Mive chain to a given block",
+		ArgsUsage: "<blockNumber>",
+		Flags: flags.Merge([]cli.Flag{
+			utils.StateSchemeFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `
+The rollback command rewinds the head of the Mive chain to the given block,
+deleting the blocks above it, so that they are derived again from L1 the next
+time the node is started. It is meant to repair a corrupted or wrongly derived
+chain. If the state of the given block is not available, the chain is rewound
+further, down to the first block whose state is.
+
+A running node can be rewound the same way with debug_setHead, exposed over the
+authenticated RPC endpoint.`,
+	}
+)
+
+// initGenesis will initialise the given JSON format genesis file and writes it as
+// the zero'd block (i.e. genesis) or will fail hard if it can't succeed.
+func initGenesis(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		gethutils.Fatalf("need genesis.json file as the only argument")
+	}
+	genesisPath := ctx.Args().First()
+	if len(genesisPath) == 0 {
+		gethutils.Fatalf("invalid path to genesis file")
+	}
+	file, err := os.Open(genesisPath)
+	if err != nil {
+		gethutils.Fatalf("Failed to read genesis file: %v", err)
+	}
+	defer file.Close()
+
+	genesis := new(core.Genesis)
+	if err := json.NewDecoder(file).Decode(genesis); err != nil {
+		gethutils.Fatalf("invalid genesis file: %v", err)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	ethClient := utils.MakeEthClient(ctx)
+	defer ethClient.Close()
+
+	chaindb := utils.MakeChainDatabase(ctx, stack, false)
+	defer chaindb.Close()
+
+	triedb := utils.MakeTrieDatabase(ctx, chaindb, false, false)
+	defer triedb.Close()
+
+	_, hash, err := core.SetupGenesisBlockWithOverride(context.Background(), chaindb, triedb, genesis, nil, ethClient)
+	if err != nil {
+		gethutils.Fatalf("Failed to write genesis block: %v", err)
+	}
+	log.Info("Successfully wrote genesis state", "database", "chaindata", "number", genesis.Config.Mive.GenesisBlock, "hash", hash)
+	return nil
+}
+
+func importChain(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		gethutils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, false)
+	defer db.Close()
+
+	// Start periodically gathering memory profiles
+	var peakMemAlloc, peakMemSys uint64
+	go func() {
+		stats := new(runtime.MemStats)
+		for {
+			runtime.ReadMemStats(stats)
+			if stats.Alloc > peakMemAlloc {
+				peakMemAlloc = stats.Alloc
+			}
+			if stats.Sys > peakMemSys {
+				peakMemSys = stats.Sys
+			}
+			time.Sleep(5 * time.Second)
+		}
+	}()
+	// Import the chain
+	start := time.Now()
+
+	var importErr error
+
+	if ctx.Args().Len() == 1 {
+		if err := utils.ImportChain(chain, ctx.Args().First()); err != nil {
+			importErr = err
+			log.Error("Import error", "err", err)
+		}
+	} else {
+		for _, arg := range ctx.Args().Slice() {
+			if err := utils.ImportChain(chain, arg); err != nil {
+				importErr = err
+				log.Error("Import error", "file", arg, "err", err)
+			}
+		}
+	}
+	chain.Stop()
+	fmt.Printf("Import done in %v.\n\n", time.Since(start))
+
+	// Output pre-compaction stats mostly to see the import trashing
+	showLeveldbStats(db)
+
+	// Print the memory statistics used by the importing
+	mem := new(runtime.MemStats)
+	runtime.ReadMemStats(mem)
+
+	fmt.Printf("Object memory: %.3f MB current, %.3f MB peak\n", float64(mem.Alloc)/1024/1024, float64(peakMemAlloc)/1024/1024)
+	fmt.Printf("System memory: %.3f MB current, %.3f MB peak\n", float64(mem.Sys)/1024/1024, float64(peakMemSys)/1024/1024)
+	fmt.Printf("Allocations:   %.3f million\n", float64(mem.Mallocs)/1000000)
+	fmt.Printf("GC pause:      %v\n\n", time.Duration(mem.PauseTotalNs))
+
+	return importErr
+}
+
+func exportChain(ctx *cli.Context) error {
+	if ctx.Args().Len() < 1 {
+		gethutils.Fatalf("This command requires an argument.")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, true)
+	defer db.Close()
+	defer chain.Stop()
+
+	start := time.Now()
+
+	var err error
+	fp := ctx.Args().First()
+	if ctx.Args().Len() < 3 {
+		err = utils.ExportChain(chain, fp)
+	} else {
+		// This can be improved to allow for numbers larger than 9223372036854775807
+		first, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
+		last, lerr := strconv.ParseInt(ctx.Args().Get(2), 10, 64)
+		if ferr != nil || lerr != nil {
+			gethutils.Fatalf("Export error in parsing parameters: block number not an integer\n")
+		}
+		if first < 0 || last < 0 {
+			gethutils.Fatalf("Export error: block number must be greater than 0\n")
+		}
+		if head := chain.CurrentSnapBlock(); uint64(last) > head.Number.Uint64() {
+			gethutils.Fatalf("Export error: block number %d larger than head block %d\n", uint64(last), head.Number.Uint64())
+		}
+		err = utils.ExportAppendChain(chain, fp, uint64(first), uint64(last))
+	}
+	if err != nil {
+		gethutils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
+func importHistory(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		gethutils.Fatalf("usage: %s", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, false)
+	defer db.Close()
+	defer chain.Stop()
+
+	start := time.Now()
+	if err := utils.ImportHistory(chain, ctx.Args().First(), networkName(ctx)); err != nil {
+		return err
+	}
+	fmt.Printf("Import done in %v\n", time.Since(start))
+	return nil
+}
+
+func exportHistory(ctx *cli.Context) error {
+	if ctx.Args().Len() != 3 {
+		gethutils.Fatalf("usage: %s", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, true)
+	defer db.Close()
+	defer chain.Stop()
+
+	first, ferr := strconv.ParseInt(ctx.Args().Get(1), 10, 64)
+	last, lerr := strconv.ParseInt(ctx.Args().Get(2), 10, 64)
+	if ferr != nil || lerr != nil {
+		gethutils.Fatalf("Export error in parsing parameters: block number not an integer\n")
+	}
+	if first < 0 || last < 0 {
+		gethutils.Fatalf("Export error: block number must be greater than 0\n")
+	}
+	if genesis := chain.Genesis().Number.Uint64(); uint64(first) < genesis {
+		gethutils.Fatalf("Export error: block number %d lower than genesis block %d\n", first, genesis)
+	}
+	start := time.Now()
+	if err := utils.ExportHistory(chain, ctx.Args().First(), networkName(ctx), uint64(first), uint64(last)); err != nil {
+		gethutils.Fatalf("Export error: %v\n", err)
+	}
+	fmt.Printf("Export done in %v\n", time.Since(start))
+	return nil
+}
+
+func rollbackChain(ctx *cli.Context) error {
+	if ctx.Args().Len() != 1 {
+		gethutils.Fatalf("usage: %s", ctx.Command.ArgsUsage)
+	}
+	number, err := strconv.ParseUint(ctx.Args().First(), 10, 64)
+	if err != nil {
+		gethutils.Fatalf("Rollback error in parsing parameters: block number not an integer\n")
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	chain, db := utils.MakeChain(ctx, stack, false)
+	defer db.Close()
+	defer chain.Stop()
+
+	head := chain.CurrentBlock().NumberU64()
+	if number > head {
+		gethutils.Fatalf("Rollback error: block number %d larger than head block %d\n", number, head)
+	}
+	if genesis := chain.Genesis().NumberU64(); number < genesis {
+		gethutils.Fatalf("Rollback error: block number %d lower than genesis block %d\n", number, genesis)
+	}
+	// Checkpoint synced chains have no blocks below the checkpoint, rewinding
+	// into the gap would drop the chain back to the genesis.
+	if chain.GetHeaderByNumber(number) == nil {
+		gethutils.Fatalf("Rollback error: block %d not found\n", number)
+	}
+	start := time.Now()
+	if err := chain.SetHead(number); err != nil {
+		gethutils.Fatalf("Rollback error: %v\n", err)
+	}
+	current := chain.CurrentBlock()
+	log.Info("Rolled back chain", "from", head, "number", current.Number, "hash", current.Hash, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// networkName returns the name of the network selected by the command line
+// flags, which Era1 archives are named after.
+func networkName(ctx *cli.Context) string {
+	switch {
+	case ctx.Bool(utils.GoerliFlag.Name):
+		return "goerli"
+	case ctx.Bool(utils.SepoliaFlag.Name):
+		return "sepolia"
+	case ctx.Bool(utils.HoleskyFlag.Name):
+		return "holesky"
+	default:
+		return "mainnet"
+	}
+}
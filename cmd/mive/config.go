@@ -9,7 +9,6 @@ import (
 	"unicode"
 
 	"github.com/ethereum/go-ethereum/accounts"
-	"github.com/ethereum/go-ethereum/accounts/external"
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/accounts/usbwallet"
 	gethutils "github.com/ethereum/go-ethereum/cmd/utils"
@@ -17,14 +16,26 @@ import (
 	"github.com/naoina/toml"
 	"github.com/urfave/cli/v2"
 
+	"github.com/ethereum-mive/mive/accounts/external"
 	"github.com/ethereum-mive/mive/cmd/utils"
 	"github.com/ethereum-mive/mive/internal/flags"
+	"github.com/ethereum-mive/mive/internal/miveapi"
 	"github.com/ethereum-mive/mive/internal/version"
+	"github.com/ethereum-mive/mive/mive/miveconfig"
 	"github.com/ethereum-mive/mive/node"
 	"github.com/ethereum-mive/mive/params"
 )
 
 var (
+	dumpConfigCommand = &cli.Command{
+		Action:      dumpConfig,
+		Name:        "dumpconfig",
+		Usage:       "Export configuration values in a TOML format",
+		ArgsUsage:   "<dumpfile (optional)>",
+		Flags:       flags.Merge(nodeFlags, rpcFlags),
+		Description: `Export configuration values in TOML format (to stdout by default).`,
+	}
+
 	configFileFlag = &cli.StringFlag{
 		Name:     "config",
 		Usage:    "TOML configuration file",
@@ -50,6 +61,7 @@ var tomlSettings = toml.Config{
 }
 
 type miveConfig struct {
+	Mive miveconfig.Config
 	Node node.Config
 }
 
@@ -84,6 +96,7 @@ func defaultNodeConfig() node.Config {
 func loadBaseConfig(ctx *cli.Context) miveConfig {
 	// Load defaults.
 	cfg := miveConfig{
+		Mive: miveconfig.Defaults,
 		Node: defaultNodeConfig(),
 	}
 
@@ -114,10 +127,16 @@ func makeConfigNode(ctx *cli.Context) (*node.Node, miveConfig) {
 	return stack, cfg
 }
 
-// makeFullNode loads geth configuration and creates the Ethereum backend.
-func makeFullNode(ctx *cli.Context) *node.Node {
-	stack, _ := makeConfigNode(ctx)
-	return stack
+// makeFullNode loads mive configuration and creates the Mive backend.
+func makeFullNode(ctx *cli.Context) (*node.Node, miveapi.Backend) {
+	stack, cfg := makeConfigNode(ctx)
+	utils.SetupTracing(ctx, stack)
+	utils.SetMiveConfig(ctx, &cfg.Mive)
+	if ctx.Bool(utils.DeveloperFlag.Name) {
+		utils.RegisterDeveloperL1(ctx, stack, &cfg.Mive)
+	}
+	backend, _ := utils.RegisterMiveService(stack, &cfg.Mive)
+	return stack, backend
 }
 
 func setAccountManagerBackends(conf *node.Config, am *accounts.Manager, keydir string) error {
@@ -167,3 +186,33 @@ func setAccountManagerBackends(conf *node.Config, am *accounts.Manager, keydir s
 
 	return nil
 }
+
+// dumpConfig is the dumpconfig command.
+func dumpConfig(ctx *cli.Context) error {
+	cfg := loadBaseConfig(ctx)
+	utils.SetMiveConfig(ctx, &cfg.Mive)
+	comment := ""
+
+	if cfg.Mive.Genesis != nil {
+		cfg.Mive.Genesis = nil
+		comment += "# Note: this config doesn't contain the genesis block.\n\n"
+	}
+
+	out, err := tomlSettings.Marshal(&cfg)
+	if err != nil {
+		return err
+	}
+
+	dump := os.Stdout
+	if ctx.NArg() > 0 {
+		dump, err = os.OpenFile(ctx.Args().Get(0), os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		defer dump.Close()
+	}
+	dump.WriteString(comment)
+	dump.Write(out)
+
+	return nil
+}
@@ -0,0 +1,139 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"fmt"
+
+	gethutils "github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-mive/mive/cmd/utils"
+	"github.com/ethereum-mive/mive/console"
+	"github.com/ethereum-mive/mive/internal/flags"
+)
+
+var (
+	consoleFlags = []cli.Flag{utils.JSpathFlag, utils.ExecFlag, utils.PreloadJSFlag}
+
+	consoleCommand = &cli.Command{
+		Action: localConsole,
+		Name:   "console",
+		Usage:  "Start an interactive JavaScript environment",
+		Flags:  flags.Merge(nodeFlags, rpcFlags, consoleFlags),
+		Description: `
+The Mive console is an interactive shell for the JavaScript runtime environment
+which exposes a node admin interface as well as the Ðapp JavaScript API, extended
+with the mive, debug, trace and txpool namespaces of the node.`,
+	}
+
+	attachCommand = &cli.Command{
+		Action:    remoteConsole,
+		Name:      "attach",
+		Usage:     "Start an interactive JavaScript environment (connect to node)",
+		ArgsUsage: "[endpoint]",
+		Flags:     flags.Merge([]cli.Flag{utils.DataDirFlag, utils.HttpHeaderFlag}, consoleFlags),
+		Description: `
+The Mive console is an interactive shell for the JavaScript runtime environment
+which exposes a node admin interface as well as the Ðapp JavaScript API, extended
+with the mive, debug, trace and txpool namespaces of the node.
+This command allows to open a console on a running mive node, over IPC (the
+default endpoint being the one of the data directory), HTTP or WebSocket.`,
+	}
+)
+
+// localConsole starts a new mive node, attaching a JavaScript console to it at the
+// same time.
+func localConsole(ctx *cli.Context) error {
+	// Create and start the node based on the CLI flags
+	prepare(ctx)
+	stack, _ := makeFullNode(ctx)
+	startNode(ctx, stack, true)
+	defer stack.Close()
+
+	// Attach to the newly started node and create the JavaScript console.
+	client := stack.Attach()
+	config := console.Config{
+		DataDir: stack.DataDir(),
+		DocRoot: ctx.String(utils.JSpathFlag.Name),
+		Client:  client,
+		Preload: gethutils.MakeConsolePreloads(ctx),
+	}
+	console, err := console.New(config)
+	if err != nil {
+		return fmt.Errorf("failed to start the JavaScript console: %v", err)
+	}
+	defer console.Stop(false)
+
+	// If only a short execution was requested, evaluate and return.
+	if script := ctx.String(utils.ExecFlag.Name); script != "" {
+		console.Evaluate(script)
+		return nil
+	}
+
+	// Track node shutdown and stop the console when it goes down.
+	// This happens when SIGTERM is sent to the process.
+	go func() {
+		stack.Wait()
+		console.StopInteractive()
+	}()
+
+	// Print the welcome screen and enter interactive mode.
+	console.Welcome()
+	console.Interactive()
+	return nil
+}
+
+// remoteConsole will connect to a remote mive instance, attaching a JavaScript
+// console to it.
+func remoteConsole(ctx *cli.Context) error {
+	if ctx.Args().Len() > 1 {
+		gethutils.Fatalf("invalid command-line: too many arguments")
+	}
+	cfg := defaultNodeConfig()
+	utils.SetDataDir(ctx, &cfg)
+
+	endpoint := ctx.Args().First()
+	if endpoint == "" {
+		endpoint = cfg.IPCEndpoint()
+	}
+	client, err := gethutils.DialRPCWithHeaders(endpoint, ctx.StringSlice(utils.HttpHeaderFlag.Name))
+	if err != nil {
+		gethutils.Fatalf("Unable to attach to remote mive: %v", err)
+	}
+	config := console.Config{
+		DataDir: cfg.DataDir,
+		DocRoot: ctx.String(utils.JSpathFlag.Name),
+		Client:  client,
+		Preload: gethutils.MakeConsolePreloads(ctx),
+	}
+	console, err := console.New(config)
+	if err != nil {
+		gethutils.Fatalf("Failed to start the JavaScript console: %v", err)
+	}
+	defer console.Stop(false)
+
+	if script := ctx.String(utils.ExecFlag.Name); script != "" {
+		console.Evaluate(script)
+		return nil
+	}
+
+	// Otherwise print the welcome screen and enter interactive mode
+	console.Welcome()
+	console.Interactive()
+	return nil
+}
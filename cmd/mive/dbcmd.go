@@ -0,0 +1,445 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	gethutils "github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/console/prompt"
+	gethrawdb "github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-mive/mive/cmd/utils"
+	"github.com/ethereum-mive/mive/core/rawdb"
+	"github.com/ethereum-mive/mive/internal/flags"
+	"github.com/ethereum-mive/mive/mive/miveconfig"
+)
+
+var (
+	forceFlag = &cli.BoolFlag{
+		Name:  "force",
+		Usage: "Skip the confirmation prompt",
+	}
+	migrateTargetFlag = &cli.StringFlag{
+		Name:  "to",
+		Usage: "Backing database engine to migrate to ('leveldb' or 'pebble')",
+		Value: "pebble",
+	}
+
+	dbCommand = &cli.Command{
+		Name:      "db",
+		Usage:     "Low level database operations",
+		ArgsUsage: "",
+		Subcommands: []*cli.Command{
+			dbInspectCmd,
+			dbStatCmd,
+			dbCompactCmd,
+			dbGetCmd,
+			dbDeleteCmd,
+			dbMigrateCmd,
+		},
+	}
+	dbInspectCmd = &cli.Command{
+		Action:      inspect,
+		Name:        "inspect",
+		ArgsUsage:   "<prefix> <start>",
+		Flags:       flags.Merge(utils.NetworkFlags, utils.DatabaseFlags),
+		Usage:       "Inspect the storage size for each type of data in the database",
+		Description: `This commands iterates the entire database. If the optional 'prefix' and 'start' arguments are provided, then the iteration is limited to the given subset of data.`,
+	}
+	dbStatCmd = &cli.Command{
+		Action: dbStats,
+		Name:   "stats",
+		Usage:  "Print leveldb statistics",
+		Flags:  flags.Merge(utils.NetworkFlags, utils.DatabaseFlags),
+	}
+	dbCompactCmd = &cli.Command{
+		Action: dbCompact,
+		Name:   "compact",
+		Usage:  "Compact leveldb database. WARNING: May take a very long time",
+		Flags:  flags.Merge(utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command performs a database compaction.
+WARNING: This operation may take a very long time to finish, and may cause database
+corruption if it is aborted during execution'!`,
+	}
+	dbGetCmd = &cli.Command{
+		Action:      dbGet,
+		Name:        "get",
+		Usage:       "Show the value of a database key",
+		ArgsUsage:   "<hex-encoded key>",
+		Flags:       flags.Merge(utils.NetworkFlags, utils.DatabaseFlags),
+		Description: "This command looks up the specified database key from the database.",
+	}
+	dbDeleteCmd = &cli.Command{
+		Action:    dbDelete,
+		Name:      "delete",
+		Usage:     "Delete a database key (WARNING: may corrupt your database)",
+		ArgsUsage: "<hex-encoded key>",
+		Flags: flags.Merge([]cli.Flag{
+			forceFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command deletes the specified database key from the database,
+after showing its current value and asking for confirmation (unless --force is set).
+WARNING: This is a low-level operation which may cause database corruption!`,
+	}
+	dbMigrateCmd = &cli.Command{
+		Action: dbMigrate,
+		Name:   "migrate",
+		Usage:  "Migrate the chain database to another backing engine",
+		Flags: flags.Merge([]cli.Flag{
+			migrateTargetFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command copies every key-value entry of the chain database into a new
+database backed by the engine given with --to, then swaps the two. The ancient
+store consists of flat files independent of the engine, it's carried over to the
+migrated database as is if it's kept in the default location.
+
+The migration can be aborted at any time and is resumed from where it stopped by
+running the command again. The original database is kept next to the migrated one,
+suffixed with the name of its engine, and can be removed afterwards.`,
+	}
+)
+
+func inspect(ctx *cli.Context) error {
+	var (
+		prefix []byte
+		start  []byte
+	)
+	if ctx.NArg() > 2 {
+		return fmt.Errorf("max 2 arguments: %v", ctx.Command.ArgsUsage)
+	}
+	if ctx.NArg() >= 1 {
+		if d, err := hexutil.Decode(ctx.Args().Get(0)); err != nil {
+			return fmt.Errorf("failed to hex-decode 'prefix': %v", err)
+		} else {
+			prefix = d
+		}
+	}
+	if ctx.NArg() >= 2 {
+		if d, err := hexutil.Decode(ctx.Args().Get(1)); err != nil {
+			return fmt.Errorf("failed to hex-decode 'start': %v", err)
+		} else {
+			start = d
+		}
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	return rawdb.InspectDatabase(db, prefix, start)
+}
+
+func showLeveldbStats(db ethdb.KeyValueStater) {
+	if stats, err := db.Stat("leveldb.stats"); err != nil {
+		log.Warn("Failed to read database stats", "error", err)
+	} else {
+		fmt.Println(stats)
+	}
+	if ioStats, err := db.Stat("leveldb.iostats"); err != nil {
+		log.Warn("Failed to read database iostats", "error", err)
+	} else {
+		fmt.Println(ioStats)
+	}
+}
+
+func dbStats(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	showLeveldbStats(db)
+	return nil
+}
+
+func dbCompact(ctx *cli.Context) error {
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	log.Info("Stats before compaction")
+	showLeveldbStats(db)
+
+	log.Info("Triggering compaction")
+	if err := db.Compact(nil, nil); err != nil {
+		log.Info("Compact err", "error", err)
+		return err
+	}
+	log.Info("Stats after compaction")
+	showLeveldbStats(db)
+	return nil
+}
+
+// dbGet shows the value of a given database key
+func dbGet(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, true)
+	defer db.Close()
+
+	key, err := common.ParseHexOrString(ctx.Args().Get(0))
+	if err != nil {
+		log.Info("Could not decode the key", "error", err)
+		return err
+	}
+
+	data, err := db.Get(key)
+	if err != nil {
+		log.Info("Get operation failed", "key", fmt.Sprintf("%#x", key), "error", err)
+		return err
+	}
+	fmt.Printf("key %#x: %#x\n", key, data)
+	return nil
+}
+
+// dbDelete deletes a key from the database
+func dbDelete(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	db := utils.MakeChainDatabase(ctx, stack, false)
+	defer db.Close()
+
+	key, err := common.ParseHexOrString(ctx.Args().Get(0))
+	if err != nil {
+		log.Info("Could not decode the key", "error", err)
+		return err
+	}
+	data, err := db.Get(key)
+	if err != nil {
+		log.Info("Key not found in the database", "key", fmt.Sprintf("%#x", key), "error", err)
+		return err
+	}
+	fmt.Printf("Previous value: %#x\n", data)
+
+	if !ctx.Bool(forceFlag.Name) {
+		confirm, err := prompt.Stdin.PromptConfirm(fmt.Sprintf("Delete key %#x?", key))
+		if err != nil {
+			gethutils.Fatalf("%v", err)
+		}
+		if !confirm {
+			log.Info("Key deletion skipped", "key", fmt.Sprintf("%#x", key))
+			return nil
+		}
+	}
+	if err = db.Delete(key); err != nil {
+		log.Info("Delete operation returned an error", "key", fmt.Sprintf("%#x", key), "error", err)
+		return err
+	}
+	return nil
+}
+
+// migrationKey tracks the progress of an engine migration in the database being
+// migrated to.
+var migrationKey = []byte("MiveDatabaseMigration")
+
+// migrationProgress is the resumption point of an engine migration, it's stored
+// atomically with each batch of copied entries.
+type migrationProgress struct {
+	Source string // Engine of the database being migrated from
+	Next   []byte // Key to resume copying from
+	Keys   uint64 // Number of entries copied so far
+	Size   uint64 // Total size of the entries copied so far
+	Done   bool   // Whether all entries have been copied
+}
+
+// dbMigrate copies the chain database into a new one backed by another engine,
+// then swaps the two.
+func dbMigrate(ctx *cli.Context) error {
+	target := ctx.String(migrateTargetFlag.Name)
+	if target != "leveldb" && target != "pebble" {
+		return fmt.Errorf("invalid --%s '%s', allowed 'leveldb' or 'pebble'", migrateTargetFlag.Name, target)
+	}
+	stack, _ := makeConfigNode(ctx)
+	defer stack.Close()
+
+	var (
+		dir     = stack.ResolvePath("chaindata")
+		tmp     = dir + ".migration"
+		cache   = miveconfig.Defaults.DatabaseCache / 2
+		handles = gethutils.MakeDatabaseHandles(0) / 2
+		resumed = common.FileExist(tmp)
+	)
+	if !resumed {
+		engine := gethrawdb.PreexistingDatabase(dir)
+		if engine == "" {
+			return errors.New("no chain database found")
+		}
+		if engine == target {
+			// The previous migration may have been interrupted right after the
+			// swap, make sure its marker doesn't linger around.
+			db, err := gethrawdb.Open(gethrawdb.OpenOptions{Type: target, Directory: dir, Cache: cache, Handles: handles})
+			if err != nil {
+				return err
+			}
+			defer db.Close()
+
+			log.Info("Chain database is already backed by the target engine", "engine", target)
+			return db.Delete(migrationKey)
+		}
+	}
+	dst, err := gethrawdb.Open(gethrawdb.OpenOptions{Type: target, Directory: tmp, Cache: cache, Handles: handles})
+	if err != nil {
+		return err
+	}
+	progress := new(migrationProgress)
+	if blob, _ := dst.Get(migrationKey); len(blob) > 0 {
+		if err := rlp.DecodeBytes(blob, progress); err != nil {
+			dst.Close()
+			return fmt.Errorf("invalid migration progress: %v", err)
+		}
+	} else if resumed {
+		dst.Close()
+		return fmt.Errorf("unknown database found at %s, remove it to start over", tmp)
+	}
+	if !progress.Done {
+		if progress.Source == "" {
+			progress.Source = gethrawdb.PreexistingDatabase(dir)
+		}
+		src, err := gethrawdb.Open(gethrawdb.OpenOptions{Directory: dir, Cache: cache, Handles: handles, ReadOnly: true})
+		if err != nil {
+			dst.Close()
+			return err
+		}
+		err = migrateKeyValues(src, dst, progress)
+		src.Close()
+		if err != nil {
+			dst.Close()
+			return err
+		}
+	}
+	if err := dst.Close(); err != nil {
+		return err
+	}
+	// All entries are copied, carry the ancient store over if it's kept in the
+	// database directory, then swap the databases.
+	if ctx.String(utils.AncientFlag.Name) == "" {
+		from, to := filepath.Join(dir, "ancient"), filepath.Join(tmp, "ancient")
+		if common.FileExist(from) && !common.FileExist(to) {
+			if err := os.Rename(from, to); err != nil {
+				return err
+			}
+		}
+	}
+	backup := dir + "." + progress.Source
+	if common.FileExist(dir) {
+		if common.FileExist(backup) {
+			return fmt.Errorf("can't back up the original database, %s already exists", backup)
+		}
+		if err := os.Rename(dir, backup); err != nil {
+			return err
+		}
+	}
+	if err := os.Rename(tmp, dir); err != nil {
+		return err
+	}
+	db, err := gethrawdb.Open(gethrawdb.OpenOptions{Type: target, Directory: dir, Cache: cache, Handles: handles})
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	if err := db.Delete(migrationKey); err != nil {
+		return err
+	}
+	log.Info("Migrated chain database", "engine", target, "original", backup)
+	return nil
+}
+
+// migrateKeyValues streams the entries of the source database into the destination
+// one, starting from the recorded progress.
+func migrateKeyValues(src ethdb.KeyValueStore, dst ethdb.KeyValueStore, progress *migrationProgress) error {
+	var (
+		it     = src.NewIterator(nil, progress.Next)
+		batch  = dst.NewBatch()
+		start  = time.Now()
+		logged = time.Now()
+	)
+	defer it.Release()
+
+	// Persist the progress along with the copied entries, so the migration can be
+	// resumed after an interruption at any point.
+	commit := func() error {
+		blob, err := rlp.EncodeToBytes(progress)
+		if err != nil {
+			return err
+		}
+		if err := batch.Put(migrationKey, blob); err != nil {
+			return err
+		}
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		batch.Reset()
+		return nil
+	}
+	if progress.Keys > 0 {
+		log.Info("Resuming database migration", "keys", progress.Keys, "size", common.StorageSize(progress.Size))
+	} else {
+		log.Info("Starting database migration", "from", progress.Source)
+	}
+	for it.Next() {
+		key, value := it.Key(), it.Value()
+		if err := batch.Put(key, value); err != nil {
+			return err
+		}
+		progress.Next = append(common.CopyBytes(key), 0)
+		progress.Keys++
+		progress.Size += uint64(len(key) + len(value))
+
+		if batch.ValueSize() >= ethdb.IdealBatchSize {
+			if err := commit(); err != nil {
+				return err
+			}
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Migrating database", "keys", progress.Keys, "size", common.StorageSize(progress.Size), "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	progress.Done = true
+	if err := commit(); err != nil {
+		return err
+	}
+	log.Info("Copied database entries", "keys", progress.Keys, "size", common.StorageSize(progress.Size), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	dnsTreeNodesFile = "nodes.json"        // List of the node records of a tree
+	dnsTreeMetaFile  = "enrtree-info.json" // Signed root of a tree
+)
+
+var (
+	dnsCommand = &cli.Command{
+		Name:  "dns",
+		Usage: "DNS discovery tree utilities",
+		Subcommands: []*cli.Command{
+			dnsSignCommand,
+			dnsTXTCommand,
+		},
+		Description: `The DNS discovery trees publish lists of Mive nodes as DNS TXT records, the
+nodes get them with --discovery.dns. A tree is kept in a directory holding the
+list of the node records (ENRs) in nodes.json, and the signed root of the tree
+along with the links to other trees in enrtree-info.json.`,
+	}
+	dnsSignCommand = &cli.Command{
+		Action:    dnsSign,
+		Name:      "sign",
+		Usage:     "Sign a DNS discovery tree of Mive nodes",
+		ArgsUsage: "<tree-directory> <key-file>",
+		Flags:     []cli.Flag{dnsDomainFlag, dnsSeqFlag},
+		Description: `This command builds the tree of the nodes listed in the tree directory, signs
+it with the hex-encoded private key of the key file and stores the signed root.
+The sequence number of the tree is bumped, and the enrtree:// URL of the tree is
+printed. The domain defaults to the one of the previously signed tree, or to the
+name of the tree directory.`,
+	}
+	dnsTXTCommand = &cli.Command{
+		Action:    dnsToTXT,
+		Name:      "to-txt",
+		Usage:     "Create the DNS TXT records of a signed discovery tree",
+		ArgsUsage: "<tree-directory> [ <output-file> ]",
+		Description: `This command writes the TXT records to deploy for the signed tree, as JSON
+mapping the record names to their values, to the output file or to stdout.`,
+	}
+
+	dnsDomainFlag = &cli.StringFlag{
+		Name:  "domain",
+		Usage: "Domain name of the tree",
+	}
+	dnsSeqFlag = &cli.UintFlag{
+		Name:  "seq",
+		Usage: "New sequence number of the tree",
+	}
+)
+
+// dnsTreeMeta is the signed root of a tree, with the links to other trees.
+type dnsTreeMeta struct {
+	URL   string   `json:"url,omitempty"`
+	Seq   uint     `json:"seq"`
+	Sig   string   `json:"signature,omitempty"`
+	Links []string `json:"links,omitempty"`
+}
+
+// dnsSign signs the tree of the given directory.
+func dnsSign(ctx *cli.Context) error {
+	if ctx.NArg() != 2 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	dir := ctx.Args().Get(0)
+	nodes, meta, err := loadDNSTree(dir)
+	if err != nil {
+		return err
+	}
+	key, err := crypto.LoadECDSA(ctx.Args().Get(1))
+	if err != nil {
+		return fmt.Errorf("could not load the signing key: %v", err)
+	}
+	domain := ctx.String(dnsDomainFlag.Name)
+	if domain == "" && meta.URL != "" {
+		if domain, _, err = dnsdisc.ParseURL(meta.URL); err != nil {
+			return fmt.Errorf("invalid tree URL: %v", err)
+		}
+	}
+	if domain == "" {
+		abs, err := filepath.Abs(dir)
+		if err != nil {
+			return err
+		}
+		domain = filepath.Base(abs)
+	}
+	seq := meta.Seq + 1
+	if ctx.IsSet(dnsSeqFlag.Name) {
+		seq = ctx.Uint(dnsSeqFlag.Name)
+	}
+	tree, err := dnsdisc.MakeTree(seq, nodes, meta.Links)
+	if err != nil {
+		return err
+	}
+	url, err := tree.Sign(key, domain)
+	if err != nil {
+		return fmt.Errorf("could not sign the tree: %v", err)
+	}
+	meta.URL, meta.Seq, meta.Sig = url, tree.Seq(), tree.Signature()
+	if err := writeJSONFile(filepath.Join(dir, dnsTreeMetaFile), meta); err != nil {
+		return err
+	}
+	log.Info("Signed DNS discovery tree", "domain", domain, "seq", meta.Seq, "nodes", len(nodes), "links", len(meta.Links))
+	fmt.Println(url)
+	return nil
+}
+
+// dnsToTXT writes the TXT records of the signed tree of the given directory.
+func dnsToTXT(ctx *cli.Context) error {
+	if ctx.NArg() < 1 || ctx.NArg() > 2 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	nodes, meta, err := loadDNSTree(ctx.Args().Get(0))
+	if err != nil {
+		return err
+	}
+	if meta.URL == "" || meta.Sig == "" {
+		return errors.New("the tree isn't signed")
+	}
+	domain, pubkey, err := dnsdisc.ParseURL(meta.URL)
+	if err != nil {
+		return fmt.Errorf("invalid tree URL: %v", err)
+	}
+	tree, err := dnsdisc.MakeTree(meta.Seq, nodes, meta.Links)
+	if err != nil {
+		return err
+	}
+	if err := tree.SetSignature(pubkey, meta.Sig); err != nil {
+		return fmt.Errorf("the tree was modified since it was signed: %v", err)
+	}
+	records := tree.ToTXT(domain)
+	if output := ctx.Args().Get(1); output != "" {
+		return writeJSONFile(output, records)
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(records)
+}
+
+// loadDNSTree loads the node records and the signed root of the tree of the
+// given directory. The root is empty if the tree was never signed.
+func loadDNSTree(dir string) ([]*enode.Node, *dnsTreeMeta, error) {
+	var records []string
+	if err := common.LoadJSON(filepath.Join(dir, dnsTreeNodesFile), &records); err != nil {
+		return nil, nil, err
+	}
+	nodes := make([]*enode.Node, 0, len(records))
+	for _, record := range records {
+		node, err := enode.Parse(enode.ValidSchemes, record)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid node record %q: %v", record, err)
+		}
+		// The records of the tree are verified by the nodes, they must be
+		// signed ENRs rather than enode URLs.
+		if node.Record().IdentityScheme() != "v4" {
+			return nil, nil, fmt.Errorf("node %v has no signed record", node.ID())
+		}
+		nodes = append(nodes, node)
+	}
+	meta := new(dnsTreeMeta)
+	if err := common.LoadJSON(filepath.Join(dir, dnsTreeMetaFile), meta); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, nil, err
+	}
+	return nodes, meta, nil
+}
+
+// writeJSONFile writes the indented JSON encoding of the value to the file.
+func writeJSONFile(file string, value interface{}) error {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, append(data, '\n'), 0644)
+}
@@ -3,19 +3,261 @@ package main
 import (
 	"fmt"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	gethutils "github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/urfave/cli/v2"
+
+	"github.com/ethereum-mive/mive/cmd/utils"
+	"github.com/ethereum-mive/mive/internal/debug"
 	"github.com/ethereum-mive/mive/internal/flags"
+	"github.com/ethereum-mive/mive/node"
+
+	// Force-load the tracer engines to trigger registration
+	_ "github.com/ethereum/go-ethereum/eth/tracers/js"
+	_ "github.com/ethereum/go-ethereum/eth/tracers/native"
 )
 
 const (
 	clientIdentifier = "mive" // Client identifier
 )
 
+var (
+	// flags that configure the node
+	nodeFlags = flags.Merge([]cli.Flag{
+		configFileFlag,
+		utils.MinFreeDiskSpaceFlag,
+		utils.ShutdownTimeoutFlag,
+		utils.ReadOnlyFlag,
+		utils.KeyStoreDirFlag,
+		utils.ExternalSignerFlag,
+		utils.USBFlag,
+		utils.NetworkIdFlag,
+		utils.SyncModeFlag,
+		utils.CheckpointNumberFlag,
+		utils.CheckpointRootFlag,
+		utils.CheckpointURLFlag,
+		utils.CheckpointArchiveFlag,
+		utils.SnapshotFlag,
+		utils.BloomFilterSizeFlag,
+		utils.BloomBitsBlocksFlag,
+		utils.CacheLogSizeFlag,
+		utils.CacheTrieTimeLimitFlag,
+		utils.CacheTrieJournalFlag,
+		utils.CacheTrieRejournalFlag,
+		utils.GCModeFlag,
+		utils.StateSchemeFlag,
+		utils.StateHistoryFlag,
+		utils.TxLookupLimitFlag,
+		utils.LightKDFFlag,
+		utils.MiveEthFlag,
+		utils.MiveEthArchiveFlag,
+		utils.MiveEthPolicyFlag,
+		utils.MiveEthRateLimitFlag,
+		utils.MiveEthRetriesFlag,
+		utils.MiveBeaconFlag,
+		utils.MiveBeaconAddressFlag,
+		utils.MiveGenesisBlockFlag,
+		utils.MiveConfirmationsFlag,
+		utils.MiveBadBlockReportFlag,
+		utils.MiveTxPoolFlag,
+		utils.MiveTxPoolLifetimeFlag,
+		utils.MiveTxPoolTrustFlag,
+		utils.MiveRelayerFlag,
+		utils.MiveRelayerFeeCapFlag,
+		utils.MiveRelayerBumpIntervalFlag,
+		utils.MiveProposerFlag,
+		utils.MiveProposerIntervalFlag,
+		utils.MiveHealthFlag,
+		utils.MiveHealthMaxLagFlag,
+		utils.MiveExternalDriverFlag,
+		utils.MiveBadHashesFlag,
+		utils.OverrideCancun,
+		utils.OverrideVerkle,
+		utils.OverrideMiveM1,
+		utils.OverrideMiveM2,
+		utils.UnlockedAccountFlag,
+		utils.PasswordFileFlag,
+		utils.InsecureUnlockAllowedFlag,
+		utils.GpoBlocksFlag,
+		utils.GpoPercentileFlag,
+		utils.GpoMaxGasPriceFlag,
+		utils.GpoIgnoreGasPriceFlag,
+		utils.VMEnableDebugFlag,
+		utils.DeveloperFlag,
+		utils.DeveloperPeriodFlag,
+		utils.DeveloperGasLimitFlag,
+	}, utils.NetworkFlags, utils.NetworkingFlags, utils.DatabaseFlags)
+
+	rpcFlags = []cli.Flag{
+		utils.HTTPEnabledFlag,
+		utils.HTTPListenAddrFlag,
+		utils.HTTPPortFlag,
+		utils.HTTPCORSDomainFlag,
+		utils.AuthListenFlag,
+		utils.AuthPortFlag,
+		utils.AuthVirtualHostsFlag,
+		utils.JWTSecretFlag,
+		utils.HTTPVirtualHostsFlag,
+		utils.GraphQLEnabledFlag,
+		utils.GraphQLCORSDomainFlag,
+		utils.GraphQLVirtualHostsFlag,
+		utils.HTTPApiFlag,
+		utils.HTTPPathPrefixFlag,
+		utils.WSEnabledFlag,
+		utils.WSListenAddrFlag,
+		utils.WSPortFlag,
+		utils.WSApiFlag,
+		utils.WSAllowedOriginsFlag,
+		utils.WSPathPrefixFlag,
+		utils.IPCDisabledFlag,
+		utils.IPCPathFlag,
+		utils.AllowUnprotectedTxs,
+		utils.BatchRequestLimit,
+		utils.BatchResponseMaxSize,
+		utils.RPCGlobalGasCapFlag,
+		utils.RPCGlobalEVMTimeoutFlag,
+		utils.RPCReexecFlag,
+		utils.RPCGlobalTxFeeCapFlag,
+		utils.RPCLogsRangeLimitFlag,
+	}
+)
+
 var app = flags.NewApp("the mive command line interface")
 
+func init() {
+	// Initialize the CLI app and start Mive
+	app.Action = mive
+	app.Commands = []*cli.Command{
+		// See config.go
+		dumpConfigCommand,
+		// See chaincmd.go
+		initCommand,
+		importCommand,
+		exportCommand,
+		importHistoryCommand,
+		exportHistoryCommand,
+		rollbackCommand,
+		// See accountcmd.go
+		accountCommand,
+		// See consolecmd.go
+		consoleCommand,
+		attachCommand,
+		// See payloadcmd.go
+		payloadCommand,
+		// See dbcmd.go
+		dbCommand,
+		// See snapshot.go
+		snapshotCommand,
+		// See dnscmd.go
+		dnsCommand,
+	}
+	app.Flags = flags.Merge(nodeFlags, rpcFlags, consoleFlags, utils.MetricsFlags, utils.TracingFlags, debug.Flags)
+	app.Before = func(ctx *cli.Context) error {
+		flags.MigrateGlobalFlags(ctx)
+		return debug.Setup(ctx)
+	}
+	app.After = func(ctx *cli.Context) error {
+		debug.Exit()
+		return nil
+	}
+}
+
 func main() {
 	if err := app.Run(os.Args); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
 }
+
+// mive is the main entry point into the system if no special subcommand is run.
+// It creates a default node based on the command line arguments and runs it in
+// blocking mode, waiting for it to be shut down.
+func mive(ctx *cli.Context) error {
+	if args := ctx.Args().Slice(); len(args) > 0 {
+		return fmt.Errorf("invalid command: %q", args[0])
+	}
+	prepare(ctx)
+	stack, _ := makeFullNode(ctx)
+	defer stack.Close()
+
+	startNode(ctx, stack, false)
+	stack.Wait()
+	return nil
+}
+
+// prepare manipulates the process before the node is started: the collected
+// metrics are exported if requested, along with the process metrics.
+func prepare(ctx *cli.Context) {
+	// Start metrics export if enabled
+	utils.SetupMetrics(ctx)
+
+	// Start system runtime metrics collection
+	go metrics.CollectProcessMetrics(3 * time.Second)
+}
+
+// startNode boots up the system node and all registered protocols, after which
+// it unlocks any requested accounts. The node is shut down gracefully on SIGINT
+// or SIGTERM, see shutdown.
+func startNode(ctx *cli.Context, stack *node.Node, isConsole bool) {
+	if err := stack.Start(); err != nil {
+		gethutils.Fatalf("Error starting protocol stack: %v", err)
+	}
+	go func() {
+		sigc := make(chan os.Signal, 1)
+		signal.Notify(sigc, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigc)
+
+		for sig := range sigc {
+			// In JS console mode, SIGINT is ignored because it's handled by the
+			// console. However, SIGTERM still shuts down the node.
+			if !isConsole || sig == syscall.SIGTERM {
+				break
+			}
+		}
+		shutdown(stack, sigc, ctx.Duration(utils.ShutdownTimeoutFlag.Name))
+	}()
+	unlockAccounts(ctx, stack)
+}
+
+// shutdown closes the node and waits for it to stop: derivation is interrupted
+// once the block being processed is committed, the chain state and snapshot are
+// journaled and the databases closed. If the node doesn't stop within the given
+// timeout (0 meaning no limit) or the process is interrupted 10 more times, the
+// exit is forced with the stacks of all goroutines printed.
+func shutdown(stack *node.Node, sigc <-chan os.Signal, timeout time.Duration) {
+	log.Info("Got interrupt, shutting down...", "timeout", common.PrettyDuration(timeout))
+
+	done := make(chan struct{})
+	go func() {
+		stack.Close()
+		close(done)
+	}()
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	for interrupts := 9; ; interrupts-- {
+		select {
+		case <-done:
+			return
+		case <-expired:
+			log.Error("Shutdown timed out, forcing exit", "timeout", common.PrettyDuration(timeout))
+		case <-sigc:
+			if interrupts > 0 {
+				log.Warn("Already shutting down, interrupt more to force exit.", "times", interrupts)
+				continue
+			}
+		}
+		break
+	}
+	debug.Exit() // ensure trace and CPU profile data is flushed.
+	debug.LoudPanic("boom")
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+var (
+	payloadCommand = &cli.Command{
+		Name:  "payload",
+		Usage: "Beacon transaction payload utilities",
+		Subcommands: []*cli.Command{
+			payloadCompressCmd,
+		},
+	}
+	payloadCompressCmd = &cli.Command{
+		Action:    payloadCompress,
+		Name:      "compress",
+		Usage:     "Compress a beacon transaction payload",
+		ArgsUsage: "<hex-encoded payload>",
+		Description: `This command compresses the given beacon transaction payload, i.e. the L1
+calldata carrying Mive transactions, and prints the compressed payload. The calldata
+gas of both payloads is logged: compression mostly pays off for large payloads, such
+as contract deployments.`,
+	}
+)
+
+// payloadCompress compresses the given beacon transaction payload.
+func payloadCompress(ctx *cli.Context) error {
+	if ctx.NArg() != 1 {
+		return fmt.Errorf("required arguments: %v", ctx.Command.ArgsUsage)
+	}
+	payload, err := common.ParseHexOrString(ctx.Args().Get(0))
+	if err != nil {
+		return fmt.Errorf("could not decode the payload: %v", err)
+	}
+	txs, err := mivetypes.DecodePayload(payload)
+	if err != nil {
+		return fmt.Errorf("invalid payload: %v", err)
+	}
+	compressed, err := mivetypes.CompressPayload(payload)
+	if err != nil {
+		return err
+	}
+	log.Info("Compressed payload", "txs", len(txs), "size", len(payload), "compressed", len(compressed),
+		"gas", dataGas(payload), "compressedgas", dataGas(compressed))
+	fmt.Printf("%#x\n", compressed)
+	return nil
+}
+
+// dataGas returns the calldata gas of the given beacon transaction payload.
+func dataGas(payload []byte) uint64 {
+	gas, _ := core.IntrinsicGas(payload, nil, false, true, true, true)
+	return gas - params.TxGas
+}
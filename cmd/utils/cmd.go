@@ -0,0 +1,377 @@
+// Copyright 2014 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+package utils
+
+import (
+	"compress/gzip"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	"github.com/ethereum-mive/mive/core"
+	"github.com/ethereum-mive/mive/internal/era"
+)
+
+const (
+	importBatchSize = 2500
+)
+
+// ImportChain imports the Mive blocks exported into the given file, along with
+// their receipts and rejections, into the chain.
+func ImportChain(chain *core.BlockChain, fn string) error {
+	// Watch for Ctrl-C while the import is running.
+	// If a signal is received, the import will stop at the next batch.
+	interrupt := make(chan os.Signal, 1)
+	stop := make(chan struct{})
+	signal.Notify(interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+	defer close(interrupt)
+	go func() {
+		if _, ok := <-interrupt; ok {
+			log.Info("Interrupted during import, stopping at next batch")
+		}
+		close(stop)
+	}()
+	checkInterrupt := func() bool {
+		select {
+		case <-stop:
+			return true
+		default:
+			return false
+		}
+	}
+
+	log.Info("Importing blockchain", "file", fn)
+
+	// Open the file handle and potentially unwrap the gzip stream
+	fh, err := os.Open(fn)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var reader io.Reader = fh
+	if strings.HasSuffix(fn, ".gz") {
+		if reader, err = gzip.NewReader(reader); err != nil {
+			return err
+		}
+	}
+	stream := rlp.NewStream(reader, 0)
+
+	// Run actual the import.
+	blocks := make([]*core.ExportedBlock, importBatchSize)
+	n := 0
+	for batch := 0; ; batch++ {
+		// Load a batch of RLP blocks.
+		if checkInterrupt() {
+			return errors.New("interrupted")
+		}
+		i := 0
+		for ; i < importBatchSize; i++ {
+			var b core.ExportedBlock
+			if err := stream.Decode(&b); err == io.EOF {
+				break
+			} else if err != nil {
+				return fmt.Errorf("at block %d: %v", n, err)
+			}
+			blocks[i] = &b
+			n++
+		}
+		if i == 0 {
+			break
+		}
+		// Import the batch.
+		if checkInterrupt() {
+			return errors.New("interrupted")
+		}
+		if failindex, err := chain.InsertExportedChain(blocks[:i]); err != nil {
+			var failnumber uint64
+			if failindex > 0 && failindex < i {
+				failnumber = blocks[failindex].Block.NumberU64()
+			} else {
+				failnumber = blocks[0].Block.NumberU64()
+			}
+			return fmt.Errorf("invalid block %d: %v", failnumber, err)
+		}
+		log.Info("Imported batch of blocks", "batch", batch, "first", blocks[0].Block.Number(), "last", blocks[i-1].Block.Number())
+	}
+	return nil
+}
+
+// ExportChain exports the Mive chain into the specified file, truncating any
+// data already present in the file.
+func ExportChain(blockchain *core.BlockChain, fn string) error {
+	log.Info("Exporting blockchain", "file", fn)
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	// Iterate over the blocks and export them
+	if err := blockchain.Export(writer); err != nil {
+		return err
+	}
+	log.Info("Exported blockchain", "file", fn)
+
+	return nil
+}
+
+// ExportAppendChain exports the Mive chain into the specified file, appending
+// to the file if data already exists in it.
+func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, last uint64) error {
+	log.Info("Exporting blockchain", "file", fn)
+
+	// Open the file handle and potentially wrap with a gzip stream
+	fh, err := os.OpenFile(fn, os.O_CREATE|os.O_APPEND|os.O_WRONLY, os.ModePerm)
+	if err != nil {
+		return err
+	}
+	defer fh.Close()
+
+	var writer io.Writer = fh
+	if strings.HasSuffix(fn, ".gz") {
+		writer = gzip.NewWriter(writer)
+		defer writer.(*gzip.Writer).Close()
+	}
+	// Iterate over the blocks and export them
+	if err := blockchain.ExportN(writer, first, last); err != nil {
+		return err
+	}
+	log.Info("Exported blockchain to", "file", fn)
+	return nil
+}
+
+// ExportHistory exports the Mive chain into the specified directory as Era1
+// archives, along with a checksums.txt file listing the sha256 checksum of each
+// archive. The archives are aligned to epochs of era.MaxEra1Size blocks.
+func ExportHistory(bc *core.BlockChain, dir string, network string, first, last uint64) error {
+	log.Info("Exporting blockchain history", "dir", dir)
+	if head := bc.CurrentSnapBlock().Number.Uint64(); head < last {
+		log.Warn("Last block beyond head, setting last = head", "head", head, "last", last)
+		last = head
+	}
+	if first > last {
+		return fmt.Errorf("export failed: first (%d) is greater than last (%d)", first, last)
+	}
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("error creating output directory: %w", err)
+	}
+	var (
+		start     = time.Now()
+		reported  = time.Now()
+		step      = uint64(era.MaxEra1Size)
+		checksums []string
+	)
+	for from := first; from <= last; {
+		var (
+			epoch = from / step
+			to    = (epoch+1)*step - 1
+		)
+		if to > last {
+			to = last
+		}
+		err := func() error {
+			filename := filepath.Join(dir, era.Filename(network, int(epoch), common.Hash{}))
+			f, err := os.Create(filename)
+			if err != nil {
+				return fmt.Errorf("could not create era file: %w", err)
+			}
+			defer f.Close()
+
+			w := era.NewBuilder(f)
+			for n := from; n <= to; n++ {
+				block := bc.GetMiveBlockByNumber(n)
+				if block == nil {
+					return fmt.Errorf("export failed on #%d: not found", n)
+				}
+				receipts := bc.GetReceiptsByHash(block.Hash())
+				if receipts == nil {
+					return fmt.Errorf("export failed on #%d: receipts not found", n)
+				}
+				if err := w.Add(block, receipts, bc.GetRejections(block.Hash())); err != nil {
+					return err
+				}
+			}
+			root, err := w.Finalize()
+			if err != nil {
+				return fmt.Errorf("export failed to finalize %d: %w", epoch, err)
+			}
+			// Set correct filename with root.
+			if err := os.Rename(filename, filepath.Join(dir, era.Filename(network, int(epoch), root))); err != nil {
+				return err
+			}
+			// Compute checksum of entire Era1.
+			if _, err := f.Seek(0, io.SeekStart); err != nil {
+				return err
+			}
+			h := sha256.New()
+			if _, err := io.Copy(h, f); err != nil {
+				return fmt.Errorf("unable to calculate checksum: %w", err)
+			}
+			checksums = append(checksums, common.BytesToHash(h.Sum(nil)).Hex())
+			return nil
+		}()
+		if err != nil {
+			return err
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Exporting blocks", "exported", to-first+1, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+		from = to + 1
+	}
+	if err := os.WriteFile(filepath.Join(dir, "checksums.txt"), []byte(strings.Join(checksums, "\n")), os.ModePerm); err != nil {
+		return fmt.Errorf("unable to write checksums.txt: %w", err)
+	}
+
+	log.Info("Exported blockchain to", "dir", dir)
+	return nil
+}
+
+// ImportHistory imports the Era1 archives of the given network found in the
+// specified directory into the chain. The archives are verified against the
+// checksums.txt file of the directory and against their accumulators.
+func ImportHistory(chain *core.BlockChain, dir string, network string) error {
+	log.Info("Importing blockchain history", "dir", dir)
+
+	entries, err := era.ReadDir(dir, network)
+	if err != nil {
+		return fmt.Errorf("error reading %s: %w", dir, err)
+	}
+	checksums, err := readList(filepath.Join(dir, "checksums.txt"))
+	if err != nil {
+		return fmt.Errorf("unable to read checksums.txt: %w", err)
+	}
+	if len(checksums) != len(entries) {
+		return fmt.Errorf("expected equal number of checksums and entries, have: %d checksums, %d entries", len(checksums), len(entries))
+	}
+	var (
+		start    = time.Now()
+		reported = time.Now()
+		imported = 0
+	)
+	for i, filename := range entries {
+		err := func() error {
+			f, err := os.Open(filepath.Join(dir, filename))
+			if err != nil {
+				return fmt.Errorf("unable to open era: %w", err)
+			}
+			defer f.Close()
+
+			// Validate checksum.
+			h := sha256.New()
+			if _, err := io.Copy(h, f); err != nil {
+				return fmt.Errorf("unable to recalculate checksum: %w", err)
+			}
+			if have, want := common.BytesToHash(h.Sum(nil)).Hex(), checksums[i]; have != want {
+				return fmt.Errorf("checksum mismatch: have %s, want %s", have, want)
+			}
+			// Import all block data from Era1.
+			e, err := era.From(f)
+			if err != nil {
+				return fmt.Errorf("error opening era: %w", err)
+			}
+			it, err := era.NewIterator(e)
+			if err != nil {
+				return fmt.Errorf("error making era reader: %w", err)
+			}
+			var (
+				blocks []*core.ExportedBlock
+				hashes []common.Hash
+			)
+			for it.Next() {
+				block, err := it.Block()
+				if err != nil {
+					return fmt.Errorf("error reading block %d: %w", it.Number(), err)
+				}
+				receipts, err := it.Receipts()
+				if err != nil {
+					return fmt.Errorf("error reading receipts %d: %w", it.Number(), err)
+				}
+				rejections, err := it.Rejections()
+				if err != nil {
+					return fmt.Errorf("error reading rejections %d: %w", it.Number(), err)
+				}
+				blocks = append(blocks, &core.ExportedBlock{Block: block, Receipts: receipts, Rejections: rejections})
+				hashes = append(hashes, block.Hash())
+			}
+			if err := it.Error(); err != nil {
+				return fmt.Errorf("error iterating era %s: %w", filename, err)
+			}
+			// Verify the block hashes against the accumulator of the archive
+			// and its file name.
+			root, err := e.Accumulator()
+			if err != nil {
+				return fmt.Errorf("error reading accumulator: %w", err)
+			}
+			if have := era.ComputeAccumulator(hashes); have != root {
+				return fmt.Errorf("accumulator mismatch: have %x, want %x", have, root)
+			}
+			if want := era.Filename(network, int(e.Start()/uint64(era.MaxEra1Size)), root); filename != want {
+				return fmt.Errorf("era file name mismatch: have %s, want %s", filename, want)
+			}
+			if _, err := chain.InsertExportedChain(blocks); err != nil {
+				return fmt.Errorf("error inserting blocks: %w", err)
+			}
+			imported += len(blocks)
+			return nil
+		}()
+		if err != nil {
+			return err
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Importing Era files", "head", chain.CurrentSnapBlock().Number, "imported", imported, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	log.Info("Imported blockchain history", "dir", dir, "blocks", imported, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// readList reads the non-empty lines of the given file.
+func readList(filename string) ([]string, error) {
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var list []string
+	for _, line := range strings.Split(string(b), "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			list = append(list, line)
+		}
+	}
+	return list, nil
+}
@@ -19,16 +19,47 @@ package utils
 
 import (
 	"fmt"
+	"math/big"
+	"net"
+	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/cmd/utils"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/metrics/exp"
+	"github.com/ethereum/go-ethereum/metrics/influxdb"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/netutil"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/triedb/hashdb"
+	"github.com/ethereum/go-ethereum/trie/triedb/pathdb"
 	"github.com/urfave/cli/v2"
 
+	mivecore "github.com/ethereum-mive/mive/core"
+	miveethclient "github.com/ethereum-mive/mive/ethclient"
 	"github.com/ethereum-mive/mive/internal/flags"
+	"github.com/ethereum-mive/mive/internal/miveapi"
+	"github.com/ethereum-mive/mive/internal/telemetry"
+	"github.com/ethereum-mive/mive/mive"
+	"github.com/ethereum-mive/mive/mive/devnet"
+	"github.com/ethereum-mive/mive/mive/gasprice"
+	"github.com/ethereum-mive/mive/mive/miveconfig"
+	"github.com/ethereum-mive/mive/mive/txpool"
 	"github.com/ethereum-mive/mive/node"
+	miveparams "github.com/ethereum-mive/mive/params"
 )
 
 // These are all the command line flags we support.
@@ -57,9 +88,21 @@ var (
 		Usage:    "Root directory for ancient data (default = inside chaindata)",
 		Category: flags.EthCategory,
 	}
-	MinFreeDiskSpaceFlag = &flags.DirectoryFlag{
+	MinFreeDiskSpaceFlag = &cli.Uint64Flag{
 		Name:     "datadir.minfreedisk",
-		Usage:    "Minimum free disk space in MB, once reached triggers auto shut down (default = --cache.gc converted to MB, 0 = disabled)",
+		Usage:    "Minimum free disk space in MB, once reached triggers auto shut down (0 = disabled)",
+		Value:    miveconfig.Defaults.MinFreeDiskSpace,
+		Category: flags.EthCategory,
+	}
+	ShutdownTimeoutFlag = &cli.DurationFlag{
+		Name:     "shutdown.timeout",
+		Usage:    "Maximum time to wait for the node to shut down gracefully on interrupt before forcing the exit (0 = no limit)",
+		Value:    5 * time.Minute,
+		Category: flags.EthCategory,
+	}
+	ReadOnlyFlag = &cli.BoolFlag{
+		Name:     "readonly",
+		Usage:    "Open the chain database without write access and serve the existing data over RPC, without deriving the chain",
 		Category: flags.EthCategory,
 	}
 	KeyStoreDirFlag = &flags.DirectoryFlag{
@@ -90,27 +133,276 @@ var (
 	}
 	SepoliaFlag = &cli.BoolFlag{
 		Name:     "sepolia",
-		Usage:    "Sepolia network: pre-configured proof-of-work test network",
+		Usage:    "Sepolia network: Mive on the pre-configured proof-of-work test network",
 		Category: flags.EthCategory,
 	}
 	HoleskyFlag = &cli.BoolFlag{
 		Name:     "holesky",
-		Usage:    "Holesky network: pre-configured proof-of-stake test network",
+		Usage:    "Holesky network: Mive on the pre-configured proof-of-stake test network",
 		Category: flags.EthCategory,
 	}
 
+	// Dev mode
+	DeveloperFlag = &cli.BoolFlag{
+		Name:     "dev",
+		Usage:    "Ephemeral Mive chain derived from an in-process simulated L1 chain, with a pre-funded developer account relaying the transactions",
+		Category: flags.DevCategory,
+	}
+	DeveloperPeriodFlag = &cli.Uint64Flag{
+		Name:     "dev.period",
+		Usage:    "Block period of the simulated L1 chain in developer mode (0 = seal blocks only if transactions are pending)",
+		Category: flags.DevCategory,
+	}
+	DeveloperGasLimitFlag = &cli.Uint64Flag{
+		Name:     "dev.gaslimit",
+		Usage:    "Block gas limit of the simulated L1 chain in developer mode",
+		Value:    11500000,
+		Category: flags.DevCategory,
+	}
+
 	SnapshotFlag = &cli.BoolFlag{
 		Name:     "snapshot",
 		Usage:    `Enables snapshot-database mode (default = enable)`,
 		Value:    true,
 		Category: flags.EthCategory,
 	}
+	BloomFilterSizeFlag = &cli.Uint64Flag{
+		Name:     "bloomfilter.size",
+		Usage:    "Megabytes of memory allocated to bloom-filter for pruning",
+		Value:    2048,
+		Category: flags.EthCategory,
+	}
+	BloomBitsBlocksFlag = &cli.Uint64Flag{
+		Name:     "bloombits.blocks",
+		Usage:    "Number of blocks per section of the log filtering index, a multiple of 8 (changing it regenerates the index)",
+		Value:    miveconfig.Defaults.BloomBitsBlocks,
+		Category: flags.PerfCategory,
+	}
+	CacheLogSizeFlag = &cli.IntFlag{
+		Name:     "cache.blocklogs",
+		Usage:    "Size (in number of blocks) of the log cache for filtering",
+		Value:    miveconfig.Defaults.FilterLogCacheSize,
+		Category: flags.PerfCategory,
+	}
+	CacheTrieTimeLimitFlag = &cli.DurationFlag{
+		Name:     "cache.trie.timelimit",
+		Usage:    "Block processing time after which the in-memory tries are flushed to disk, only relevant in state.scheme=hash",
+		Value:    miveconfig.Defaults.TrieTimeout,
+		Category: flags.PerfCategory,
+	}
+	// The clean trie cache isn't journaled by the trie databases anymore, the
+	// journal flags are only accepted for compatibility with existing setups.
+	CacheTrieJournalFlag = &cli.StringFlag{
+		Name:     "cache.trie.journal",
+		Usage:    "Disk journal directory for trie cache to survive node restarts (no effect)",
+		Hidden:   true,
+		Category: flags.PerfCategory,
+	}
+	CacheTrieRejournalFlag = &cli.DurationFlag{
+		Name:     "cache.trie.rejournal",
+		Usage:    "Time interval to regenerate the trie cache journal (no effect)",
+		Hidden:   true,
+		Category: flags.PerfCategory,
+	}
+	GCModeFlag = &cli.StringFlag{
+		Name:     "gcmode",
+		Usage:    `Blockchain garbage collection mode, only relevant in state.scheme=hash ("full", "archive")`,
+		Value:    "full",
+		Category: flags.StateCategory,
+	}
+	StateSchemeFlag = &cli.StringFlag{
+		Name:     "state.scheme",
+		Usage:    "Scheme to use for storing Mive state ('hash' or 'path')",
+		Category: flags.StateCategory,
+	}
+	StateHistoryFlag = &cli.Uint64Flag{
+		Name:     "history.state",
+		Usage:    "Number of recent blocks to retain state history for, only relevant in state.scheme=path (default = 90,000 blocks, 0 = entire chain)",
+		Value:    miveconfig.Defaults.StateHistory,
+		Category: flags.StateCategory,
+	}
+	TxLookupLimitFlag = &cli.Uint64Flag{
+		Name:     "txlookuplimit",
+		Usage:    "Number of recent blocks to maintain transactions index for (default = about one year, 0 = entire chain)",
+		Value:    miveconfig.Defaults.TxLookupLimit,
+		Category: flags.StateCategory,
+	}
 	LightKDFFlag = &cli.BoolFlag{
 		Name:     "lightkdf",
 		Usage:    "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
 		Category: flags.AccountCategory,
 	}
 
+	SyncModeFlag = &cli.StringFlag{
+		Name:     "syncmode",
+		Usage:    `Blockchain sync mode ("full" or "checkpoint")`,
+		Value:    miveconfig.Defaults.SyncMode,
+		Category: flags.EthCategory,
+	}
+	CheckpointNumberFlag = &cli.Uint64Flag{
+		Name:     "checkpoint.number",
+		Usage:    "Number of the trusted Mive block to bootstrap the chain from in checkpoint sync mode",
+		Category: flags.EthCategory,
+	}
+	CheckpointRootFlag = &cli.StringFlag{
+		Name:     "checkpoint.root",
+		Usage:    "State root of the trusted Mive block to bootstrap the chain from in checkpoint sync mode",
+		Category: flags.EthCategory,
+	}
+	CheckpointURLFlag = &cli.StringFlag{
+		Name:     "checkpoint.url",
+		Usage:    "URL to retrieve the trusted checkpoint from, if not given with --checkpoint.number and --checkpoint.root",
+		Category: flags.EthCategory,
+	}
+	CheckpointArchiveFlag = &cli.StringFlag{
+		Name:     "checkpoint.archive",
+		Usage:    "File path or HTTP URL of the state snapshot archive taken at the checkpoint",
+		Category: flags.EthCategory,
+	}
+
+	// Mive settings
+	MiveEthFlag = &cli.StringFlag{
+		Name:     "mive.eth",
+		Aliases:  []string{"mive.ethrpc"},
+		Usage:    "Comma separated list of L1 endpoints the Mive chain is derived from",
+		Category: flags.MiveCategory,
+	}
+	MiveEthArchiveFlag = &cli.StringFlag{
+		Name:     "mive.eth.archive",
+		Usage:    "L1 archive endpoint used to retrieve historical blocks the primary endpoint can no longer serve",
+		Category: flags.MiveCategory,
+	}
+	MiveEthPolicyFlag = &cli.StringFlag{
+		Name:     "mive.eth.policy",
+		Usage:    "Distribution of L1 requests across the endpoints ('failover', 'score' or 'roundrobin')",
+		Value:    string(miveethclient.DefaultConfig.Policy),
+		Category: flags.MiveCategory,
+	}
+	MiveEthRateLimitFlag = &cli.Float64Flag{
+		Name:     "mive.eth.ratelimit",
+		Usage:    "Maximum number of requests per second sent to each L1 endpoint (0 = unlimited)",
+		Category: flags.MiveCategory,
+	}
+	MiveEthRetriesFlag = &cli.IntFlag{
+		Name:     "mive.eth.retries",
+		Usage:    "Number of times an L1 request failing with a transient error is retried",
+		Value:    miveethclient.DefaultConfig.MaxRetries,
+		Category: flags.MiveCategory,
+	}
+	MiveBeaconFlag = &cli.StringFlag{
+		Name:     "mive.beacon",
+		Usage:    "Beacon node REST endpoint the blobs of blob-carrying beacon transactions are retrieved from",
+		Category: flags.MiveCategory,
+	}
+	MiveBeaconAddressFlag = &cli.StringFlag{
+		Name:     "mive.beacon.address",
+		Usage:    "Initial beacon address of the Mive deployment to follow, overriding the network's one",
+		Category: flags.MiveCategory,
+	}
+	MiveGenesisBlockFlag = &cli.Uint64Flag{
+		Name:     "mive.genesisblock",
+		Usage:    "L1 block the Mive chain of the deployment to follow starts at, overriding the network's one",
+		Category: flags.MiveCategory,
+	}
+	MiveConfirmationsFlag = &cli.Uint64Flag{
+		Name:     "mive.confirmations",
+		Usage:    "Number of L1 blocks the derivation stays behind the L1 head",
+		Value:    miveconfig.Defaults.Confirmations,
+		Category: flags.MiveCategory,
+	}
+	MiveBadBlockReportFlag = &cli.StringFlag{
+		Name:     "mive.badblock.report",
+		Usage:    "URL the L1 blocks the derivation fails on are posted to as JSON",
+		Category: flags.MiveCategory,
+	}
+	MiveTxPoolFlag = &cli.BoolFlag{
+		Name:     "mive.txpool",
+		Usage:    "Observe the pending beacon transactions on L1 and serve them as pending Mive transactions",
+		Category: flags.MiveCategory,
+	}
+	MiveTxPoolLifetimeFlag = &cli.DurationFlag{
+		Name:     "mive.txpool.lifetime",
+		Usage:    "Maximum amount of time a pending Mive transaction is kept",
+		Value:    miveconfig.Defaults.TxPool.Lifetime,
+		Category: flags.MiveCategory,
+	}
+	MiveTxPoolTrustFlag = &cli.StringFlag{
+		Name:     "mive.txpool.trust",
+		Usage:    `Pending transactions included in the pending block ("none", "priced" or "all")`,
+		Value:    string(miveconfig.Defaults.TxPool.PendingTrust),
+		Category: flags.MiveCategory,
+	}
+	MiveRelayerFlag = &cli.StringFlag{
+		Name:     "mive.relayer",
+		Usage:    "Account wrapping the Mive transactions sent over RPC into beacon transactions (executed as this account)",
+		Category: flags.MiveCategory,
+	}
+	MiveRelayerFeeCapFlag = &cli.Uint64Flag{
+		Name:     "mive.relayer.feecap",
+		Usage:    "Maximum L1 fee cap per gas (gwei) of the relayed transactions, including replacements",
+		Value:    new(big.Int).Div(miveconfig.Defaults.RelayerFeeCap, big.NewInt(params.GWei)).Uint64(),
+		Category: flags.MiveCategory,
+	}
+	MiveRelayerBumpIntervalFlag = &cli.DurationFlag{
+		Name:     "mive.relayer.bumpinterval",
+		Usage:    "Time a relayed transaction may be pending before it is replaced with a better priced one",
+		Value:    miveconfig.Defaults.RelayerBumpInterval,
+		Category: flags.MiveCategory,
+	}
+	MiveProposerFlag = &cli.StringFlag{
+		Name:     "mive.proposer",
+		Usage:    "L1 contract the output roots of the Mive blocks are proposed to from the relayer account",
+		Category: flags.MiveCategory,
+	}
+	MiveProposerIntervalFlag = &cli.Uint64Flag{
+		Name:     "mive.proposer.interval",
+		Usage:    "Number of Mive blocks between two proposed output roots",
+		Value:    miveconfig.Defaults.ProposerInterval,
+		Category: flags.MiveCategory,
+	}
+	MiveHealthFlag = &cli.BoolFlag{
+		Name:     "mive.health",
+		Usage:    "Serve the /healthz and /readyz probes on the HTTP-RPC server",
+		Category: flags.MiveCategory,
+	}
+	MiveHealthMaxLagFlag = &cli.Uint64Flag{
+		Name:     "mive.health.maxlag",
+		Usage:    "Number of L1 blocks the Mive head may lag behind the confirmed L1 head for the node to be ready",
+		Value:    miveconfig.Defaults.HealthMaxLag,
+		Category: flags.MiveCategory,
+	}
+	MiveExternalDriverFlag = &cli.BoolFlag{
+		Name:     "mive.driver.external",
+		Usage:    "Let an external driver push the L1 blocks through the authenticated engine API instead of following L1",
+		Category: flags.MiveCategory,
+	}
+	MiveBadHashesFlag = &cli.StringFlag{
+		Name:     "mive.badhashes",
+		Usage:    "Comma separated list of Mive block hashes to reject as invalid",
+		Category: flags.MiveCategory,
+	}
+
+	OverrideCancun = &cli.Uint64Flag{
+		Name:     "override.cancun",
+		Usage:    "Manually specify the Cancun fork timestamp, overriding the bundled setting",
+		Category: flags.EthCategory,
+	}
+	OverrideVerkle = &cli.Uint64Flag{
+		Name:     "override.verkle",
+		Usage:    "Manually specify the Verkle fork timestamp, overriding the bundled setting",
+		Category: flags.EthCategory,
+	}
+	OverrideMiveM1 = &cli.Uint64Flag{
+		Name:     "override.mive.m1",
+		Usage:    "Manually specify the Mive M1 upgrade block number, overriding the bundled setting",
+		Category: flags.MiveCategory,
+	}
+	OverrideMiveM2 = &cli.Uint64Flag{
+		Name:     "override.mive.m2",
+		Usage:    "Manually specify the Mive M2 upgrade timestamp, overriding the bundled setting",
+		Category: flags.MiveCategory,
+	}
+
 	// Account settings
 	UnlockedAccountFlag = &cli.StringFlag{
 		Name:     "unlock",
@@ -136,6 +428,32 @@ var (
 		Category: flags.AccountCategory,
 	}
 
+	// Gas price oracle settings
+	GpoBlocksFlag = &cli.IntFlag{
+		Name:     "gpo.blocks",
+		Usage:    "Number of recent blocks to check for gas prices",
+		Value:    miveconfig.Defaults.GPO.Blocks,
+		Category: flags.GasPriceCategory,
+	}
+	GpoPercentileFlag = &cli.IntFlag{
+		Name:     "gpo.percentile",
+		Usage:    "Suggested gas price is the given percentile of a set of recent transaction gas prices",
+		Value:    miveconfig.Defaults.GPO.Percentile,
+		Category: flags.GasPriceCategory,
+	}
+	GpoMaxGasPriceFlag = &cli.Int64Flag{
+		Name:     "gpo.maxprice",
+		Usage:    "Maximum Mive transaction priority fee (reduced from L1) to be recommended by gpo",
+		Value:    miveconfig.Defaults.GPO.MaxPrice.Int64(),
+		Category: flags.GasPriceCategory,
+	}
+	GpoIgnoreGasPriceFlag = &cli.Int64Flag{
+		Name:     "gpo.ignoreprice",
+		Usage:    "Mive gas price below which gpo will ignore transactions",
+		Value:    miveconfig.Defaults.GPO.IgnorePrice.Int64(),
+		Category: flags.GasPriceCategory,
+	}
+
 	// EVM settings
 	VMEnableDebugFlag = &cli.BoolFlag{
 		Name:     "vmdebug",
@@ -156,6 +474,12 @@ var (
 		Value:    ethconfig.Defaults.RPCEVMTimeout,
 		Category: flags.APICategory,
 	}
+	RPCReexecFlag = &cli.Uint64Flag{
+		Name:     "rpc.reexec",
+		Usage:    "Sets the default number of blocks re-executed to regenerate the historical state needed by traces",
+		Value:    miveconfig.Defaults.RPCReexec,
+		Category: flags.APICategory,
+	}
 	RPCGlobalTxFeeCapFlag = &cli.Float64Flag{
 		Name:     "rpc.txfeecap",
 		Usage:    "Sets a cap on transaction fee (in ether) that can be sent via the RPC APIs (0 = no cap)",
@@ -163,6 +487,13 @@ var (
 		Category: flags.APICategory,
 	}
 
+	RPCLogsRangeLimitFlag = &cli.Uint64Flag{
+		Name:     "rpc.logsrange",
+		Usage:    "Sets a cap on the number of blocks a single eth_getLogs query may span (0 = no cap)",
+		Value:    miveconfig.Defaults.RPCLogsRangeLimit,
+		Category: flags.APICategory,
+	}
+
 	// Authenticated RPC HTTP settings
 	AuthListenFlag = &cli.StringFlag{
 		Name:     "authrpc.addr",
@@ -302,6 +633,18 @@ var (
 		Usage:    "Comma separated list of JavaScript files to preload into the console",
 		Category: flags.APICategory,
 	}
+	JSpathFlag = &flags.DirectoryFlag{
+		Name:     "jspath",
+		Usage:    "JavaScript root path for `loadScript`",
+		Value:    flags.DirectoryString("."),
+		Category: flags.APICategory,
+	}
+	HttpHeaderFlag = &cli.StringSliceFlag{
+		Name:     "header",
+		Aliases:  []string{"H"},
+		Usage:    "Pass custom headers to the RPC server when using the mive attach console. This flag can be given multiple times.",
+		Category: flags.APICategory,
+	}
 	AllowUnprotectedTxs = &cli.BoolFlag{
 		Name:     "rpc.allow-unprotected-txs",
 		Usage:    "Allow for unprotected (non EIP155 signed) transactions to be submitted via RPC",
@@ -319,6 +662,217 @@ var (
 		Value:    node.DefaultConfig.BatchResponseMaxSize,
 		Category: flags.APICategory,
 	}
+
+	// Network Settings
+	MaxPeersFlag = &cli.IntFlag{
+		Name:     "maxpeers",
+		Usage:    "Maximum number of network peers (network disabled if set to 0)",
+		Value:    node.DefaultConfig.P2P.MaxPeers,
+		Category: flags.NetworkingCategory,
+	}
+	ListenPortFlag = &cli.IntFlag{
+		Name:     "port",
+		Usage:    "Network listening port",
+		Value:    30403,
+		Category: flags.NetworkingCategory,
+	}
+	BootnodesFlag = &cli.StringFlag{
+		Name:     "bootnodes",
+		Usage:    "Comma separated enode URLs of the Mive nodes to bootstrap the P2P discovery from",
+		Value:    "",
+		Category: flags.NetworkingCategory,
+	}
+	NoDiscoverFlag = &cli.BoolFlag{
+		Name:     "nodiscover",
+		Usage:    "Disables the peer discovery mechanism (manual peer addition)",
+		Category: flags.NetworkingCategory,
+	}
+	DiscoveryV4Flag = &cli.BoolFlag{
+		Name:     "discovery.v4",
+		Usage:    "Enables the V4 discovery mechanism",
+		Value:    node.DefaultConfig.P2P.DiscoveryV4,
+		Category: flags.NetworkingCategory,
+	}
+	DiscoveryV5Flag = &cli.BoolFlag{
+		Name:     "discovery.v5",
+		Usage:    "Enables the V5 discovery mechanism, finding the Mive nodes by the chain they advertise",
+		Value:    node.DefaultConfig.P2P.DiscoveryV5,
+		Category: flags.NetworkingCategory,
+	}
+	DNSDiscoveryFlag = &cli.StringFlag{
+		Name:     "discovery.dns",
+		Usage:    "Comma separated DNS discovery lists (enrtree:// URLs) of Mive nodes",
+		Category: flags.NetworkingCategory,
+	}
+	NetrestrictFlag = &cli.StringFlag{
+		Name:     "netrestrict",
+		Usage:    "Restricts network communication to the given IP networks (CIDR masks)",
+		Category: flags.NetworkingCategory,
+	}
+
+	// Metrics flags
+	MetricsEnabledFlag = &cli.BoolFlag{
+		Name:     "metrics",
+		Usage:    "Enable metrics collection and reporting",
+		Category: flags.MetricsCategory,
+	}
+	MetricsEnabledExpensiveFlag = &cli.BoolFlag{
+		Name:     "metrics.expensive",
+		Usage:    "Enable expensive metrics collection and reporting",
+		Category: flags.MetricsCategory,
+	}
+
+	// MetricsHTTPFlag defines the endpoint for a stand-alone metrics HTTP endpoint.
+	// Since the pprof service enables sensitive/vulnerable behavior, this allows a user
+	// to enable a public-OK metrics endpoint without having to worry about ALSO exposing
+	// other profiling behavior or information.
+	MetricsHTTPFlag = &cli.StringFlag{
+		Name:     "metrics.addr",
+		Usage:    `Enable stand-alone metrics HTTP server listening interface, serving /debug/metrics (expvar) and /debug/metrics/prometheus`,
+		Category: flags.MetricsCategory,
+	}
+	MetricsPortFlag = &cli.IntFlag{
+		Name: "metrics.port",
+		Usage: `Metrics HTTP server listening port.
+Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.`,
+		Value:    metrics.DefaultConfig.Port,
+		Category: flags.MetricsCategory,
+	}
+	MetricsEnableInfluxDBFlag = &cli.BoolFlag{
+		Name:     "metrics.influxdb",
+		Usage:    "Enable metrics export/push to an external InfluxDB database",
+		Category: flags.MetricsCategory,
+	}
+	MetricsInfluxDBEndpointFlag = &cli.StringFlag{
+		Name:     "metrics.influxdb.endpoint",
+		Usage:    "InfluxDB API endpoint to report metrics to",
+		Value:    metrics.DefaultConfig.InfluxDBEndpoint,
+		Category: flags.MetricsCategory,
+	}
+	MetricsInfluxDBDatabaseFlag = &cli.StringFlag{
+		Name:     "metrics.influxdb.database",
+		Usage:    "InfluxDB database name to push reported metrics to",
+		Value:    "mive",
+		Category: flags.MetricsCategory,
+	}
+	MetricsInfluxDBUsernameFlag = &cli.StringFlag{
+		Name:     "metrics.influxdb.username",
+		Usage:    "Username to authorize access to the database",
+		Value:    metrics.DefaultConfig.InfluxDBUsername,
+		Category: flags.MetricsCategory,
+	}
+	MetricsInfluxDBPasswordFlag = &cli.StringFlag{
+		Name:     "metrics.influxdb.password",
+		Usage:    "Password to authorize access to the database",
+		Value:    metrics.DefaultConfig.InfluxDBPassword,
+		Category: flags.MetricsCategory,
+	}
+	// Tags are part of every measurement sent to InfluxDB. Queries on tags are faster in InfluxDB.
+	// For example `host` tag could be used so that we can group all nodes and average a measurement
+	// across all of them, but also so that we can select a specific node and inspect its measurements.
+	// https://docs.influxdata.com/influxdb/v1.4/concepts/key_concepts/#tag-key
+	MetricsInfluxDBTagsFlag = &cli.StringFlag{
+		Name:     "metrics.influxdb.tags",
+		Usage:    "Comma-separated InfluxDB tags (key/values) attached to all measurements",
+		Value:    "host=localhost",
+		Category: flags.MetricsCategory,
+	}
+	MetricsEnableInfluxDBV2Flag = &cli.BoolFlag{
+		Name:     "metrics.influxdbv2",
+		Usage:    "Enable metrics export/push to an external InfluxDB v2 database",
+		Category: flags.MetricsCategory,
+	}
+	MetricsInfluxDBTokenFlag = &cli.StringFlag{
+		Name:     "metrics.influxdb.token",
+		Usage:    "Token to authorize access to the database (v2 only)",
+		Value:    metrics.DefaultConfig.InfluxDBToken,
+		Category: flags.MetricsCategory,
+	}
+	MetricsInfluxDBBucketFlag = &cli.StringFlag{
+		Name:     "metrics.influxdb.bucket",
+		Usage:    "InfluxDB bucket name to push reported metrics to (v2 only)",
+		Value:    "mive",
+		Category: flags.MetricsCategory,
+	}
+	MetricsInfluxDBOrganizationFlag = &cli.StringFlag{
+		Name:     "metrics.influxdb.organization",
+		Usage:    "InfluxDB organization name (v2 only)",
+		Value:    "mive",
+		Category: flags.MetricsCategory,
+	}
+
+	// Tracing flags
+	TracingEnabledFlag = &cli.BoolFlag{
+		Name:     "otel",
+		Usage:    "Enable the export of OpenTelemetry traces of the derivation and of the RPC requests",
+		Category: flags.MetricsCategory,
+	}
+	TracingEndpointFlag = &cli.StringFlag{
+		Name:     "otel.endpoint",
+		Usage:    "URL of the OTLP/HTTP collector the traces are exported to",
+		Value:    "http://localhost:4318",
+		Category: flags.MetricsCategory,
+	}
+	TracingSampleRateFlag = &cli.Float64Flag{
+		Name:     "otel.samplerate",
+		Usage:    "Fraction of the traces exported, between 0 and 1",
+		Value:    1,
+		Category: flags.MetricsCategory,
+	}
+)
+
+var (
+	// NetworkFlags is the flag group of all built-in supported networks.
+	NetworkFlags = []cli.Flag{
+		MainnetFlag,
+		GoerliFlag,
+		SepoliaFlag,
+		HoleskyFlag,
+	}
+
+	// NetworkingFlags is the flag group of all P2P networking flags.
+	NetworkingFlags = []cli.Flag{
+		MaxPeersFlag,
+		ListenPortFlag,
+		BootnodesFlag,
+		NoDiscoverFlag,
+		DiscoveryV4Flag,
+		DiscoveryV5Flag,
+		DNSDiscoveryFlag,
+		NetrestrictFlag,
+	}
+
+	// MetricsFlags is the flag group of all metrics flags.
+	MetricsFlags = []cli.Flag{
+		MetricsEnabledFlag,
+		MetricsEnabledExpensiveFlag,
+		MetricsHTTPFlag,
+		MetricsPortFlag,
+		MetricsEnableInfluxDBFlag,
+		MetricsInfluxDBEndpointFlag,
+		MetricsInfluxDBDatabaseFlag,
+		MetricsInfluxDBUsernameFlag,
+		MetricsInfluxDBPasswordFlag,
+		MetricsInfluxDBTagsFlag,
+		MetricsEnableInfluxDBV2Flag,
+		MetricsInfluxDBTokenFlag,
+		MetricsInfluxDBBucketFlag,
+		MetricsInfluxDBOrganizationFlag,
+	}
+
+	// TracingFlags is the flag group of all tracing flags.
+	TracingFlags = []cli.Flag{
+		TracingEnabledFlag,
+		TracingEndpointFlag,
+		TracingSampleRateFlag,
+	}
+
+	// DatabaseFlags is the flag group of all database flags.
+	DatabaseFlags = []cli.Flag{
+		DataDirFlag,
+		AncientFlag,
+		DBEngineFlag,
+	}
 )
 
 // setHTTP creates the HTTP RPC listener interface string from the set
@@ -424,8 +978,76 @@ func setIPC(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// setBootstrapNodes creates a list of bootstrap nodes from the command line
+// flags, reverting to the main network ones if none have been specified. The
+// same nodes bootstrap both discovery versions.
+func setBootstrapNodes(ctx *cli.Context, cfg *p2p.Config) {
+	urls := miveparams.MainnetBootnodes
+	switch {
+	case ctx.IsSet(BootnodesFlag.Name):
+		urls = utils.SplitAndTrim(ctx.String(BootnodesFlag.Name))
+	case cfg.BootstrapNodes != nil:
+		return // Already set by config file, don't apply defaults.
+	case ctx.Bool(SepoliaFlag.Name):
+		urls = miveparams.SepoliaBootnodes
+	case ctx.Bool(HoleskyFlag.Name):
+		urls = miveparams.HoleskyBootnodes
+	}
+	cfg.BootstrapNodes = make([]*enode.Node, 0, len(urls))
+	for _, url := range urls {
+		node, err := enode.Parse(enode.ValidSchemes, url)
+		if err != nil {
+			utils.Fatalf("Bootstrap URL invalid: %s: %v", url, err)
+		}
+		cfg.BootstrapNodes = append(cfg.BootstrapNodes, node)
+	}
+	cfg.BootstrapNodesV5 = cfg.BootstrapNodes
+}
+
+// SetP2PConfig applies the P2P networking command line flags to the config.
+func SetP2PConfig(ctx *cli.Context, cfg *p2p.Config) {
+	setBootstrapNodes(ctx, cfg)
+
+	if ctx.IsSet(ListenPortFlag.Name) {
+		cfg.ListenAddr = fmt.Sprintf(":%d", ctx.Int(ListenPortFlag.Name))
+	}
+	if ctx.IsSet(MaxPeersFlag.Name) {
+		cfg.MaxPeers = ctx.Int(MaxPeersFlag.Name)
+	}
+	utils.CheckExclusive(ctx, DiscoveryV4Flag, NoDiscoverFlag)
+	utils.CheckExclusive(ctx, DiscoveryV5Flag, NoDiscoverFlag)
+	if ctx.IsSet(DiscoveryV4Flag.Name) {
+		cfg.DiscoveryV4 = ctx.Bool(DiscoveryV4Flag.Name)
+	}
+	if ctx.IsSet(DiscoveryV5Flag.Name) {
+		cfg.DiscoveryV5 = ctx.Bool(DiscoveryV5Flag.Name)
+	}
+	if ctx.Bool(NoDiscoverFlag.Name) {
+		cfg.NoDiscovery = true
+		cfg.DiscoveryV4 = false
+		cfg.DiscoveryV5 = false
+	}
+	if ctx.Bool(DeveloperFlag.Name) {
+		// --dev mode can't use p2p networking.
+		cfg.MaxPeers = 0
+		cfg.ListenAddr = ""
+		cfg.NoDial = true
+		cfg.NoDiscovery = true
+		cfg.DiscoveryV4 = false
+		cfg.DiscoveryV5 = false
+	}
+	if netrestrict := ctx.String(NetrestrictFlag.Name); netrestrict != "" {
+		list, err := netutil.ParseNetlist(netrestrict)
+		if err != nil {
+			utils.Fatalf("Option %q: %v", NetrestrictFlag.Name, err)
+		}
+		cfg.NetRestrict = list
+	}
+}
+
 // SetNodeConfig applies node-related command line flags to the config.
 func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
+	SetP2PConfig(ctx, &cfg.P2P)
 	setIPC(ctx, cfg)
 	setHTTP(ctx, cfg)
 	setGraphQL(ctx, cfg)
@@ -443,6 +1065,9 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	if ctx.IsSet(KeyStoreDirFlag.Name) {
 		cfg.KeyStoreDir = ctx.String(KeyStoreDirFlag.Name)
 	}
+	if ctx.IsSet(DeveloperFlag.Name) {
+		cfg.UseLightweightKDF = true
+	}
 	if ctx.IsSet(LightKDFFlag.Name) {
 		cfg.UseLightweightKDF = ctx.Bool(LightKDFFlag.Name)
 	}
@@ -462,10 +1087,409 @@ func SetNodeConfig(ctx *cli.Context, cfg *node.Config) {
 	}
 }
 
+// MakePasswordList reads password lines from the file specified by the global --password flag.
+func MakePasswordList(ctx *cli.Context) []string {
+	path := ctx.Path(PasswordFileFlag.Name)
+	if path == "" {
+		return nil
+	}
+	text, err := os.ReadFile(path)
+	if err != nil {
+		utils.Fatalf("Failed to read password file: %v", err)
+	}
+	lines := strings.Split(string(text), "\n")
+	// Sanitise DOS line endings.
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], "\r")
+	}
+	return lines
+}
+
+func setGPO(ctx *cli.Context, cfg *gasprice.Config) {
+	if ctx.IsSet(GpoBlocksFlag.Name) {
+		cfg.Blocks = ctx.Int(GpoBlocksFlag.Name)
+	}
+	if ctx.IsSet(GpoPercentileFlag.Name) {
+		cfg.Percentile = ctx.Int(GpoPercentileFlag.Name)
+	}
+	if ctx.IsSet(GpoMaxGasPriceFlag.Name) {
+		cfg.MaxPrice = big.NewInt(ctx.Int64(GpoMaxGasPriceFlag.Name))
+	}
+	if ctx.IsSet(GpoIgnoreGasPriceFlag.Name) {
+		cfg.IgnorePrice = big.NewInt(ctx.Int64(GpoIgnoreGasPriceFlag.Name))
+	}
+}
+
+// SetMiveConfig applies mive-related command line flags to the config.
+func SetMiveConfig(ctx *cli.Context, cfg *miveconfig.Config) {
+	// Avoid conflicting network flags
+	utils.CheckExclusive(ctx, MainnetFlag, DeveloperFlag, GoerliFlag, SepoliaFlag, HoleskyFlag)
+	utils.CheckExclusive(ctx, DeveloperFlag, ExternalSignerFlag) // Can't use both ephemeral unlocked and external signer
+	utils.CheckExclusive(ctx, DeveloperFlag, MiveEthFlag)        // The simulated L1 chain is the only L1 endpoint
+
+	setGPO(ctx, &cfg.GPO)
+	cfg.DatabaseHandles = utils.MakeDatabaseHandles(0)
+	if !ctx.Bool(SnapshotFlag.Name) {
+		cfg.SnapshotCache = 0 // Disabled
+	}
+	if gcmode := ctx.String(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
+		utils.Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
+	}
+	if ctx.IsSet(GCModeFlag.Name) {
+		cfg.NoPruning = ctx.String(GCModeFlag.Name) == "archive"
+	}
+	if ctx.IsSet(ReadOnlyFlag.Name) {
+		cfg.ReadOnly = ctx.Bool(ReadOnlyFlag.Name)
+	}
+	if ctx.IsSet(MinFreeDiskSpaceFlag.Name) {
+		cfg.MinFreeDiskSpace = ctx.Uint64(MinFreeDiskSpaceFlag.Name)
+	}
+	if ctx.IsSet(CacheTrieTimeLimitFlag.Name) {
+		cfg.TrieTimeout = ctx.Duration(CacheTrieTimeLimitFlag.Name)
+	}
+	for _, name := range []string{CacheTrieJournalFlag.Name, CacheTrieRejournalFlag.Name} {
+		if ctx.IsSet(name) {
+			log.Warn("The trie cache is not journaled, ignoring flag", "flag", name)
+		}
+	}
+	if cfg.NoPruning && !cfg.Preimages {
+		cfg.Preimages = true
+		log.Info("Enabling recording of key preimages since archive mode is used")
+	}
+	if ctx.IsSet(TxLookupLimitFlag.Name) {
+		cfg.TxLookupLimit = ctx.Uint64(TxLookupLimitFlag.Name)
+	}
+	if ctx.String(GCModeFlag.Name) == "archive" && cfg.TxLookupLimit != 0 {
+		cfg.TxLookupLimit = 0
+		log.Warn("Disabled transaction unindexing for archive node")
+	}
+	if ctx.IsSet(StateSchemeFlag.Name) {
+		cfg.StateScheme = ctx.String(StateSchemeFlag.Name)
+	}
+	if ctx.IsSet(StateHistoryFlag.Name) {
+		cfg.StateHistory = ctx.Uint64(StateHistoryFlag.Name)
+	}
+	if ctx.IsSet(SyncModeFlag.Name) {
+		cfg.SyncMode = ctx.String(SyncModeFlag.Name)
+	}
+	if cfg.SyncMode != "full" && cfg.SyncMode != "checkpoint" {
+		utils.Fatalf("--%s must be either 'full' or 'checkpoint'", SyncModeFlag.Name)
+	}
+	if ctx.IsSet(CheckpointNumberFlag.Name) {
+		cfg.CheckpointNumber = ctx.Uint64(CheckpointNumberFlag.Name)
+	}
+	if ctx.IsSet(CheckpointRootFlag.Name) {
+		if err := cfg.CheckpointRoot.UnmarshalText([]byte(ctx.String(CheckpointRootFlag.Name))); err != nil {
+			utils.Fatalf("Invalid checkpoint root: %v", err)
+		}
+	}
+	if ctx.IsSet(CheckpointURLFlag.Name) {
+		cfg.CheckpointURL = ctx.String(CheckpointURLFlag.Name)
+	}
+	if ctx.IsSet(CheckpointArchiveFlag.Name) {
+		cfg.CheckpointArchive = ctx.String(CheckpointArchiveFlag.Name)
+	}
+	if ctx.IsSet(MiveEthFlag.Name) {
+		cfg.EthRpcUrls = utils.SplitAndTrim(ctx.String(MiveEthFlag.Name))
+	}
+	if ctx.IsSet(MiveEthArchiveFlag.Name) {
+		cfg.EthArchiveRpcUrl = ctx.String(MiveEthArchiveFlag.Name)
+	}
+	if ctx.IsSet(MiveEthPolicyFlag.Name) {
+		cfg.EthRpcPolicy = ctx.String(MiveEthPolicyFlag.Name)
+	}
+	if ctx.IsSet(MiveEthRateLimitFlag.Name) {
+		cfg.EthRpcRateLimit = ctx.Float64(MiveEthRateLimitFlag.Name)
+	}
+	if ctx.IsSet(MiveEthRetriesFlag.Name) {
+		cfg.EthRpcMaxRetries = ctx.Int(MiveEthRetriesFlag.Name)
+	}
+	if ctx.IsSet(MiveBeaconFlag.Name) {
+		cfg.BeaconRpcUrl = ctx.String(MiveBeaconFlag.Name)
+	}
+	switch {
+	case ctx.Bool(SepoliaFlag.Name):
+		cfg.Genesis = mivecore.DefaultSepoliaGenesisBlock()
+	case ctx.Bool(HoleskyFlag.Name):
+		cfg.Genesis = mivecore.DefaultHoleskyGenesisBlock()
+	}
+	if ctx.IsSet(OverrideCancun.Name) {
+		v := ctx.Uint64(OverrideCancun.Name)
+		cfg.OverrideCancun = &v
+	}
+	if ctx.IsSet(OverrideVerkle.Name) {
+		v := ctx.Uint64(OverrideVerkle.Name)
+		cfg.OverrideVerkle = &v
+	}
+	if ctx.IsSet(OverrideMiveM1.Name) {
+		v := ctx.Uint64(OverrideMiveM1.Name)
+		cfg.OverrideMiveM1 = &v
+	}
+	if ctx.IsSet(OverrideMiveM2.Name) {
+		v := ctx.Uint64(OverrideMiveM2.Name)
+		cfg.OverrideMiveM2 = &v
+	}
+	if ctx.IsSet(MiveBeaconAddressFlag.Name) {
+		addr := ctx.String(MiveBeaconAddressFlag.Name)
+		if !common.IsHexAddress(addr) {
+			utils.Fatalf("Invalid beacon address: %s", addr)
+		}
+		beacon := common.HexToAddress(addr)
+		cfg.BeaconAddress = &beacon
+	}
+	if ctx.IsSet(MiveGenesisBlockFlag.Name) {
+		number := ctx.Uint64(MiveGenesisBlockFlag.Name)
+		cfg.GenesisBlock = &number
+	}
+	if ctx.IsSet(MiveConfirmationsFlag.Name) {
+		cfg.Confirmations = ctx.Uint64(MiveConfirmationsFlag.Name)
+	}
+	if ctx.IsSet(MiveBadBlockReportFlag.Name) {
+		cfg.BadBlockReportURL = ctx.String(MiveBadBlockReportFlag.Name)
+	}
+	if ctx.IsSet(MiveTxPoolFlag.Name) {
+		cfg.ObserveTxPool = ctx.Bool(MiveTxPoolFlag.Name)
+	}
+	if ctx.IsSet(MiveTxPoolLifetimeFlag.Name) {
+		cfg.TxPool.Lifetime = ctx.Duration(MiveTxPoolLifetimeFlag.Name)
+	}
+	if ctx.IsSet(MiveTxPoolTrustFlag.Name) {
+		cfg.TxPool.PendingTrust = txpool.Trust(ctx.String(MiveTxPoolTrustFlag.Name))
+	}
+	if ctx.IsSet(MiveRelayerFlag.Name) {
+		addr := ctx.String(MiveRelayerFlag.Name)
+		if !common.IsHexAddress(addr) {
+			utils.Fatalf("Invalid relayer account: %s", addr)
+		}
+		cfg.Relayer = common.HexToAddress(addr)
+	}
+	if ctx.IsSet(MiveRelayerFeeCapFlag.Name) {
+		cfg.RelayerFeeCap = new(big.Int).Mul(new(big.Int).SetUint64(ctx.Uint64(MiveRelayerFeeCapFlag.Name)), big.NewInt(params.GWei))
+	}
+	if ctx.IsSet(MiveRelayerBumpIntervalFlag.Name) {
+		cfg.RelayerBumpInterval = ctx.Duration(MiveRelayerBumpIntervalFlag.Name)
+	}
+	if ctx.IsSet(MiveProposerFlag.Name) {
+		addr := ctx.String(MiveProposerFlag.Name)
+		if !common.IsHexAddress(addr) {
+			utils.Fatalf("Invalid proposer contract: %s", addr)
+		}
+		cfg.Proposer = common.HexToAddress(addr)
+	}
+	if ctx.IsSet(MiveProposerIntervalFlag.Name) {
+		cfg.ProposerInterval = ctx.Uint64(MiveProposerIntervalFlag.Name)
+	}
+	if ctx.IsSet(MiveHealthFlag.Name) {
+		cfg.HealthEndpoints = ctx.Bool(MiveHealthFlag.Name)
+	}
+	if ctx.IsSet(MiveHealthMaxLagFlag.Name) {
+		cfg.HealthMaxLag = ctx.Uint64(MiveHealthMaxLagFlag.Name)
+	}
+	if ctx.IsSet(MiveExternalDriverFlag.Name) {
+		cfg.ExternalDriver = ctx.Bool(MiveExternalDriverFlag.Name)
+	}
+	if ctx.IsSet(MiveBadHashesFlag.Name) {
+		for _, hash := range utils.SplitAndTrim(ctx.String(MiveBadHashesFlag.Name)) {
+			if len(hash) != 2*common.HashLength+2 || !strings.HasPrefix(hash, "0x") {
+				utils.Fatalf("Invalid bad block hash: %s", hash)
+			}
+			cfg.BadHashes = append(cfg.BadHashes, common.HexToHash(hash))
+		}
+	}
+	if ctx.IsSet(DNSDiscoveryFlag.Name) {
+		if urls := ctx.String(DNSDiscoveryFlag.Name); urls == "" {
+			cfg.DiscoveryURLs = []string{}
+		} else {
+			cfg.DiscoveryURLs = utils.SplitAndTrim(urls)
+		}
+	}
+	if ctx.IsSet(BloomBitsBlocksFlag.Name) {
+		cfg.BloomBitsBlocks = ctx.Uint64(BloomBitsBlocksFlag.Name)
+	}
+	if cfg.BloomBitsBlocks == 0 || cfg.BloomBitsBlocks%8 != 0 {
+		utils.Fatalf("--%s must be a non-zero multiple of 8", BloomBitsBlocksFlag.Name)
+	}
+	if ctx.IsSet(CacheLogSizeFlag.Name) {
+		cfg.FilterLogCacheSize = ctx.Int(CacheLogSizeFlag.Name)
+	}
+	if ctx.IsSet(RPCGlobalGasCapFlag.Name) {
+		cfg.RPCGasCap = ctx.Uint64(RPCGlobalGasCapFlag.Name)
+	}
+	if cfg.RPCGasCap != 0 {
+		log.Info("Set global gas cap", "cap", cfg.RPCGasCap)
+	} else {
+		log.Info("Global gas cap disabled")
+	}
+	if ctx.IsSet(RPCGlobalEVMTimeoutFlag.Name) {
+		cfg.RPCEVMTimeout = ctx.Duration(RPCGlobalEVMTimeoutFlag.Name)
+	}
+	if ctx.IsSet(RPCReexecFlag.Name) {
+		cfg.RPCReexec = ctx.Uint64(RPCReexecFlag.Name)
+	}
+	if ctx.IsSet(RPCGlobalTxFeeCapFlag.Name) {
+		cfg.RPCTxFeeCap = ctx.Float64(RPCGlobalTxFeeCapFlag.Name)
+	}
+	if ctx.IsSet(RPCLogsRangeLimitFlag.Name) {
+		cfg.RPCLogsRangeLimit = ctx.Uint64(RPCLogsRangeLimitFlag.Name)
+	}
+}
+
+// RegisterDeveloperL1 starts the simulated L1 chain of the developer mode, to be
+// stopped along with the stack, and configures the Mive service to derive from
+// it. The developer account is reused from the keystore or created, unlocked,
+// funded on both chains and relays the Mive transactions submitted over RPC.
+func RegisterDeveloperL1(ctx *cli.Context, stack *node.Node, cfg *miveconfig.Config) {
+	var passphrase string
+	if list := MakePasswordList(ctx); len(list) > 0 {
+		passphrase = list[0]
+	}
+	// Unlock the developer account by local keystore.
+	var ks *keystore.KeyStore
+	if keystores := stack.AccountManager().Backends(keystore.KeyStoreType); len(keystores) > 0 {
+		ks = keystores[0].(*keystore.KeyStore)
+	}
+	if ks == nil {
+		utils.Fatalf("Keystore is not available")
+	}
+	// Figure out the dev account address, the configured relayer if any.
+	var (
+		developer accounts.Account
+		err       error
+	)
+	if cfg.Relayer != (common.Address{}) {
+		developer = accounts.Account{Address: cfg.Relayer}
+	} else if accs := ks.Accounts(); len(accs) > 0 {
+		developer = accs[0]
+	} else {
+		developer, err = ks.NewAccount(passphrase)
+		if err != nil {
+			utils.Fatalf("Failed to create developer account: %v", err)
+		}
+	}
+	if err := ks.Unlock(developer, passphrase); err != nil {
+		utils.Fatalf("Failed to unlock developer account: %v", err)
+	}
+	log.Info("Using developer account", "address", developer.Address)
+
+	l1, err := devnet.NewL1(devnet.Config{
+		DataDir:   stack.ResolvePath("l1"),
+		Developer: developer.Address,
+		Period:    ctx.Uint64(DeveloperPeriodFlag.Name),
+		GasLimit:  ctx.Uint64(DeveloperGasLimitFlag.Name),
+	})
+	if err != nil {
+		utils.Fatalf("Failed to start the simulated L1 chain: %v", err)
+	}
+	stack.RegisterLifecycle(l1)
+	log.Info("Started simulated L1 chain", "endpoint", l1.Endpoint(), "beacon", miveparams.DefaultBeaconAddress, "contract", devnet.BeaconContract)
+
+	cfg.Genesis = devnet.Genesis(developer.Address)
+	cfg.EthRpcUrls = []string{l1.Endpoint()}
+	cfg.Relayer = developer.Address
+	cfg.FollowInterval = time.Second
+}
+
+// RegisterMiveService adds a Mive client to the stack, deriving the Mive chain
+// from L1 and serving it over RPC and to the `mive` peers.
+func RegisterMiveService(stack *node.Node, cfg *miveconfig.Config) (miveapi.Backend, *mive.Mive) {
+	backend, err := mive.New(stack, cfg)
+	if err != nil {
+		utils.Fatalf("Failed to register the Mive service: %v", err)
+	}
+	return backend.APIBackend, backend
+}
+
+// SetupMetrics starts the exporters of the collected metrics requested on the
+// command line: the push to InfluxDB and the stand-alone HTTP endpoint serving
+// them in the expvar and Prometheus formats.
+func SetupMetrics(ctx *cli.Context) {
+	if !metrics.Enabled {
+		return
+	}
+	log.Info("Enabling metrics collection")
+
+	var (
+		enableExport   = ctx.Bool(MetricsEnableInfluxDBFlag.Name)
+		enableExportV2 = ctx.Bool(MetricsEnableInfluxDBV2Flag.Name)
+	)
+	if enableExport || enableExportV2 {
+		utils.CheckExclusive(ctx, MetricsEnableInfluxDBFlag, MetricsEnableInfluxDBV2Flag)
+
+		v1FlagIsSet := ctx.IsSet(MetricsInfluxDBUsernameFlag.Name) ||
+			ctx.IsSet(MetricsInfluxDBPasswordFlag.Name)
+
+		v2FlagIsSet := ctx.IsSet(MetricsInfluxDBTokenFlag.Name) ||
+			ctx.IsSet(MetricsInfluxDBOrganizationFlag.Name) ||
+			ctx.IsSet(MetricsInfluxDBBucketFlag.Name)
+
+		if enableExport && v2FlagIsSet {
+			utils.Fatalf("Flags --%s, --%s, --%s are only available for influxdb-v2", MetricsInfluxDBOrganizationFlag.Name, MetricsInfluxDBTokenFlag.Name, MetricsInfluxDBBucketFlag.Name)
+		} else if enableExportV2 && v1FlagIsSet {
+			utils.Fatalf("Flags --%s, --%s are only available for influxdb-v1", MetricsInfluxDBUsernameFlag.Name, MetricsInfluxDBPasswordFlag.Name)
+		}
+	}
+	var (
+		endpoint = ctx.String(MetricsInfluxDBEndpointFlag.Name)
+		tags     = utils.SplitTagsFlag(ctx.String(MetricsInfluxDBTagsFlag.Name))
+	)
+	if enableExport {
+		var (
+			database = ctx.String(MetricsInfluxDBDatabaseFlag.Name)
+			username = ctx.String(MetricsInfluxDBUsernameFlag.Name)
+			password = ctx.String(MetricsInfluxDBPasswordFlag.Name)
+		)
+		log.Info("Enabling metrics export to InfluxDB")
+		go influxdb.InfluxDBWithTags(metrics.DefaultRegistry, 10*time.Second, endpoint, database, username, password, "mive.", tags)
+	} else if enableExportV2 {
+		var (
+			token        = ctx.String(MetricsInfluxDBTokenFlag.Name)
+			bucket       = ctx.String(MetricsInfluxDBBucketFlag.Name)
+			organization = ctx.String(MetricsInfluxDBOrganizationFlag.Name)
+		)
+		log.Info("Enabling metrics export to InfluxDB (v2)")
+		go influxdb.InfluxDBV2WithTags(metrics.DefaultRegistry, 10*time.Second, endpoint, token, bucket, organization, "mive.", tags)
+	}
+	if ctx.IsSet(MetricsHTTPFlag.Name) {
+		address := net.JoinHostPort(ctx.String(MetricsHTTPFlag.Name), fmt.Sprintf("%d", ctx.Int(MetricsPortFlag.Name)))
+		log.Info("Enabling stand-alone metrics HTTP endpoint", "address", address)
+		exp.Setup(address)
+	} else if ctx.IsSet(MetricsPortFlag.Name) {
+		log.Warn(fmt.Sprintf("--%s specified without --%s, metrics server will not start.", MetricsPortFlag.Name, MetricsHTTPFlag.Name))
+	}
+}
+
+// SetupTracing starts exporting the OpenTelemetry traces if requested on the
+// command line. The pending spans are flushed when the node stops.
+func SetupTracing(ctx *cli.Context, stack *node.Node) {
+	if !ctx.Bool(TracingEnabledFlag.Name) {
+		return
+	}
+	var (
+		endpoint = ctx.String(TracingEndpointFlag.Name)
+		rate     = ctx.Float64(TracingSampleRateFlag.Name)
+	)
+	if rate < 0 || rate > 1 {
+		utils.Fatalf("Invalid --%s %v, must be between 0 and 1", TracingSampleRateFlag.Name, rate)
+	}
+	exporter, err := telemetry.Setup(telemetry.Config{
+		Endpoint:   endpoint,
+		SampleRate: rate,
+		Service:    stack.Config().Name,
+		Version:    stack.Config().Version,
+	})
+	if err != nil {
+		utils.Fatalf("Failed to set up tracing: %v", err)
+	}
+	stack.RegisterLifecycle(exporter)
+	log.Info("Enabling OpenTelemetry trace export", "endpoint", endpoint, "samplerate", rate)
+}
+
 func SetDataDir(ctx *cli.Context, cfg *node.Config) {
 	switch {
 	case ctx.IsSet(DataDirFlag.Name):
 		cfg.DataDir = ctx.String(DataDirFlag.Name)
+	case ctx.Bool(DeveloperFlag.Name):
+		cfg.DataDir = "" // unless explicitly requested, use memory databases
 	case ctx.Bool(GoerliFlag.Name) && cfg.DataDir == node.DefaultDataDir():
 		cfg.DataDir = filepath.Join(node.DefaultDataDir(), "goerli")
 	case ctx.Bool(SepoliaFlag.Name) && cfg.DataDir == node.DefaultDataDir():
@@ -474,3 +1498,93 @@ func SetDataDir(ctx *cli.Context, cfg *node.Config) {
 		cfg.DataDir = filepath.Join(node.DefaultDataDir(), "holesky")
 	}
 }
+
+// MakeChainDatabase opens the database of the Mive chain.
+func MakeChainDatabase(ctx *cli.Context, stack *node.Node, readonly bool) ethdb.Database {
+	var (
+		cache   = miveconfig.Defaults.DatabaseCache
+		handles = utils.MakeDatabaseHandles(0)
+	)
+	chainDb, err := stack.OpenDatabaseWithFreezer("chaindata", cache, handles, ctx.String(AncientFlag.Name), "", readonly)
+	if err != nil {
+		utils.Fatalf("Could not open database: %v", err)
+	}
+	return chainDb
+}
+
+// MakeChain creates a chain manager from set command line flags. No L1 endpoint
+// is available to the chain, the database is expected to be initialized.
+func MakeChain(ctx *cli.Context, stack *node.Node, readonly bool) (*mivecore.BlockChain, ethdb.Database) {
+	chainDb := MakeChainDatabase(ctx, stack, readonly)
+
+	if gcmode := ctx.String(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
+		utils.Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
+	}
+	scheme, err := rawdb.ParseStateScheme(ctx.String(StateSchemeFlag.Name), chainDb)
+	if err != nil {
+		utils.Fatalf("%v", err)
+	}
+	cache := core.DefaultCacheConfigWithScheme(scheme)
+	cache.TrieDirtyDisabled = ctx.String(GCModeFlag.Name) == "archive"
+	cache.StateHistory = ctx.Uint64(StateHistoryFlag.Name)
+	if !ctx.Bool(SnapshotFlag.Name) {
+		cache.SnapshotLimit = 0 // Disabled
+	}
+	// Disable the transaction indexer/unindexer in read-only mode, and unless
+	// requested, leave the existing indices as they are.
+	var limit *uint64
+	if ctx.IsSet(TxLookupLimitFlag.Name) && !readonly {
+		l := ctx.Uint64(TxLookupLimitFlag.Name)
+		limit = &l
+	}
+	var chain *mivecore.BlockChain
+	if readonly {
+		chain, err = mivecore.NewReadOnlyBlockChain(chainDb, cache, nil, vm.Config{}, nil)
+	} else {
+		chain, err = mivecore.NewBlockChain(chainDb, cache, nil, nil, nil, vm.Config{}, nil, limit)
+	}
+	if err != nil {
+		utils.Fatalf("Can't create BlockChain: %v", err)
+	}
+	return chain, chainDb
+}
+
+// MakeEthClient dials the L1 endpoints set on the command line.
+func MakeEthClient(ctx *cli.Context) *miveethclient.Client {
+	config := miveethclient.DefaultConfig
+	config.URLs = utils.SplitAndTrim(ctx.String(MiveEthFlag.Name))
+	config.ArchiveURL = ctx.String(MiveEthArchiveFlag.Name)
+	config.Policy = miveethclient.Policy(ctx.String(MiveEthPolicyFlag.Name))
+	config.RateLimit = ctx.Float64(MiveEthRateLimitFlag.Name)
+	config.MaxRetries = ctx.Int(MiveEthRetriesFlag.Name)
+
+	client, err := miveethclient.Dial(config)
+	if err != nil {
+		utils.Fatalf("Failed to connect to the L1 endpoints: %v", err)
+	}
+	return client
+}
+
+// MakeTrieDatabase constructs a trie database based on the configured scheme.
+func MakeTrieDatabase(ctx *cli.Context, disk ethdb.Database, preimage bool, readOnly bool) *trie.Database {
+	config := &trie.Config{
+		Preimages: preimage,
+	}
+	scheme, err := rawdb.ParseStateScheme(ctx.String(StateSchemeFlag.Name), disk)
+	if err != nil {
+		utils.Fatalf("%v", err)
+	}
+	if scheme == rawdb.HashScheme {
+		// Read-only mode is not implemented in hash mode,
+		// ignore the parameter silently. TODO(rjl493456442)
+		// please config it if read mode is implemented.
+		config.HashDB = hashdb.Defaults
+		return trie.NewDatabase(disk, config)
+	}
+	if readOnly {
+		config.PathDB = pathdb.ReadOnly
+	} else {
+		config.PathDB = pathdb.Defaults
+	}
+	return trie.NewDatabase(disk, config)
+}
@@ -0,0 +1,223 @@
+package beacon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"runtime"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	miveconsensus "github.com/ethereum-mive/mive/consensus"
+	mivecore "github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+var (
+	// errOriginMismatch is returned if a Mive header doesn't match the L1 block
+	// it is derived from.
+	errOriginMismatch = errors.New("header mismatches its L1 origin")
+
+	// errNonCanonicalOrigin is returned if the L1 block a Mive header is
+	// derived from is not canonical on L1 anymore.
+	errNonCanonicalOrigin = errors.New("non-canonical L1 origin")
+
+	// errInvalidTimestamp is returned if a Mive header is older than its
+	// parent.
+	errInvalidTimestamp = errors.New("invalid timestamp")
+)
+
+// L1Reader retrieves the L1 headers the Mive headers are verified against.
+type L1Reader interface {
+	HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// Beacon is a consensus engine following L1: a Mive header is valid if it is
+// derived from a canonical L1 block, its origin, sharing its hash, number,
+// parent and time. The fields of the header resulting from the execution of
+// the block are left to the block validator.
+type Beacon struct {
+	l1            L1Reader
+	finalizedOnly bool // Whether the headers derived from unfinalized L1 blocks are rejected
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New creates a beacon follower engine verifying the headers against the given
+// L1 chain. If finalizedOnly is set, only the headers derived from finalized
+// L1 blocks are accepted, otherwise their origin only has to be canonical.
+func New(l1 L1Reader, finalizedOnly bool) *Beacon {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Beacon{
+		l1:            l1,
+		finalizedOnly: finalizedOnly,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// VerifyHeader checks whether a header conforms to the consensus rules.
+func (b *Beacon) VerifyHeader(chain miveconsensus.ChainHeaderReader, header *mivetypes.Header) error {
+	finalized, err := b.finalized()
+	if err != nil {
+		return err
+	}
+	return b.verifyHeader(chain, header, nil, finalized)
+}
+
+// VerifyHeaders is similar to VerifyHeader, but verifies a batch of headers
+// concurrently. The method returns a quit channel to abort the operations and
+// a results channel to retrieve the async verifications (the order is that of
+// the input slice).
+func (b *Beacon) VerifyHeaders(chain miveconsensus.ChainHeaderReader, headers []*mivetypes.Header) (chan<- struct{}, <-chan error) {
+	abort, results := make(chan struct{}), make(chan error, len(headers))
+	if len(headers) == 0 {
+		return abort, results
+	}
+	// The finalized L1 block is retrieved once for the whole batch
+	finalized, err := b.finalized()
+	if err != nil {
+		for range headers {
+			results <- err
+		}
+		return abort, results
+	}
+	workers := runtime.GOMAXPROCS(0)
+	if len(headers) < workers {
+		workers = len(headers)
+	}
+	var (
+		inputs = make(chan int)
+		done   = make(chan int, workers)
+		errs   = make([]error, len(headers))
+	)
+	for i := 0; i < workers; i++ {
+		go func() {
+			for index := range inputs {
+				var parent *mivetypes.Header
+				if index > 0 {
+					parent = headers[index-1]
+				}
+				errs[index] = b.verifyHeader(chain, headers[index], parent, finalized)
+				done <- index
+			}
+		}()
+	}
+	go func() {
+		defer close(inputs)
+		var (
+			in, out = 0, 0
+			checked = make([]bool, len(headers))
+			inputs  = inputs
+		)
+		for {
+			select {
+			case inputs <- in:
+				if in++; in == len(headers) {
+					// Reached end of headers, stop sending to workers
+					inputs = nil
+				}
+			case index := <-done:
+				for checked[index] = true; checked[out]; out++ {
+					results <- errs[out]
+					if out == len(headers)-1 {
+						return
+					}
+				}
+			case <-abort:
+				return
+			}
+		}
+	}()
+	return abort, results
+}
+
+// finalized retrieves the finalized L1 header if only the headers derived from
+// finalized L1 blocks are accepted, nil otherwise.
+func (b *Beacon) finalized() (*types.Header, error) {
+	if !b.finalizedOnly {
+		return nil, nil
+	}
+	header, err := b.l1.HeaderByNumber(b.ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve finalized L1 block: %w", err)
+	}
+	return header, nil
+}
+
+// verifyHeader checks whether a header conforms to the consensus rules. The
+// parent is retrieved from the chain if not given. The finalized L1 header is
+// only given if only the headers derived from finalized L1 blocks are
+// accepted.
+func (b *Beacon) verifyHeader(chain miveconsensus.ChainHeaderReader, header *mivetypes.Header, parent *mivetypes.Header, finalized *types.Header) error {
+	config := chain.Config()
+	if header.Number.Cmp(config.Mive.GenesisBlock) < 0 {
+		return consensus.ErrInvalidNumber
+	}
+	// Ensure the header is derived from a canonical L1 block, and matches it
+	if finalized != nil && header.Number.Cmp(finalized.Number) > 0 {
+		return miveconsensus.ErrUnfinalizedOrigin
+	}
+	origin, err := b.l1.HeaderByNumber(b.ctx, header.Number)
+	if err != nil {
+		return fmt.Errorf("%w: %v", miveconsensus.ErrUnknownOrigin, err)
+	}
+	if origin.Hash() != header.Hash {
+		return errNonCanonicalOrigin
+	}
+	if origin.ParentHash != header.ParentHash {
+		return fmt.Errorf("%w: parent hash %v, want %v", errOriginMismatch, header.ParentHash, origin.ParentHash)
+	}
+	if origin.Time != header.Time {
+		return fmt.Errorf("%w: timestamp %d, want %d", errOriginMismatch, header.Time, origin.Time)
+	}
+	// The genesis header is not derived from a parent, the other ones have to
+	// extend a known one
+	if header.Number.Cmp(config.Mive.GenesisBlock) == 0 {
+		return nil
+	}
+	if parent == nil {
+		parent = chain.GetHeader(header.ParentHash, header.Number.Uint64()-1)
+	}
+	if parent == nil || parent.Hash != header.ParentHash || parent.Number.Uint64()+1 != header.Number.Uint64() {
+		return consensus.ErrUnknownAncestor
+	}
+	if header.Time < parent.Time {
+		return errInvalidTimestamp
+	}
+	// Ensure the fields are within their supported ranges
+	if limit := mivecore.BlockGasLimit(origin.GasLimit, config); header.GasUsed > limit {
+		return fmt.Errorf("invalid gasUsed: have %d, gasLimit %d", header.GasUsed, limit)
+	}
+	if !config.IsWithdrawals(header.Number, header.Time) && header.WithdrawalsHash != (common.Hash{}) {
+		return fmt.Errorf("invalid withdrawalsRoot: have %v, expected zero", header.WithdrawalsHash)
+	}
+	want, err := mivecore.CalcBaseFee(config, parent, origin)
+	if err != nil {
+		return err
+	}
+	switch {
+	case want == nil && header.BaseFee != nil:
+		return fmt.Errorf("invalid baseFee: have %v, expected nil", header.BaseFee)
+	case want != nil && (header.BaseFee == nil || header.BaseFee.Cmp(want) != 0):
+		return fmt.Errorf("invalid baseFee: have %v, want %v", header.BaseFee, want)
+	}
+	return nil
+}
+
+// APIs implements consensus.Engine, returning the user facing RPC APIs.
+func (b *Beacon) APIs(chain miveconsensus.ChainHeaderReader) []rpc.API {
+	return nil
+}
+
+// Close implements consensus.Engine, aborting the pending L1 requests.
+func (b *Beacon) Close() error {
+	b.cancel()
+	return nil
+}
@@ -0,0 +1,62 @@
+package consensus
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// ErrUnknownOrigin is returned if the L1 block a Mive header claims to be
+	// derived from can't be retrieved, e.g. as the L1 view of the node lags.
+	ErrUnknownOrigin = errors.New("unknown L1 origin")
+
+	// ErrUnfinalizedOrigin is returned if the L1 block a Mive header is derived
+	// from is not finalized yet, while only finalized headers are accepted.
+	ErrUnfinalizedOrigin = errors.New("unfinalized L1 origin")
+
+	// ErrInvalidTxRoot is returned if the transactions of an L1 block don't
+	// match the transaction root in its header.
+	ErrInvalidTxRoot = errors.New("invalid transaction root")
+
+	// ErrInvalidGasUsed is returned if the gas used by executing an L1 block
+	// doesn't match the gas used recorded in the Mive header.
+	ErrInvalidGasUsed = errors.New("invalid gas used")
+
+	// ErrInvalidBloom is returned if the bloom of the execution receipts
+	// doesn't match the bloom recorded in the Mive header.
+	ErrInvalidBloom = errors.New("invalid bloom")
+
+	// ErrInvalidReceiptHash is returned if the root hash of the execution
+	// receipts doesn't match the receipt root recorded in the Mive header.
+	ErrInvalidReceiptHash = errors.New("invalid receipt root hash")
+
+	// ErrInvalidStateRoot is returned if the state root after executing an L1
+	// block doesn't match the state root recorded in the Mive header.
+	ErrInvalidStateRoot = errors.New("invalid merkle root")
+
+	// ErrInvalidWithdrawalsRoot is returned if the storage root of the
+	// withdrawal contract after executing an L1 block doesn't match the
+	// withdrawals root recorded in the Mive header.
+	ErrInvalidWithdrawalsRoot = errors.New("invalid withdrawals root")
+)
+
+// ValidationError is returned if a Mive block is inconsistent with the L1 block
+// it is derived from, or with the results of executing it. Err is one of the
+// ErrInvalid* errors.
+type ValidationError struct {
+	Number uint64      // Number of the offending block
+	Hash   common.Hash // Hash of the offending block
+	Err    error       // Rule that was violated
+	Have   interface{} // Value found in the block
+	Want   interface{} // Value resulting from the L1 block or its execution
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%v in block #%d [%x..] (have %v, want %v)", e.Err, e.Number, e.Hash.Bytes()[:4], e.Have, e.Want)
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,107 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+
+	miveconsensus "github.com/ethereum-mive/mive/consensus"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/params"
+)
+
+// BlockValidator is responsible for validating L1 block contents and the Mive
+// headers derived from them.
+//
+// BlockValidator implements Validator.
+type BlockValidator struct {
+	config *params.ChainConfig // Chain configuration options
+	bc     *BlockChain         // Canonical block chain
+}
+
+// NewBlockValidator returns a new block validator which is safe for re-use
+func NewBlockValidator(config *params.ChainConfig, blockchain *BlockChain) *BlockValidator {
+	validator := &BlockValidator{
+		config: config,
+		bc:     blockchain,
+	}
+	return validator
+}
+
+// ValidateBody validates the L1 block's transactions against its header, and
+// checks that the Mive block it is derived from is known and has its state
+// available.
+func (v *BlockValidator) ValidateBody(block *types.Block) error {
+	// Check whether the block is already imported.
+	if v.bc.HasBlockAndState(block.Hash(), block.NumberU64()) {
+		return core.ErrKnownBlock
+	}
+	// The L1 block was retrieved from a remote endpoint, make sure its body is
+	// consistent with the header before deriving anything from it.
+	header := block.Header()
+	if hash := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); hash != header.TxHash {
+		return &miveconsensus.ValidationError{
+			Number: block.NumberU64(), Hash: block.Hash(),
+			Err: miveconsensus.ErrInvalidTxRoot, Have: header.TxHash, Want: hash,
+		}
+	}
+	if !v.bc.HasBlockAndState(block.ParentHash(), block.NumberU64()-1) {
+		if !v.bc.HasHeader(block.ParentHash(), block.NumberU64()-1) {
+			return consensus.ErrUnknownAncestor
+		}
+		return consensus.ErrPrunedAncestor
+	}
+	return nil
+}
+
+// ValidateState validates the various changes that happen after a state transition,
+// such as amount of used gas, the receipt roots and the state root itself.
+func (v *BlockValidator) ValidateState(header *mivetypes.Header, statedb *state.StateDB, receipts types.Receipts, usedGas uint64) error {
+	mismatch := func(err error, have, want interface{}) error {
+		return &miveconsensus.ValidationError{
+			Number: header.NumberU64(), Hash: header.Hash,
+			Err: err, Have: have, Want: want,
+		}
+	}
+	if header.GasUsed != usedGas {
+		return mismatch(miveconsensus.ErrInvalidGasUsed, header.GasUsed, usedGas)
+	}
+	// Validate the received block's bloom with the one derived from the generated receipts.
+	// For valid blocks this should always validate to true.
+	rbloom := types.CreateBloom(receipts)
+	if rbloom != header.Bloom {
+		return mismatch(miveconsensus.ErrInvalidBloom, header.Bloom, rbloom)
+	}
+	// The receipt Trie's root (R = (Tr [[H1, R1], ... [Hn, Rn]]))
+	receiptSha := types.DeriveSha(receipts, trie.NewStackTrie(nil))
+	if receiptSha != header.ReceiptHash {
+		return mismatch(miveconsensus.ErrInvalidReceiptHash, header.ReceiptHash, receiptSha)
+	}
+	// Validate the state root against the received state root and throw
+	// an error if they don't match.
+	if root := statedb.IntermediateRoot(v.config.Eth.IsEIP158(header.Number)); header.Root != root {
+		return mismatch(miveconsensus.ErrInvalidStateRoot, header.Root, root)
+	}
+	if root := WithdrawalsRoot(statedb, header.Number, header.Time, v.config); header.WithdrawalsHash != root {
+		return mismatch(miveconsensus.ErrInvalidWithdrawalsRoot, header.WithdrawalsHash, root)
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -12,26 +13,29 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/common/prque"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/exports/syncx"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/trie/triedb/hashdb"
 	"github.com/ethereum/go-ethereum/trie/triedb/pathdb"
+	"go.opentelemetry.io/otel/attribute"
 
 	miveconsensus "github.com/ethereum-mive/mive/consensus"
 	miverawdb "github.com/ethereum-mive/mive/core/rawdb"
 	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/internal/telemetry"
 	miveparams "github.com/ethereum-mive/mive/params"
 )
 
@@ -69,11 +73,17 @@ var (
 	blockReorgAddMeter  = metrics.NewRegisteredMeter("chain/reorg/add", nil)
 	blockReorgDropMeter = metrics.NewRegisteredMeter("chain/reorg/drop", nil)
 
+	beaconSeenMeter     = metrics.NewRegisteredMeter("chain/beacon/seen", nil)     // L1 transactions sent to a beacon address
+	beaconDecodedMeter  = metrics.NewRegisteredMeter("chain/beacon/decoded", nil)  // Beacon transactions decoded into Mive transactions
+	beaconRejectedMeter = metrics.NewRegisteredMeter("chain/beacon/rejected", nil) // Beacon transactions rejected
+	beaconDecodeTimer   = metrics.NewRegisteredTimer("chain/beacon/decodes", nil)  // Time spent decoding the beacon transactions of an L1 block
+
 	blockPrefetchExecuteTimer   = metrics.NewRegisteredTimer("chain/prefetch/executes", nil)
 	blockPrefetchInterruptMeter = metrics.NewRegisteredMeter("chain/prefetch/interrupts", nil)
 
 	errInsertionInterrupted = errors.New("insertion is interrupted")
 	errChainStopped         = errors.New("blockchain is stopped")
+	errChainReadOnly        = errors.New("blockchain is read-only")
 	errInvalidOldChain      = errors.New("invalid old chain")
 	errInvalidNewChain      = errors.New("invalid new chain")
 )
@@ -124,7 +134,9 @@ type BlockChain struct {
 	chainSideFeed event.Feed
 	chainHeadFeed event.Feed
 	logsFeed      event.Feed
+	miveTxsFeed   event.Feed
 	blockProcFeed event.Feed
+	badBlockFeed  event.Feed
 	scope         event.SubscriptionScope
 	genesisHeader *mivetypes.Header
 
@@ -139,8 +151,13 @@ type BlockChain struct {
 	currentFinalBlock atomic.Pointer[mivetypes.Header] // Latest (consensus) finalized block
 	currentSafeBlock  atomic.Pointer[mivetypes.Header] // Latest (consensus) safe block
 
+	derivedTxs  atomic.Uint64 // Number of Mive transactions derived since startup
+	rejectedTxs atomic.Uint64 // Number of beacon transactions rejected since startup
+
+	bodyCache     *lru.Cache[common.Hash, *mivetypes.Body]
 	receiptsCache *lru.Cache[common.Hash, []*types.Receipt]
 	blockCache    *lru.Cache[common.Hash, *types.Block]
+	txLookupCache *lru.Cache[common.Hash, *rawdb.LegacyTxLookupEntry]
 
 	// future blocks are blocks added for later processing
 	futureBlocks *lru.Cache[common.Hash, *types.Block]
@@ -151,29 +168,66 @@ type BlockChain struct {
 	procInterrupt atomic.Bool    // interrupt signaler for block processing
 
 	engine     miveconsensus.Engine
-	validator  core.Validator // Block and state validator interface
+	validator  Validator // Block and state validator interface
 	prefetcher core.Prefetcher
-	processor  core.Processor // Block transaction processor interface
+	processor  Processor // Block transaction processor interface
 	vmConfig   vm.Config
 
-	ethClient *ethclient.Client
+	ethClient L1Reader
+
+	readOnly bool // Whether the database is opened read-only, the chain is then never modified
+
+	// txLookupLimit is the maximum number of blocks from head whose tx indices
+	// are reserved:
+	//  * 0:   means no limit and regenerate any missing indexes
+	//  * N:   means N block limit [HEAD-N+1, HEAD] and delete extra indexes
+	//  * nil: disable tx reindexer/deleter, but still index new blocks
+	txLookupLimit uint64
 
 	ctx       context.Context
 	ctxCancel context.CancelFunc
 }
 
-func NewBlockChain(db ethdb.Database, cacheConfig *core.CacheConfig, genesis *Genesis, overrides *core.ChainOverrides, engine miveconsensus.Engine, vmConfig vm.Config, ethClient *ethclient.Client) (*BlockChain, error) {
+// NewBlockChain returns a fully initialised block chain using information
+// available in the database, setting up the genesis block if needed.
+func NewBlockChain(db ethdb.Database, cacheConfig *core.CacheConfig, genesis *Genesis, overrides *ChainOverrides, engine miveconsensus.Engine, vmConfig vm.Config, ethClient L1Reader, txLookupLimit *uint64) (*BlockChain, error) {
+	return newBlockChain(db, cacheConfig, genesis, overrides, engine, vmConfig, ethClient, txLookupLimit, false)
+}
+
+// NewReadOnlyBlockChain returns a block chain serving the data of an initialized
+// database opened without write access. The chain config is the stored one, and
+// the chain can't be modified: nothing is inserted, rewound or repaired.
+func NewReadOnlyBlockChain(db ethdb.Database, cacheConfig *core.CacheConfig, engine miveconsensus.Engine, vmConfig vm.Config, ethClient L1Reader) (*BlockChain, error) {
+	return newBlockChain(db, cacheConfig, nil, nil, engine, vmConfig, ethClient, nil, true)
+}
+
+func newBlockChain(db ethdb.Database, cacheConfig *core.CacheConfig, genesis *Genesis, overrides *ChainOverrides, engine miveconsensus.Engine, vmConfig vm.Config, ethClient L1Reader, txLookupLimit *uint64, readOnly bool) (*BlockChain, error) {
 	// Open trie database with provided config
-	triedb := trie.NewDatabase(db, triedbConfig(cacheConfig))
+	config := triedbConfig(cacheConfig)
+	if readOnly && config.PathDB != nil {
+		config.PathDB.ReadOnly = true
+	}
+	triedb := trie.NewDatabase(db, config)
 
 	ctx, ctxCancel := context.WithCancel(context.Background())
 
-	chainConfig, genesisHash, genesisErr := SetupGenesisBlockWithOverride(ctx, db, triedb, genesis, overrides, ethClient)
+	var (
+		chainConfig *miveparams.ChainConfig
+		genesisErr  error
+	)
+	if readOnly {
+		chainConfig, genesisErr = readStoredChainConfig(db)
+	} else {
+		chainConfig, _, genesisErr = SetupGenesisBlockWithOverride(ctx, db, triedb, genesis, overrides, ethClient)
+	}
 	if _, ok := genesisErr.(*params.ConfigCompatError); genesisErr != nil && !ok {
 		ctxCancel()
 		return nil, genesisErr
 	}
-	_ = genesisHash
+	if err := InstallPrecompiles(chainConfig); err != nil {
+		ctxCancel()
+		return nil, err
+	}
 	log.Info("")
 	log.Info(strings.Repeat("-", 153))
 	for _, line := range strings.Split(chainConfig.Description(), "\n") {
@@ -190,18 +244,22 @@ func NewBlockChain(db ethdb.Database, cacheConfig *core.CacheConfig, genesis *Ge
 		triegc:        prque.New[int64, common.Hash](nil),
 		quit:          make(chan struct{}),
 		chainmu:       syncx.NewClosableMutex(),
+		bodyCache:     lru.NewCache[common.Hash, *mivetypes.Body](bodyCacheLimit),
 		receiptsCache: lru.NewCache[common.Hash, []*types.Receipt](receiptsCacheLimit),
 		blockCache:    lru.NewCache[common.Hash, *types.Block](blockCacheLimit),
+		txLookupCache: lru.NewCache[common.Hash, *rawdb.LegacyTxLookupEntry](txLookupCacheLimit),
 		futureBlocks:  lru.NewCache[common.Hash, *types.Block](maxFutureBlocks),
 		engine:        engine,
 		vmConfig:      vmConfig,
 		ethClient:     ethClient,
+		readOnly:      readOnly,
 		ctx:           ctx,
 		ctxCancel:     ctxCancel,
 	}
 
 	bc.flushInterval.Store(int64(cacheConfig.TrieTimeLimit))
 	bc.stateCache = state.NewDatabaseWithNodeDB(bc.db, bc.triedb)
+	bc.validator = NewBlockValidator(chainConfig, bc)
 	bc.prefetcher = newStatePrefetcher(chainConfig, bc, engine)
 	bc.processor = NewStateProcessor(chainConfig, bc, engine)
 
@@ -214,6 +272,11 @@ func NewBlockChain(db ethdb.Database, cacheConfig *core.CacheConfig, genesis *Ge
 	if bc.genesisHeader == nil {
 		return nil, core.ErrNoGenesis
 	}
+	// The Mive chain doesn't start at zero, track where it starts for the tools
+	// operating on the raw database (e.g. the freezer)
+	if miverawdb.ReadGenesisNumber(bc.db) == nil && !readOnly {
+		miverawdb.WriteGenesisNumber(bc.db, bc.genesisHeader.NumberU64())
+	}
 
 	bc.currentBlock.Store(nil)
 	bc.currentSnapBlock.Store(nil)
@@ -223,10 +286,96 @@ func NewBlockChain(db ethdb.Database, cacheConfig *core.CacheConfig, genesis *Ge
 	// If Mive is initialized with an external ancient store, re-initialize the
 	// missing chain indexes and chain flags. This procedure can survive crash
 	// and can be resumed in next restart since chain flags are updated in last step.
-	if bc.empty() {
-		rawdb.InitDatabaseFromFreezer(bc.db)
+	if bc.empty() && !readOnly {
+		miverawdb.InitDatabaseFromFreezer(bc.db)
+	}
+	// Load blockchain states from disk
+	if err := bc.loadLastState(); err != nil {
+		return nil, err
+	}
+	// Make sure the state associated with the block is available, or log out
+	// if there is no available state, waiting for state sync.
+	head := bc.CurrentBlock()
+	if !bc.HasState(head.Root) {
+		if head.NumberU64() == bc.genesisHeader.NumberU64() {
+			// The genesis state is missing, which is only possible in the path-based
+			// scheme. This situation occurs when the initial state sync is not finished
+			// yet, or the chain head is rewound below the pivot point. In both scenarios,
+			// there is no possible recovery approach except for rerunning a snap sync.
+			// Do nothing here until the state syncer picks it up.
+			log.Info("Genesis state is missing, wait state sync")
+		} else if readOnly {
+			return nil, fmt.Errorf("head state missing in read-only database: #%d [%x..]", head.Number, head.Hash.Bytes()[:4])
+		} else {
+			// Head state is missing, before the state recovery, find out the
+			// disk layer point of snapshot(if it's enabled). Make sure the
+			// rewound point is lower than disk layer.
+			var diskRoot common.Hash
+			if bc.cacheConfig.SnapshotLimit > 0 {
+				diskRoot = rawdb.ReadSnapshotRoot(bc.db)
+			}
+			if diskRoot != (common.Hash{}) {
+				log.Warn("Head state missing, repairing", "number", head.Number, "hash", head.Hash, "snaproot", diskRoot)
+
+				snapDisk, err := bc.setHeadBeyondRoot(head.NumberU64(), 0, diskRoot, true)
+				if err != nil {
+					return nil, err
+				}
+				// Chain rewound, persist old snapshot number to indicate recovery procedure
+				if snapDisk != 0 {
+					rawdb.WriteSnapshotRecoveryNumber(bc.db, snapDisk)
+				}
+			} else {
+				log.Warn("Head state missing, repairing", "number", head.Number, "hash", head.Hash)
+				if _, err := bc.setHeadBeyondRoot(head.NumberU64(), 0, common.Hash{}, true); err != nil {
+					return nil, err
+				}
+			}
+		}
 	}
+	// Check the current state of the block hashes and make sure that we do not have any of the bad blocks in our chain
+	for _, hash := range miveparams.BadHashes() {
+		if header := bc.GetHeaderByHash(hash); header != nil {
+			// get the canonical block corresponding to the offending header's number
+			headerByNumber := bc.GetHeaderByNumber(header.NumberU64())
+			// make sure the headerByNumber (if present) is in our current canonical chain
+			if headerByNumber != nil && headerByNumber.Hash == header.Hash {
+				log.Error("Found bad hash, rewinding chain", "number", header.Number, "hash", header.Hash)
+				if err := bc.SetHead(header.NumberU64() - 1); err != nil {
+					return nil, err
+				}
+				log.Error("Chain rewind was successful, resuming normal operation")
+			}
+		}
+	}
+	// Load any existing snapshot, regenerating it if loading failed
+	if bc.cacheConfig.SnapshotLimit > 0 {
+		// If the chain was rewound past the snapshot persistent layer (causing
+		// a recovery block number to be persisted to disk), check if we're still
+		// in recovery mode and in that case, don't invalidate the snapshot on a
+		// head mismatch.
+		var recover bool
+
+		head := bc.CurrentBlock()
+		if layer := rawdb.ReadSnapshotRecoveryNumber(bc.db); layer != nil && *layer >= head.NumberU64() {
+			log.Warn("Enabling snapshot recovery", "chainhead", head.Number, "diskbase", *layer)
+			recover = true
+		}
+		snapconfig := snapshot.Config{
+			CacheSize:  bc.cacheConfig.SnapshotLimit,
+			Recovery:   recover,
+			NoBuild:    bc.cacheConfig.SnapshotNoBuild || readOnly,
+			AsyncBuild: !bc.cacheConfig.SnapshotWait,
+		}
+		bc.snaps, _ = snapshot.New(snapconfig, bc.db, bc.triedb, head.Root)
+	}
+	// Start tx indexer/unindexer if required.
+	if txLookupLimit != nil {
+		bc.txLookupLimit = *txLookupLimit
 
+		bc.wg.Add(1)
+		go bc.maintainTxIndex()
+	}
 	return bc, nil
 }
 
@@ -254,15 +403,16 @@ func (bc *BlockChain) loadLastState() error {
 		log.Warn("Empty database, resetting chain")
 		return bc.Reset()
 	}
-	// Make sure the entire head header is available
-	headBlock := bc.GetBlockByHash(head)
-	if headBlock == nil {
+	// Make sure the entire head header is available. Only the stored Mive header
+	// is checked: its L1 block may be neither cached nor retrievable at startup,
+	// which doesn't make the chain corrupt.
+	headHeader := bc.GetHeaderByHash(head)
+	if headHeader == nil {
 		// Corrupt or empty database, init from scratch
 		log.Warn("Head block missing, resetting chain", "hash", head)
 		return bc.Reset()
 	}
-
-	headHeader := bc.GetHeaderByHash(head)
+	headBlock := headHeader
 	// Everything seems to be fine, set as the head block
 	bc.currentBlock.Store(headHeader)
 	headBlockGauge.Update(int64(headHeader.NumberU64()))
@@ -300,17 +450,17 @@ func (bc *BlockChain) loadLastState() error {
 	// Issue a status log for the user
 	currentSnapBlock := bc.CurrentSnapBlock()
 	currentFinalBlock := bc.CurrentFinalBlock()
-	if headHeader.Hash != headBlock.Hash() {
+	if headHeader.Hash != headBlock.Hash {
 		log.Info("Loaded most recent local header",
 			"number", headHeader.Number,
 			"hash", headHeader.Hash,
 			"age", common.PrettyAge(time.Unix(int64(headHeader.Time), 0)))
 	}
 	log.Info("Loaded most recent local block",
-		"number", headBlock.Number(),
-		"hash", headBlock.Hash(),
-		"age", common.PrettyAge(time.Unix(int64(headBlock.Time()), 0)))
-	if headBlock.Hash() != currentSnapBlock.Hash {
+		"number", headBlock.Number,
+		"hash", headBlock.Hash,
+		"age", common.PrettyAge(time.Unix(int64(headBlock.Time), 0)))
+	if headBlock.Hash != currentSnapBlock.Hash {
 		log.Info("Loaded most recent local snap block",
 			"number", currentSnapBlock.Number,
 			"hash", currentSnapBlock.Hash,
@@ -364,6 +514,62 @@ func (bc *BlockChain) ResetWithGenesisBlock(genesis *mivetypes.Header) error {
 	return nil
 }
 
+// ExportedBlock is the unit of a chain export: a Mive block along with the
+// receipts and rejections produced while deriving it, in their storage form.
+type ExportedBlock struct {
+	Block      *mivetypes.Block
+	Receipts   []*types.ReceiptForStorage
+	Rejections mivetypes.Rejections
+}
+
+// Export writes the active chain to the given writer. Blocks are exported up to
+// the snap block, as receipts are available for them whether or not the state is.
+func (bc *BlockChain) Export(w io.Writer) error {
+	return bc.ExportN(w, bc.genesisHeader.NumberU64(), bc.CurrentSnapBlock().NumberU64())
+}
+
+// ExportN writes a subset of the active chain to the given writer.
+func (bc *BlockChain) ExportN(w io.Writer, first uint64, last uint64) error {
+	if first > last {
+		return fmt.Errorf("export failed: first (%d) is greater than last (%d)", first, last)
+	}
+	log.Info("Exporting batch of blocks", "count", last-first+1)
+
+	var (
+		parentHash common.Hash
+		start      = time.Now()
+		reported   = time.Now()
+	)
+	for nr := first; nr <= last; nr++ {
+		block := bc.GetMiveBlockByNumber(nr)
+		if block == nil {
+			return fmt.Errorf("export failed on #%d: not found", nr)
+		}
+		if nr > first && block.ParentHash() != parentHash {
+			return errors.New("export failed: chain reorg during export")
+		}
+		parentHash = block.Hash()
+
+		var receipts []*types.ReceiptForStorage
+		for _, receipt := range rawdb.ReadRawReceipts(bc.db, block.Hash(), nr) {
+			receipts = append(receipts, (*types.ReceiptForStorage)(receipt))
+		}
+		exported := &ExportedBlock{
+			Block:      block,
+			Receipts:   receipts,
+			Rejections: miverawdb.ReadRejections(bc.db, block.Hash(), nr),
+		}
+		if err := rlp.Encode(w, exported); err != nil {
+			return err
+		}
+		if time.Since(reported) >= 8*time.Second {
+			log.Info("Exporting blocks", "exported", nr-first, "elapsed", common.PrettyDuration(time.Since(start)))
+			reported = time.Now()
+		}
+	}
+	return nil
+}
+
 // SetHead rewinds the local chain to a new head. Depending on whether the node
 // was snap synced or full synced and in which state, the method will try to
 // delete minimal data from disk whilst retaining chain consistency.
@@ -429,7 +635,22 @@ func (bc *BlockChain) SetSafe(header *mivetypes.Header) {
 	}
 }
 
+// SetTrieFlushInterval configures how often in-memory tries are persisted to disk.
+// The interval is in terms of block processing time, not wall clock.
+// It is thread-safe and can be called repeatedly without side effects.
+func (bc *BlockChain) SetTrieFlushInterval(interval time.Duration) {
+	bc.flushInterval.Store(int64(interval))
+}
+
+// GetTrieFlushInterval gets the in-memory tries flush interval
+func (bc *BlockChain) GetTrieFlushInterval() time.Duration {
+	return time.Duration(bc.flushInterval.Load())
+}
+
 func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Hash, repair bool) (uint64, error) {
+	if bc.readOnly {
+		return 0, errChainReadOnly
+	}
 	if !bc.chainmu.TryLock() {
 		return 0, errChainStopped
 	}
@@ -539,8 +760,17 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 		}
 		return headHeader, wipe // Only force wipe if full synced
 	}
-	// Rewind the header chain, deleting all block bodies until then
+	// Rewind the header chain, deleting all block bodies until then. The logs of
+	// the dropped blocks are announced as removed, from the head downwards.
+	var deletedLogs []*types.Log
 	delFn := func(db ethdb.KeyValueWriter, hash common.Hash, num uint64) {
+		if logs := bc.collectLogs(hash, num, true); len(logs) > 0 {
+			deletedLogs = append(deletedLogs, logs...)
+			if len(deletedLogs) > 512 {
+				bc.rmLogsFeed.Send(core.RemovedLogsEvent{Logs: deletedLogs})
+				deletedLogs = nil
+			}
+		}
 		// Ignore the error here since light client won't hit this path
 		frozen, _ := bc.db.Ancients()
 		if num+1 <= frozen {
@@ -552,11 +782,15 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 			// Remove the hash <-> number mapping from the active store.
 			rawdb.DeleteHeaderNumber(db, hash)
 		} else {
-			// Remove relative receipts from the active store.
+			// Remove relative body and receipts from the active store.
 			// The header and canonical hash will be
 			// removed in the hc.SetHead function.
+			rawdb.DeleteBody(db, hash, num)
 			rawdb.DeleteReceipts(db, hash, num)
 		}
+		// The rejections are not frozen, they're kept in the active store
+		// for the ancient blocks as well.
+		miverawdb.DeleteRejections(db, hash, num)
 		// Todo(rjl493456442) txlookup, bloombits, etc
 	}
 
@@ -577,10 +811,15 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 			bc.hc.SetHead(head, updateFn, delFn)
 		}
 	}
+	if len(deletedLogs) > 0 {
+		bc.rmLogsFeed.Send(core.RemovedLogsEvent{Logs: deletedLogs})
+	}
 
 	// Clear out any stale content from the caches
+	bc.bodyCache.Purge()
 	bc.receiptsCache.Purge()
 	bc.blockCache.Purge()
+	bc.txLookupCache.Purge()
 	bc.futureBlocks.Purge()
 
 	// Clear safe block, finalized block if needed
@@ -595,6 +834,22 @@ func (bc *BlockChain) setHeadBeyondRoot(head uint64, time uint64, root common.Ha
 	return rootNumber, bc.loadLastState()
 }
 
+// collectLogs collects the logs that were generated or removed during the
+// processing of a Mive block. It must be called before the receipts of the
+// block are deleted.
+func (bc *BlockChain) collectLogs(hash common.Hash, number uint64, removed bool) []*types.Log {
+	var logs []*types.Log
+	for _, receipt := range miverawdb.ReadReceipts(bc.db, hash, number) {
+		for _, log := range receipt.Logs {
+			if removed {
+				log.Removed = true
+			}
+			logs = append(logs, log)
+		}
+	}
+	return logs
+}
+
 // writeHeadBlock injects a new head block into the current block chain. This method
 // assumes that the block is indeed a true head. It will also reset the head
 // header and the head snap sync block to this very same block if they are older
@@ -607,6 +862,10 @@ func (bc *BlockChain) writeHeadBlock(header *mivetypes.Header) {
 	rawdb.WriteHeadHeaderHash(batch, header.Hash)
 	rawdb.WriteHeadFastBlockHash(batch, header.Hash)
 	rawdb.WriteCanonicalHash(batch, header.Hash, header.NumberU64())
+	if body := bc.GetBody(header.Hash); body != nil {
+		miverawdb.WriteTxLookupEntriesByBody(batch, header.NumberU64(), body)
+		miverawdb.WriteOriginLookupEntries(batch, header.NumberU64(), body, miverawdb.ReadRejections(bc.db, header.Hash, header.NumberU64()))
+	}
 	rawdb.WriteHeadBlockHash(batch, header.Hash)
 
 	// Flush the whole batch into the disk, exit the node if failed
@@ -623,6 +882,146 @@ func (bc *BlockChain) writeHeadBlock(header *mivetypes.Header) {
 	headBlockGauge.Update(int64(header.NumberU64()))
 }
 
+// InsertChain attempts to derive the Mive chain from the given batch of L1
+// blocks and insert the results into the canonical chain. If an error is
+// returned it will return the index number of the failing block as well an
+// error describing what went wrong.
+func (bc *BlockChain) InsertChain(chain types.Blocks) (int, error) {
+	// Sanity check that we have something meaningful to import
+	if len(chain) == 0 {
+		return 0, nil
+	}
+	// Do a sanity check that the provided chain is actually ordered and linked.
+	for i := 1; i < len(chain); i++ {
+		block, prev := chain[i], chain[i-1]
+		if block.NumberU64() != prev.NumberU64()+1 || block.ParentHash() != prev.Hash() {
+			log.Error("Non contiguous block insert",
+				"number", block.Number(),
+				"hash", block.Hash(),
+				"parent", block.ParentHash(),
+				"prevnumber", prev.Number(),
+				"prevhash", prev.Hash(),
+			)
+			return 0, fmt.Errorf("non contiguous insert: item %d is #%d [%x..], item %d is #%d [%x..] (parent [%x..])", i-1, prev.NumberU64(),
+				prev.Hash().Bytes()[:4], i, block.NumberU64(), block.Hash().Bytes()[:4], block.ParentHash().Bytes()[:4])
+		}
+	}
+	// Pre-checks passed, start the full block imports
+	if bc.readOnly {
+		return 0, errChainReadOnly
+	}
+	if !bc.chainmu.TryLock() {
+		return 0, errChainStopped
+	}
+	defer bc.chainmu.Unlock()
+	return bc.insertChain(chain, true)
+}
+
+// InsertExportedChain writes a batch of trusted Mive blocks, along with their
+// receipts and rejections, into the canonical chain without deriving them. The
+// blocks are expected to extend the local chain. As the state of the blocks is
+// not part of an export, the head block is only moved to the inserted blocks if
+// their state happens to be available, otherwise only the head header is. The
+// blocks are then validated rather than derived again when the L1 chain is
+// replayed on top of the available state.
+func (bc *BlockChain) InsertExportedChain(chain []*ExportedBlock) (int, error) {
+	// Sanity check that we have something meaningful to import
+	if len(chain) == 0 {
+		return 0, nil
+	}
+	// Do a sanity check that the provided chain is actually ordered and linked.
+	for i := 1; i < len(chain); i++ {
+		block, prev := chain[i].Block, chain[i-1].Block
+		if block.NumberU64() != prev.NumberU64()+1 || block.ParentHash() != prev.Hash() {
+			return 0, fmt.Errorf("non contiguous insert: item %d is #%d [%x..], item %d is #%d [%x..] (parent [%x..])", i-1, prev.NumberU64(),
+				prev.Hash().Bytes()[:4], i, block.NumberU64(), block.Hash().Bytes()[:4], block.ParentHash().Bytes()[:4])
+		}
+	}
+	if bc.readOnly {
+		return 0, errChainReadOnly
+	}
+	if !bc.chainmu.TryLock() {
+		return 0, errChainStopped
+	}
+	defer bc.chainmu.Unlock()
+
+	var (
+		batch = bc.db.NewBatch()
+		last  *mivetypes.Header
+	)
+	for i, exported := range chain {
+		if bc.insertStopped() {
+			return i, errInsertionInterrupted
+		}
+		var (
+			block    = exported.Block
+			number   = block.NumberU64()
+			receipts = make(types.Receipts, len(exported.Receipts))
+		)
+		// Skip the blocks the local chain already has, reject the conflicting ones
+		if canon := bc.GetCanonicalHash(number); canon != (common.Hash{}) {
+			if canon != block.Hash() {
+				return i, fmt.Errorf("block #%d [%x..] conflicts with the local chain [%x..]", number, block.Hash().Bytes()[:4], canon.Bytes()[:4])
+			}
+			continue
+		}
+		// The first inserted block has to extend the local chain
+		if last == nil && bc.GetCanonicalHash(number-1) != block.ParentHash() {
+			return i, fmt.Errorf("containing header #%d [%x..] unknown", number-1, block.ParentHash().Bytes()[:4])
+		}
+		// Ensure the receipts belong to the block
+		if len(receipts) != len(block.Transactions()) {
+			return i, fmt.Errorf("block #%d [%x..] has %d transactions but %d receipts", number, block.Hash().Bytes()[:4], len(block.Transactions()), len(receipts))
+		}
+		for j, receipt := range exported.Receipts {
+			receipts[j] = (*types.Receipt)(receipt)
+		}
+		if hash := types.DeriveSha(receipts, trie.NewStackTrie(nil)); hash != block.ReceiptHash() {
+			return i, fmt.Errorf("invalid receipt root hash (remote: %x local: %x)", block.ReceiptHash(), hash)
+		}
+		miverawdb.WriteBlock(batch, block)
+		rawdb.WriteReceipts(batch, block.Hash(), number, receipts)
+		miverawdb.WriteRejections(batch, block.Hash(), number, exported.Rejections)
+		rawdb.WriteCanonicalHash(batch, block.Hash(), number)
+		miverawdb.WriteTxLookupEntriesByBody(batch, number, block.Body())
+		miverawdb.WriteOriginLookupEntries(batch, number, block.Body(), exported.Rejections)
+
+		if batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return i, err
+			}
+			batch.Reset()
+		}
+		last = block.Header()
+	}
+	if last == nil {
+		return len(chain), nil
+	}
+	rawdb.WriteHeadHeaderHash(batch, last.Hash)
+	rawdb.WriteHeadFastBlockHash(batch, last.Hash)
+	if err := batch.Write(); err != nil {
+		return len(chain), err
+	}
+	bc.hc.SetCurrentHeader(last)
+	bc.currentSnapBlock.Store(last)
+	headFastBlockGauge.Update(int64(last.NumberU64()))
+
+	// Promote the inserted blocks to the head if their state is available
+	if bc.HasState(last.Root) {
+		bc.writeHeadBlock(last)
+	} else {
+		log.Warn("State of the inserted blocks is not available", "number", last.Number, "hash", last.Hash, "root", last.Root)
+	}
+	return len(chain), nil
+}
+
+// insertChain is the internal implementation of InsertChain, which assumes that
+// 1) chains are contiguous, and 2) The chain mutex is held.
+//
+// Every L1 block is executed on top of the state of its parent Mive block and
+// the results are written as the Mive block with the same hash. While a block
+// is being processed, the next one is executed on a throwaway state to warm up
+// the caches.
 func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error) {
 	// If the chain is terminating, don't even bother starting up.
 	if bc.insertStopped() {
@@ -632,7 +1031,459 @@ func (bc *BlockChain) insertChain(chain types.Blocks, setHead bool) (int, error)
 	// Start a parallel signature recovery (signer will fluke on fork transition, minimal perf loss)
 	core.SenderCacher.RecoverFromBlocks(types.MakeSigner(bc.chainConfig.Eth, chain[0].Number(), chain[0].Time()), chain)
 
-	return 0, nil
+	// Keep a local copy of the L1 blocks, they are needed again whenever Mive
+	// blocks are re-executed (tracing, reorgs, restarts).
+	batch := bc.db.NewBatch()
+	for _, block := range chain {
+		if !miverawdb.HasL1Block(bc.db, block.Hash()) {
+			miverawdb.WriteL1Block(batch, block)
+		}
+		bc.blockCache.Add(block.Hash(), block)
+	}
+	if err := batch.Write(); err != nil {
+		log.Crit("Failed to write L1 blocks", "err", err)
+	}
+
+	var (
+		start     = time.Now()
+		processed int
+		txcount   int
+		gas       uint64
+		lastCanon *types.Block
+	)
+	ctx, span := telemetry.StartSpan(context.Background(), "chain.insert", attribute.Int64("mive.from", chain[0].Number().Int64()), attribute.Int("mive.blocks", len(chain)))
+	defer func() {
+		span.SetAttributes(attribute.Int("mive.processed", processed))
+		span.End()
+	}()
+	// Fire a single chain head event if we've progressed the chain
+	defer func() {
+		if lastCanon != nil && bc.CurrentBlock().Hash == lastCanon.Hash() {
+			bc.chainHeadFeed.Send(core.ChainHeadEvent{Block: lastCanon})
+		}
+		if processed > 0 {
+			head := bc.CurrentBlock()
+			log.Info("Imported new Mive chain segment", "number", head.Number, "hash", head.Hash,
+				"blocks", processed, "txs", txcount, "mgas", float64(gas)/1000000,
+				"elapsed", common.PrettyDuration(time.Since(start)))
+		}
+	}()
+	bc.blockProcFeed.Send(true)
+	defer bc.blockProcFeed.Send(false)
+
+	for i, block := range chain {
+		// If the chain is terminating, stop processing blocks
+		if bc.insertStopped() {
+			log.Debug("Abort during block processing")
+			return i, errInsertionInterrupted
+		}
+		// If the block is known bad, abort straight out
+		if miveparams.IsBadHash(block.Hash()) {
+			bc.reportBlock(block, nil, nil, 0, nil, core.ErrBannedHash)
+			return i, core.ErrBannedHash
+		}
+		err := bc.validator.ValidateBody(block)
+		if errors.Is(err, core.ErrKnownBlock) {
+			// The block was already derived (e.g. the chain was rewound while
+			// its state was retained), only move the head forward if needed.
+			if setHead && bc.CurrentBlock().Hash == block.ParentHash() {
+				bc.writeHeadBlock(bc.GetHeader(block.Hash(), block.NumberU64()))
+				lastCanon = block
+			}
+			continue
+		}
+		if err != nil {
+			var invalid *miveconsensus.ValidationError
+			if errors.As(err, &invalid) {
+				bc.reportBlock(block, nil, nil, 0, nil, err)
+			}
+			return i, err
+		}
+		pstart := time.Now()
+		parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+		statedb, err := state.New(parent.Root, bc.stateCache, bc.snaps)
+		if err != nil {
+			return i, err
+		}
+		// Enable prefetching to pull in trie node paths while processing transactions
+		statedb.StartPrefetcher("chain")
+
+		// If we have a followup block, run that against the current state to pre-cache
+		// transactions and probabilistically some of the account/storage trie nodes.
+		var followupInterrupt atomic.Bool
+		if !bc.cacheConfig.TrieCleanNoPrefetch && i+1 < len(chain) {
+			throwaway, _ := state.New(parent.Root, bc.stateCache, bc.snaps)
+
+			go func(start time.Time, followup *types.Block, throwaway *state.StateDB) {
+				bc.prefetcher.Prefetch(followup, throwaway, bc.vmConfig, &followupInterrupt)
+
+				blockPrefetchExecuteTimer.Update(time.Since(start))
+				if followupInterrupt.Load() {
+					blockPrefetchInterruptMeter.Mark(1)
+				}
+			}(time.Now(), chain[i+1], throwaway)
+		}
+		// Process block using the parent state as reference point
+		_, execSpan := telemetry.StartSpan(ctx, "chain.execute", attribute.Int64("mive.number", block.Number().Int64()), attribute.Int("l1.txs", len(block.Transactions())))
+		txs, rejections, receipts, logs, usedGas, err := bc.processor.Process(block, statedb, bc.vmConfig)
+		execSpan.SetAttributes(attribute.Int("mive.txs", len(txs)), attribute.Int("mive.rejections", len(rejections)), attribute.Int64("mive.gas", int64(usedGas)))
+		telemetry.EndSpan(execSpan, err)
+		if err != nil {
+			bc.reportBlock(block, nil, nil, 0, nil, err)
+			followupInterrupt.Store(true)
+			statedb.StopPrefetcher()
+			return i, err
+		}
+		ptime := time.Since(pstart)
+
+		// Derive the Mive header from the execution results. If the block was
+		// derived before (e.g. its state got lost), the results are validated
+		// against the stored header instead.
+		header := bc.GetHeader(block.Hash(), block.NumberU64())
+		if header == nil {
+			baseFee, err := CalcBaseFee(bc.chainConfig, bc.GetHeader(block.ParentHash(), block.NumberU64()-1), block.Header())
+			if err != nil {
+				followupInterrupt.Store(true)
+				statedb.StopPrefetcher()
+				return i, err
+			}
+			header = &mivetypes.Header{
+				ParentHash:      block.ParentHash(),
+				Hash:            block.Hash(),
+				Number:          block.Number(),
+				Time:            block.Time(),
+				Root:            statedb.IntermediateRoot(bc.chainConfig.Eth.IsEIP158(block.Number())),
+				ReceiptHash:     types.DeriveSha(receipts, trie.NewStackTrie(nil)),
+				Bloom:           types.CreateBloom(receipts),
+				GasUsed:         usedGas,
+				WithdrawalsHash: WithdrawalsRoot(statedb, block.Number(), block.Time(), bc.chainConfig),
+				BaseFee:         baseFee,
+			}
+		}
+		vstart := time.Now()
+		_, validateSpan := telemetry.StartSpan(ctx, "chain.validate", attribute.Int64("mive.number", block.Number().Int64()))
+		err = bc.validator.ValidateState(header, statedb, receipts, usedGas)
+		telemetry.EndSpan(validateSpan, err)
+		if err != nil {
+			bc.reportBlock(block, txs, receipts, usedGas, statedb, err)
+			followupInterrupt.Store(true)
+			statedb.StopPrefetcher()
+			return i, err
+		}
+		vtime := time.Since(vstart)
+		proctime := time.Since(pstart) // processing + validation
+
+		// Update the metrics touched during block processing and validation
+		accountReadTimer.Update(statedb.AccountReads)                   // Account reads are complete(in processing)
+		storageReadTimer.Update(statedb.StorageReads)                   // Storage reads are complete(in processing)
+		snapshotAccountReadTimer.Update(statedb.SnapshotAccountReads)   // Account reads are complete(in processing)
+		snapshotStorageReadTimer.Update(statedb.SnapshotStorageReads)   // Storage reads are complete(in processing)
+		accountUpdateTimer.Update(statedb.AccountUpdates)               // Account updates are complete(in validation)
+		storageUpdateTimer.Update(statedb.StorageUpdates)               // Storage updates are complete(in validation)
+		accountHashTimer.Update(statedb.AccountHashes)                  // Account hashes are complete(in validation)
+		storageHashTimer.Update(statedb.StorageHashes)                  // Storage hashes are complete(in validation)
+		triehash := statedb.AccountHashes + statedb.StorageHashes       // The time spent on tries hashing
+		trieUpdate := statedb.AccountUpdates + statedb.StorageUpdates   // The time spent on tries update
+		trieRead := statedb.SnapshotAccountReads + statedb.AccountReads // The time spent on account read
+		trieRead += statedb.SnapshotStorageReads + statedb.StorageReads // The time spent on storage read
+		blockExecutionTimer.Update(ptime - trieRead)                    // The time spent on EVM processing
+		blockValidationTimer.Update(vtime - (triehash + trieUpdate))    // The time spent on block validation
+
+		// Write the block to the chain and get the status.
+		wstart := time.Now()
+		miveBlock := mivetypes.NewBlock(header, &mivetypes.Body{Transactions: txs})
+		_, commitSpan := telemetry.StartSpan(ctx, "chain.commit", attribute.Int64("mive.number", block.Number().Int64()))
+		err = bc.writeBlockWithState(miveBlock, rejections, receipts, statedb)
+		telemetry.EndSpan(commitSpan, err)
+		if err != nil {
+			followupInterrupt.Store(true)
+			statedb.StopPrefetcher()
+			return i, err
+		}
+		followupInterrupt.Store(true)
+		statedb.StopPrefetcher()
+
+		if setHead {
+			bc.writeHeadBlock(header)
+			bc.chainFeed.Send(core.ChainEvent{Block: block, Hash: block.Hash(), Logs: logs})
+			if len(logs) > 0 {
+				bc.logsFeed.Send(logs)
+			}
+			if len(txs) > 0 {
+				bc.miveTxsFeed.Send(NewMiveTxsEvent{Block: miveBlock})
+			}
+			lastCanon = block
+		}
+		// Update the metrics touched during block commit
+		accountCommitTimer.Update(statedb.AccountCommits)   // Account commits are complete, we can mark them
+		storageCommitTimer.Update(statedb.StorageCommits)   // Storage commits are complete, we can mark them
+		snapshotCommitTimer.Update(statedb.SnapshotCommits) // Snapshot commits are complete, we can mark them
+		triedbCommitTimer.Update(statedb.TrieDBCommits)     // Trie database commits are complete, we can mark them
+
+		blockWriteTimer.Update(time.Since(wstart) - statedb.AccountCommits - statedb.StorageCommits - statedb.SnapshotCommits - statedb.TrieDBCommits)
+		blockInsertTimer.UpdateSince(pstart)
+
+		bc.gcproc += proctime
+		processed++
+		txcount += len(txs)
+		bc.derivedTxs.Add(uint64(len(txs)))
+		bc.rejectedTxs.Add(uint64(len(rejections)))
+		for _, tx := range block.Transactions() {
+			if isBeaconTx(tx, block.Number(), bc.chainConfig) {
+				beaconSeenMeter.Mark(1)
+			}
+		}
+		beaconDecodedMeter.Mark(int64(len(txs)))
+		beaconRejectedMeter.Mark(int64(len(rejections)))
+		gas += usedGas
+	}
+	return len(chain), nil
+}
+
+// reportBlock logs a bad block error and records the block in the database.
+// The execution results are only given if the block failed the validation of
+// its state, in which case they are summarized in the record.
+func (bc *BlockChain) reportBlock(block *types.Block, txs mivetypes.Transactions, receipts types.Receipts, usedGas uint64, statedb *state.StateDB, err error) {
+	bad := &mivetypes.BadBlock{
+		Origin: block,
+		Error:  err.Error(),
+	}
+	if statedb != nil {
+		// The parent was there to execute the block, the base fee can't fail
+		baseFee, _ := CalcBaseFee(bc.chainConfig, bc.GetHeader(block.ParentHash(), block.NumberU64()-1), block.Header())
+		bad.Header = &mivetypes.Header{
+			ParentHash:      block.ParentHash(),
+			Hash:            block.Hash(),
+			Number:          block.Number(),
+			Time:            block.Time(),
+			Root:            statedb.IntermediateRoot(bc.chainConfig.Eth.IsEIP158(block.Number())),
+			ReceiptHash:     types.DeriveSha(receipts, trie.NewStackTrie(nil)),
+			Bloom:           types.CreateBloom(receipts),
+			GasUsed:         usedGas,
+			WithdrawalsHash: WithdrawalsRoot(statedb, block.Number(), block.Time(), bc.chainConfig),
+			BaseFee:         baseFee,
+		}
+		// Summarize the state of the senders and recipients of the Mive
+		// transactions, where a divergence most likely shows up.
+		seen := make(map[common.Address]bool)
+		touch := func(addr common.Address) {
+			if seen[addr] {
+				return
+			}
+			seen[addr] = true
+			bad.Accounts = append(bad.Accounts, &mivetypes.BadBlockAccount{
+				Address:  addr,
+				Nonce:    statedb.GetNonce(addr),
+				Balance:  statedb.GetBalance(addr),
+				CodeHash: statedb.GetCodeHash(addr),
+				Root:     statedb.GetStorageRoot(addr),
+			})
+		}
+		for _, tx := range txs {
+			touch(tx.From)
+			if tx.Tx.To != nil {
+				touch(*tx.Tx.To)
+			}
+		}
+	}
+	miverawdb.WriteBadBlock(bc.db, bad)
+	bc.badBlockFeed.Send(BadBlockEvent{Block: bad})
+
+	var receiptString string
+	for i, receipt := range receipts {
+		receiptString += fmt.Sprintf("\n  %d: cumulative: %v gas: %v contract: %v status: %v tx: %v logs: %v bloom: %x state: %x",
+			i, receipt.CumulativeGasUsed, receipt.GasUsed, receipt.ContractAddress.Hex(),
+			receipt.Status, receipt.TxHash.Hex(), receipt.Logs, receipt.Bloom, receipt.PostState)
+	}
+	log.Error(fmt.Sprintf(`
+########## BAD MIVE BLOCK #########
+Number: %v
+Hash: %#x
+%v
+
+Error: %v
+##############################
+`, block.Number(), block.Hash(), receiptString, err))
+}
+
+// writeBlockWithState writes the derived Mive block and all associated state
+// to the database.
+func (bc *BlockChain) writeBlockWithState(block *mivetypes.Block, rejections mivetypes.Rejections, receipts []*types.Receipt, state *state.StateDB) error {
+	if !bc.HasHeader(block.ParentHash(), block.NumberU64()-1) {
+		return consensus.ErrUnknownAncestor
+	}
+	// Irrelevant of the canonical status, write the block itself to the database.
+	//
+	// Note all the components of block(hash->number map, header, body, receipts)
+	// should be written atomically. BlockBatch is used for containing all components.
+	blockBatch := bc.db.NewBatch()
+	miverawdb.WriteBlock(blockBatch, block)
+	rawdb.WriteReceipts(blockBatch, block.Hash(), block.NumberU64(), receipts)
+	miverawdb.WriteRejections(blockBatch, block.Hash(), block.NumberU64(), rejections)
+	rawdb.WritePreimages(blockBatch, state.Preimages())
+	if err := blockBatch.Write(); err != nil {
+		log.Crit("Failed to write block into disk", "err", err)
+	}
+	// Commit all cached state changes into underlying memory database.
+	root, err := state.Commit(block.NumberU64(), bc.chainConfig.Eth.IsEIP158(block.Number()))
+	if err != nil {
+		return err
+	}
+	// If node is running in path mode, skip explicit gc operation
+	// which is unnecessary in this mode.
+	if bc.triedb.Scheme() == rawdb.PathScheme {
+		return nil
+	}
+	// If we're running an archive node, always flush
+	if bc.cacheConfig.TrieDirtyDisabled {
+		return bc.triedb.Commit(root, false)
+	}
+	// Full but not archive node, do proper garbage collection
+	bc.triedb.Reference(root, common.Hash{}) // metadata reference to keep trie alive
+	bc.triegc.Push(root, -int64(block.NumberU64()))
+
+	// Flush limits are not considered for the first TriesInMemory blocks.
+	current := block.NumberU64()
+	if current <= bc.genesisHeader.NumberU64()+core.TriesInMemory {
+		return nil
+	}
+	// If we exceeded our memory allowance, flush matured singleton nodes to disk
+	var (
+		_, nodes, imgs = bc.triedb.Size() // all memory is contained within the nodes return for hashdb
+		limit          = common.StorageSize(bc.cacheConfig.TrieDirtyLimit) * 1024 * 1024
+	)
+	if nodes > limit || imgs > 4*1024*1024 {
+		bc.triedb.Cap(limit - ethdb.IdealBatchSize)
+	}
+	// Find the next state trie we need to commit
+	chosen := current - core.TriesInMemory
+	flushInterval := time.Duration(bc.flushInterval.Load())
+	// If we exceeded time allowance, flush an entire trie to disk
+	if bc.gcproc > flushInterval {
+		// If the header is missing (canonical chain behind), we're reorging a low
+		// diff sidechain. Suspend committing until this operation is completed.
+		header := bc.GetHeaderByNumber(chosen)
+		if header == nil {
+			log.Warn("Reorg in progress, trie commit postponed", "number", chosen)
+		} else {
+			// If we're exceeding limits but haven't reached a large enough memory gap,
+			// warn the user that the system is becoming unstable.
+			if chosen < bc.lastWrite+core.TriesInMemory && bc.gcproc >= 2*flushInterval {
+				log.Info("State in memory for too long, committing", "time", bc.gcproc, "allowance", flushInterval, "optimum", float64(chosen-bc.lastWrite)/core.TriesInMemory)
+			}
+			// Flush an entire trie and restart the counters
+			bc.triedb.Commit(header.Root, true)
+			bc.lastWrite = chosen
+			bc.gcproc = 0
+		}
+	}
+	// Garbage collect anything below our required write retention
+	for !bc.triegc.Empty() {
+		root, number := bc.triegc.Pop()
+		if uint64(-number) > chosen {
+			bc.triegc.Push(root, number)
+			break
+		}
+		bc.triedb.Dereference(root)
+	}
+	return nil
+}
+
+// stopWithoutSaving stops the blockchain service. If any imports are currently in progress
+// it will abort them using the procInterrupt. This method stops all running
+// goroutines, but does not do all the post-stop work of persisting data.
+// OBS! It is generally recommended to use the Stop method!
+// This method has been exposed to allow tests to stop the blockchain while simulating
+// a crash.
+func (bc *BlockChain) stopWithoutSaving() {
+	if !bc.stopping.CompareAndSwap(false, true) {
+		return
+	}
+
+	// Unsubscribe all subscriptions registered from blockchain.
+	bc.scope.Close()
+
+	// Signal shutdown to all goroutines.
+	close(bc.quit)
+	bc.StopInsert()
+
+	// Abort all in-flight L1 requests, an insertion waiting for data would
+	// otherwise hold the chain mutex until the request times out.
+	bc.ctxCancel()
+
+	// Now wait for all chain modifications to end and persistent goroutines to exit.
+	//
+	// Note: Close waits for the mutex to become available, i.e. any running chain
+	// modification will have exited when Close returns. Since we also called StopInsert,
+	// the mutex should become available quickly. It cannot be taken again after Close has
+	// returned.
+	bc.chainmu.Close()
+	bc.wg.Wait()
+}
+
+// Stop stops the blockchain service. If any imports are currently in progress
+// it will abort them using the procInterrupt.
+func (bc *BlockChain) Stop() {
+	bc.stopWithoutSaving()
+
+	// Ensure that the entirety of the state snapshot is journaled to disk.
+	var snapBase common.Hash
+	if bc.snaps != nil {
+		if !bc.readOnly {
+			var err error
+			if snapBase, err = bc.snaps.Journal(bc.CurrentBlock().Root); err != nil {
+				log.Error("Failed to journal state snapshot", "err", err)
+			}
+		}
+		bc.snaps.Release()
+	}
+	if bc.readOnly {
+		// Nothing was modified, there's nothing to persist
+	} else if bc.triedb.Scheme() == rawdb.PathScheme {
+		// Ensure that the in-memory trie nodes are journaled to disk properly.
+		if err := bc.triedb.Journal(bc.CurrentBlock().Root); err != nil {
+			log.Info("Failed to journal in-memory trie nodes", "err", err)
+		}
+	} else {
+		// Ensure the state of a recent block is also stored to disk before exiting.
+		// We're writing three different states to catch different restart scenarios:
+		//  - HEAD:     So we don't need to reprocess any blocks in the general case
+		//  - HEAD-1:   So we don't do large reorgs if our HEAD becomes an uncle
+		//  - HEAD-127: So we have a hard limit on the number of blocks reexecuted
+		if !bc.cacheConfig.TrieDirtyDisabled {
+			triedb := bc.triedb
+
+			for _, offset := range []uint64{0, 1, core.TriesInMemory - 1} {
+				if number := bc.CurrentBlock().NumberU64(); number > offset {
+					recent := bc.GetHeaderByNumber(number - offset)
+					if recent == nil {
+						continue
+					}
+					log.Info("Writing cached state to disk", "block", recent.Number, "hash", recent.Hash, "root", recent.Root)
+					if err := triedb.Commit(recent.Root, true); err != nil {
+						log.Error("Failed to commit recent state trie", "err", err)
+					}
+				}
+			}
+			if snapBase != (common.Hash{}) {
+				log.Info("Writing snapshot state to disk", "root", snapBase)
+				if err := triedb.Commit(snapBase, true); err != nil {
+					log.Error("Failed to commit recent state trie", "err", err)
+				}
+			}
+			for !bc.triegc.Empty() {
+				triedb.Dereference(bc.triegc.PopItem())
+			}
+			if _, nodes, _ := triedb.Size(); nodes != 0 { // all memory is contained within the nodes return for hashdb
+				log.Error("Dangling trie nodes after full cleanup")
+			}
+		}
+	}
+	// Close the trie database, release all the held resources as the last step.
+	if err := bc.triedb.Close(); err != nil {
+		log.Error("Failed to close trie database", "err", err)
+	}
+	log.Info("Blockchain stopped")
 }
 
 // StopInsert interrupts all insertion methods, causing them to return
@@ -646,3 +1497,101 @@ func (bc *BlockChain) StopInsert() {
 func (bc *BlockChain) insertStopped() bool {
 	return bc.procInterrupt.Load()
 }
+
+// indexBlocks reindexes or unindexes transactions depending on user configuration
+func (bc *BlockChain) indexBlocks(tail *uint64, head uint64, done chan struct{}) {
+	defer func() { close(done) }()
+
+	// If head is the genesis, it means the chain is just initialized and no
+	// blocks are inserted, so don't need to indexing anything.
+	genesis := bc.genesisHeader.NumberU64()
+	if head == genesis {
+		return
+	}
+
+	// The tail flag is not existent, it means the node is just initialized
+	// and all blocks(may from ancient store) are not indexed yet.
+	if tail == nil {
+		from := genesis
+		if bc.txLookupLimit != 0 && head-genesis >= bc.txLookupLimit {
+			from = head - bc.txLookupLimit + 1
+		}
+		miverawdb.IndexTransactions(bc.db, from, head+1, bc.quit)
+		return
+	}
+	// The tail flag is existent, but the whole chain is required to be indexed.
+	if bc.txLookupLimit == 0 || head-genesis < bc.txLookupLimit {
+		if *tail > genesis {
+			// It can happen when chain is rewound to a historical point which
+			// is even lower than the indexes tail, recap the indexing target
+			// to new head to avoid reading non-existent block bodies.
+			end := *tail
+			if end > head+1 {
+				end = head + 1
+			}
+			miverawdb.IndexTransactions(bc.db, genesis, end, bc.quit)
+		}
+		return
+	}
+	// Update the transaction index to the new chain state
+	if head-bc.txLookupLimit+1 < *tail {
+		// Reindex a part of missing indices and rewind index tail to HEAD-limit
+		miverawdb.IndexTransactions(bc.db, head-bc.txLookupLimit+1, *tail, bc.quit)
+	} else {
+		// Unindex a part of stale indices and forward index tail to HEAD-limit
+		miverawdb.UnindexTransactions(bc.db, *tail, head-bc.txLookupLimit+1, bc.quit)
+	}
+}
+
+// maintainTxIndex is responsible for the construction and deletion of the
+// transaction index, along with the index of the beacon transactions by their
+// L1 hash.
+//
+// User can use flag `txlookuplimit` to specify a "recentness" block, below
+// which ancient tx indices get deleted. If `txlookuplimit` is 0, it means
+// all tx indices will be reserved.
+//
+// The user can adjust the txlookuplimit value for each launch after sync,
+// Mive will automatically construct the missing indices or delete the extra
+// indices.
+func (bc *BlockChain) maintainTxIndex() {
+	defer bc.wg.Done()
+
+	// Listening to chain events and manipulate the transaction indexes.
+	var (
+		done   chan struct{}                       // Non-nil if background unindexing or reindexing routine is active.
+		headCh = make(chan core.ChainHeadEvent, 1) // Buffered to avoid locking up the event feed
+	)
+	sub := bc.SubscribeChainHeadEvent(headCh)
+	if sub == nil {
+		return
+	}
+	defer sub.Unsubscribe()
+	log.Info("Initialized transaction indexer", "limit", bc.TxLookupLimit())
+
+	// Launch the initial processing if chain is not empty. This step is
+	// useful in these scenarios that chain has no progress and indexer
+	// is never triggered.
+	if head := miverawdb.ReadHeadBlock(bc.db); head != nil {
+		done = make(chan struct{})
+		go bc.indexBlocks(rawdb.ReadTxIndexTail(bc.db), head.NumberU64(), done)
+	}
+
+	for {
+		select {
+		case head := <-headCh:
+			if done == nil {
+				done = make(chan struct{})
+				go bc.indexBlocks(rawdb.ReadTxIndexTail(bc.db), head.Block.NumberU64(), done)
+			}
+		case <-done:
+			done = nil
+		case <-bc.quit:
+			if done != nil {
+				log.Info("Waiting background transaction indexer to exit")
+				<-done
+			}
+			return
+		}
+	}
+}
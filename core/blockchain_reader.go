@@ -1,6 +1,7 @@
 package core
 
 import (
+	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -17,49 +18,36 @@ import (
 	"github.com/ethereum/go-ethereum/trie"
 
 	miveconsensus "github.com/ethereum-mive/mive/consensus"
+	miverawdb "github.com/ethereum-mive/mive/core/rawdb"
 	mivetypes "github.com/ethereum-mive/mive/core/types"
 	miveparams "github.com/ethereum-mive/mive/params"
 )
 
-func (bc *BlockChain) EthCurrentHeader() *types.Header {
-	header, err := bc.ethClient.HeaderByNumber(bc.ctx, nil)
-	if err != nil {
-		log.Error("Get current block header", "err", err)
-		return nil
-	}
-	return header
+// EthCurrentHeader retrieves the head header of the L1 chain.
+func (bc *BlockChain) EthCurrentHeader() (*types.Header, error) {
+	return bc.ethClient.HeaderByNumber(bc.ctx, nil)
 }
 
-// EthGetHeader retrieves a block header from the database by hash and number.
-func (bc *BlockChain) EthGetHeader(hash common.Hash, number uint64) *types.Header {
+// EthGetHeader retrieves an L1 block header by hash and number.
+func (bc *BlockChain) EthGetHeader(hash common.Hash, number uint64) (*types.Header, error) {
 	header, err := bc.ethClient.HeaderByHash(bc.ctx, hash)
 	if err != nil {
-		log.Error("Get block header", "hash", hash, "err", err)
-		return nil
+		return nil, err
 	}
 	if header.Number.Cmp(new(big.Int).SetUint64(number)) != 0 {
-		log.Error("Get block header", "hash", hash, "err", consensus.ErrInvalidNumber)
-		return nil
+		return nil, consensus.ErrInvalidNumber
 	}
-	return header
+	return header, nil
 }
 
-func (bc *BlockChain) EthGetHeaderByNumber(number uint64) *types.Header {
-	header, err := bc.ethClient.HeaderByNumber(bc.ctx, new(big.Int).SetUint64(number))
-	if err != nil {
-		log.Error("Get block header", "number", number, "err", err)
-		return nil
-	}
-	return header
+// EthGetHeaderByNumber retrieves an L1 block header by number.
+func (bc *BlockChain) EthGetHeaderByNumber(number uint64) (*types.Header, error) {
+	return bc.ethClient.HeaderByNumber(bc.ctx, new(big.Int).SetUint64(number))
 }
 
-func (bc *BlockChain) EthGetHeaderByHash(hash common.Hash) *types.Header {
-	header, err := bc.ethClient.HeaderByHash(bc.ctx, hash)
-	if err != nil {
-		log.Error("Get block header", "hash", hash, "err", err)
-		return nil
-	}
-	return header
+// EthGetHeaderByHash retrieves an L1 block header by hash.
+func (bc *BlockChain) EthGetHeaderByHash(hash common.Hash) (*types.Header, error) {
+	return bc.ethClient.HeaderByHash(bc.ctx, hash)
 }
 
 // CurrentHeader retrieves the current head header of the canonical chain. The
@@ -122,28 +110,133 @@ func (bc *BlockChain) GetHeadersFrom(number, count uint64) []rlp.RawValue {
 	return bc.hc.GetHeadersFrom(number, count)
 }
 
+// GetBody retrieves a Mive block body (transactions) from the database by
+// hash, caching it if found.
+func (bc *BlockChain) GetBody(hash common.Hash) *mivetypes.Body {
+	// Short circuit if the body's already in the cache, retrieve otherwise
+	if cached, ok := bc.bodyCache.Get(hash); ok {
+		return cached
+	}
+	number := bc.hc.GetBlockNumber(hash)
+	if number == nil {
+		return nil
+	}
+	body := miverawdb.ReadBody(bc.db, hash, *number)
+	if body == nil {
+		return nil
+	}
+	// Cache the found body for next time and return
+	bc.bodyCache.Add(hash, body)
+	return body
+}
+
+// GetMiveBlock retrieves a Mive block from the database by hash and number.
+func (bc *BlockChain) GetMiveBlock(hash common.Hash, number uint64) *mivetypes.Block {
+	header := bc.GetHeader(hash, number)
+	if header == nil {
+		return nil
+	}
+	body := bc.GetBody(hash)
+	if body == nil {
+		return nil
+	}
+	return mivetypes.NewBlock(header, body)
+}
+
+// GetMiveBlockByHash retrieves a Mive block from the database by hash.
+func (bc *BlockChain) GetMiveBlockByHash(hash common.Hash) *mivetypes.Block {
+	number := bc.hc.GetBlockNumber(hash)
+	if number == nil {
+		return nil
+	}
+	return bc.GetMiveBlock(hash, *number)
+}
+
+// GetMiveBlockByNumber retrieves a canonical Mive block from the database by
+// number.
+func (bc *BlockChain) GetMiveBlockByNumber(number uint64) *mivetypes.Block {
+	hash := rawdb.ReadCanonicalHash(bc.db, number)
+	if hash == (common.Hash{}) {
+		return nil
+	}
+	return bc.GetMiveBlock(hash, number)
+}
+
 // GetBlock retrieves a block by hash and number,
 // caching it if found.
 func (bc *BlockChain) GetBlock(hash common.Hash, number uint64) *types.Block {
+	block, err := bc.EthGetBlock(hash, number)
+	if err != nil {
+		log.Error("Get block", "hash", hash, "number", number, "err", err)
+		return nil
+	}
+	return block
+}
+
+// EthGetBlock retrieves an L1 block by hash and number, caching it if found.
+// Unlike GetBlock, failures to retrieve the block are returned to the caller.
+func (bc *BlockChain) EthGetBlock(hash common.Hash, number uint64) (*types.Block, error) {
 	// Short circuit if the block's already in the cache, retrieve otherwise
 	if block, ok := bc.blockCache.Get(hash); ok {
-		return block
+		return block, nil
+	}
+	// Try the local L1 block cache before going to the network
+	if block := miverawdb.ReadL1Block(bc.db, hash); block != nil {
+		if block.NumberU64() != number {
+			return nil, consensus.ErrInvalidNumber
+		}
+		bc.blockCache.Add(hash, block)
+		return block, nil
 	}
 	block, err := bc.ethClient.BlockByHash(bc.ctx, hash)
 	if err != nil {
-		log.Error("Get block", "hash", hash, "err", err)
-		return nil
-	}
-	if block.Number().Cmp(new(big.Int).SetUint64(number)) != 0 {
-		log.Error("Get block", "hash", hash, "err", consensus.ErrInvalidNumber)
-		return nil
+		return nil, err
 	}
-	if block == nil {
-		return nil
+	if block.NumberU64() != number {
+		return nil, consensus.ErrInvalidNumber
 	}
 	// Cache the found block for next time and return
+	miverawdb.WriteL1Block(bc.db, block)
 	bc.blockCache.Add(block.Hash(), block)
-	return block
+	return block, nil
+}
+
+// EthGetReceipts retrieves the receipts of the given L1 block, caching them
+// locally if found. The receipts are checked against the receipt root of the
+// block, as the deposits and beacon events they carry decide what executes on
+// Mive.
+func (bc *BlockChain) EthGetReceipts(hash common.Hash, number uint64) (types.Receipts, error) {
+	// The receipts metadata is derived from the cached block, make sure it's there
+	block, err := bc.EthGetBlock(hash, number)
+	if err != nil {
+		return nil, err
+	}
+	if receipts := miverawdb.ReadL1Receipts(bc.db, hash, bc.chainConfig.Eth); receipts != nil {
+		// Receipts cached by older versions were never checked, drop them if bad
+		if err := verifyL1Receipts(block, receipts); err == nil {
+			return receipts, nil
+		}
+		log.Warn("Dropping invalid cached L1 receipts", "hash", hash, "number", number)
+		miverawdb.DeleteL1Receipts(bc.db, hash)
+	}
+	receipts, err := bc.ethClient.BlockReceipts(bc.ctx, hash, number)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyL1Receipts(block, receipts); err != nil {
+		return nil, err
+	}
+	miverawdb.WriteL1Receipts(bc.db, hash, receipts)
+	return receipts, nil
+}
+
+// verifyL1Receipts checks the given receipts against the receipt root of the L1
+// block they belong to.
+func verifyL1Receipts(block *types.Block, receipts types.Receipts) error {
+	if root := types.DeriveSha(receipts, trie.NewStackTrie(nil)); root != block.ReceiptHash() {
+		return fmt.Errorf("%w: block %d (%v): have %v, want %v", ErrInvalidL1Receipts, block.NumberU64(), block.Hash(), root, block.ReceiptHash())
+	}
+	return nil
 }
 
 // GetBlockByHash retrieves a block by hash, caching it if found.
@@ -174,11 +267,7 @@ func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
 	if number == nil {
 		return nil
 	}
-	header := bc.GetHeader(hash, *number)
-	if header == nil {
-		return nil
-	}
-	receipts := rawdb.ReadReceipts(bc.db, hash, *number, header.Time, bc.chainConfig.Eth)
+	receipts := miverawdb.ReadReceipts(bc.db, hash, *number)
 	if receipts == nil {
 		return nil
 	}
@@ -186,6 +275,40 @@ func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
 	return receipts
 }
 
+// GetTransactionLookup retrieves the lookup associate with the given Mive
+// transaction hash from the cache or database.
+func (bc *BlockChain) GetTransactionLookup(hash common.Hash) *rawdb.LegacyTxLookupEntry {
+	// Short circuit if the txlookup already in the cache, retrieve otherwise
+	if lookup, exist := bc.txLookupCache.Get(hash); exist {
+		return lookup
+	}
+	tx, blockHash, blockNumber, txIndex := miverawdb.ReadTransaction(bc.db, hash)
+	if tx == nil {
+		return nil
+	}
+	lookup := &rawdb.LegacyTxLookupEntry{BlockHash: blockHash, BlockIndex: blockNumber, Index: txIndex}
+	bc.txLookupCache.Add(hash, lookup)
+	return lookup
+}
+
+// GetTransactionByOrigin resolves the beacon transaction with the given L1 hash
+// to the Mive transaction it produced on the canonical chain, or to the record
+// of its rejection, along with the hash and number of the Mive block in which
+// it was processed.
+func (bc *BlockChain) GetTransactionByOrigin(hash common.Hash) (*mivetypes.Transaction, *mivetypes.Rejection, common.Hash, uint64) {
+	return miverawdb.ReadTransactionByOrigin(bc.db, hash)
+}
+
+// GetRejections retrieves the records of the beacon transactions that were
+// rejected in the Mive block with the given hash.
+func (bc *BlockChain) GetRejections(hash common.Hash) mivetypes.Rejections {
+	number := bc.hc.GetBlockNumber(hash)
+	if number == nil {
+		return nil
+	}
+	return miverawdb.ReadRejections(bc.db, hash, *number)
+}
+
 // GetCanonicalHash returns the canonical hash for a given block number
 func (bc *BlockChain) GetCanonicalHash(number uint64) common.Hash {
 	return bc.hc.GetCanonicalHash(number)
@@ -251,12 +374,12 @@ func (bc *BlockChain) Snapshots() *snapshot.Tree {
 }
 
 // Validator returns the current validator.
-func (bc *BlockChain) Validator() core.Validator {
+func (bc *BlockChain) Validator() Validator {
 	return bc.validator
 }
 
 // Processor returns the current processor.
-func (bc *BlockChain) Processor() core.Processor {
+func (bc *BlockChain) Processor() Processor {
 	return bc.processor
 }
 
@@ -275,6 +398,24 @@ func (bc *BlockChain) GetVMConfig() *vm.Config {
 	return &bc.vmConfig
 }
 
+// TxLookupLimit retrieves the txlookup limit used by blockchain to prune
+// stale transaction indices.
+func (bc *BlockChain) TxLookupLimit() uint64 {
+	return bc.txLookupLimit
+}
+
+// BadBlocks returns the most recent L1 blocks whose derivation failed, sorted
+// in reverse order by number.
+func (bc *BlockChain) BadBlocks() []*mivetypes.BadBlock {
+	return miverawdb.ReadAllBadBlocks(bc.db)
+}
+
+// TxStats returns the number of Mive transactions derived and the number of
+// beacon transactions rejected since the chain was started.
+func (bc *BlockChain) TxStats() (derived uint64, rejected uint64) {
+	return bc.derivedTxs.Load(), bc.rejectedTxs.Load()
+}
+
 // TrieDB retrieves the low level trie database used for data storage.
 func (bc *BlockChain) TrieDB() *trie.Database {
 	return bc.triedb
@@ -305,6 +446,16 @@ func (bc *BlockChain) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscript
 	return bc.scope.Track(bc.logsFeed.Subscribe(ch))
 }
 
+// SubscribeNewMiveTxsEvent registers a subscription of NewMiveTxsEvent.
+func (bc *BlockChain) SubscribeNewMiveTxsEvent(ch chan<- NewMiveTxsEvent) event.Subscription {
+	return bc.scope.Track(bc.miveTxsFeed.Subscribe(ch))
+}
+
+// SubscribeBadBlockEvent registers a subscription of BadBlockEvent.
+func (bc *BlockChain) SubscribeBadBlockEvent(ch chan<- BadBlockEvent) event.Subscription {
+	return bc.scope.Track(bc.badBlockFeed.Subscribe(ch))
+}
+
 // SubscribeBlockProcessingEvent registers a subscription of bool where true means
 // block processing has started while false means it has stopped.
 func (bc *BlockChain) SubscribeBlockProcessingEvent(ch chan<- bool) event.Subscription {
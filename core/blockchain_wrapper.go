@@ -4,6 +4,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 )
 
 type BlockChainWrapper struct {
@@ -15,5 +16,10 @@ func (bc *BlockChainWrapper) Engine() consensus.Engine {
 }
 
 func (bc *BlockChainWrapper) GetHeader(hash common.Hash, number uint64) *types.Header {
-	return bc.EthGetHeader(hash, number)
+	header, err := bc.EthGetHeader(hash, number)
+	if err != nil {
+		log.Error("Get block header", "hash", hash, "number", number, "err", err)
+		return nil
+	}
+	return header
 }
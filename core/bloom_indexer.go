@@ -0,0 +1,145 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"context"
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/bitutil"
+	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+const (
+	// bloomThrottling is the time to wait between processing two consecutive index
+	// sections. It's useful during chain upgrades to prevent disk overload.
+	bloomThrottling = 100 * time.Millisecond
+)
+
+// bloomSectionSizeKey tracks the section size the bloom bits index was built
+// with, sections of another size can't be reused.
+var bloomSectionSizeKey = []byte("size")
+
+// BloomIndexer implements a core.ChainIndexer, building up a rotated bloom bits index
+// for the Mive header bloom filters, permitting blazing fast filtering.
+type BloomIndexer struct {
+	size    uint64               // section size to generate bloombits for
+	db      ethdb.Database       // database instance to write index data and metadata into
+	gen     *bloombits.Generator // generator to rotate the bloom bits crating the bloom index
+	section uint64               // Section is the section number being processed currently
+	next    uint                 // Index of the next block of the section to add a bloom for
+	head    common.Hash          // Head is the hash of the last header of the section, if any
+}
+
+// NewBloomIndexer returns a chain indexer that generates bloom bits data for the
+// canonical chain for fast logs filtering.
+func NewBloomIndexer(db ethdb.Database, size, confirms uint64) *ChainIndexer {
+	backend := &BloomIndexer{
+		db:   db,
+		size: size,
+	}
+	table := rawdb.NewTable(db, string(rawdb.BloomBitsIndexPrefix))
+
+	// Drop the whole index if the section size changed, it's rebuilt from scratch
+	blob, _ := table.Get(bloomSectionSizeKey)
+	if len(blob) != 8 || binary.BigEndian.Uint64(blob) != size {
+		if len(blob) == 8 {
+			log.Warn("Bloom bits section size changed, regenerating index", "old", binary.BigEndian.Uint64(blob), "new", size)
+			for i := 0; i < types.BloomBitLength; i++ {
+				rawdb.DeleteBloombits(db, uint(i), 0, math.MaxUint64)
+			}
+		}
+		it := table.NewIterator([]byte("shead"), nil)
+		for it.Next() {
+			table.Delete(it.Key())
+		}
+		it.Release()
+		table.Delete([]byte("count"))
+
+		var data [8]byte
+		binary.BigEndian.PutUint64(data[:], size)
+		table.Put(bloomSectionSizeKey, data[:])
+	}
+	return NewChainIndexer(db, table, backend, size, confirms, bloomThrottling, "bloombits")
+}
+
+// Reset implements core.ChainIndexerBackend, starting a new bloombits index
+// section.
+func (b *BloomIndexer) Reset(ctx context.Context, section uint64, lastSectionHead common.Hash) error {
+	gen, err := bloombits.NewGenerator(uint(b.size))
+	b.gen, b.section, b.next, b.head = gen, section, 0, common.Hash{}
+	return err
+}
+
+// Process implements core.ChainIndexerBackend, adding a new header's bloom into
+// the index.
+func (b *BloomIndexer) Process(ctx context.Context, header *mivetypes.Header) error {
+	index := uint(header.Number.Uint64() - b.section*b.size)
+	if err := b.pad(index); err != nil {
+		return err
+	}
+	if err := b.gen.AddBloom(index, header.Bloom); err != nil {
+		return err
+	}
+	b.next, b.head = index+1, header.Hash
+	return nil
+}
+
+// pad adds empty blooms to the section up to the given index, in place of the
+// blocks missing from the chain. They don't have any logs.
+func (b *BloomIndexer) pad(index uint) error {
+	if b.next < index {
+		b.head = common.Hash{} // Missing blocks have no canonical hash
+	}
+	for ; b.next < index; b.next++ {
+		if err := b.gen.AddBloom(b.next, types.Bloom{}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Commit implements core.ChainIndexerBackend, finalizing the bloom section and
+// writing it out into the database.
+func (b *BloomIndexer) Commit() error {
+	if err := b.pad(uint(b.size)); err != nil {
+		return err
+	}
+	batch := b.db.NewBatchWithSize((int(b.size) / 8) * types.BloomBitLength)
+	for i := 0; i < types.BloomBitLength; i++ {
+		bits, err := b.gen.Bitset(uint(i))
+		if err != nil {
+			return err
+		}
+		rawdb.WriteBloomBits(batch, uint(i), b.section, b.head, bitutil.CompressBytes(bits))
+	}
+	return batch.Write()
+}
+
+// Prune returns an empty error since we don't support pruning here.
+func (b *BloomIndexer) Prune(threshold uint64) error {
+	return nil
+}
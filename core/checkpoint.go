@@ -0,0 +1,85 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/trie"
+
+	miverawdb "github.com/ethereum-mive/mive/core/rawdb"
+	"github.com/ethereum-mive/mive/core/state/snapshot"
+)
+
+var errNoGenesis = errors.New("genesis not found in chain, initialize the database first")
+
+// CheckCheckpoint verifies that the chain stored in the database can be moved
+// to the checkpoint of a state snapshot: the checkpoint has to be ahead of the
+// local chain and must not conflict with it.
+func CheckCheckpoint(db ethdb.Reader, checkpoint *snapshot.Checkpoint) error {
+	var (
+		block  = checkpoint.Block
+		number = block.NumberU64()
+	)
+	genesis := miverawdb.ReadGenesisNumber(db)
+	if genesis == nil {
+		return errNoGenesis
+	}
+	if number <= *genesis {
+		return fmt.Errorf("checkpoint #%d is not after the genesis #%d", number, *genesis)
+	}
+	if head := miverawdb.ReadHeadBlock(db); head != nil && head.NumberU64() >= number {
+		return fmt.Errorf("local chain at #%d is not behind the checkpoint #%d", head.NumberU64(), number)
+	}
+	if canon := rawdb.ReadCanonicalHash(db, number); canon != (common.Hash{}) && canon != block.Hash() {
+		return fmt.Errorf("checkpoint #%d [%x..] conflicts with the local chain [%x..]", number, block.Hash().Bytes()[:4], canon.Bytes()[:4])
+	}
+	if len(checkpoint.Receipts) != len(block.Transactions()) {
+		return fmt.Errorf("checkpoint has %d transactions but %d receipts", len(block.Transactions()), len(checkpoint.Receipts))
+	}
+	receipts := make(types.Receipts, len(checkpoint.Receipts))
+	for i, receipt := range checkpoint.Receipts {
+		receipts[i] = (*types.Receipt)(receipt)
+	}
+	if hash := types.DeriveSha(receipts, trie.NewStackTrie(nil)); hash != block.ReceiptHash() {
+		return fmt.Errorf("invalid receipt root hash (remote: %x local: %x)", block.ReceiptHash(), hash)
+	}
+	return nil
+}
+
+// WriteCheckpoint makes the checkpoint of an imported state snapshot the head
+// of the chain stored in the database. The state of the checkpoint must be
+// available already. The blocks between the genesis and the checkpoint are left
+// missing, the chain is derived onwards from the checkpoint.
+func WriteCheckpoint(db ethdb.Database, checkpoint *snapshot.Checkpoint) error {
+	if err := CheckCheckpoint(db, checkpoint); err != nil {
+		return err
+	}
+	var (
+		block    = checkpoint.Block
+		hash     = block.Hash()
+		number   = block.NumberU64()
+		receipts = make(types.Receipts, len(checkpoint.Receipts))
+		batch    = db.NewBatch()
+	)
+	for i, receipt := range checkpoint.Receipts {
+		receipts[i] = (*types.Receipt)(receipt)
+	}
+	miverawdb.WriteBlock(batch, block)
+	rawdb.WriteReceipts(batch, hash, number, receipts)
+	miverawdb.WriteRejections(batch, hash, number, checkpoint.Rejections)
+	rawdb.WriteCanonicalHash(batch, hash, number)
+	miverawdb.WriteTxLookupEntriesByBody(batch, number, block.Body())
+	miverawdb.WriteOriginLookupEntries(batch, number, block.Body(), checkpoint.Rejections)
+
+	// The transactions before the checkpoint are not available for indexing
+	rawdb.WriteTxIndexTail(batch, number)
+
+	rawdb.WriteHeadHeaderHash(batch, hash)
+	rawdb.WriteHeadFastBlockHash(batch, hash)
+	rawdb.WriteHeadBlockHash(batch, hash)
+	return batch.Write()
+}
@@ -0,0 +1,19 @@
+package core
+
+import "errors"
+
+var (
+	// ErrInvalidPayload is returned if the data of a transaction sent to the
+	// beacon address can't be decoded into a Mive transaction.
+	ErrInvalidPayload = errors.New("invalid Mive transaction payload")
+
+	// ErrMissingBlobs is returned if the blobs carrying the payload of a beacon
+	// transaction are not available locally, so the block including it can't
+	// be processed.
+	ErrMissingBlobs = errors.New("missing blobs of beacon transaction")
+
+	// ErrInvalidL1Receipts is returned if the receipts of an L1 block retrieved
+	// from the L1 endpoint don't match the receipt root of its header, so the
+	// deposits and beacon events they carry can't be trusted.
+	ErrInvalidL1Receipts = errors.New("L1 receipts don't match the receipt root")
+)
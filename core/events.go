@@ -0,0 +1,17 @@
+package core
+
+import (
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+// NewMiveTxsEvent is posted when a Mive block has been derived and inserted into
+// the canonical chain, carrying the Mive transactions executed in it.
+type NewMiveTxsEvent struct{ Block *mivetypes.Block }
+
+// BadBlockEvent is posted when the derivation of a Mive block from an L1 block
+// failed, carrying the record of the bad block.
+type BadBlockEvent struct{ Block *mivetypes.BadBlock }
+
+// NewPendingTxsEvent is posted when pending Mive transactions are observed in
+// the pending pool of L1, before they are included in any L1 block.
+type NewPendingTxsEvent struct{ Txs []*mivetypes.Transaction }
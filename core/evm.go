@@ -5,6 +5,8 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	cmath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -24,11 +26,12 @@ type ChainContext interface {
 	GetHeader(common.Hash, uint64) *mivetypes.Header
 }
 
-// NewEVMBlockContext creates a new context for use in the EVM.
-func NewEVMBlockContext(header *types.Header, chain *BlockChain, author *common.Address, config *params.ChainConfig) vm.BlockContext {
+// NewEVMBlockContext creates a new context for use in the EVM. It fails if the
+// Mive base fee can't be computed, as the parent block is missing.
+func NewEVMBlockContext(header *types.Header, chain *BlockChain, author *common.Address, config *params.ChainConfig) (vm.BlockContext, error) {
 	// Set coinbase to beneficiary address.
 	if author == nil {
-		author = &params.BeneficiaryAddress
+		author = &config.Mive.Beneficiary
 	}
 
 	ctx := core.NewEVMBlockContext(header, &BlockChainWrapper{chain}, author)
@@ -37,16 +40,22 @@ func NewEVMBlockContext(header *types.Header, chain *BlockChain, author *common.
 	ctx.GetHash = GetHashFn(header, chain)
 
 	feeReductionDenom := new(big.Int).SetUint64(config.FeeReductionDenominator())
-	if ctx.BaseFee != nil {
+	if config.IsMiveBaseFee(header.Number) {
+		baseFee, err := CalcBaseFee(config, chain.GetHeader(header.ParentHash, header.Number.Uint64()-1), header)
+		if err != nil {
+			return vm.BlockContext{}, err
+		}
+		ctx.BaseFee = baseFee
+	} else if ctx.BaseFee != nil {
 		ctx.BaseFee = new(big.Int).Div(ctx.BaseFee, feeReductionDenom)
 	}
 	if ctx.BlobBaseFee != nil {
 		ctx.BlobBaseFee = new(big.Int).Div(ctx.BlobBaseFee, feeReductionDenom)
 	}
 
-	ctx.GasLimit = blockGasLimit(ctx.GasLimit, config)
+	ctx.GasLimit = BlockGasLimit(ctx.GasLimit, config)
 
-	return ctx
+	return ctx, nil
 }
 
 // GetHashFn returns a GetHashFunc which retrieves header hashes by number
@@ -88,7 +97,43 @@ func GetHashFn(ref *types.Header, chain ChainContext) func(n uint64) common.Hash
 	}
 }
 
-func blockGasLimit(gasLimit uint64, config *params.ChainConfig) uint64 {
+// CalcBaseFee returns the Mive base fee of the block derived from the L1 block
+// with the given header, on top of the given parent, nil before the Mive base
+// fee fork. The base fee is adjusted from the one of the parent like EIP-1559,
+// the gas target being half the Mive block gas limit. The first block of the
+// fork starts from the reduced L1 base fee. The parent is required, a missing
+// one failing with consensus.ErrUnknownAncestor.
+func CalcBaseFee(config *params.ChainConfig, parent *mivetypes.Header, header *types.Header) (*big.Int, error) {
+	if !config.IsMiveBaseFee(header.Number) {
+		return nil, nil
+	}
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	if parent.BaseFee == nil {
+		return reducedBaseFee(config, header), nil
+	}
+	return eip1559.CalcBaseFee(config.Eth, &types.Header{
+		Number:   parent.Number,
+		GasLimit: BlockGasLimit(header.GasLimit, config),
+		GasUsed:  parent.GasUsed,
+		BaseFee:  parent.BaseFee,
+	}), nil
+}
+
+// reducedBaseFee returns the base fee of the given L1 block divided by the fee
+// reduction denominator, zero before London.
+func reducedBaseFee(config *params.ChainConfig, header *types.Header) *big.Int {
+	baseFee := new(big.Int)
+	if header.BaseFee != nil {
+		baseFee.Div(header.BaseFee, new(big.Int).SetUint64(config.FeeReductionDenominator()))
+	}
+	return baseFee
+}
+
+// BlockGasLimit returns the gas limit of the Mive block derived from an L1 block
+// with the given gas limit.
+func BlockGasLimit(gasLimit uint64, config *params.ChainConfig) uint64 {
 	gasLimit, overflow := cmath.SafeMul(gasLimit, config.BlockGasLimitMultiplier())
 	if overflow {
 		gasLimit = cmath.MaxUint64
@@ -5,14 +5,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/trie"
@@ -25,16 +26,39 @@ import (
 
 //go:generate go run github.com/fjl/gencodec -type Genesis -field-override genesisSpecMarshaling -out gen_genesis.go
 
-var errGenesisNoConfig = errors.New("genesis has no chain configuration")
+var (
+	errGenesisNoConfig = errors.New("genesis has no chain configuration")
+	errGenesisNoStart  = errors.New("genesis has no Mive genesis block number")
+	errGenesisNoL1     = errors.New("genesis is not initialized and no L1 endpoint to retrieve it from")
+	errGenesisNoStore  = errors.New("genesis is not initialized in the database")
+)
+
+// ChainOverrides contains the changes to the chain config applied on startup,
+// e.g. to test upcoming forks.
+type ChainOverrides struct {
+	OverrideCancun *uint64
+	OverrideVerkle *uint64
+	OverrideMiveM1 *big.Int
+	OverrideMiveM2 *uint64
+}
 
 type Genesis struct {
 	Config *params.ChainConfig `json:"config"`
 	Alloc  GenesisAlloc        `json:"alloc" gencodec:"required"`
+
+	// System contracts deployed in the genesis state, in addition to the
+	// allocated accounts.
+	Predeploys []*Predeploy `json:"predeploys,omitempty"`
+
+	// Mive base fee of the genesis block if the Mive base fee fork is active
+	// from it, nil to start from the reduced L1 base fee.
+	BaseFee *big.Int `json:"baseFeePerGas"`
 }
 
 // field type overrides for gencodec
 type genesisSpecMarshaling struct {
-	Alloc map[common.UnprefixedAddress]core.GenesisAccount
+	Alloc   map[common.UnprefixedAddress]core.GenesisAccount
+	BaseFee *math.HexOrDecimal256
 }
 
 // GenesisAlloc specifies the initial state that is part of the genesis block.
@@ -121,9 +145,14 @@ func (ga *GenesisAlloc) flush(db ethdb.Database, triedb *trie.Database, blockhas
 	return nil
 }
 
-func SetupGenesisBlockWithOverride(ctx context.Context, db ethdb.Database, triedb *trie.Database, genesis *Genesis, overrides *core.ChainOverrides, ethClient *ethclient.Client) (*params.ChainConfig, common.Hash, error) {
-	if genesis != nil && genesis.Config == nil {
-		return &params.ChainConfig{}, common.Hash{}, errGenesisNoConfig
+func SetupGenesisBlockWithOverride(ctx context.Context, db ethdb.Database, triedb *trie.Database, genesis *Genesis, overrides *ChainOverrides, ethClient L1Reader) (*params.ChainConfig, common.Hash, error) {
+	if genesis != nil {
+		if genesis.Config == nil || genesis.Config.Eth == nil {
+			return &params.ChainConfig{}, common.Hash{}, errGenesisNoConfig
+		}
+		if genesis.Config.Mive == nil || genesis.Config.Mive.GenesisBlock == nil {
+			return &params.ChainConfig{}, common.Hash{}, errGenesisNoStart
+		}
 	}
 	applyOverrides := func(config *params.ChainConfig) {
 		if config != nil {
@@ -133,6 +162,12 @@ func SetupGenesisBlockWithOverride(ctx context.Context, db ethdb.Database, tried
 			if overrides != nil && overrides.OverrideVerkle != nil {
 				config.Eth.VerkleTime = overrides.OverrideVerkle
 			}
+			if overrides != nil && overrides.OverrideMiveM1 != nil {
+				config.Mive.M1Block = overrides.OverrideMiveM1
+			}
+			if overrides != nil && overrides.OverrideMiveM2 != nil {
+				config.Mive.M2Time = overrides.OverrideMiveM2
+			}
 		}
 	}
 
@@ -142,22 +177,29 @@ func SetupGenesisBlockWithOverride(ctx context.Context, db ethdb.Database, tried
 	applyOverrides(genesis.Config)
 
 	genesisNum := genesis.Config.Mive.GenesisBlock
-	genesisBlock, err := ethClient.BlockByNumber(ctx, genesisNum)
-	if err != nil {
-		return &params.ChainConfig{}, common.Hash{}, err
+	stored := rawdb.ReadCanonicalHash(db, genesisNum.Uint64())
+
+	// Retrieve the L1 block the Mive chain starts at. Without an L1 endpoint
+	// (e.g. in offline database tools), the stored genesis is trusted as is.
+	var genesisBlock *types.Block
+	if ethClient != nil {
+		var err error
+		if genesisBlock, err = ethClient.BlockByNumber(ctx, genesisNum); err != nil {
+			return &params.ChainConfig{}, common.Hash{}, err
+		}
+	} else if (stored == common.Hash{}) {
+		return &params.ChainConfig{}, common.Hash{}, errGenesisNoL1
 	}
-	genesisHash := genesisBlock.Hash()
 
 	// Just commit the new block if there is no stored genesis block.
-	stored := rawdb.ReadCanonicalHash(db, genesisNum.Uint64())
 	if (stored == common.Hash{}) {
 		header, err := genesis.Commit(db, triedb, genesisBlock)
 		return genesis.Config, header.Hash, err
 	}
 
 	// Ensure the stored genesis matches with the given one.
-	if genesisHash != stored {
-		return genesis.Config, genesisHash, &core.GenesisMismatchError{stored, genesisHash}
+	if genesisBlock != nil && genesisBlock.Hash() != stored {
+		return genesis.Config, genesisBlock.Hash(), &core.GenesisMismatchError{Stored: stored, New: genesisBlock.Hash()}
 	}
 
 	// The genesis block is present(perhaps in ancient database) while the
@@ -166,6 +208,9 @@ func SetupGenesisBlockWithOverride(ctx context.Context, db ethdb.Database, tried
 	// in this case.
 	header := miverawdb.ReadHeader(db, stored, genesisNum.Uint64())
 	if header.Root != types.EmptyRootHash && !triedb.Initialized(header.Root) {
+		if genesisBlock == nil {
+			return genesis.Config, stored, errGenesisNoL1
+		}
 		header, err := genesis.Commit(db, triedb, genesisBlock)
 		return genesis.Config, header.Hash, err
 	}
@@ -200,6 +245,24 @@ func SetupGenesisBlockWithOverride(ctx context.Context, db ethdb.Database, tried
 	return newcfg, stored, nil
 }
 
+// readStoredChainConfig retrieves the chain config stored along the genesis
+// block of an initialized database, without writing anything.
+func readStoredChainConfig(db ethdb.Database) (*params.ChainConfig, error) {
+	number := miverawdb.ReadGenesisNumber(db)
+	if number == nil {
+		return nil, errGenesisNoStore
+	}
+	stored := rawdb.ReadCanonicalHash(db, *number)
+	if (stored == common.Hash{}) {
+		return nil, errGenesisNoStore
+	}
+	config := miverawdb.ReadChainConfig(db, stored)
+	if config == nil {
+		return nil, errors.New("found genesis block without chain config")
+	}
+	return config, nil
+}
+
 // IsVerkle indicates whether the state is already stored in a verkle
 // tree at genesis time.
 func (g *Genesis) IsVerkle(block *types.Block) bool {
@@ -208,11 +271,15 @@ func (g *Genesis) IsVerkle(block *types.Block) bool {
 
 // ToHeader returns the genesis block header according to genesis specification.
 func (g *Genesis) ToHeader(block *types.Block) *mivetypes.Header {
-	root, err := g.Alloc.hash(g.IsVerkle(block), block.NumberU64())
+	alloc, err := g.alloc()
 	if err != nil {
 		panic(err)
 	}
-	return &mivetypes.Header{
+	root, err := alloc.hash(g.IsVerkle(block), block.NumberU64())
+	if err != nil {
+		panic(err)
+	}
+	header := &mivetypes.Header{
 		ParentHash:  block.ParentHash(),
 		Hash:        block.Hash(),
 		Number:      block.Number(),
@@ -220,24 +287,37 @@ func (g *Genesis) ToHeader(block *types.Block) *mivetypes.Header {
 		Root:        root,
 		ReceiptHash: types.EmptyReceiptsHash,
 	}
+	if g.Config.IsMiveBaseFee(block.Number()) {
+		if g.BaseFee != nil {
+			header.BaseFee = new(big.Int).Set(g.BaseFee)
+		} else {
+			header.BaseFee = reducedBaseFee(g.Config, block.Header())
+		}
+	}
+	return header
 }
 
 func (g *Genesis) Commit(db ethdb.Database, triedb *trie.Database, block *types.Block) (*mivetypes.Header, error) {
-	header := g.ToHeader(block)
 	config := g.Config
 	if err := config.CheckConfigForkOrder(); err != nil {
 		return nil, err
 	}
+	alloc, err := g.alloc()
+	if err != nil {
+		return nil, err
+	}
+	header := g.ToHeader(block)
 	if config.Eth.Clique != nil && len(block.Extra()) < 32+crypto.SignatureLength {
 		return nil, errors.New("can't start clique chain without signers")
 	}
 	// All the checks has passed, flush the states derived from the genesis
 	// specification as well as the specification itself into the provided
 	// database.
-	if err := g.Alloc.flush(db, triedb, block.Hash(), block.NumberU64()); err != nil {
+	if err := alloc.flush(db, triedb, block.Hash(), block.NumberU64()); err != nil {
 		return nil, err
 	}
-	miverawdb.WriteHeader(db, header)
+	miverawdb.WriteBlock(db, mivetypes.NewBlockWithHeader(header))
+	miverawdb.WriteGenesisNumber(db, header.NumberU64())
 	rawdb.WriteReceipts(db, block.Hash(), block.NumberU64(), nil)
 	rawdb.WriteCanonicalHash(db, block.Hash(), block.NumberU64())
 	rawdb.WriteHeadBlockHash(db, block.Hash())
@@ -254,3 +334,21 @@ func DefaultGenesisBlock() *Genesis {
 		Alloc:  make(GenesisAlloc),
 	}
 }
+
+// DefaultSepoliaGenesisBlock returns the Mive genesis block for the Sepolia
+// test network.
+func DefaultSepoliaGenesisBlock() *Genesis {
+	return &Genesis{
+		Config: params.SepoliaChainConfig,
+		Alloc:  make(GenesisAlloc),
+	}
+}
+
+// DefaultHoleskyGenesisBlock returns the Mive genesis block for the Holesky
+// test network.
+func DefaultHoleskyGenesisBlock() *Genesis {
+	return &Genesis{
+		Config: params.HoleskyChainConfig,
+		Alloc:  make(GenesisAlloc),
+	}
+}
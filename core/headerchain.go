@@ -269,11 +269,11 @@ func (hc *HeaderChain) ValidateHeaderChain(chain []*mivetypes.Header) (int, erro
 				parentHash.Bytes()[:4], i, chain[i].Number, hash.Bytes()[:4], chain[i].ParentHash[:4])
 		}
 		// If the header is a banned one, straight out abort
-		if core.BadHashes[chain[i].ParentHash] {
+		if params.IsBadHash(chain[i].ParentHash) {
 			return i - 1, core.ErrBannedHash
 		}
 		// If it's the last header in the cunk, we need to check it too
-		if i == len(chain)-1 && core.BadHashes[chain[i].Hash] {
+		if i == len(chain)-1 && params.IsBadHash(chain[i].Hash) {
 			return i, core.ErrBannedHash
 		}
 	}
@@ -514,6 +514,11 @@ func (hc *HeaderChain) setHead(headBlock uint64, headTime uint64, updateFn Updat
 		// startup, so failing hard there is ok.
 		log.Crit("Rejecting genesis rewind via timestamp", "target", headTime, "genesis", hc.genesisHeader.Time)
 	}
+	// The Mive chain starts at the L1 block it was launched at, not at zero, so
+	// a block target below the genesis block would wipe the genesis as well.
+	if headTime == 0 && headBlock < hc.genesisHeader.Number.Uint64() {
+		headBlock = hc.genesisHeader.Number.Uint64()
+	}
 	var (
 		parentHash common.Hash
 		batch      = hc.chainDb.NewBatch()
@@ -0,0 +1,132 @@
+package core
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+
+	miveparams "github.com/ethereum-mive/mive/params"
+)
+
+// L1StatePrecompile is the name the L1 state precompile is scheduled under in
+// the chain configuration. It requires the L1 block attributes fork, keeping
+// the state roots it proves against.
+const L1StatePrecompile = "l1State"
+
+var (
+	errL1StateInput   = errors.New("malformed L1 state proof input")
+	errL1StateBlock   = errors.New("L1 state root not available")
+	errL1StateAccount = errors.New("invalid L1 account proof")
+	errL1StateStorage = errors.New("invalid L1 storage proof")
+)
+
+// The L1 state precompile takes the ABI encoding of
+//
+//	(uint256 number, address account, bytes32 slot, bytes[] accountProof, bytes[] storageProof)
+//
+// with the proofs as returned by eth_getProof for the L1 block with the given
+// number, which must be among the recent ones, and returns the ABI encoding of
+//
+//	(uint256 nonce, uint256 balance, bytes32 codeHash, bytes32 storageRoot, bytes32 value)
+//
+// for the proven account and storage slot. An account or slot proven absent is
+// returned zeroed, and the storage proof is ignored for an absent account or an
+// account without storage, for which eth_getProof returns an empty one.
+var l1StateInput, l1StateOutput abi.Arguments
+
+func init() {
+	arguments := func(types ...string) abi.Arguments {
+		var args abi.Arguments
+		for _, t := range types {
+			typ, err := abi.NewType(t, "", nil)
+			if err != nil {
+				panic(err)
+			}
+			args = append(args, abi.Argument{Type: typ})
+		}
+		return args
+	}
+	l1StateInput = arguments("uint256", "address", "bytes32", "bytes[]", "bytes[]")
+	l1StateOutput = arguments("uint256", "uint256", "bytes32", "bytes32", "bytes32")
+
+	RegisterPrecompile(L1StatePrecompile, miveparams.L1StateAddress, new(l1StatePrecompile))
+}
+
+// l1StatePrecompile proves L1 accounts and storage slots against the recent L1
+// state roots kept by the L1 block contract.
+type l1StatePrecompile struct{}
+
+// RequiredGas implements Precompile.
+func (p *l1StatePrecompile) RequiredGas(input []byte) uint64 {
+	return miveparams.L1StateGas + uint64(len(input)+31)/32*miveparams.L1StateWordGas
+}
+
+// Run implements Precompile.
+func (p *l1StatePrecompile) Run(evm *vm.EVM, caller vm.ContractRef, input []byte, value *big.Int, readOnly bool) ([]byte, error) {
+	args, err := l1StateInput.Unpack(input)
+	if err != nil {
+		return nil, errL1StateInput
+	}
+	var (
+		number       = args[0].(*big.Int)
+		account      = args[1].(common.Address)
+		slot         = args[2].([32]byte)
+		accountProof = args[3].([][]byte)
+		storageProof = args[4].([][]byte)
+	)
+	// The L1 block has to be among the recent ones, the current one included
+	head := evm.Context.BlockNumber
+	if number.Cmp(head) > 0 || new(big.Int).Sub(head, number).Cmp(big.NewInt(miveparams.L1StateRootHistory)) >= 0 {
+		return nil, errL1StateBlock
+	}
+	root := evm.StateDB.GetState(miveparams.L1BlockAddress, l1StateRootSlot(number.Uint64()))
+	if root == (common.Hash{}) {
+		return nil, errL1StateBlock
+	}
+	enc, err := trie.VerifyProof(root, crypto.Keccak256(account[:]), proofDB(accountProof))
+	if err != nil {
+		return nil, errL1StateAccount
+	}
+	var (
+		acc  = types.StateAccount{Balance: new(big.Int)}
+		word common.Hash
+	)
+	if enc != nil {
+		if err := rlp.DecodeBytes(enc, &acc); err != nil {
+			return nil, errL1StateAccount
+		}
+	}
+	// The storage of an account without storage isn't proven, its slots are
+	// all zero and eth_getProof returns empty storage proofs for it.
+	if enc != nil && acc.Root != types.EmptyRootHash {
+		enc, err := trie.VerifyProof(acc.Root, crypto.Keccak256(slot[:]), proofDB(storageProof))
+		if err != nil {
+			return nil, errL1StateStorage
+		}
+		if enc != nil {
+			_, content, _, err := rlp.Split(enc)
+			if err != nil {
+				return nil, errL1StateStorage
+			}
+			word.SetBytes(content)
+		}
+	}
+	return l1StateOutput.Pack(new(big.Int).SetUint64(acc.Nonce), acc.Balance, common.BytesToHash(acc.CodeHash), acc.Root, word)
+}
+
+// proofDB returns the given trie proof nodes, indexed by hash.
+func proofDB(proof [][]byte) *memorydb.Database {
+	db := memorydb.New()
+	for _, node := range proof {
+		db.Put(crypto.Keccak256(node), node)
+	}
+	return db
+}
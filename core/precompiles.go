@@ -0,0 +1,116 @@
+package core
+
+import (
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	miveparams "github.com/ethereum-mive/mive/params"
+)
+
+// Precompile is a precompiled contract specific to Mive, running with access
+// to the EVM calling it.
+type Precompile interface {
+	RequiredGas(input []byte) uint64                                                                     // RequiredGas calculates the contract gas use
+	Run(evm *vm.EVM, caller vm.ContractRef, input []byte, value *big.Int, readOnly bool) ([]byte, error) // Run runs the precompiled contract
+}
+
+// precompile is a Mive precompile registered under a name, activated at the
+// block scheduled for the name in the chain configuration.
+type precompile struct {
+	name     string
+	address  common.Address
+	contract Precompile
+}
+
+var (
+	precompiles   = make(map[string]*precompile)
+	precompilesMu sync.Mutex
+
+	// installed is the set of the precompiles added to the EVM. The EVM has a
+	// single global set of extra precompiles, holding the ones scheduled by any
+	// chain of the process: each of them is only run on the EVMs of the chains
+	// activating it, see precompile.Run.
+	installed = make(map[string]bool)
+)
+
+// RegisterPrecompile registers a Mive precompile under the given name, at the
+// given address. It's activated by scheduling the name in the chain
+// configuration. It panics if the name or the address is already taken, or if
+// the precompiles were already installed.
+func RegisterPrecompile(name string, address common.Address, contract Precompile) {
+	precompilesMu.Lock()
+	defer precompilesMu.Unlock()
+
+	if len(installed) > 0 {
+		panic(fmt.Sprintf("precompile %s registered after installation", name))
+	}
+	if _, ok := precompiles[name]; ok {
+		panic(fmt.Sprintf("precompile %s registered twice", name))
+	}
+	for _, p := range precompiles {
+		if p.address == address {
+			panic(fmt.Sprintf("precompile %s registered at the address of %s", name, p.name))
+		}
+	}
+	precompiles[name] = &precompile{name: name, address: address, contract: contract}
+}
+
+// InstallPrecompiles adds the Mive precompiles scheduled in the given chain
+// configuration to the EVM, if not yet added for another chain. The precompiles
+// no chain schedules are left out, so they aren't warm nor charged for.
+//
+// The EVM can't leave a precompile out of its set, nor out of the access list,
+// until the block activating it: before it, the address would be warm and its
+// gas charged, so that scheduling a precompile would change the execution of the
+// blocks preceding its activation. The precompiles are thus only accepted from
+// the genesis block on.
+func InstallPrecompiles(config *miveparams.ChainConfig) error {
+	precompilesMu.Lock()
+	defer precompilesMu.Unlock()
+
+	names := make([]string, 0, len(config.Mive.Precompiles))
+	for name, block := range config.Mive.Precompiles {
+		if _, ok := precompiles[name]; !ok {
+			return fmt.Errorf("unknown precompile %s scheduled", name)
+		}
+		if block != nil && !config.IsPrecompile(name, config.Mive.GenesisBlock) {
+			return fmt.Errorf("precompile %s scheduled at block %v, after the genesis block %v", name, block, config.Mive.GenesisBlock)
+		}
+		if block != nil && !installed[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		vm.AddPrecompiledContracts(precompiles[name])
+		installed[name] = true
+	}
+	return nil
+}
+
+// Address implements vm.ContractRef, returning the address of the precompile.
+func (p *precompile) Address() common.Address {
+	return p.address
+}
+
+// RequiredGas implements vm.ExtraPrecompiledContract.
+func (p *precompile) RequiredGas(input []byte) uint64 {
+	return p.contract.RequiredGas(input)
+}
+
+// Run implements vm.ExtraPrecompiledContract, running the precompile if the
+// chain of the EVM activated it. Otherwise, i.e. on the chains of the process
+// not scheduling it, it returns nothing like an account without code, only
+// charging its gas.
+func (p *precompile) Run(evm *vm.EVM, caller vm.ContractRef, input []byte, value *big.Int, readOnly bool) ([]byte, error) {
+	config := miveparams.ChainConfigOf(evm.ChainConfig())
+	if config == nil || !config.IsPrecompile(p.name, evm.Context.BlockNumber) {
+		return nil, nil
+	}
+	return p.contract.Run(evm, caller, input, value, readOnly)
+}
@@ -0,0 +1,119 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	miveparams "github.com/ethereum-mive/mive/params"
+)
+
+var (
+	// The EIP-1967 slots of a proxy storing the address of its implementation
+	// and of its admin.
+	proxyImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	proxyAdminSlot          = common.HexToHash("0xb53127684a568b3173ae13b9f8a6016e243e63b6e8ee1178d6a717850b5d6103")
+)
+
+// L1BlockPredeploy is the L1 block contract, deployed at the L1 block
+// attributes fork.
+var L1BlockPredeploy = &Predeploy{
+	Name:    "l1Block",
+	Address: miveparams.L1BlockAddress,
+	Code:    miveparams.L1BlockCode,
+}
+
+// Predeploy is a named system contract deployed at a fixed address, from the
+// genesis block or at a fork, e.g. the beacon interpreter, the withdrawal
+// portal or a fee vault.
+type Predeploy struct {
+	Name    string                      `json:"name"`
+	Address common.Address              `json:"address"`
+	Code    hexutil.Bytes               `json:"code"`
+	Storage map[common.Hash]common.Hash `json:"storage,omitempty"` // Storage template, written over the storage of the contract
+	Proxy   *PredeployProxy             `json:"proxy,omitempty"`   // Proxy the contract is deployed behind, nil if none
+}
+
+// PredeployProxy is an EIP-1967 proxy a predeploy is deployed behind, at the
+// address of the predeploy, letting its admin upgrade the contract. The storage
+// template of the predeploy is written to the proxy, which owns the storage.
+type PredeployProxy struct {
+	Code           hexutil.Bytes  `json:"code"`
+	Admin          common.Address `json:"admin"`
+	Implementation common.Address `json:"implementation,omitempty"` // Address the contract is deployed at, derived from the name if zero
+}
+
+// ImplementationAddress returns the address the contract of the predeploy is
+// deployed at: the address of the predeploy itself without a proxy.
+func (p *Predeploy) ImplementationAddress() common.Address {
+	switch {
+	case p.Proxy == nil:
+		return p.Address
+	case p.Proxy.Implementation != (common.Address{}):
+		return p.Proxy.Implementation
+	default:
+		return common.BytesToAddress(crypto.Keccak256([]byte("mive.predeploy." + p.Name)))
+	}
+}
+
+// accounts returns the accounts of the predeploy as allocated at genesis, the
+// proxy and its implementation for a proxied predeploy.
+func (p *Predeploy) accounts() GenesisAlloc {
+	storage := make(map[common.Hash]common.Hash, len(p.Storage)+2)
+	for key, value := range p.Storage {
+		storage[key] = value
+	}
+	if p.Proxy == nil {
+		return GenesisAlloc{p.Address: {Code: p.Code, Storage: storage}}
+	}
+	impl := p.ImplementationAddress()
+	storage[proxyImplementationSlot] = common.BytesToHash(impl[:])
+	storage[proxyAdminSlot] = common.BytesToHash(p.Proxy.Admin[:])
+	return GenesisAlloc{
+		p.Address: {Code: p.Proxy.Code, Storage: storage},
+		impl:      {Code: p.Code},
+	}
+}
+
+// Apply deploys the predeploy in the given state: it sets the code of its
+// accounts and writes its storage template, leaving the rest of the storage
+// and the balances as they are. Applied at a fork, it regenerates the system
+// contract, upgrading its code.
+func (p *Predeploy) Apply(statedb *state.StateDB) {
+	for addr, account := range p.accounts() {
+		statedb.SetCode(addr, account.Code)
+		for key, value := range account.Storage {
+			statedb.SetState(addr, key, value)
+		}
+	}
+}
+
+// alloc returns the genesis allocation with the predeploys added. Predeploys
+// can't be allocated explicitly as well.
+func (g *Genesis) alloc() (GenesisAlloc, error) {
+	if len(g.Predeploys) == 0 {
+		return g.Alloc, nil
+	}
+	alloc := make(GenesisAlloc, len(g.Alloc))
+	for addr, account := range g.Alloc {
+		alloc[addr] = account
+	}
+	names := make(map[string]bool)
+	for _, p := range g.Predeploys {
+		if names[p.Name] {
+			return nil, fmt.Errorf("predeploy %s specified twice", p.Name)
+		}
+		names[p.Name] = true
+
+		for addr, account := range p.accounts() {
+			if _, ok := alloc[addr]; ok {
+				return nil, fmt.Errorf("predeploy %s conflicts with account %v", p.Name, addr)
+			}
+			alloc[addr] = account
+		}
+	}
+	return alloc, nil
+}
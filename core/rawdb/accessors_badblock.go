@@ -0,0 +1,70 @@
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/exp/slices"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+// badBlockToKeep is the maximum number of bad blocks kept in the database.
+const badBlockToKeep = 10
+
+// ReadAllBadBlocks retrieves all the bad blocks in the database, sorted in
+// reverse order by number.
+func ReadAllBadBlocks(db ethdb.KeyValueReader) []*mivetypes.BadBlock {
+	blob, err := db.Get(badBlockKey)
+	if err != nil {
+		return nil
+	}
+	var badBlocks []*mivetypes.BadBlock
+	if err := rlp.DecodeBytes(blob, &badBlocks); err != nil {
+		log.Error("Invalid bad blocks RLP", "err", err)
+		return nil
+	}
+	return badBlocks
+}
+
+// WriteBadBlock serializes the bad block into the database. If the cumulated
+// bad blocks exceed the limitation, the oldest ones are dropped.
+func WriteBadBlock(db ethdb.KeyValueStore, block *mivetypes.BadBlock) {
+	blob, err := db.Get(badBlockKey)
+	if err != nil {
+		log.Warn("Failed to load old bad blocks", "error", err)
+	}
+	var badBlocks []*mivetypes.BadBlock
+	if len(blob) > 0 {
+		if err := rlp.DecodeBytes(blob, &badBlocks); err != nil {
+			log.Crit("Failed to decode old bad blocks", "error", err)
+		}
+	}
+	// The same L1 block may fail repeatedly while derivation is stalled, only
+	// keep its latest failure.
+	badBlocks = slices.DeleteFunc(badBlocks, func(b *mivetypes.BadBlock) bool {
+		return b.Origin.Hash() == block.Origin.Hash()
+	})
+	badBlocks = append(badBlocks, block)
+	slices.SortFunc(badBlocks, func(a, b *mivetypes.BadBlock) int {
+		// Note: sorting in descending number order.
+		return -a.Origin.Number().Cmp(b.Origin.Number())
+	})
+	if len(badBlocks) > badBlockToKeep {
+		badBlocks = badBlocks[:badBlockToKeep]
+	}
+	data, err := rlp.EncodeToBytes(badBlocks)
+	if err != nil {
+		log.Crit("Failed to encode bad blocks", "err", err)
+	}
+	if err := db.Put(badBlockKey, data); err != nil {
+		log.Crit("Failed to write bad blocks", "err", err)
+	}
+}
+
+// DeleteBadBlocks deletes all the bad blocks from the database.
+func DeleteBadBlocks(db ethdb.KeyValueWriter) {
+	if err := db.Delete(badBlockKey); err != nil {
+		log.Crit("Failed to delete bad blocks", "err", err)
+	}
+}
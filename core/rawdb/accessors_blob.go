@@ -0,0 +1,43 @@
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// HasBlobSidecar verifies the existence of the archived blobs of an L1
+// transaction.
+func HasBlobSidecar(db ethdb.KeyValueReader, hash common.Hash) bool {
+	has, _ := db.Has(blobSidecarKey(hash))
+	return has
+}
+
+// ReadBlobSidecar retrieves the archived blobs of an L1 transaction by its hash.
+func ReadBlobSidecar(db ethdb.KeyValueReader, hash common.Hash) *types.BlobTxSidecar {
+	data, _ := db.Get(blobSidecarKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	sidecar := new(types.BlobTxSidecar)
+	if err := rlp.DecodeBytes(data, sidecar); err != nil {
+		log.Error("Invalid blob sidecar RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return sidecar
+}
+
+// WriteBlobSidecar archives the blobs of an L1 transaction. Unlike the beacon
+// nodes, which only serve blobs for a limited time, the archive keeps them for
+// as long as the blocks including the transaction may have to be re-executed.
+func WriteBlobSidecar(db ethdb.KeyValueWriter, hash common.Hash, sidecar *types.BlobTxSidecar) {
+	data, err := rlp.EncodeToBytes(sidecar)
+	if err != nil {
+		log.Crit("Failed to RLP encode blob sidecar", "err", err)
+	}
+	if err := db.Put(blobSidecarKey(hash), data); err != nil {
+		log.Crit("Failed to store blob sidecar", "err", err)
+	}
+}
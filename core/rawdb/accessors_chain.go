@@ -1,8 +1,12 @@
 package rawdb
 
 import (
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
@@ -10,9 +14,28 @@ import (
 	mivetypes "github.com/ethereum-mive/mive/core/types"
 )
 
+// ReadHeaderRLP retrieves a block header in its raw RLP database encoding.
+func ReadHeaderRLP(db ethdb.Reader, hash common.Hash, number uint64) rlp.RawValue {
+	var data []byte
+	db.ReadAncients(func(reader ethdb.AncientReaderOp) error {
+		// First try to look up the data in ancient database. Unlike in Ethereum,
+		// Mive headers are not identified by the hash of their encoding, so the
+		// hash is checked against the canonical hash instead.
+		if h, _ := reader.Ancient(rawdb.ChainFreezerHashTable, number); common.BytesToHash(h) == hash {
+			if data, _ = reader.Ancient(rawdb.ChainFreezerHeaderTable, number); len(data) > 0 {
+				return nil
+			}
+		}
+		// If not, try reading from leveldb
+		data, _ = db.Get(rawdb.HeaderKey(number, hash))
+		return nil
+	})
+	return data
+}
+
 // ReadHeader retrieves the block header corresponding to the hash.
 func ReadHeader(db ethdb.Reader, hash common.Hash, number uint64) *mivetypes.Header {
-	data := rawdb.ReadHeaderRLP(db, hash, number)
+	data := ReadHeaderRLP(db, hash, number)
 	if len(data) == 0 {
 		return nil
 	}
@@ -45,6 +68,33 @@ func WriteHeader(db ethdb.KeyValueWriter, header *mivetypes.Header) {
 	}
 }
 
+// FindCommonAncestor returns the last common ancestor of two Mive block headers.
+func FindCommonAncestor(db ethdb.Reader, a, b *mivetypes.Header) *mivetypes.Header {
+	for bn := b.Number.Uint64(); a.Number.Uint64() > bn; {
+		a = ReadHeader(db, a.ParentHash, a.Number.Uint64()-1)
+		if a == nil {
+			return nil
+		}
+	}
+	for an := a.Number.Uint64(); an < b.Number.Uint64(); {
+		b = ReadHeader(db, b.ParentHash, b.Number.Uint64()-1)
+		if b == nil {
+			return nil
+		}
+	}
+	for a.Hash != b.Hash {
+		a = ReadHeader(db, a.ParentHash, a.Number.Uint64()-1)
+		if a == nil {
+			return nil
+		}
+		b = ReadHeader(db, b.ParentHash, b.Number.Uint64()-1)
+		if b == nil {
+			return nil
+		}
+	}
+	return a
+}
+
 // ReadHeadHeader returns the current canonical head header.
 func ReadHeadHeader(db ethdb.Reader) *mivetypes.Header {
 	headHeaderHash := rawdb.ReadHeadHeaderHash(db)
@@ -57,3 +107,149 @@ func ReadHeadHeader(db ethdb.Reader) *mivetypes.Header {
 	}
 	return ReadHeader(db, headHeaderHash, *headHeaderNumber)
 }
+
+// ReadHeadBlock returns the current canonical head block.
+func ReadHeadBlock(db ethdb.Reader) *mivetypes.Block {
+	headBlockHash := rawdb.ReadHeadBlockHash(db)
+	if headBlockHash == (common.Hash{}) {
+		return nil
+	}
+	headBlockNumber := rawdb.ReadHeaderNumber(db, headBlockHash)
+	if headBlockNumber == nil {
+		return nil
+	}
+	return ReadBlock(db, headBlockHash, *headBlockNumber)
+}
+
+// ReadReceipts retrieves all the transaction receipts belonging to a Mive block,
+// including the metadata fields derived from the block body.
+func ReadReceipts(db ethdb.Reader, hash common.Hash, number uint64) types.Receipts {
+	receipts := rawdb.ReadRawReceipts(db, hash, number)
+	if receipts == nil {
+		return nil
+	}
+	body := ReadBody(db, hash, number)
+	if body == nil {
+		log.Error("Missing body but have receipt", "hash", hash, "number", number)
+		return nil
+	}
+	if len(body.Transactions) != len(receipts) {
+		log.Error("Transaction and receipt count mismatch", "hash", hash, "number", number, "txs", len(body.Transactions), "receipts", len(receipts))
+		return nil
+	}
+	var logIndex uint
+	for i, receipt := range receipts {
+		tx := body.Transactions[i]
+
+		receipt.TxHash = tx.Hash()
+		receipt.BlockHash = hash
+		receipt.BlockNumber = new(big.Int).SetUint64(number)
+		receipt.TransactionIndex = uint(i)
+
+		// The contract address can be derived from the transaction itself
+		if tx.Tx.To == nil {
+			receipt.ContractAddress = crypto.CreateAddress(tx.From, tx.Nonce)
+		}
+
+		if i == 0 {
+			receipt.GasUsed = receipt.CumulativeGasUsed
+		} else {
+			receipt.GasUsed = receipt.CumulativeGasUsed - receipts[i-1].CumulativeGasUsed
+		}
+		for _, log := range receipt.Logs {
+			log.BlockNumber = number
+			log.BlockHash = hash
+			log.TxHash = receipt.TxHash
+			log.TxIndex = uint(i)
+			log.Index = logIndex
+			logIndex++
+		}
+	}
+	return receipts
+}
+
+// ReadBody retrieves the body of the Mive block corresponding to the hash.
+func ReadBody(db ethdb.Reader, hash common.Hash, number uint64) *mivetypes.Body {
+	data := rawdb.ReadBodyRLP(db, hash, number)
+	if len(data) == 0 {
+		return nil
+	}
+	body := new(mivetypes.Body)
+	if err := rlp.DecodeBytes(data, body); err != nil {
+		log.Error("Invalid block body RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return body
+}
+
+// WriteBody stores the body of a Mive block into the database.
+func WriteBody(db ethdb.KeyValueWriter, hash common.Hash, number uint64, body *mivetypes.Body) {
+	data, err := rlp.EncodeToBytes(body)
+	if err != nil {
+		log.Crit("Failed to RLP encode body", "err", err)
+	}
+	rawdb.WriteBodyRLP(db, hash, number, data)
+}
+
+// ReadRejections retrieves the records of the beacon transactions that were
+// rejected while deriving the Mive block corresponding to the hash.
+func ReadRejections(db ethdb.KeyValueReader, hash common.Hash, number uint64) mivetypes.Rejections {
+	data, _ := db.Get(rejectionsKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var rejections mivetypes.Rejections
+	if err := rlp.DecodeBytes(data, &rejections); err != nil {
+		log.Error("Invalid rejections RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return rejections
+}
+
+// WriteRejections stores the records of the beacon transactions that were
+// rejected while deriving a Mive block.
+func WriteRejections(db ethdb.KeyValueWriter, hash common.Hash, number uint64, rejections mivetypes.Rejections) {
+	data, err := rlp.EncodeToBytes(rejections)
+	if err != nil {
+		log.Crit("Failed to RLP encode rejections", "err", err)
+	}
+	if err := db.Put(rejectionsKey(number, hash), data); err != nil {
+		log.Crit("Failed to store rejections", "err", err)
+	}
+}
+
+// DeleteRejections removes the rejection records associated with a Mive block.
+func DeleteRejections(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(rejectionsKey(number, hash)); err != nil {
+		log.Crit("Failed to delete rejections", "err", err)
+	}
+}
+
+// ReadBlock retrieves an entire Mive block corresponding to the hash, assembling
+// it back from the stored header and body. If either the header or body could
+// not be retrieved nil is returned.
+func ReadBlock(db ethdb.Reader, hash common.Hash, number uint64) *mivetypes.Block {
+	header := ReadHeader(db, hash, number)
+	if header == nil {
+		return nil
+	}
+	body := ReadBody(db, hash, number)
+	if body == nil {
+		return nil
+	}
+	return mivetypes.NewBlock(header, body)
+}
+
+// WriteBlock serializes a Mive block into the database, header and body separately.
+func WriteBlock(db ethdb.KeyValueWriter, block *mivetypes.Block) {
+	WriteBody(db, block.Hash(), block.NumberU64(), block.Body())
+	WriteHeader(db, block.Header())
+}
+
+// DeleteBlock removes all Mive block data associated with a hash.
+func DeleteBlock(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	rawdb.DeleteReceipts(db, hash, number)
+	DeleteRejections(db, hash, number)
+	rawdb.DeleteHeader(db, hash, number)
+	rawdb.DeleteBody(db, hash, number)
+}
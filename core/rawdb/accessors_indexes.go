@@ -0,0 +1,130 @@
+package rawdb
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+// WriteTxLookupEntriesByBody stores a positional metadata for every Mive
+// transaction in a block body, enabling hash based transaction and receipt
+// lookups.
+func WriteTxLookupEntriesByBody(db ethdb.KeyValueWriter, number uint64, body *mivetypes.Body) {
+	rawdb.WriteTxLookupEntries(db, number, mivetypes.Transactions(body.Transactions).Hashes())
+}
+
+// ReadTransaction retrieves a specific Mive transaction from the database, along
+// with its added positional metadata.
+func ReadTransaction(db ethdb.Reader, hash common.Hash) (*mivetypes.Transaction, common.Hash, uint64, uint64) {
+	blockNumber := rawdb.ReadTxLookupEntry(db, hash)
+	if blockNumber == nil {
+		return nil, common.Hash{}, 0, 0
+	}
+	blockHash := rawdb.ReadCanonicalHash(db, *blockNumber)
+	if blockHash == (common.Hash{}) {
+		return nil, common.Hash{}, 0, 0
+	}
+	body := ReadBody(db, blockHash, *blockNumber)
+	if body == nil {
+		log.Error("Transaction referenced missing", "number", *blockNumber, "hash", blockHash)
+		return nil, common.Hash{}, 0, 0
+	}
+	for txIndex, tx := range body.Transactions {
+		if tx.Hash() == hash {
+			return tx, blockHash, *blockNumber, uint64(txIndex)
+		}
+	}
+	log.Error("Transaction not found", "number", *blockNumber, "hash", blockHash, "txhash", hash)
+	return nil, common.Hash{}, 0, 0
+}
+
+// ReadReceipt retrieves a specific Mive transaction receipt from the database,
+// along with its added positional metadata.
+func ReadReceipt(db ethdb.Reader, hash common.Hash) (*types.Receipt, common.Hash, uint64, uint64) {
+	tx, blockHash, blockNumber, txIndex := ReadTransaction(db, hash)
+	if tx == nil {
+		return nil, common.Hash{}, 0, 0
+	}
+	receipts := ReadReceipts(db, blockHash, blockNumber)
+	if uint64(len(receipts)) <= txIndex {
+		log.Error("Receipt not found", "number", blockNumber, "hash", blockHash, "txhash", hash)
+		return nil, common.Hash{}, 0, 0
+	}
+	return receipts[txIndex], blockHash, blockNumber, txIndex
+}
+
+// ReadOriginLookupEntry retrieves the number of the Mive block in which the
+// beacon transaction with the given L1 hash was processed.
+func ReadOriginLookupEntry(db ethdb.Reader, hash common.Hash) *uint64 {
+	data, _ := db.Get(originLookupKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	number := new(big.Int).SetBytes(data).Uint64()
+	return &number
+}
+
+// writeOriginLookupEntry stores a positional metadata for a beacon transaction.
+func writeOriginLookupEntry(db ethdb.KeyValueWriter, hash common.Hash, numberBytes []byte) {
+	if err := db.Put(originLookupKey(hash), numberBytes); err != nil {
+		log.Crit("Failed to store origin lookup entry", "err", err)
+	}
+}
+
+// WriteOriginLookupEntries stores a positional metadata for every beacon
+// transaction processed in a Mive block, whether it produced a Mive transaction
+// or got rejected, enabling lookups by the hash of the L1 transaction.
+func WriteOriginLookupEntries(db ethdb.KeyValueWriter, number uint64, body *mivetypes.Body, rejections mivetypes.Rejections) {
+	numberBytes := new(big.Int).SetUint64(number).Bytes()
+	for _, tx := range body.Transactions {
+		writeOriginLookupEntry(db, tx.Origin, numberBytes)
+	}
+	for _, rejection := range rejections {
+		writeOriginLookupEntry(db, rejection.Origin, numberBytes)
+	}
+}
+
+// DeleteOriginLookupEntry removes the positional metadata of a beacon transaction.
+func DeleteOriginLookupEntry(db ethdb.KeyValueWriter, hash common.Hash) {
+	if err := db.Delete(originLookupKey(hash)); err != nil {
+		log.Crit("Failed to delete origin lookup entry", "err", err)
+	}
+}
+
+// ReadTransactionByOrigin resolves the beacon transaction with the given L1 hash
+// to the outcome of its processing on the canonical Mive chain: either the Mive
+// transaction it produced, or the record of its rejection. The hash and number
+// of the Mive block are returned along with it.
+func ReadTransactionByOrigin(db ethdb.Reader, hash common.Hash) (*mivetypes.Transaction, *mivetypes.Rejection, common.Hash, uint64) {
+	blockNumber := ReadOriginLookupEntry(db, hash)
+	if blockNumber == nil {
+		return nil, nil, common.Hash{}, 0
+	}
+	blockHash := rawdb.ReadCanonicalHash(db, *blockNumber)
+	if blockHash == (common.Hash{}) {
+		return nil, nil, common.Hash{}, 0
+	}
+	body := ReadBody(db, blockHash, *blockNumber)
+	if body == nil {
+		log.Error("Transaction referenced missing", "number", *blockNumber, "hash", blockHash)
+		return nil, nil, common.Hash{}, 0
+	}
+	for _, tx := range body.Transactions {
+		if tx.Origin == hash {
+			return tx, nil, blockHash, *blockNumber
+		}
+	}
+	for _, rejection := range ReadRejections(db, blockHash, *blockNumber) {
+		if rejection.Origin == hash {
+			return nil, rejection, blockHash, *blockNumber
+		}
+	}
+	// The lookup entry is stale, the block was rewound
+	return nil, nil, common.Hash{}, 0
+}
@@ -0,0 +1,123 @@
+package rawdb
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// HasL1Block verifies the existence of a locally cached L1 block.
+func HasL1Block(db ethdb.KeyValueReader, hash common.Hash) bool {
+	has, _ := db.Has(l1BlockKey(hash))
+	return has
+}
+
+// ReadL1Block retrieves a locally cached L1 block by hash.
+func ReadL1Block(db ethdb.KeyValueReader, hash common.Hash) *types.Block {
+	data, _ := db.Get(l1BlockKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(data, block); err != nil {
+		log.Error("Invalid L1 block RLP", "hash", hash, "err", err)
+		return nil
+	}
+	return block
+}
+
+// WriteL1Block stores an L1 block into the local cache.
+func WriteL1Block(db ethdb.KeyValueWriter, block *types.Block) {
+	data, err := rlp.EncodeToBytes(block)
+	if err != nil {
+		log.Crit("Failed to RLP encode L1 block", "err", err)
+	}
+	if err := db.Put(l1BlockKey(block.Hash()), data); err != nil {
+		log.Crit("Failed to store L1 block", "err", err)
+	}
+}
+
+// DeleteL1Block removes an L1 block from the local cache.
+func DeleteL1Block(db ethdb.KeyValueWriter, hash common.Hash) {
+	if err := db.Delete(l1BlockKey(hash)); err != nil {
+		log.Crit("Failed to delete L1 block", "err", err)
+	}
+}
+
+// ReadRawL1Receipts retrieves the locally cached receipts of an L1 block. The
+// receipt metadata fields are not guaranteed to be populated, so they should
+// not be used. Use ReadL1Receipts instead if the metadata is needed.
+func ReadRawL1Receipts(db ethdb.KeyValueReader, hash common.Hash) types.Receipts {
+	data, _ := db.Get(l1ReceiptsKey(hash))
+	if len(data) == 0 {
+		return nil
+	}
+	// Convert the receipts from their storage form to their internal representation
+	storageReceipts := []*types.ReceiptForStorage{}
+	if err := rlp.DecodeBytes(data, &storageReceipts); err != nil {
+		log.Error("Invalid L1 receipt array RLP", "hash", hash, "err", err)
+		return nil
+	}
+	receipts := make(types.Receipts, len(storageReceipts))
+	for i, storageReceipt := range storageReceipts {
+		receipts[i] = (*types.Receipt)(storageReceipt)
+	}
+	return receipts
+}
+
+// ReadL1Receipts retrieves the locally cached receipts of an L1 block, including
+// their metadata fields. The metadata is derived from the cached block, so nil
+// is returned if the block itself is not cached.
+func ReadL1Receipts(db ethdb.KeyValueReader, hash common.Hash, config *params.ChainConfig) types.Receipts {
+	receipts := ReadRawL1Receipts(db, hash)
+	if receipts == nil {
+		return nil
+	}
+	block := ReadL1Block(db, hash)
+	if block == nil {
+		log.Error("Missing L1 block but have receipts", "hash", hash)
+		return nil
+	}
+	baseFee := block.BaseFee()
+	if baseFee == nil {
+		baseFee = new(big.Int)
+	}
+	var blobGasPrice *big.Int
+	if excessBlobGas := block.ExcessBlobGas(); excessBlobGas != nil {
+		blobGasPrice = eip4844.CalcBlobFee(*excessBlobGas)
+	}
+	if err := receipts.DeriveFields(config, hash, block.NumberU64(), block.Time(), baseFee, blobGasPrice, block.Transactions()); err != nil {
+		log.Error("Failed to derive L1 receipts fields", "hash", hash, "number", block.NumberU64(), "err", err)
+		return nil
+	}
+	return receipts
+}
+
+// WriteL1Receipts stores the receipts of an L1 block into the local cache.
+func WriteL1Receipts(db ethdb.KeyValueWriter, hash common.Hash, receipts types.Receipts) {
+	// Convert the receipts into their storage form and serialize them
+	storageReceipts := make([]*types.ReceiptForStorage, len(receipts))
+	for i, receipt := range receipts {
+		storageReceipts[i] = (*types.ReceiptForStorage)(receipt)
+	}
+	bytes, err := rlp.EncodeToBytes(storageReceipts)
+	if err != nil {
+		log.Crit("Failed to encode L1 block receipts", "err", err)
+	}
+	if err := db.Put(l1ReceiptsKey(hash), bytes); err != nil {
+		log.Crit("Failed to store L1 block receipts", "err", err)
+	}
+}
+
+// DeleteL1Receipts removes the receipts of an L1 block from the local cache.
+func DeleteL1Receipts(db ethdb.KeyValueWriter, hash common.Hash) {
+	if err := db.Delete(l1ReceiptsKey(hash)); err != nil {
+		log.Crit("Failed to delete L1 block receipts", "err", err)
+	}
+}
@@ -1,6 +1,7 @@
 package rawdb
 
 import (
+	"encoding/binary"
 	"encoding/json"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -38,3 +39,30 @@ func WriteChainConfig(db ethdb.KeyValueWriter, hash common.Hash, cfg *params.Cha
 		log.Crit("Failed to store chain config", "err", err)
 	}
 }
+
+// ReadGenesisNumber retrieves the number of the Mive genesis block, which is
+// the L1 block the Mive chain was launched at.
+func ReadGenesisNumber(db ethdb.KeyValueReader) *uint64 {
+	data, _ := db.Get(genesisNumberKey)
+	if len(data) != 8 {
+		return nil
+	}
+	number := binary.BigEndian.Uint64(data)
+	return &number
+}
+
+// WriteGenesisNumber stores the number of the Mive genesis block.
+func WriteGenesisNumber(db ethdb.KeyValueWriter, number uint64) {
+	if err := db.Put(genesisNumberKey, encodeBlockNumber(number)); err != nil {
+		log.Crit("Failed to store genesis number", "err", err)
+	}
+}
+
+// WriteHealthProbe stores the time of the latest health check, probing whether
+// the database is still writable. Unlike the other writers, a failure is
+// returned instead of being fatal.
+func WriteHealthProbe(db ethdb.KeyValueWriter, time uint64) error {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, time)
+	return db.Put(healthProbeKey, enc)
+}
@@ -0,0 +1,43 @@
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+// ReadRelayedTxs retrieves the in-flight transactions of the given relayer
+// account.
+func ReadRelayedTxs(db ethdb.KeyValueReader, relayer common.Address) []*mivetypes.RelayedTx {
+	blob, err := db.Get(relayedTxsKey(relayer))
+	if err != nil {
+		return nil
+	}
+	var txs []*mivetypes.RelayedTx
+	if err := rlp.DecodeBytes(blob, &txs); err != nil {
+		log.Error("Invalid relayed transactions RLP", "relayer", relayer, "err", err)
+		return nil
+	}
+	return txs
+}
+
+// WriteRelayedTxs stores the in-flight transactions of the given relayer
+// account, replacing the previous ones. An empty list deletes the entry.
+func WriteRelayedTxs(db ethdb.KeyValueWriter, relayer common.Address, txs []*mivetypes.RelayedTx) {
+	if len(txs) == 0 {
+		if err := db.Delete(relayedTxsKey(relayer)); err != nil {
+			log.Crit("Failed to delete relayed transactions", "err", err)
+		}
+		return
+	}
+	data, err := rlp.EncodeToBytes(txs)
+	if err != nil {
+		log.Crit("Failed to encode relayed transactions", "err", err)
+	}
+	if err := db.Put(relayedTxsKey(relayer), data); err != nil {
+		log.Crit("Failed to write relayed transactions", "err", err)
+	}
+}
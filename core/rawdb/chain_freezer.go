@@ -0,0 +1,517 @@
+// Copyright 2022 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+const (
+	// freezerRecheckInterval is the frequency to check the key-value database for
+	// chain progression that might permit new blocks to be frozen into immutable
+	// storage.
+	freezerRecheckInterval = time.Minute
+
+	// freezerBatchLimit is the maximum number of blocks to freeze in one batch
+	// before doing an fsync and deleting it from the key-value store.
+	freezerBatchLimit = 30000
+
+	// freezerTableSize defines the maximum size of freezer data files.
+	freezerTableSize = 2 * 1000 * 1000 * 1000
+)
+
+// chainFreezerNoSnappy configures whether compression is disabled for the
+// ancient tables. The tables are named after their go-ethereum counterparts, so
+// that the go-ethereum accessors of block bodies, receipts and canonical hashes
+// can serve frozen Mive data as well. There's no total difficulty table, Mive
+// doesn't track it.
+var chainFreezerNoSnappy = map[string]bool{
+	rawdb.ChainFreezerHeaderTable:  false,
+	rawdb.ChainFreezerHashTable:    true,
+	rawdb.ChainFreezerBodiesTable:  false,
+	rawdb.ChainFreezerReceiptTable: false,
+}
+
+// errOutOfBounds is returned if the item requested is not stored in the freezer.
+var errOutOfBounds = errors.New("out of bounds")
+
+// chainFreezer is a wrapper of freezer with additional chain freezing feature.
+// The background thread will keep moving ancient chain segments from key-value
+// database to flat files for saving space on live database.
+//
+// The Mive chain starts at the L1 block it was launched at rather than at zero,
+// so the items of the underlying freezer are shifted by the number of the Mive
+// genesis block: item n holds block offset+n. The offset is only known once the
+// genesis is committed, until then the freezer is empty.
+type chainFreezer struct {
+	threshold atomic.Uint64 // Number of recent blocks not to freeze (params.FullImmutabilityThreshold apart from tests)
+	offset    atomic.Uint64 // Number of the first Mive block
+
+	freezer  *rawdb.Freezer
+	readonly bool
+	quit     chan struct{}
+	wg       sync.WaitGroup
+	trigger  chan chan struct{} // Manual blocking freeze trigger, test determinism
+}
+
+// newChainFreezer initializes the freezer for ancient Mive chain data.
+func newChainFreezer(db ethdb.KeyValueStore, datadir string, namespace string, readonly bool) (*chainFreezer, error) {
+	freezer, err := rawdb.NewFreezer(datadir, namespace, readonly, freezerTableSize, chainFreezerNoSnappy)
+	if err != nil {
+		return nil, err
+	}
+	cf := chainFreezer{
+		freezer:  freezer,
+		readonly: readonly,
+		quit:     make(chan struct{}),
+		trigger:  make(chan chan struct{}),
+	}
+	cf.threshold.Store(params.FullImmutabilityThreshold)
+
+	if offset := ReadGenesisNumber(db); offset != nil {
+		cf.offset.Store(*offset)
+	} else if frozen, _ := freezer.Ancients(); frozen > 0 {
+		// The key-value store was wiped, but the freezer wasn't. Recover the
+		// offset from the first frozen header.
+		offset, err := freezerOffset(freezer)
+		if err != nil {
+			freezer.Close()
+			return nil, err
+		}
+		cf.offset.Store(offset)
+		if !readonly {
+			WriteGenesisNumber(db, offset)
+		}
+	}
+	return &cf, nil
+}
+
+// freezerOffset derives the number of the first Mive block from the first
+// header stored in the given non-empty freezer.
+func freezerOffset(freezer *rawdb.Freezer) (uint64, error) {
+	tail, err := freezer.Tail()
+	if err != nil {
+		return 0, err
+	}
+	data, err := freezer.Ancient(rawdb.ChainFreezerHeaderTable, tail)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve first header from ancient %v", err)
+	}
+	header := new(mivetypes.Header)
+	if err := rlp.DecodeBytes(data, header); err != nil {
+		return 0, fmt.Errorf("invalid first header in ancient %v", err)
+	}
+	return header.NumberU64() - tail, nil
+}
+
+// Close closes the chain freezer instance and terminates the background thread.
+func (f *chainFreezer) Close() error {
+	select {
+	case <-f.quit:
+	default:
+		close(f.quit)
+	}
+	f.wg.Wait()
+	return f.freezer.Close()
+}
+
+// HasAncient returns an indicator whether the specified ancient data exists
+// in the freezer.
+func (f *chainFreezer) HasAncient(kind string, number uint64) (bool, error) {
+	return (&offsetReader{f.freezer, f.offset.Load()}).HasAncient(kind, number)
+}
+
+// Ancient retrieves an ancient binary blob from the append-only immutable files.
+func (f *chainFreezer) Ancient(kind string, number uint64) ([]byte, error) {
+	return (&offsetReader{f.freezer, f.offset.Load()}).Ancient(kind, number)
+}
+
+// AncientRange retrieves multiple items in sequence, starting from the index 'start'.
+func (f *chainFreezer) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	return (&offsetReader{f.freezer, f.offset.Load()}).AncientRange(kind, start, count, maxBytes)
+}
+
+// Ancients returns the number of the first block not stored in the freezer.
+func (f *chainFreezer) Ancients() (uint64, error) {
+	return (&offsetReader{f.freezer, f.offset.Load()}).Ancients()
+}
+
+// Tail returns the number of the first block stored in the freezer.
+func (f *chainFreezer) Tail() (uint64, error) {
+	return (&offsetReader{f.freezer, f.offset.Load()}).Tail()
+}
+
+// AncientSize returns the ancient size of the specified category.
+func (f *chainFreezer) AncientSize(kind string) (uint64, error) {
+	return f.freezer.AncientSize(kind)
+}
+
+// ReadAncients runs the given read operation while ensuring that no writes take place
+// on the underlying freezer.
+func (f *chainFreezer) ReadAncients(fn func(ethdb.AncientReaderOp) error) error {
+	offset := f.offset.Load()
+	return f.freezer.ReadAncients(func(op ethdb.AncientReaderOp) error {
+		return fn(&offsetReader{op, offset})
+	})
+}
+
+// ModifyAncients runs the given write operation.
+func (f *chainFreezer) ModifyAncients(fn func(ethdb.AncientWriteOp) error) (int64, error) {
+	offset := f.offset.Load()
+	return f.freezer.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		return fn(&offsetWriter{op, offset})
+	})
+}
+
+// TruncateHead discards any recent data above the provided threshold number.
+// It returns the previous head number.
+func (f *chainFreezer) TruncateHead(number uint64) (uint64, error) {
+	offset := f.offset.Load()
+	if number < offset {
+		number = offset
+	}
+	old, err := f.freezer.TruncateHead(number - offset)
+	return old + offset, err
+}
+
+// TruncateTail discards any recent data below the provided threshold number.
+// It returns the previous value
+func (f *chainFreezer) TruncateTail(number uint64) (uint64, error) {
+	offset := f.offset.Load()
+	if number < offset {
+		number = offset
+	}
+	old, err := f.freezer.TruncateTail(number - offset)
+	return old + offset, err
+}
+
+// Sync flushes all data tables to disk.
+func (f *chainFreezer) Sync() error {
+	return f.freezer.Sync()
+}
+
+// MigrateTable processes the entries in a given table in sequence
+// converting them to a new format if they're of an old format.
+func (f *chainFreezer) MigrateTable(kind string, convert func([]byte) ([]byte, error)) error {
+	return f.freezer.MigrateTable(kind, convert)
+}
+
+// freeze is a background thread that periodically checks the blockchain for any
+// import progress and moves ancient data from the fast database into the freezer.
+//
+// This functionality is deliberately broken off from block importing to avoid
+// incurring additional data shuffling delays on block propagation.
+func (f *chainFreezer) freeze(db ethdb.KeyValueStore) {
+	var (
+		backoff   bool
+		triggered chan struct{} // Used in tests
+		nfdb      = rawdb.NewDatabase(db)
+	)
+	timer := time.NewTimer(freezerRecheckInterval)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-f.quit:
+			log.Info("Freezer shutting down")
+			return
+		default:
+		}
+		if backoff {
+			// If we were doing a manual trigger, notify it
+			if triggered != nil {
+				triggered <- struct{}{}
+				triggered = nil
+			}
+			select {
+			case <-timer.C:
+				backoff = false
+				timer.Reset(freezerRecheckInterval)
+			case triggered = <-f.trigger:
+				backoff = false
+			case <-f.quit:
+				return
+			}
+		}
+		// The freezer can't be used until the first block of the chain is known
+		offset := ReadGenesisNumber(db)
+		if offset == nil {
+			log.Debug("Mive genesis unavailable") // new chain, empty database
+			backoff = true
+			continue
+		}
+		f.offset.Store(*offset)
+
+		// Retrieve the freezing threshold.
+		hash := rawdb.ReadHeadBlockHash(nfdb)
+		if hash == (common.Hash{}) {
+			log.Debug("Current full block hash unavailable") // new chain, empty database
+			backoff = true
+			continue
+		}
+		number := rawdb.ReadHeaderNumber(nfdb, hash)
+		threshold := f.threshold.Load()
+		frozen, _ := f.Ancients()
+		switch {
+		case number == nil:
+			log.Error("Current full block number unavailable", "hash", hash)
+			backoff = true
+			continue
+
+		case *number < *offset+threshold:
+			log.Debug("Current full block not old enough to freeze", "number", *number, "hash", hash, "delay", threshold)
+			backoff = true
+			continue
+
+		case *number-threshold <= frozen:
+			log.Debug("Ancient blocks frozen already", "number", *number, "hash", hash, "frozen", frozen)
+			backoff = true
+			continue
+		}
+		head := ReadHeader(nfdb, hash, *number)
+		if head == nil {
+			log.Error("Current full block unavailable", "number", *number, "hash", hash)
+			backoff = true
+			continue
+		}
+
+		// Seems we have data ready to be frozen, process in usable batches
+		var (
+			start = time.Now()
+			first = frozen
+			limit = *number - threshold
+		)
+		if limit-first > freezerBatchLimit {
+			limit = first + freezerBatchLimit
+		}
+		// A chain bootstrapped from a state snapshot has no history before its
+		// checkpoint, wait for it to be backfilled.
+		if rawdb.ReadCanonicalHash(nfdb, first) == (common.Hash{}) {
+			log.Debug("Chain history missing, can't freeze blocks", "number", first)
+			backoff = true
+			continue
+		}
+		ancients, err := f.freezeRange(nfdb, first, limit)
+		if err != nil {
+			log.Error("Error in block freeze operation", "err", err)
+			backoff = true
+			continue
+		}
+
+		// Batch of blocks have been frozen, flush them before wiping from leveldb
+		if err := f.Sync(); err != nil {
+			log.Crit("Failed to flush frozen tables", "err", err)
+		}
+
+		// Wipe out all data from the active database
+		batch := db.NewBatch()
+		for i := 0; i < len(ancients); i++ {
+			// Always keep the genesis block in active database
+			if first+uint64(i) != *offset {
+				rawdb.DeleteBlockWithoutNumber(batch, ancients[i], first+uint64(i))
+				rawdb.DeleteCanonicalHash(batch, first+uint64(i))
+			}
+		}
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to delete frozen canonical blocks", "err", err)
+		}
+		batch.Reset()
+
+		// Wipe out side chains also and track dangling side chains
+		var dangling []common.Hash
+		frozen, _ = f.Ancients() // Needs reload after during freezeRange
+		for number := first; number < frozen; number++ {
+			// Always keep the genesis block in active database
+			if number != *offset {
+				dangling = rawdb.ReadAllHashes(db, number)
+				for _, hash := range dangling {
+					log.Trace("Deleting side chain", "number", number, "hash", hash)
+					DeleteBlock(batch, hash, number)
+				}
+			}
+		}
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to delete frozen side blocks", "err", err)
+		}
+		batch.Reset()
+
+		// Step into the future and delete any dangling side chains
+		if frozen > *offset {
+			tip := frozen
+			for len(dangling) > 0 {
+				drop := make(map[common.Hash]struct{})
+				for _, hash := range dangling {
+					log.Debug("Dangling parent from Freezer", "number", tip-1, "hash", hash)
+					drop[hash] = struct{}{}
+				}
+				children := rawdb.ReadAllHashes(db, tip)
+				for i := 0; i < len(children); i++ {
+					// Dig up the child and ensure it's dangling
+					child := ReadHeader(nfdb, children[i], tip)
+					if child == nil {
+						log.Error("Missing dangling header", "number", tip, "hash", children[i])
+						continue
+					}
+					if _, ok := drop[child.ParentHash]; !ok {
+						children = append(children[:i], children[i+1:]...)
+						i--
+						continue
+					}
+					// Delete all block data associated with the child
+					log.Debug("Deleting dangling block", "number", tip, "hash", children[i], "parent", child.ParentHash)
+					DeleteBlock(batch, children[i], tip)
+				}
+				dangling = children
+				tip++
+			}
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to delete dangling side blocks", "err", err)
+			}
+		}
+
+		// Log something friendly for the user
+		context := []interface{}{
+			"blocks", frozen - first, "elapsed", common.PrettyDuration(time.Since(start)), "number", frozen - 1,
+		}
+		if n := len(ancients); n > 0 {
+			context = append(context, []interface{}{"hash", ancients[n-1]}...)
+		}
+		log.Debug("Deep froze chain segment", context...)
+
+		// Avoid database thrashing with tiny writes
+		if frozen-first < freezerBatchLimit {
+			backoff = true
+		}
+	}
+}
+
+func (f *chainFreezer) freezeRange(nfdb ethdb.Database, number, limit uint64) (hashes []common.Hash, err error) {
+	hashes = make([]common.Hash, 0, limit-number)
+
+	_, err = f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for ; number <= limit; number++ {
+			// Retrieve all the components of the canonical block.
+			hash := rawdb.ReadCanonicalHash(nfdb, number)
+			if hash == (common.Hash{}) {
+				return fmt.Errorf("canonical hash missing, can't freeze block %d", number)
+			}
+			header := ReadHeaderRLP(nfdb, hash, number)
+			if len(header) == 0 {
+				return fmt.Errorf("block header missing, can't freeze block %d", number)
+			}
+			body := rawdb.ReadBodyRLP(nfdb, hash, number)
+			if len(body) == 0 {
+				return fmt.Errorf("block body missing, can't freeze block %d", number)
+			}
+			receipts := rawdb.ReadReceiptsRLP(nfdb, hash, number)
+			if len(receipts) == 0 {
+				return fmt.Errorf("block receipts missing, can't freeze block %d", number)
+			}
+
+			// Write to the batch.
+			if err := op.AppendRaw(rawdb.ChainFreezerHashTable, number, hash[:]); err != nil {
+				return fmt.Errorf("can't write hash to Freezer: %v", err)
+			}
+			if err := op.AppendRaw(rawdb.ChainFreezerHeaderTable, number, header); err != nil {
+				return fmt.Errorf("can't write header to Freezer: %v", err)
+			}
+			if err := op.AppendRaw(rawdb.ChainFreezerBodiesTable, number, body); err != nil {
+				return fmt.Errorf("can't write body to Freezer: %v", err)
+			}
+			if err := op.AppendRaw(rawdb.ChainFreezerReceiptTable, number, receipts); err != nil {
+				return fmt.Errorf("can't write receipts to Freezer: %v", err)
+			}
+
+			hashes = append(hashes, hash)
+		}
+		return nil
+	})
+
+	return hashes, err
+}
+
+// offsetReader translates the block numbers of ancient reads into the item
+// numbers of the underlying freezer.
+type offsetReader struct {
+	ethdb.AncientReaderOp
+	offset uint64
+}
+
+func (r *offsetReader) HasAncient(kind string, number uint64) (bool, error) {
+	if number < r.offset {
+		return false, nil
+	}
+	return r.AncientReaderOp.HasAncient(kind, number-r.offset)
+}
+
+func (r *offsetReader) Ancient(kind string, number uint64) ([]byte, error) {
+	if number < r.offset {
+		return nil, errOutOfBounds
+	}
+	return r.AncientReaderOp.Ancient(kind, number-r.offset)
+}
+
+func (r *offsetReader) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	if start < r.offset {
+		return nil, errOutOfBounds
+	}
+	return r.AncientReaderOp.AncientRange(kind, start-r.offset, count, maxBytes)
+}
+
+func (r *offsetReader) Ancients() (uint64, error) {
+	items, err := r.AncientReaderOp.Ancients()
+	return items + r.offset, err
+}
+
+func (r *offsetReader) Tail() (uint64, error) {
+	tail, err := r.AncientReaderOp.Tail()
+	return tail + r.offset, err
+}
+
+// offsetWriter translates the block numbers of ancient writes into the item
+// numbers of the underlying freezer.
+type offsetWriter struct {
+	ethdb.AncientWriteOp
+	offset uint64
+}
+
+func (w *offsetWriter) Append(kind string, number uint64, item interface{}) error {
+	if number < w.offset {
+		return errOutOfBounds
+	}
+	return w.AncientWriteOp.Append(kind, number-w.offset, item)
+}
+
+func (w *offsetWriter) AppendRaw(kind string, number uint64, item []byte) error {
+	if number < w.offset {
+		return errOutOfBounds
+	}
+	return w.AncientWriteOp.AppendRaw(kind, number-w.offset, item)
+}
@@ -0,0 +1,381 @@
+// Copyright 2018 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/olekukonko/tablewriter"
+)
+
+// freezerdb is a database wrapper that enables freezer data retrievals.
+type freezerdb struct {
+	ancientRoot string
+	ethdb.KeyValueStore
+	ethdb.AncientStore
+}
+
+// AncientDatadir returns the path of root ancient directory.
+func (frdb *freezerdb) AncientDatadir() (string, error) {
+	return frdb.ancientRoot, nil
+}
+
+// Close implements io.Closer, closing both the fast key-value store as well as
+// the slow ancient tables.
+func (frdb *freezerdb) Close() error {
+	var errs []error
+	if err := frdb.AncientStore.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := frdb.KeyValueStore.Close(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) != 0 {
+		return fmt.Errorf("%v", errs)
+	}
+	return nil
+}
+
+// Freeze is a helper method used for external testing to trigger and block until
+// a freeze cycle completes, without having to sleep for a minute to trigger the
+// automatic background run.
+func (frdb *freezerdb) Freeze(threshold uint64) error {
+	if frdb.AncientStore.(*chainFreezer).readonly {
+		return errors.New("read only")
+	}
+	// Set the freezer threshold to a temporary value
+	defer func(old uint64) {
+		frdb.AncientStore.(*chainFreezer).threshold.Store(old)
+	}(frdb.AncientStore.(*chainFreezer).threshold.Load())
+	frdb.AncientStore.(*chainFreezer).threshold.Store(threshold)
+
+	// Trigger a freeze cycle and block until it's done
+	trigger := make(chan struct{}, 1)
+	frdb.AncientStore.(*chainFreezer).trigger <- trigger
+	<-trigger
+	return nil
+}
+
+// NewDatabaseWithFreezer creates a high level database on top of a given key-
+// value data store with a freezer moving immutable Mive chain segments into
+// cold storage. The Mive freezer is kept in its own directory within the given
+// ancient root, next to the go-ethereum ones.
+func NewDatabaseWithFreezer(db ethdb.KeyValueStore, ancient string, namespace string, readonly bool) (ethdb.Database, error) {
+	// Create the idle freezer instance
+	frdb, err := newChainFreezer(db, filepath.Join(ancient, "mive"), namespace, readonly)
+	if err != nil {
+		return nil, err
+	}
+	// The freezer only holds the ancient part of the chain, ensure there's no gap
+	// between it and the rest of the chain in the key-value store. Otherwise we
+	// might end up with a non-functional freezer.
+	if frozen, _ := frdb.Ancients(); frozen > frdb.offset.Load() {
+		kvdb := rawdb.NewDatabase(db)
+		if kvhash := rawdb.ReadCanonicalHash(kvdb, frozen); kvhash == (common.Hash{}) {
+			// Subsequent header after the freezer limit is missing from the database.
+			// Reject startup if the database has a more recent head.
+			if head := rawdb.ReadHeaderNumber(kvdb, rawdb.ReadHeadHeaderHash(kvdb)); head != nil && *head > frozen-1 {
+				frdb.Close()
+				return nil, fmt.Errorf("gap in the chain between ancients [#%d - #%d] and leveldb [#%d]", frdb.offset.Load(), frozen-1, *head)
+			}
+			// Database contains only older data than the freezer, this happens if the
+			// state was wiped and reinited from an existing freezer.
+		}
+	}
+	// Freezer is consistent with the key-value database, permit combining the two
+	if !frdb.readonly {
+		frdb.wg.Add(1)
+		go func() {
+			frdb.freeze(db)
+			frdb.wg.Done()
+		}()
+	}
+	return &freezerdb{
+		ancientRoot:   ancient,
+		KeyValueStore: db,
+		AncientStore:  frdb,
+	}, nil
+}
+
+// The go-ethereum schema prefixes and singleton keys, mirrored here to classify
+// the entries of the Mive chaindata as they aren't exported.
+var (
+	headerPrefix        = []byte("h")
+	headerHashSuffix    = []byte("n")
+	headerNumberPrefix  = []byte("H")
+	blockBodyPrefix     = []byte("b")
+	blockReceiptsPrefix = []byte("r")
+	txLookupPrefix      = []byte("l")
+	bloomBitsPrefix     = []byte("B")
+	stateIDPrefix       = []byte("L")
+	configPrefix        = []byte("ethereum-config-")
+	genesisPrefix       = []byte("ethereum-genesis-")
+
+	metadataKeys = [][]byte{
+		[]byte("DatabaseVersion"), []byte("LastHeader"), []byte("LastBlock"), []byte("LastFast"),
+		[]byte("LastFinalized"), []byte("LastPivot"), []byte("TrieSync"), []byte("SnapshotDisabled"),
+		rawdb.SnapshotRootKey, []byte("SnapshotJournal"), []byte("SnapshotGenerator"),
+		[]byte("SnapshotRecovery"), []byte("TransactionIndexTail"), []byte("FastTransactionLookupLimit"),
+		[]byte("unclean-shutdown"), []byte("InvalidBlock"), []byte("eth2-transition"),
+		[]byte("SkeletonSyncStatus"), []byte("LastStateID"), []byte("TrieJournal"),
+		[]byte("SnapshotSyncStatus"), []byte("SnapSyncStatus"), genesisNumberKey,
+	}
+)
+
+// stateFreezerTables lists the tables of the state history freezer maintained
+// by the path-based trie database.
+var stateFreezerTables = []string{"history.meta", "account.index", "storage.index", "account.data", "storage.data"}
+
+// stat stores sizes and count for a parameter
+type stat struct {
+	size  common.StorageSize
+	count uint64
+}
+
+// Add size to the stat and increase the counter by 1
+func (s *stat) Add(size common.StorageSize) {
+	s.size += size
+	s.count++
+}
+
+func (s *stat) Size() string {
+	return s.size.String()
+}
+
+func (s *stat) Count() string {
+	return fmt.Sprintf("%d", s.count)
+}
+
+// InspectDatabase traverses the entire Mive chaindata and checks the size of
+// all different categories of data, including the Mive specific ones.
+func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
+	it := db.NewIterator(keyPrefix, keyStart)
+	defer it.Release()
+
+	var (
+		count  int64
+		start  = time.Now()
+		logged = time.Now()
+
+		// Key-value store statistics
+		headers         stat
+		bodies          stat
+		receipts        stat
+		numHashPairings stat
+		hashNumPairings stat
+		txLookups       stat
+		originLookups   stat
+		rejections      stat
+		l1Blocks        stat
+		l1Receipts      stat
+		bloomBits       stat
+		legacyTries     stat
+		stateLookups    stat
+		accountTries    stat
+		storageTries    stat
+		codes           stat
+		accountSnaps    stat
+		storageSnaps    stat
+		preimages       stat
+
+		// Meta- and unaccounted data
+		metadata    stat
+		unaccounted stat
+
+		// Totals
+		total common.StorageSize
+	)
+	// Inspect key-value database first.
+	for it.Next() {
+		var (
+			key  = it.Key()
+			size = common.StorageSize(len(key) + len(it.Value()))
+		)
+		total += size
+		switch {
+		case bytes.HasPrefix(key, originLookupPrefix) && len(key) == (len(originLookupPrefix)+common.HashLength):
+			originLookups.Add(size)
+		case bytes.HasPrefix(key, rejectionsPrefix) && len(key) == (len(rejectionsPrefix)+8+common.HashLength):
+			rejections.Add(size)
+		case bytes.HasPrefix(key, l1BlockPrefix) && len(key) == (len(l1BlockPrefix)+common.HashLength):
+			l1Blocks.Add(size)
+		case bytes.HasPrefix(key, l1ReceiptsPrefix) && len(key) == (len(l1ReceiptsPrefix)+common.HashLength):
+			l1Receipts.Add(size)
+		case bytes.HasPrefix(key, headerPrefix) && len(key) == (len(headerPrefix)+8+common.HashLength):
+			headers.Add(size)
+		case bytes.HasPrefix(key, blockBodyPrefix) && len(key) == (len(blockBodyPrefix)+8+common.HashLength):
+			bodies.Add(size)
+		case bytes.HasPrefix(key, blockReceiptsPrefix) && len(key) == (len(blockReceiptsPrefix)+8+common.HashLength):
+			receipts.Add(size)
+		case bytes.HasPrefix(key, headerPrefix) && bytes.HasSuffix(key, headerHashSuffix):
+			numHashPairings.Add(size)
+		case bytes.HasPrefix(key, headerNumberPrefix) && len(key) == (len(headerNumberPrefix)+common.HashLength):
+			hashNumPairings.Add(size)
+		case rawdb.IsLegacyTrieNode(key, it.Value()):
+			legacyTries.Add(size)
+		case bytes.HasPrefix(key, stateIDPrefix) && len(key) == len(stateIDPrefix)+common.HashLength:
+			stateLookups.Add(size)
+		case rawdb.IsAccountTrieNode(key):
+			accountTries.Add(size)
+		case rawdb.IsStorageTrieNode(key):
+			storageTries.Add(size)
+		case bytes.HasPrefix(key, rawdb.CodePrefix) && len(key) == len(rawdb.CodePrefix)+common.HashLength:
+			codes.Add(size)
+		case bytes.HasPrefix(key, txLookupPrefix) && len(key) == (len(txLookupPrefix)+common.HashLength):
+			txLookups.Add(size)
+		case bytes.HasPrefix(key, rawdb.SnapshotAccountPrefix) && len(key) == (len(rawdb.SnapshotAccountPrefix)+common.HashLength):
+			accountSnaps.Add(size)
+		case bytes.HasPrefix(key, rawdb.SnapshotStoragePrefix) && len(key) == (len(rawdb.SnapshotStoragePrefix)+2*common.HashLength):
+			storageSnaps.Add(size)
+		case bytes.HasPrefix(key, rawdb.PreimagePrefix) && len(key) == (len(rawdb.PreimagePrefix)+common.HashLength):
+			preimages.Add(size)
+		case bytes.HasPrefix(key, configPrefix) && len(key) == (len(configPrefix)+common.HashLength):
+			metadata.Add(size)
+		case bytes.HasPrefix(key, genesisPrefix) && len(key) == (len(genesisPrefix)+common.HashLength):
+			metadata.Add(size)
+		case bytes.HasPrefix(key, bloomBitsPrefix) && len(key) == (len(bloomBitsPrefix)+10+common.HashLength):
+			bloomBits.Add(size)
+		case bytes.HasPrefix(key, rawdb.BloomBitsIndexPrefix):
+			bloomBits.Add(size)
+		default:
+			var accounted bool
+			for _, meta := range metadataKeys {
+				if bytes.Equal(key, meta) {
+					metadata.Add(size)
+					accounted = true
+					break
+				}
+			}
+			if !accounted {
+				unaccounted.Add(size)
+			}
+		}
+		count++
+		if count%1000 == 0 && time.Since(logged) > 8*time.Second {
+			log.Info("Inspecting database", "count", count, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := it.Error(); err != nil {
+		return err
+	}
+	// Display the database statistic of key-value store.
+	stats := [][]string{
+		{"Key-Value store", "Headers", headers.Size(), headers.Count()},
+		{"Key-Value store", "Bodies", bodies.Size(), bodies.Count()},
+		{"Key-Value store", "Receipt lists", receipts.Size(), receipts.Count()},
+		{"Key-Value store", "Rejection lists", rejections.Size(), rejections.Count()},
+		{"Key-Value store", "Block number->hash", numHashPairings.Size(), numHashPairings.Count()},
+		{"Key-Value store", "Block hash->number", hashNumPairings.Size(), hashNumPairings.Count()},
+		{"Key-Value store", "Transaction index", txLookups.Size(), txLookups.Count()},
+		{"Key-Value store", "L1 origin index", originLookups.Size(), originLookups.Count()},
+		{"Key-Value store", "L1 blocks", l1Blocks.Size(), l1Blocks.Count()},
+		{"Key-Value store", "L1 receipt lists", l1Receipts.Size(), l1Receipts.Count()},
+		{"Key-Value store", "Bloombit index", bloomBits.Size(), bloomBits.Count()},
+		{"Key-Value store", "Contract codes", codes.Size(), codes.Count()},
+		{"Key-Value store", "Hash trie nodes", legacyTries.Size(), legacyTries.Count()},
+		{"Key-Value store", "Path trie state lookups", stateLookups.Size(), stateLookups.Count()},
+		{"Key-Value store", "Path trie account nodes", accountTries.Size(), accountTries.Count()},
+		{"Key-Value store", "Path trie storage nodes", storageTries.Size(), storageTries.Count()},
+		{"Key-Value store", "Trie preimages", preimages.Size(), preimages.Count()},
+		{"Key-Value store", "Account snapshot", accountSnaps.Size(), accountSnaps.Count()},
+		{"Key-Value store", "Storage snapshot", storageSnaps.Size(), storageSnaps.Count()},
+		{"Key-Value store", "Singleton metadata", metadata.Size(), metadata.Count()},
+	}
+	// Inspect the append-only file stores then.
+	ancients, size, err := inspectFreezers(db)
+	if err != nil {
+		return err
+	}
+	stats = append(stats, ancients...)
+	total += size
+
+	table := tablewriter.NewWriter(os.Stdout)
+	table.SetHeader([]string{"Database", "Category", "Size", "Items"})
+	table.SetFooter([]string{"", "Total", total.String(), " "})
+	table.AppendBulk(stats)
+	table.Render()
+
+	if unaccounted.size > 0 {
+		log.Error("Database contains unaccounted data", "size", unaccounted.size, "count", unaccounted.count)
+	}
+	return nil
+}
+
+// inspectFreezers reports the storage size of every table of the Mive chain
+// freezer and, in path scheme, of the state history freezer, along with their
+// total size.
+func inspectFreezers(db ethdb.Database) ([][]string, common.StorageSize, error) {
+	var (
+		stats [][]string
+		total common.StorageSize
+	)
+	inspect := func(name string, tables []string, reader ethdb.AncientReader) error {
+		ancients, err := reader.Ancients()
+		if err != nil {
+			return err
+		}
+		tail, err := reader.Tail()
+		if err != nil {
+			return err
+		}
+		for _, table := range tables {
+			size, err := reader.AncientSize(table)
+			if err != nil {
+				return err
+			}
+			stats = append(stats, []string{
+				fmt.Sprintf("Ancient store (%s)", name),
+				strings.Title(table),
+				common.StorageSize(size).String(),
+				fmt.Sprintf("%d", ancients-tail),
+			})
+			total += common.StorageSize(size)
+		}
+		return nil
+	}
+	tables := []string{rawdb.ChainFreezerHeaderTable, rawdb.ChainFreezerHashTable, rawdb.ChainFreezerBodiesTable, rawdb.ChainFreezerReceiptTable}
+	if err := inspect("Chain", tables, db); err != nil {
+		return nil, 0, err
+	}
+	if rawdb.ReadStateScheme(db) == rawdb.PathScheme {
+		datadir, err := db.AncientDatadir()
+		if err != nil {
+			return nil, 0, err
+		}
+		f, err := rawdb.NewStateFreezer(datadir, true)
+		if err != nil {
+			return nil, 0, err
+		}
+		defer f.Close()
+
+		if err := inspect("State", stateFreezerTables, f); err != nil {
+			return nil, 0, err
+		}
+	}
+	return stats, total, nil
+}
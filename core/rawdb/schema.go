@@ -0,0 +1,65 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// The fields below define the low level database schema prefixing of the data
+// Mive stores on top of the go-ethereum schema.
+var (
+	// genesisNumberKey tracks the number of the first block of the Mive chain.
+	genesisNumberKey = []byte("mive-genesis-number")
+
+	l1BlockPrefix     = []byte("mive-l1-block-")    // l1BlockPrefix + hash -> L1 block
+	l1ReceiptsPrefix  = []byte("mive-l1-receipts-") // l1ReceiptsPrefix + hash -> L1 block receipts
+	blobSidecarPrefix = []byte("mive-blobs-")       // blobSidecarPrefix + L1 tx hash -> blob sidecar
+
+	rejectionsPrefix   = []byte("mive-rejections-") // rejectionsPrefix + num (uint64 big endian) + hash -> rejections
+	originLookupPrefix = []byte("mive-origin-")     // originLookupPrefix + L1 tx hash -> Mive block number
+	relayedTxsPrefix   = []byte("mive-relayed-")    // relayedTxsPrefix + relayer address -> in-flight relayed transactions
+
+	// badBlockKey tracks the list of bad blocks seen by the local derivation.
+	badBlockKey = []byte("mive-invalid-blocks")
+
+	// healthProbeKey tracks the time of the latest write probe of the health check.
+	healthProbeKey = []byte("mive-health-probe")
+)
+
+// encodeBlockNumber encodes a block number as big endian uint64
+func encodeBlockNumber(number uint64) []byte {
+	enc := make([]byte, 8)
+	binary.BigEndian.PutUint64(enc, number)
+	return enc
+}
+
+// l1BlockKey = l1BlockPrefix + hash
+func l1BlockKey(hash common.Hash) []byte {
+	return append(append([]byte{}, l1BlockPrefix...), hash.Bytes()...)
+}
+
+// l1ReceiptsKey = l1ReceiptsPrefix + hash
+func l1ReceiptsKey(hash common.Hash) []byte {
+	return append(append([]byte{}, l1ReceiptsPrefix...), hash.Bytes()...)
+}
+
+// blobSidecarKey = blobSidecarPrefix + hash
+func blobSidecarKey(hash common.Hash) []byte {
+	return append(append([]byte{}, blobSidecarPrefix...), hash.Bytes()...)
+}
+
+// rejectionsKey = rejectionsPrefix + num (uint64 big endian) + hash
+func rejectionsKey(number uint64, hash common.Hash) []byte {
+	return append(append(append([]byte{}, rejectionsPrefix...), encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// originLookupKey = originLookupPrefix + hash
+func originLookupKey(hash common.Hash) []byte {
+	return append(append([]byte{}, originLookupPrefix...), hash.Bytes()...)
+}
+
+// relayedTxsKey = relayedTxsPrefix + address
+func relayedTxsKey(relayer common.Address) []byte {
+	return append(append([]byte{}, relayedTxsPrefix...), relayer.Bytes()...)
+}
@@ -0,0 +1,378 @@
+// Package snapshot implements archives of the flat state snapshot of a Mive
+// block, used to bootstrap nodes from a checkpoint instead of deriving the whole
+// chain.
+//
+// An archive is a gzip compressed RLP stream. It starts with the checkpoint, the
+// Mive block the state belongs to (along with its receipts and rejections), whose
+// state root commits to the archived state. The accounts follow, sorted by hash,
+// each one followed by its storage slots in chunks terminated by an empty one.
+package snapshot
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/snapshot"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+const (
+	// archiveVersion is the version of the archive format.
+	archiveVersion = 1
+
+	// slotChunkSize is the maximum number of storage slots in a chunk.
+	slotChunkSize = 1024
+)
+
+var (
+	errArchiveVersion = errors.New("unsupported snapshot archive version")
+	errArchiveRoot    = errors.New("state root mismatch")
+)
+
+// Checkpoint is the Mive block a state snapshot archive is taken at.
+type Checkpoint struct {
+	Block      *mivetypes.Block
+	Receipts   []*types.ReceiptForStorage
+	Rejections mivetypes.Rejections
+}
+
+// Root returns the state root the archive commits to.
+func (c *Checkpoint) Root() common.Hash {
+	return c.Block.Root()
+}
+
+// archiveHeader is the first item of an archive.
+type archiveHeader struct {
+	Version    uint64
+	Checkpoint *Checkpoint
+}
+
+// archiveAccount is an account of the archived state, in its slim encoding.
+type archiveAccount struct {
+	Hash    common.Hash
+	Account []byte
+	Code    []byte
+}
+
+// archiveSlot is a storage slot of an archived account.
+type archiveSlot struct {
+	Hash  common.Hash
+	Value []byte
+}
+
+// Export writes the state of the checkpoint block, as found in the snapshot tree,
+// into an archive.
+func Export(w io.Writer, db ethdb.KeyValueReader, snaptree *snapshot.Tree, checkpoint *Checkpoint) error {
+	var (
+		root = checkpoint.Root()
+		zw   = gzip.NewWriter(w)
+	)
+	if err := rlp.Encode(zw, &archiveHeader{Version: archiveVersion, Checkpoint: checkpoint}); err != nil {
+		return err
+	}
+	accIt, err := snaptree.AccountIterator(root, common.Hash{})
+	if err != nil {
+		return err
+	}
+	defer accIt.Release()
+
+	var (
+		start    = time.Now()
+		logged   = time.Now()
+		accounts uint64
+		slots    uint64
+	)
+	for accIt.Next() {
+		account, err := types.FullAccount(accIt.Account())
+		if err != nil {
+			return err
+		}
+		entry := &archiveAccount{Hash: accIt.Hash(), Account: accIt.Account()}
+		if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+			if entry.Code = rawdb.ReadCode(db, codeHash); len(entry.Code) == 0 {
+				return fmt.Errorf("missing code %x of account %x", codeHash, accIt.Hash())
+			}
+		}
+		if err := rlp.Encode(zw, entry); err != nil {
+			return err
+		}
+		if account.Root != types.EmptyRootHash {
+			n, err := exportStorage(zw, snaptree, root, accIt.Hash())
+			if err != nil {
+				return err
+			}
+			slots += n
+		}
+		// Terminate the storage of the account
+		if err := rlp.Encode(zw, []*archiveSlot{}); err != nil {
+			return err
+		}
+		accounts++
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Exporting state snapshot", "at", accIt.Hash(), "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if err := accIt.Error(); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	log.Info("Exported state snapshot", "number", checkpoint.Block.Number(), "root", root, "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// exportStorage writes the storage of an account in chunks, returning the
+// number of slots written.
+func exportStorage(w io.Writer, snaptree *snapshot.Tree, root common.Hash, account common.Hash) (uint64, error) {
+	stIt, err := snaptree.StorageIterator(root, account, common.Hash{})
+	if err != nil {
+		return 0, err
+	}
+	defer stIt.Release()
+
+	var (
+		chunk = make([]*archiveSlot, 0, slotChunkSize)
+		slots uint64
+	)
+	for stIt.Next() {
+		chunk = append(chunk, &archiveSlot{Hash: stIt.Hash(), Value: common.CopyBytes(stIt.Slot())})
+		if len(chunk) == slotChunkSize {
+			if err := rlp.Encode(w, chunk); err != nil {
+				return 0, err
+			}
+			slots += uint64(len(chunk))
+			chunk = chunk[:0]
+		}
+	}
+	if err := stIt.Error(); err != nil {
+		return 0, err
+	}
+	if len(chunk) > 0 {
+		if err := rlp.Encode(w, chunk); err != nil {
+			return 0, err
+		}
+		slots += uint64(len(chunk))
+	}
+	return slots, nil
+}
+
+// Archive is a state snapshot archive being read.
+type Archive struct {
+	Checkpoint *Checkpoint
+
+	stream *rlp.Stream
+}
+
+// OpenArchive reads the checkpoint of an archive, leaving the state to be
+// imported by Import.
+func OpenArchive(r io.Reader) (*Archive, error) {
+	zr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	stream := rlp.NewStream(zr, 0)
+
+	var header archiveHeader
+	if err := stream.Decode(&header); err != nil {
+		return nil, err
+	}
+	if header.Version != archiveVersion {
+		return nil, fmt.Errorf("%w: %d", errArchiveVersion, header.Version)
+	}
+	if header.Checkpoint == nil || header.Checkpoint.Block == nil {
+		return nil, errors.New("snapshot archive without checkpoint")
+	}
+	return &Archive{Checkpoint: header.Checkpoint, stream: stream}, nil
+}
+
+// Import writes the archived state into the database: the state trie is
+// regenerated from the archived accounts and storage slots, and verified
+// against the state root of the checkpoint. The flat snapshot is written along
+// with it, replacing the existing one.
+func (a *Archive) Import(db ethdb.Database, triedb *trie.Database) error {
+	var (
+		root   = a.Checkpoint.Root()
+		scheme = triedb.Scheme()
+		batch  = db.NewBatch()
+	)
+	// Invalidate the persistent state while it is being written. The path-based
+	// state is reset to the imported root once done, while the hash-based nodes
+	// are only reachable from their root anyway.
+	if scheme == rawdb.PathScheme {
+		if err := triedb.Disable(); err != nil {
+			return err
+		}
+	}
+	rawdb.DeleteSnapshotRoot(batch)
+	rawdb.DeleteSnapshotJournal(batch)
+	rawdb.DeleteSnapshotGenerator(batch)
+	if err := wipeSnapshot(db, batch); err != nil {
+		return err
+	}
+	flush := func(force bool) error {
+		if force || batch.ValueSize() > ethdb.IdealBatchSize {
+			if err := batch.Write(); err != nil {
+				return err
+			}
+			batch.Reset()
+		}
+		return nil
+	}
+	accTrie := trie.NewStackTrie(trie.NewStackTrieOptions().WithWriter(func(path []byte, hash common.Hash, blob []byte) {
+		rawdb.WriteTrieNode(batch, common.Hash{}, path, hash, blob, scheme)
+	}))
+	var (
+		start    = time.Now()
+		logged   = time.Now()
+		accounts uint64
+		slots    uint64
+		prev     *common.Hash
+	)
+	for {
+		var entry archiveAccount
+		if err := a.stream.Decode(&entry); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if prev != nil && bytes.Compare(entry.Hash[:], prev[:]) <= 0 {
+			return fmt.Errorf("unordered account %x after %x", entry.Hash, *prev)
+		}
+		prev = &entry.Hash
+
+		account, err := types.FullAccount(entry.Account)
+		if err != nil {
+			return err
+		}
+		// Regenerate the storage trie of the account
+		owner := entry.Hash
+		stTrie := trie.NewStackTrie(trie.NewStackTrieOptions().WithWriter(func(path []byte, hash common.Hash, blob []byte) {
+			rawdb.WriteTrieNode(batch, owner, path, hash, blob, scheme)
+		}))
+		for {
+			var chunk []*archiveSlot
+			if err := a.stream.Decode(&chunk); err != nil {
+				return err
+			}
+			if len(chunk) == 0 {
+				break
+			}
+			for _, slot := range chunk {
+				if len(slot.Value) == 0 {
+					return fmt.Errorf("empty storage slot %x of account %x", slot.Hash, owner)
+				}
+				if err := stTrie.Update(slot.Hash[:], slot.Value); err != nil {
+					return err
+				}
+				rawdb.WriteStorageSnapshot(batch, owner, slot.Hash, slot.Value)
+				slots++
+			}
+			if err := flush(false); err != nil {
+				return err
+			}
+		}
+		if have := stTrie.Commit(); have != account.Root {
+			return fmt.Errorf("%w: account %x storage root %x, want %x", errArchiveRoot, owner, have, account.Root)
+		}
+		// Write the account itself
+		if codeHash := common.BytesToHash(account.CodeHash); codeHash != types.EmptyCodeHash {
+			if have := crypto.Keccak256Hash(entry.Code); have != codeHash {
+				return fmt.Errorf("account %x code hash mismatch: have %x, want %x", owner, have, codeHash)
+			}
+			rawdb.WriteCode(batch, codeHash, entry.Code)
+		}
+		full, err := types.FullAccountRLP(entry.Account)
+		if err != nil {
+			return err
+		}
+		if err := accTrie.Update(owner[:], full); err != nil {
+			return err
+		}
+		rawdb.WriteAccountSnapshot(batch, owner, entry.Account)
+		accounts++
+
+		if err := flush(false); err != nil {
+			return err
+		}
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Importing state snapshot", "at", owner, "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+			logged = time.Now()
+		}
+	}
+	if have := accTrie.Commit(); have != root {
+		return fmt.Errorf("%w: have %x, want %x", errArchiveRoot, have, root)
+	}
+	// Mark the flat snapshot as complete for the imported root
+	generator, err := rlp.EncodeToBytes(&journalGenerator{Done: true, Accounts: accounts, Slots: slots})
+	if err != nil {
+		return err
+	}
+	rawdb.WriteSnapshotGenerator(batch, generator)
+	rawdb.WriteSnapshotRoot(batch, root)
+	rawdb.DeleteSnapshotDisabled(batch)
+	if err := flush(true); err != nil {
+		return err
+	}
+	if scheme == rawdb.PathScheme {
+		if err := triedb.Enable(root); err != nil {
+			return err
+		}
+	}
+	log.Info("Imported state snapshot", "number", a.Checkpoint.Block.Number(), "root", root, "accounts", accounts, "slots", slots, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// journalGenerator mirrors the snapshot generator progress marker of go-ethereum.
+type journalGenerator struct {
+	Wiping   bool
+	Done     bool
+	Marker   []byte
+	Accounts uint64
+	Slots    uint64
+	Storage  uint64
+}
+
+// wipeSnapshot deletes the flat snapshot entries of the database.
+func wipeSnapshot(db ethdb.Database, batch ethdb.Batch) error {
+	for _, prefix := range [][]byte{rawdb.SnapshotAccountPrefix, rawdb.SnapshotStoragePrefix} {
+		it := db.NewIterator(prefix, nil)
+		for it.Next() {
+			key := it.Key()
+			if len(key) != len(prefix)+common.HashLength && len(key) != len(prefix)+2*common.HashLength {
+				continue
+			}
+			if err := batch.Delete(common.CopyBytes(key)); err != nil {
+				it.Release()
+				return err
+			}
+			if batch.ValueSize() > ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					it.Release()
+					return err
+				}
+				batch.Reset()
+			}
+		}
+		err := it.Error()
+		it.Release()
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -17,6 +17,7 @@
 package core
 
 import (
+	"errors"
 	"sync/atomic"
 
 	"github.com/ethereum/go-ethereum/core"
@@ -50,12 +51,15 @@ func newStatePrefetcher(config *params.ChainConfig, bc *BlockChain, engine mivec
 // the transaction messages using the statedb, but any changes are discarded. The
 // only goal is to pre-cache transaction signatures and state trie nodes.
 func (p *statePrefetcher) Prefetch(block *types.Block, statedb *state.StateDB, cfg vm.Config, interrupt *atomic.Bool) {
+	header := block.Header()
+	blockContext, err := NewEVMBlockContext(header, p.bc, nil, p.config)
+	if err != nil {
+		return
+	}
 	var (
-		header       = block.Header()
-		gaspool      = new(core.GasPool).AddGas(blockGasLimit(block.GasLimit(), p.config))
-		blockContext = NewEVMBlockContext(header, p.bc, nil, p.config)
-		evm          = vm.NewEVM(blockContext, vm.TxContext{}, statedb, p.config.Eth, cfg)
-		signer       = types.MakeSigner(p.config.Eth, header.Number, header.Time)
+		gaspool = new(core.GasPool).AddGas(BlockGasLimit(block.GasLimit(), p.config))
+		evm     = vm.NewEVM(blockContext, vm.TxContext{}, statedb, p.config.EVMConfig(), cfg)
+		signer  = types.MakeSigner(p.config.Eth, header.Number, header.Time)
 	)
 	// Iterate over and process the individual transactions
 	byzantium := p.config.Eth.IsByzantium(block.Number())
@@ -64,14 +68,26 @@ func (p *statePrefetcher) Prefetch(block *types.Block, statedb *state.StateDB, c
 		if interrupt != nil && interrupt.Load() {
 			return
 		}
-		// Convert the transaction into an executable message and pre-cache its sender
-		msg, err := TransactionToMessage(tx, signer, header.BaseFee, p.config)
+		// Convert the transaction into executable messages and pre-cache its sender
+		tx, err := withArchivedBlobs(p.bc.db, tx, block.Number(), p.config)
+		if err != nil {
+			return // Blobs not archived yet, the block will fail anyway
+		}
+		msgs, err := TransactionToMessages(tx, block.Number(), signer, blockContext.BaseFee, p.config)
+		if errors.Is(err, ErrInvalidPayload) {
+			continue // Malformed Mive transaction, nothing to execute
+		}
 		if err != nil {
 			return // Also invalid block, bail out
 		}
+		if len(msgs) == 0 {
+			continue // Not a Mive transaction, nothing to execute
+		}
 		statedb.SetTxContext(tx.Hash(), i)
-		if err := precacheTransaction(msg, p.config, gaspool, statedb, header, evm); err != nil {
-			return // Ugh, something went horribly wrong, bail out
+		for _, msg := range msgs {
+			if err := precacheTransaction(msg, p.config, gaspool, statedb, header, evm); err != nil {
+				return // Ugh, something went horribly wrong, bail out
+			}
 		}
 		// If we're pre-byzantium, pre-load trie nodes for the intermediate root
 		if !byzantium {
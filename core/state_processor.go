@@ -1,19 +1,23 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus/misc"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
-	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/log"
 
 	miveconsensus "github.com/ethereum-mive/mive/consensus"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
 	miveparams "github.com/ethereum-mive/mive/params"
 )
 
@@ -36,61 +40,335 @@ func NewStateProcessor(config *miveparams.ChainConfig, bc *BlockChain, engine mi
 	}
 }
 
-func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (types.Receipts, []*types.Log, uint64, error) {
+// Process processes the state changes according to the Mive rules by running
+// the Mive messages wrapped in the beacon transactions of the given L1 block
+// using the statedb.
+//
+// Process returns the executed Mive transactions along with the receipts and
+// logs accumulated during the process and returns the amount of gas that was
+// used in the process. L1 transactions that
+// don't carry a Mive message, or carry one that can't be applied to the state
+// (e.g. the sender can't pay for it), are skipped and don't produce a receipt.
+// Beacon transactions with a malformed payload or an inapplicable message are
+// returned as rejection records instead. The deposits made through the portal
+// contract during the L1 block are executed first, and always included.
+// An error is only returned if the block itself can't be processed.
+func (p *StateProcessor) Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (mivetypes.Transactions, mivetypes.Rejections, types.Receipts, []*types.Log, uint64, error) {
 	var (
+		txs         mivetypes.Transactions
+		rejections  mivetypes.Rejections
 		receipts    types.Receipts
 		usedGas     = new(uint64)
 		header      = block.Header()
 		blockHash   = block.Hash()
 		blockNumber = block.Number()
 		allLogs     []*types.Log
-		gp          = new(core.GasPool).AddGas(blockGasLimit(block.GasLimit(), p.config))
+		gp          = new(core.GasPool).AddGas(BlockGasLimit(block.GasLimit(), p.config))
+		decodeTime  time.Duration // Time spent decoding the payloads of the beacon transactions
 	)
-	// Mutate the block and state according to any hard-fork specs
-	if p.config.Eth.DAOForkSupport && p.config.Eth.DAOForkBlock != nil && p.config.Eth.DAOForkBlock.Cmp(block.Number()) == 0 {
-		misc.ApplyDAOHardFork(statedb)
+	context, err := NewEVMBlockContext(header, p.bc, nil, p.config)
+	if err != nil {
+		return nil, nil, nil, nil, 0, err
+	}
+	var (
+		vmenv  = vm.NewEVM(context, vm.TxContext{}, statedb, p.config.EVMConfig(), cfg)
+		signer = types.MakeSigner(p.config.Eth, header.Number, header.Time)
+	)
+	ApplyBlockStart(header, vmenv, statedb, p.config)
+
+	// In nonce ordering mode, the signed transactions whose nonce is ahead of
+	// their sender are deferred until the sender catches up within the block,
+	// so that the signed transactions submitted together needn't be included
+	// on L1 in nonce order.
+	var (
+		ordered  = p.config.IsNonceOrdering(blockNumber)
+		deferred []*pendingMsg // Deferred signed transactions, in order of appearance
+		apply    func(pending *pendingMsg)
+	)
+	apply = func(pending *pendingMsg) {
+		var (
+			tx  = pending.tx
+			msg = pending.msg
+		)
+		if ordered && !msg.SkipAccountChecks && msg.Nonce > statedb.GetNonce(msg.From) {
+			deferred = append(deferred, pending)
+			return
+		}
+		// Mive transactions are indexed by their position in the Mive block,
+		// not by the position of the wrapping transaction in the L1 block.
+		mtx := newTransaction(tx, pending.index, pending.batchIndex, msg, pending.l1Sender, statedb.GetNonce(msg.From), p.config)
+		statedb.SetTxContext(mtx.Hash(), len(receipts))
+		receipt, err := applyTransaction(msg, p.config, gp, statedb, blockNumber, blockHash, mtx, usedGas, vmenv)
+		if err != nil {
+			// The message is invalid in the current state, which doesn't make
+			// the L1 block invalid. Skip it without any side effects.
+			log.Debug("Skipping invalid Mive transaction", "block", blockNumber, "index", pending.index, "batch", pending.batchIndex, "hash", tx.Hash(), "err", err)
+			rejections = append(rejections, &mivetypes.Rejection{Origin: tx.Hash(), From: msg.From, Reason: err.Error(), BatchIndex: uint64(pending.batchIndex)})
+			return
+		}
+		txs = append(txs, mtx)
+		receipts = append(receipts, receipt)
+		allLogs = append(allLogs, receipt.Logs...)
+
+		// Execute the first deferred transaction of the sender waiting for the
+		// next nonce, which in turn executes the one after it.
+		nonce := statedb.GetNonce(msg.From)
+		for _, next := range deferred {
+			if !next.done && next.msg.From == msg.From && next.msg.Nonce == nonce {
+				next.done = true
+				apply(next)
+				break
+			}
+		}
 	}
+
+	// The events of the beacon and portal contracts are emitted during the
+	// execution of the L1 transactions, so they are only known from their
+	// receipts. Only retrieve them if the L1 block may contain any. They are
+	// checked against the receipt root of the L1 header, which also ensures
+	// there is one per L1 transaction.
 	var (
-		context = NewEVMBlockContext(header, p.bc, nil, p.config)
-		vmenv   = vm.NewEVM(context, vm.TxContext{}, statedb, p.config.Eth, cfg)
-		signer  = types.MakeSigner(p.config.Eth, header.Number, header.Time)
+		beaconEvents = p.config.IsBeaconContract(blockNumber) && types.BloomLookup(header.Bloom, p.config.Mive.BeaconContract) && types.BloomLookup(header.Bloom, mivetypes.BeaconEventTopic)
+		deposits     = p.config.IsPortal(blockNumber) && types.BloomLookup(header.Bloom, p.config.Mive.PortalContract) && types.BloomLookup(header.Bloom, mivetypes.DepositEventTopic)
+		l1Receipts   types.Receipts
 	)
-	if beaconRoot := block.BeaconRoot(); beaconRoot != nil {
-		core.ProcessBeaconBlockRoot(*beaconRoot, vmenv, statedb)
+	if beaconEvents || deposits {
+		var err error
+		if l1Receipts, err = p.bc.EthGetReceipts(blockHash, blockNumber.Uint64()); err != nil {
+			return nil, nil, nil, nil, 0, fmt.Errorf("could not retrieve L1 receipts: %w", err)
+		}
+	}
+	// Deposits are forced in at the top of the block, before any beacon
+	// transaction can spend the block gas. The minted value is credited even
+	// if the deposit fails, so the value locked on L1 is never lost.
+	if deposits {
+		for i, tx := range block.Transactions() {
+			var depositIndex int
+			for _, event := range l1Receipts[i].Logs {
+				if !mivetypes.IsDepositEvent(event, p.config.Mive.PortalContract) {
+					continue
+				}
+				deposit, err := mivetypes.ParseDeposit(event)
+				if err != nil {
+					log.Debug("Skipping malformed deposit", "block", blockNumber, "index", i, "hash", tx.Hash(), "log", event.Index, "err", err)
+					rejections = append(rejections, &mivetypes.Rejection{Origin: tx.Hash(), Reason: err.Error(), BatchIndex: uint64(depositIndex)})
+					continue
+				}
+				var (
+					msg = DepositToMessage(deposit)
+					mtx = newDeposit(tx, i, depositIndex, deposit, statedb.GetNonce(deposit.From))
+				)
+				depositIndex++
+
+				ApplyDepositMint(statedb, mtx)
+				statedb.SetTxContext(mtx.Hash(), len(receipts))
+				vmenv.Config.NoBaseFee = true
+				receipt, err := applyTransaction(msg, p.config, gp, statedb, blockNumber, blockHash, mtx, usedGas, vmenv)
+				vmenv.Config.NoBaseFee = cfg.NoBaseFee
+				if err != nil {
+					log.Debug("Failed to execute deposit", "block", blockNumber, "index", i, "hash", tx.Hash(), "err", err)
+					receipt = failedReceipt(p.config, statedb, blockNumber, blockHash, mtx, *usedGas)
+				}
+				txs = append(txs, mtx)
+				receipts = append(receipts, receipt)
+				allLogs = append(allLogs, receipt.Logs...)
+			}
+		}
 	}
 	// Iterate over and process the individual transactions
 	for i, tx := range block.Transactions() {
-		msg, err := TransactionToMessage(tx, signer, header.BaseFee, p.config)
+		tx, err := withArchivedBlobs(p.bc.db, tx, blockNumber, p.config)
 		if err != nil {
-			return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+			return nil, nil, nil, nil, 0, fmt.Errorf("could not apply tx %d: %w", i, err)
+		}
+		l1Sender, _ := types.Sender(signer, tx)
+		dstart := time.Now()
+		msgs, err := TransactionToMessages(tx, blockNumber, signer, context.BaseFee, p.config)
+		decodeTime += time.Since(dstart)
+		if errors.Is(err, ErrInvalidPayload) {
+			// The payload can't be decoded, which doesn't make the L1 block
+			// invalid either. Keep a record of it for debugging purposes.
+			log.Debug("Skipping malformed Mive transaction", "block", blockNumber, "index", i, "hash", tx.Hash(), "err", err)
+			rejections = append(rejections, &mivetypes.Rejection{Origin: tx.Hash(), From: l1Sender, Reason: err.Error()})
+		} else if err != nil {
+			return nil, nil, nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		// Execute the Mive transactions of a batch in order, each of them on
+		// top of the state left by the previous one. A transaction skipped
+		// does not prevent the following ones from executing.
+		var batchIndex int
+		for _, msg := range msgs {
+			apply(&pendingMsg{tx: tx, index: i, batchIndex: batchIndex, msg: msg, l1Sender: l1Sender})
+			batchIndex++
 		}
-		if msg == nil {
-			// Skip the transaction since it is not a valid Mive transaction.
+		// Then execute the ones originated by the beacon contract during the
+		// transaction, in the order of its events.
+		if !beaconEvents {
 			continue
 		}
-		statedb.SetTxContext(tx.Hash(), i)
-		receipt, err := applyTransaction(msg, p.config, gp, statedb, blockNumber, blockHash, tx, usedGas, vmenv)
-		if err != nil {
-			return nil, nil, 0, fmt.Errorf("could not apply tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		for _, event := range l1Receipts[i].Logs {
+			if !mivetypes.IsBeaconEvent(event, p.config.Mive.BeaconContract) {
+				continue
+			}
+			dstart := time.Now()
+			msgs, err := EventToMessages(tx, event, context.BaseFee, p.config)
+			decodeTime += time.Since(dstart)
+			if err != nil {
+				log.Debug("Skipping malformed beacon event", "block", blockNumber, "index", i, "hash", tx.Hash(), "log", event.Index, "err", err)
+				rejections = append(rejections, &mivetypes.Rejection{Origin: tx.Hash(), From: l1Sender, Reason: err.Error(), BatchIndex: uint64(batchIndex)})
+				continue
+			}
+			for _, msg := range msgs {
+				apply(&pendingMsg{tx: tx, index: i, batchIndex: batchIndex, msg: msg, l1Sender: l1Sender})
+				batchIndex++
+			}
 		}
-		receipts = append(receipts, receipt)
-		allLogs = append(allLogs, receipt.Logs...)
 	}
+	// Signed transactions still waiting for their nonce can't execute anymore
+	for _, pending := range deferred {
+		if pending.done {
+			continue
+		}
+		msg := pending.msg
+		err := fmt.Errorf("%w: address %v, tx: %d state: %d", core.ErrNonceTooHigh, msg.From.Hex(), msg.Nonce, statedb.GetNonce(msg.From))
+		log.Debug("Skipping invalid Mive transaction", "block", blockNumber, "index", pending.index, "batch", pending.batchIndex, "hash", pending.tx.Hash(), "err", err)
+		rejections = append(rejections, &mivetypes.Rejection{Origin: pending.tx.Hash(), From: msg.From, Reason: err.Error(), BatchIndex: uint64(pending.batchIndex)})
+	}
+	beaconDecodeTimer.Update(decodeTime)
+
 	// Note: no block finalization is needed here (e.g. uncle processing, block reward, etc.)
 
-	return receipts, allLogs, *usedGas, nil
+	return txs, rejections, receipts, allLogs, *usedGas, nil
+}
+
+// pendingMsg is a Mive transaction of a beacon transaction awaiting execution.
+type pendingMsg struct {
+	tx         *types.Transaction // Wrapping beacon transaction
+	index      int                // Index of the beacon transaction in its L1 block
+	batchIndex int                // Index of the transaction in the batch
+	msg        *core.Message
+	l1Sender   common.Address // Sender of the beacon transaction
+	done       bool           // Whether a deferred transaction was executed
 }
 
-func applyTransaction(msg *core.Message, config *miveparams.ChainConfig, gp *core.GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *types.Transaction, usedGas *uint64, evm *vm.EVM) (*types.Receipt, error) {
+// ApplyBlockStart mutates the state according to the rules applied at the start
+// of the given L1 block, before any of its Mive transactions is executed: the
+// DAO hard fork, the beacon block root update and the L1 block attributes
+// update.
+func ApplyBlockStart(header *types.Header, vmenv *vm.EVM, statedb *state.StateDB, config *miveparams.ChainConfig) {
+	if config.Eth.DAOForkSupport && config.Eth.DAOForkBlock != nil && config.Eth.DAOForkBlock.Cmp(header.Number) == 0 {
+		misc.ApplyDAOHardFork(statedb)
+	}
+	if header.ParentBeaconRoot != nil {
+		core.ProcessBeaconBlockRoot(*header.ParentBeaconRoot, vmenv, statedb)
+	}
+	if config.IsL1BlockAttributes(header.Number) {
+		ProcessL1BlockAttributes(header, statedb)
+	}
+}
+
+// The L1 block contract, at miveparams.L1BlockAddress, stores the attributes of
+// the L1 block the current Mive block is derived from, one per slot, and
+// exposes them to the Mive contracts through the getters:
+//
+//	function number() external view returns (uint256);
+//	function hash() external view returns (bytes32);
+//	function timestamp() external view returns (uint256);
+//	function basefee() external view returns (uint256);
+//	function blobBaseFee() external view returns (uint256);
+//
+// The slots are written by the state transition, not by a transaction, so the
+// contract has no setters. The state roots of the recent L1 blocks follow, in
+// a ring of miveparams.L1StateRootHistory slots indexed by block number, for
+// the L1 state precompile to prove L1 state against.
+const (
+	l1NumberSlot = iota
+	l1HashSlot
+	l1TimestampSlot
+	l1BaseFeeSlot
+	l1BlobBaseFeeSlot
+	l1StateRootsSlot
+)
+
+// l1StateRootSlot returns the slot of the L1 block contract storing the state
+// root of the L1 block with the given number, while recent.
+func l1StateRootSlot(number uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(l1StateRootsSlot + number%miveparams.L1StateRootHistory))
+}
+
+// ProcessL1BlockAttributes stores the attributes of the given L1 block in the
+// L1 block contract, along with its state root among the recent ones, deploying
+// the code of the contract first if it isn't yet. The base fees are zero before
+// the L1 forks introducing them.
+func ProcessL1BlockAttributes(header *types.Header, statedb *state.StateDB) {
+	addr := miveparams.L1BlockAddress
+	if statedb.GetCodeSize(addr) == 0 {
+		L1BlockPredeploy.Apply(statedb)
+	}
+	var baseFee, blobBaseFee common.Hash
+	if header.BaseFee != nil {
+		baseFee = common.BigToHash(header.BaseFee)
+	}
+	if header.ExcessBlobGas != nil {
+		blobBaseFee = common.BigToHash(eip4844.CalcBlobFee(*header.ExcessBlobGas))
+	}
+	statedb.SetState(addr, common.BigToHash(big.NewInt(l1NumberSlot)), common.BigToHash(header.Number))
+	statedb.SetState(addr, common.BigToHash(big.NewInt(l1HashSlot)), header.Hash())
+	statedb.SetState(addr, common.BigToHash(big.NewInt(l1TimestampSlot)), common.BigToHash(new(big.Int).SetUint64(header.Time)))
+	statedb.SetState(addr, common.BigToHash(big.NewInt(l1BaseFeeSlot)), baseFee)
+	statedb.SetState(addr, common.BigToHash(big.NewInt(l1BlobBaseFeeSlot)), blobBaseFee)
+	statedb.SetState(addr, l1StateRootSlot(header.Number.Uint64()), header.Root)
+}
+
+// WithdrawalsRoot returns the withdrawals root committed to by the header of the
+// block with the given number and time: the storage root of the withdrawal
+// contract in the state after the block, or zero before the withdrawals fork.
+func WithdrawalsRoot(statedb *state.StateDB, number *big.Int, time uint64, config *miveparams.ChainConfig) common.Hash {
+	if !config.IsWithdrawals(number, time) {
+		return common.Hash{}
+	}
+	return statedb.GetStorageRoot(miveparams.WithdrawalContractAddress)
+}
+
+// failedReceipt creates the receipt of a deposit whose message couldn't be
+// applied to the state. Only the mint of the deposit took effect, without using
+// any gas.
+func failedReceipt(config *miveparams.ChainConfig, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *mivetypes.Transaction, usedGas uint64) *types.Receipt {
+	var root []byte
+	if config.Eth.IsByzantium(blockNumber) {
+		statedb.Finalise(true)
+	} else {
+		root = statedb.IntermediateRoot(config.Eth.IsEIP158(blockNumber)).Bytes()
+	}
+	receipt := &types.Receipt{Type: types.LegacyTxType, PostState: root, Status: types.ReceiptStatusFailed, CumulativeGasUsed: usedGas}
+	receipt.TxHash = tx.Hash()
+	receipt.Logs = []*types.Log{}
+	receipt.Bloom = types.CreateBloom(types.Receipts{receipt})
+	receipt.BlockHash = blockHash
+	receipt.BlockNumber = blockNumber
+	receipt.TransactionIndex = uint(statedb.TxIndex())
+	return receipt
+}
+
+func applyTransaction(msg *core.Message, config *miveparams.ChainConfig, gp *core.GasPool, statedb *state.StateDB, blockNumber *big.Int, blockHash common.Hash, tx *mivetypes.Transaction, usedGas *uint64, evm *vm.EVM) (*types.Receipt, error) {
 	// Create a new context to be used in the EVM environment.
 	txContext := core.NewEVMTxContext(msg)
 	evm.Reset(txContext, statedb)
 
-	// Apply the transaction to the current state (included in the env).
+	// Apply the transaction to the current state (included in the env). The
+	// message may be rejected after the purchase of its gas, so make sure a
+	// failure leaves neither the state nor the gas pool modified.
+	var (
+		snapshot = statedb.Snapshot()
+		gas      = gp.Gas()
+	)
 	result, err := core.ApplyMessage(evm, msg, gp)
 	if err != nil {
+		statedb.RevertToSnapshot(snapshot)
+		gp.SetGas(gas)
 		return nil, err
 	}
+	ApplyFeePolicy(statedb, evm.Context, msg, tx, result.UsedGas, config)
 
 	// Update the state with pending changes.
 	var root []byte
@@ -103,7 +381,8 @@ func applyTransaction(msg *core.Message, config *miveparams.ChainConfig, gp *cor
 
 	// Create a new receipt for the transaction, storing the intermediate root and gas used
 	// by the tx.
-	receipt := &types.Receipt{Type: tx.Type(), PostState: root, CumulativeGasUsed: *usedGas}
+	// Mive transactions are untyped, all receipts are encoded as legacy ones.
+	receipt := &types.Receipt{Type: types.LegacyTxType, PostState: root, CumulativeGasUsed: *usedGas}
 	if result.Failed() {
 		receipt.Status = types.ReceiptStatusFailed
 	} else {
@@ -112,14 +391,11 @@ func applyTransaction(msg *core.Message, config *miveparams.ChainConfig, gp *cor
 	receipt.TxHash = tx.Hash()
 	receipt.GasUsed = result.UsedGas
 
-	if tx.Type() == types.BlobTxType {
-		receipt.BlobGasUsed = uint64(len(tx.BlobHashes()) * params.BlobTxBlobGasPerBlob)
-		receipt.BlobGasPrice = evm.Context.BlobBaseFee
-	}
-
 	// If the transaction created a contract, store the creation address in the receipt.
+	// Note, the address is derived from the Mive nonce of the sender, the nonce of
+	// the wrapping L1 transaction is unrelated.
 	if msg.To == nil {
-		receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, tx.Nonce())
+		receipt.ContractAddress = crypto.CreateAddress(evm.TxContext.Origin, tx.Nonce)
 	}
 
 	// Set the receipt logs and create the bloom filter.
@@ -1,62 +1,333 @@
 package core
 
 import (
+	"fmt"
 	"math/big"
 
+	"github.com/ethereum/go-ethereum/common"
 	cmath "github.com/ethereum/go-ethereum/common/math"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/log"
-	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethdb"
 
+	miverawdb "github.com/ethereum-mive/mive/core/rawdb"
 	mivetypes "github.com/ethereum-mive/mive/core/types"
 	"github.com/ethereum-mive/mive/params"
 )
 
-// TransactionToMessage converts a transaction into a Message.
-func TransactionToMessage(tx *types.Transaction, s types.Signer, baseFee *big.Int, config *params.ChainConfig) (*core.Message, error) {
-	if tx.To() == nil || *tx.To() != config.Mive.BeaconAddress {
-		// The transaction is not sent to the beacon address.
+// TransactionToMessages converts a beacon transaction into the messages of the
+// Mive transactions it carries, in execution order. Nil is returned if the
+// transaction is not a beacon transaction at the given block. Blob-carrying beacon transactions
+// are only converted if their blobs are attached as their sidecar, see
+// IsBlobBeaconTx. The base fee is the one of the Mive block context, i.e.
+// already reduced, nil to use the gas price of the transaction.
+func TransactionToMessages(tx *types.Transaction, number *big.Int, s types.Signer, baseFee *big.Int, config *params.ChainConfig) ([]*core.Message, error) {
+	if !isBeaconTx(tx, number, config) {
+		// The transaction is not sent to a beacon address.
 		return nil, nil
 	}
 	if tx.Type() == types.BlobTxType {
-		// We don't support blob transaction type.
+		if tx.BlobTxSidecar() == nil {
+			// The blobs are not available, or not meant to be executed.
+			return nil, nil
+		}
+	} else if len(tx.Data()) == 0 {
 		return nil, nil
 	}
-	if len(tx.Data()) == 0 {
-		return nil, nil
+
+	// Decode Mive transactions from the payload of the original Ethereum
+	// transaction, carried in its data or in its blobs.
+	payload, err := mivetypes.TxPayload(tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
 	}
+	from, err := types.Sender(s, tx)
+	if err != nil {
+		return nil, err
+	}
+	return payloadToMessages(tx, from, payload, baseFee, config)
+}
 
-	// Decode Mive transaction from the data payload of the original Ethereum transaction.
-	var mtx mivetypes.Tx
-	err := rlp.DecodeBytes(tx.Data(), &mtx)
+// EventToMessages converts a beacon event emitted by the given L1 transaction
+// into the messages of the Mive transactions it carries, in execution order.
+// The fees are the ones of the emitting transaction, like for the payloads it
+// carries itself, and the base fee the one of the Mive block context.
+func EventToMessages(tx *types.Transaction, log *types.Log, baseFee *big.Int, config *params.ChainConfig) ([]*core.Message, error) {
+	event, err := mivetypes.ParseBeaconEvent(log)
 	if err != nil {
-		log.Warn("Decode Mive transaction", "hash", tx.Hash(), "err", err)
-		// Skip it if it's not a valid Mive transaction.
-		return nil, nil
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
 	}
+	return payloadToMessages(tx, event.Sender, event.Payload, baseFee, config)
+}
 
-	feeReductionDenom := new(big.Int).SetUint64(config.FeeReductionDenominator())
+// payloadToMessages converts the given versioned payload, carried by the given
+// L1 transaction on behalf of the given sender, into the messages of the Mive
+// transactions it carries.
+func payloadToMessages(tx *types.Transaction, from common.Address, payload []byte, baseFee *big.Int, config *params.ChainConfig) ([]*core.Message, error) {
+	mtxs, err := mivetypes.DecodePayload(payload)
+	if err != nil {
+		// It's not a valid Mive transaction (or one of a version unknown to
+		// this node), the caller is expected to skip it.
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+	var (
+		feeReductionDenom = new(big.Int).SetUint64(config.FeeReductionDenominator())
+		gasPrice          = new(big.Int).Div(tx.GasPrice(), feeReductionDenom)
+		gasFeeCap         = new(big.Int).Div(tx.GasFeeCap(), feeReductionDenom)
+		gasTipCap         = new(big.Int).Div(tx.GasTipCap(), feeReductionDenom)
+		signer            = mivetypes.NewSigner(config.ChainID(), config.Mive.BeaconAddress)
+	)
+	msgs := make([]*core.Message, len(mtxs))
+	for i, mtx := range mtxs {
+		msg := &core.Message{
+			From:              from,
+			Nonce:             tx.Nonce(), // Note: the nonce won't be checked while handling message
+			GasLimit:          mtx.Gas,
+			GasPrice:          new(big.Int).Set(gasPrice),
+			GasFeeCap:         new(big.Int).Set(gasFeeCap),
+			GasTipCap:         new(big.Int).Set(gasTipCap),
+			To:                mtx.To,
+			Value:             mtx.Value,
+			Data:              mtx.Data,
+			AccessList:        mtx.AccessList,
+			SkipAccountChecks: true, // Skip checks
+			BlobHashes:        nil,
+			BlobGasFeeCap:     nil,
+		}
+		if mtx.Auth != nil {
+			// Signed transactions execute as their signer, who is protected
+			// from replays by the nonce check and from the fees chosen by the
+			// L1 sender by the fee cap of the signature.
+			if msg.From, err = signer.Sender(mtx); err != nil {
+				return nil, fmt.Errorf("%w: transaction %d: %v", ErrInvalidPayload, i, err)
+			}
+			msg.Nonce = mtx.Auth.Nonce
+			msg.SkipAccountChecks = false
+			msg.GasFeeCap = cmath.BigMin(msg.GasFeeCap, mtx.Auth.GasFeeCap)
+			msg.GasTipCap = cmath.BigMin(msg.GasTipCap, msg.GasFeeCap)
+			msg.GasPrice = cmath.BigMin(msg.GasPrice, msg.GasFeeCap)
+		}
+		// If baseFee provided, set gasPrice to effectiveGasPrice.
+		if baseFee != nil {
+			msg.GasPrice = cmath.BigMin(new(big.Int).Add(msg.GasTipCap, baseFee), msg.GasFeeCap)
+		}
+		msgs[i] = msg
+	}
+	return msgs, nil
+}
 
+// isBeaconTx returns whether the given L1 transaction is sent to a beacon
+// address at the given block, regardless of whether it carries a valid payload.
+func isBeaconTx(tx *types.Transaction, number *big.Int, config *params.ChainConfig) bool {
+	return tx.To() != nil && config.IsBeacon(*tx.To(), number)
+}
+
+// IsBlobBeaconTx returns whether the given L1 transaction is a beacon transaction
+// carrying its payload in blobs, executed at the given block. The blobs of such
+// transactions are not part of the L1 block, so they have to be attached before
+// the transaction is converted into messages.
+func IsBlobBeaconTx(tx *types.Transaction, number *big.Int, config *params.ChainConfig) bool {
+	return tx.Type() == types.BlobTxType && config.IsBeacon(*tx.To(), number) && config.IsBlobCarrier(number)
+}
+
+// withArchivedBlobs returns the given transaction with the blobs archived for it
+// attached, if it's a blob-carrying beacon transaction executed at the given
+// block. Other transactions are returned as is.
+func withArchivedBlobs(db ethdb.KeyValueReader, tx *types.Transaction, number *big.Int, config *params.ChainConfig) (*types.Transaction, error) {
+	if !IsBlobBeaconTx(tx, number, config) || tx.BlobTxSidecar() != nil {
+		return tx, nil
+	}
+	sidecar := miverawdb.ReadBlobSidecar(db, tx.Hash())
+	if sidecar == nil {
+		return nil, fmt.Errorf("%w: %v", ErrMissingBlobs, tx.Hash())
+	}
+	return mivetypes.WithBlobSidecar(tx, sidecar)
+}
+
+// DepositToMessage converts a deposit made through the portal contract into the
+// message of the Mive transaction it sends. Deposits are paid for on L1, so they
+// execute without any fees, and the nonce of the sender is not checked.
+func DepositToMessage(deposit *mivetypes.Deposit) *core.Message {
+	return &core.Message{
+		From:              deposit.From,
+		GasLimit:          deposit.Gas,
+		GasPrice:          new(big.Int),
+		GasFeeCap:         new(big.Int),
+		GasTipCap:         new(big.Int),
+		To:                deposit.To,
+		Value:             deposit.Value,
+		Data:              deposit.Data,
+		SkipAccountChecks: true,
+	}
+}
+
+// ApplyDepositMint credits the sender of the given Mive transaction with the
+// value it mints, if it's a deposit. The mint is applied before the deposit
+// executes, and kept even if the execution fails.
+func ApplyDepositMint(statedb *state.StateDB, tx *mivetypes.Transaction) {
+	if tx.IsDeposit() {
+		statedb.AddBalance(tx.From, tx.Mint)
+	}
+}
+
+// FeeSplit is the distribution of the fees paid by a Mive transaction.
+type FeeSplit struct {
+	Total       *big.Int // Paid by the sender
+	Beneficiary *big.Int // Credited to the beneficiary
+	Treasury    *big.Int // Credited to the treasury
+	Refund      *big.Int // Refunded to the sender of the wrapping L1 transaction
+	Burned      *big.Int // Credited to no one
+}
+
+// SplitFees returns the distribution of the fees paid by the given message,
+// executed using the given amount of gas in the block with the given number
+// and Mive base fee. Without a fee policy, the tip is credited to the
+// beneficiary and the base fee is burned, as done by the state transition.
+func SplitFees(msg *core.Message, gasUsed uint64, number *big.Int, baseFee *big.Int, config *params.ChainConfig) *FeeSplit {
+	var (
+		gas   = new(big.Int).SetUint64(gasUsed)
+		split = &FeeSplit{
+			Total:       new(big.Int).Mul(gas, msg.GasPrice),
+			Beneficiary: new(big.Int),
+			Treasury:    new(big.Int),
+			Refund:      new(big.Int),
+			Burned:      new(big.Int),
+		}
+		policy = config.Mive.FeePolicy
+	)
+	if split.Total.Sign() == 0 {
+		return split
+	}
+	if policy == nil {
+		split.Beneficiary = tipFee(msg, gas, number, baseFee, config)
+		split.Burned.Sub(split.Total, split.Beneficiary)
+		return split
+	}
+	share := func(dst *big.Int, percentage uint64) {
+		dst.Mul(split.Total, new(big.Int).SetUint64(percentage))
+		dst.Div(dst, big.NewInt(100))
+	}
+	share(split.Burned, policy.BurnPercentage)
+	share(split.Treasury, policy.TreasuryPercentage)
+	share(split.Refund, policy.RefundPercentage)
+	split.Beneficiary.Sub(split.Total, split.Burned)
+	split.Beneficiary.Sub(split.Beneficiary, split.Treasury)
+	split.Beneficiary.Sub(split.Beneficiary, split.Refund)
+	return split
+}
+
+// ApplyFeePolicy redistributes the fees paid by the given message, executed as
+// the given Mive transaction using the given amount of gas, according to the
+// fee policy of the chain. The state transition credited the tip to the
+// coinbase of the block context and burned the base fee, which the policy
+// replaces. Nothing is done without a fee policy.
+func ApplyFeePolicy(statedb *state.StateDB, context vm.BlockContext, msg *core.Message, tx *mivetypes.Transaction, gasUsed uint64, config *params.ChainConfig) {
+	policy := config.Mive.FeePolicy
+	if policy == nil {
+		return
+	}
+	split := SplitFees(msg, gasUsed, context.BlockNumber, context.BaseFee, config)
+	if split.Total.Sign() == 0 {
+		return
+	}
+	statedb.SubBalance(context.Coinbase, tipFee(msg, new(big.Int).SetUint64(gasUsed), context.BlockNumber, context.BaseFee, config))
+	statedb.AddBalance(context.Coinbase, split.Beneficiary)
+	if split.Treasury.Sign() > 0 {
+		statedb.AddBalance(policy.Treasury, split.Treasury)
+	}
+	if split.Refund.Sign() > 0 {
+		statedb.AddBalance(tx.L1Sender(), split.Refund)
+	}
+}
+
+// tipFee returns the tip credited to the coinbase by the state transition for
+// the given message executed using the given amount of gas.
+func tipFee(msg *core.Message, gas *big.Int, number *big.Int, baseFee *big.Int, config *params.ChainConfig) *big.Int {
+	tip := msg.GasPrice
+	if config.Eth.IsLondon(number) {
+		tip = cmath.BigMin(msg.GasTipCap, new(big.Int).Sub(msg.GasFeeCap, baseFee))
+	}
+	return new(big.Int).Mul(gas, tip)
+}
+
+// MiveTransactionToMessage converts an executed Mive transaction back into the
+// message it was executed as, e.g. to replay it on top of the parent state. The
+// base fee is the one of the Mive block context, i.e. already reduced.
+func MiveTransactionToMessage(tx *mivetypes.Transaction, baseFee *big.Int) *core.Message {
 	msg := &core.Message{
-		Nonce:             tx.Nonce(), // Note: the nonce won't be checked while handling message
-		GasLimit:          mtx.Gas,
-		GasPrice:          new(big.Int).Div(tx.GasPrice(), feeReductionDenom),
-		GasFeeCap:         new(big.Int).Div(tx.GasFeeCap(), feeReductionDenom),
-		GasTipCap:         new(big.Int).Div(tx.GasTipCap(), feeReductionDenom),
-		To:                mtx.To,
-		Value:             mtx.Value,
-		Data:              mtx.Data,
-		AccessList:        mtx.AccessList,
-		SkipAccountChecks: true, // Skip checks
-		BlobHashes:        nil,
-		BlobGasFeeCap:     nil,
+		From:              tx.From,
+		Nonce:             tx.Nonce,
+		GasLimit:          tx.Tx.Gas,
+		GasPrice:          new(big.Int).Set(tx.GasPrice),
+		GasFeeCap:         new(big.Int).Set(tx.GasFeeCap),
+		GasTipCap:         new(big.Int).Set(tx.GasTipCap),
+		To:                tx.Tx.To,
+		Value:             tx.Tx.Value,
+		Data:              tx.Tx.Data,
+		AccessList:        tx.Tx.AccessList,
+		SkipAccountChecks: true,
 	}
 	// If baseFee provided, set gasPrice to effectiveGasPrice.
 	if baseFee != nil {
-		reductedBaseFee := new(big.Int).Div(baseFee, feeReductionDenom)
-		msg.GasPrice = cmath.BigMin(msg.GasPrice.Add(msg.GasTipCap, reductedBaseFee), msg.GasFeeCap)
+		msg.GasPrice = cmath.BigMin(msg.GasPrice.Add(msg.GasTipCap, baseFee), msg.GasFeeCap)
+	}
+	return msg
+}
+
+// newDeposit assembles the Mive transaction executed for the given deposit, made
+// during the given L1 transaction at the given index of its L1 block, at the
+// given index among the deposits of the transaction.
+func newDeposit(tx *types.Transaction, index int, depositIndex int, deposit *mivetypes.Deposit, nonce uint64) *mivetypes.Transaction {
+	return &mivetypes.Transaction{
+		Tx: mivetypes.Tx{
+			Gas:   deposit.Gas,
+			To:    deposit.To,
+			Value: deposit.Value,
+			Data:  deposit.Data,
+		},
+		Origin:      tx.Hash(),
+		OriginIndex: uint64(index),
+		From:        deposit.From,
+		Nonce:       nonce,
+		GasPrice:    new(big.Int),
+		GasTipCap:   new(big.Int),
+		GasFeeCap:   new(big.Int),
+		BatchIndex:  uint64(depositIndex),
+		Mint:        deposit.Mint,
+	}
+}
+
+// newTransaction assembles the Mive transaction executed for the given beacon
+// transaction at the given index of its L1 block, from the message at the given
+// index of the batch the beacon transaction was converted into, the L1 sender
+// and the Mive nonce of the sender.
+func newTransaction(tx *types.Transaction, index int, batchIndex int, msg *core.Message, l1Sender common.Address, nonce uint64, config *params.ChainConfig) *mivetypes.Transaction {
+	var (
+		feeReductionDenom = new(big.Int).SetUint64(config.FeeReductionDenominator())
+		gasPrice          = new(big.Int).Div(tx.GasPrice(), feeReductionDenom)
+		sponsor           common.Address
+	)
+	if msg.From != l1Sender {
+		sponsor = l1Sender
+	}
+	return &mivetypes.Transaction{
+		Tx: mivetypes.Tx{
+			Gas:        msg.GasLimit,
+			To:         msg.To,
+			Value:      msg.Value,
+			Data:       msg.Data,
+			AccessList: msg.AccessList,
+		},
+		Origin:      tx.Hash(),
+		OriginIndex: uint64(index),
+		From:        msg.From,
+		Nonce:       nonce,
+		GasPrice:    cmath.BigMin(gasPrice, msg.GasFeeCap),
+		GasTipCap:   msg.GasTipCap,
+		GasFeeCap:   msg.GasFeeCap,
+		BatchIndex:  uint64(batchIndex),
+		Sponsor:     sponsor,
 	}
-	msg.From, err = types.Sender(s, tx)
-	return msg, err
 }
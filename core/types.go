@@ -0,0 +1,46 @@
+package core
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+// Validator is an interface which defines the standard for block validation.
+// Unlike its Ethereum counterpart, the state is validated against a derived
+// Mive header rather than the header of the L1 block that was executed.
+type Validator interface {
+	// ValidateBody validates the given L1 block's content.
+	ValidateBody(block *types.Block) error
+
+	// ValidateState validates the given statedb, receipts and gas used against
+	// the given Mive header.
+	ValidateState(header *mivetypes.Header, state *state.StateDB, receipts types.Receipts, usedGas uint64) error
+}
+
+// Processor is an interface for processing L1 blocks into Mive blocks using a
+// given initial state.
+type Processor interface {
+	// Process processes the state changes according to the Mive rules by running
+	// the Mive transactions wrapped in the L1 block using the statedb. It returns
+	// the executed Mive transactions along with their receipts and logs, and the
+	// records of the beacon transactions that were rejected.
+	Process(block *types.Block, statedb *state.StateDB, cfg vm.Config) (mivetypes.Transactions, mivetypes.Rejections, types.Receipts, []*types.Log, uint64, error)
+}
+
+// L1Reader is an interface for retrieving the L1 chain data the Mive chain is
+// derived from. It's implemented by the L1 client, and can be backed by an
+// in-memory chain to derive Mive without any L1 endpoint.
+type L1Reader interface {
+	HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error)
+	BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error)
+	BlockReceipts(ctx context.Context, hash common.Hash, number uint64) ([]*types.Receipt, error)
+}
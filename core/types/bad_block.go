@@ -0,0 +1,31 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// BadBlock is a record of an L1 block whose derivation into a Mive block failed,
+// kept to help diagnosing consensus divergences.
+type BadBlock struct {
+	Origin *types.Block // L1 block the Mive block was derived from
+	Error  string       // Error the derivation failed with
+
+	// Header derived from the execution results of the L1 block and the state
+	// of the accounts touched by its Mive transactions after the execution,
+	// only available if the results failed validation.
+	Header   *Header `rlp:"nil"`
+	Accounts []*BadBlockAccount
+}
+
+// BadBlockAccount is the summary of the state of an account after executing a
+// bad block.
+type BadBlockAccount struct {
+	Address  common.Address
+	Nonce    uint64
+	Balance  *big.Int
+	CodeHash common.Hash
+	Root     common.Hash // Storage root
+}
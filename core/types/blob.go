@@ -0,0 +1,134 @@
+package types
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Blob-carrying beacon transactions (EIP-4844) carry their payload in the blobs
+// instead of the calldata. The blobs of a transaction are read as one stream,
+// in the order of their versioned hashes. The first byte of every field element
+// is left zero so that it's always below the modulus of the BLS field, and the
+// remaining 31 bytes carry data. The stream starts with the length of the
+// payload as a 4 byte big endian integer, followed by the versioned payload,
+// and is zero padded up to the end of the last blob.
+const (
+	blobUsableBytesPerFieldElement = params.BlobTxBytesPerFieldElement - 1
+	blobUsableBytes                = params.BlobTxFieldElementsPerBlob * blobUsableBytesPerFieldElement
+	blobLengthPrefixSize           = 4
+
+	// MaxBlobsPerTx is the maximum number of blobs a beacon transaction may
+	// carry, as many as fit in an L1 block.
+	MaxBlobsPerTx = params.MaxBlobGasPerBlock / params.BlobTxBlobGasPerBlob
+)
+
+var (
+	errMissingBlobs        = errors.New("missing blobs")
+	errBlobSidecarMismatch = errors.New("blob sidecar doesn't match the blob hashes")
+	errInvalidFieldElement = errors.New("invalid blob field element")
+	errBlobPayloadTooLarge = errors.New("blob payload exceeds the blob capacity")
+)
+
+// TxPayload returns the versioned payload carried by the given beacon
+// transaction: its calldata, or the data of its blobs for a blob-carrying one.
+// The blobs have to be attached to the transaction as its sidecar.
+func TxPayload(tx *types.Transaction) ([]byte, error) {
+	if tx.Type() != types.BlobTxType {
+		return tx.Data(), nil
+	}
+	sidecar := tx.BlobTxSidecar()
+	if sidecar == nil {
+		return nil, errMissingBlobs
+	}
+	hashes, sidecarHashes := tx.BlobHashes(), sidecar.BlobHashes()
+	if len(hashes) != len(sidecarHashes) || len(sidecar.Blobs) != len(hashes) {
+		return nil, errBlobSidecarMismatch
+	}
+	for i := range hashes {
+		if hashes[i] != sidecarHashes[i] {
+			return nil, errBlobSidecarMismatch
+		}
+	}
+	return DecodeBlobs(sidecar.Blobs)
+}
+
+// WithBlobSidecar returns a copy of the given blob transaction with the given
+// sidecar attached. The hash of the transaction is not affected.
+func WithBlobSidecar(tx *types.Transaction, sidecar *types.BlobTxSidecar) (*types.Transaction, error) {
+	if tx.Type() != types.BlobTxType {
+		return nil, fmt.Errorf("not a blob transaction (type %d)", tx.Type())
+	}
+	// There's no way to set the sidecar of a transaction in place, so it is
+	// reassembled from its network encoding, which includes the sidecar.
+	enc, err := tx.WithoutBlobTxSidecar().MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	inner, err := rlp.EncodeToBytes([]interface{}{rlp.RawValue(enc[1:]), sidecar.Blobs, sidecar.Commitments, sidecar.Proofs})
+	if err != nil {
+		return nil, err
+	}
+	cpy := new(types.Transaction)
+	if err := cpy.UnmarshalBinary(append([]byte{types.BlobTxType}, inner...)); err != nil {
+		return nil, err
+	}
+	return cpy, nil
+}
+
+// EncodeBlobs encodes the given versioned payload into as many blobs as needed
+// to carry it.
+func EncodeBlobs(payload []byte) ([]kzg4844.Blob, error) {
+	if len(payload) == 0 {
+		return nil, errEmptyPayload
+	}
+	size := blobLengthPrefixSize + len(payload)
+	if size > MaxBlobsPerTx*blobUsableBytes {
+		return nil, errBlobPayloadTooLarge
+	}
+	stream := make([]byte, size)
+	binary.BigEndian.PutUint32(stream, uint32(len(payload)))
+	copy(stream[blobLengthPrefixSize:], payload)
+
+	blobs := make([]kzg4844.Blob, (size+blobUsableBytes-1)/blobUsableBytes)
+	for i := range blobs {
+		for j := 0; j < params.BlobTxFieldElementsPerBlob && len(stream) > 0; j++ {
+			offset := j*params.BlobTxBytesPerFieldElement + 1
+			stream = stream[copy(blobs[i][offset:offset+blobUsableBytesPerFieldElement], stream):]
+		}
+	}
+	return blobs, nil
+}
+
+// DecodeBlobs extracts the versioned payload carried by the given blobs.
+func DecodeBlobs(blobs []kzg4844.Blob) ([]byte, error) {
+	if len(blobs) == 0 {
+		return nil, errMissingBlobs
+	}
+	if len(blobs) > MaxBlobsPerTx {
+		return nil, errBlobPayloadTooLarge
+	}
+	stream := make([]byte, 0, len(blobs)*blobUsableBytes)
+	for i := range blobs {
+		for j := 0; j < params.BlobTxFieldElementsPerBlob; j++ {
+			offset := j * params.BlobTxBytesPerFieldElement
+			if blobs[i][offset] != 0 {
+				return nil, fmt.Errorf("%w: blob %d, element %d", errInvalidFieldElement, i, j)
+			}
+			stream = append(stream, blobs[i][offset+1:offset+params.BlobTxBytesPerFieldElement]...)
+		}
+	}
+	size := binary.BigEndian.Uint32(stream)
+	if uint64(size) > uint64(len(stream)-blobLengthPrefixSize) {
+		return nil, errBlobPayloadTooLarge
+	}
+	if size == 0 {
+		return nil, errEmptyPayload
+	}
+	return stream[blobLengthPrefixSize : blobLengthPrefixSize+int(size)], nil
+}
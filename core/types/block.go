@@ -1,11 +1,13 @@
 package types
 
 import (
+	"io"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 //go:generate go run github.com/fjl/gencodec -type Header -field-override headerMarshaling -out gen_header_json.go
@@ -22,12 +24,21 @@ type Header struct {
 	ReceiptHash common.Hash `json:"receiptsRoot" gencodec:"required"`
 	Bloom       types.Bloom `json:"logsBloom"    gencodec:"required"`
 	GasUsed     uint64      `json:"gasUsed"      gencodec:"required"`
+
+	// Storage root of the withdrawal contract after the block, committing to
+	// the withdrawals initiated up to it. Zero before the withdrawals fork.
+	WithdrawalsHash common.Hash `json:"withdrawalsRoot" rlp:"optional"`
+
+	// Mive base fee of the block, nil before the Mive base fee fork, when the
+	// reduced L1 base fee applies.
+	BaseFee *big.Int `json:"baseFeePerGas" rlp:"optional"`
 }
 
 // field type overrides for gencodec
 type headerMarshaling struct {
 	Number  *hexutil.Big
 	GasUsed hexutil.Uint64
+	BaseFee *hexutil.Big
 }
 
 // CopyHeader creates a deep copy of a block header.
@@ -36,7 +47,92 @@ func CopyHeader(h *Header) *Header {
 	if cpy.Number = new(big.Int); h.Number != nil {
 		cpy.Number.Set(h.Number)
 	}
+	if h.BaseFee != nil {
+		cpy.BaseFee = new(big.Int).Set(h.BaseFee)
+	}
 	return &cpy
 }
 
 func (h *Header) NumberU64() uint64 { return h.Number.Uint64() }
+
+// Block represents a Mive block: the header derived from an L1 block along with
+// the Mive transactions that were executed in it.
+type Block struct {
+	header       *Header
+	transactions Transactions
+}
+
+// "external" block encoding. used for Mive protocol, etc.
+type extblock struct {
+	Header *Header
+	Txs    []*Transaction
+}
+
+// NewBlock creates a new block. The input data is copied, changes to header
+// and to the field values will not affect the block.
+func NewBlock(header *Header, body *Body) *Block {
+	b := &Block{header: CopyHeader(header)}
+	if body != nil && len(body.Transactions) > 0 {
+		b.transactions = make(Transactions, len(body.Transactions))
+		copy(b.transactions, body.Transactions)
+	}
+	return b
+}
+
+// NewBlockWithHeader creates a block with the given header data. The
+// header data is copied, changes to header and to the field values
+// will not affect the block.
+func NewBlockWithHeader(header *Header) *Block {
+	return &Block{header: CopyHeader(header)}
+}
+
+// DecodeRLP decodes a block from RLP.
+func (b *Block) DecodeRLP(s *rlp.Stream) error {
+	var eb extblock
+	if err := s.Decode(&eb); err != nil {
+		return err
+	}
+	b.header, b.transactions = eb.Header, eb.Txs
+	return nil
+}
+
+// EncodeRLP serializes a block as RLP.
+func (b *Block) EncodeRLP(w io.Writer) error {
+	return rlp.Encode(w, &extblock{
+		Header: b.header,
+		Txs:    b.transactions,
+	})
+}
+
+// Body returns the non-header content of the block.
+// Note the returned data is not an independent copy.
+func (b *Block) Body() *Body {
+	return &Body{Transactions: b.transactions}
+}
+
+// Transactions returns the transactions executed in the block.
+func (b *Block) Transactions() Transactions { return b.transactions }
+
+// Transaction returns the transaction with the given hash, or nil if the block
+// doesn't contain it.
+func (b *Block) Transaction(hash common.Hash) *Transaction {
+	for _, transaction := range b.transactions {
+		if transaction.Hash() == hash {
+			return transaction
+		}
+	}
+	return nil
+}
+
+// Header returns the block header (as a copy).
+func (b *Block) Header() *Header { return CopyHeader(b.header) }
+
+func (b *Block) Number() *big.Int         { return new(big.Int).Set(b.header.Number) }
+func (b *Block) NumberU64() uint64        { return b.header.Number.Uint64() }
+func (b *Block) Hash() common.Hash        { return b.header.Hash }
+func (b *Block) ParentHash() common.Hash  { return b.header.ParentHash }
+func (b *Block) Time() uint64             { return b.header.Time }
+func (b *Block) Root() common.Hash        { return b.header.Root }
+func (b *Block) ReceiptHash() common.Hash { return b.header.ReceiptHash }
+func (b *Block) Bloom() types.Bloom       { return b.header.Bloom }
+func (b *Block) GasUsed() uint64          { return b.header.GasUsed }
@@ -0,0 +1,76 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// DepositEventTopic is the topic of the event emitted by the portal contract for
+// every deposit, i.e. value locked on L1 to be minted on Mive:
+//
+//	event TransactionDeposited(address indexed from, address indexed to, uint256 mint, uint256 value, uint64 gas, bool isCreation, bytes data)
+//
+// The minted value is credited to the sender on Mive, which then sends the
+// deposit transaction. Deposits are forced into the Mive block derived from the
+// L1 block including them, ahead of the beacon transactions.
+var DepositEventTopic = crypto.Keccak256Hash([]byte("TransactionDeposited(address,address,uint256,uint256,uint64,bool,bytes)"))
+
+var errMalformedDeposit = errors.New("malformed deposit event")
+
+// Deposit is a deposit made through the portal contract.
+type Deposit struct {
+	From  common.Address  // Account credited with the minted value, and sending the transaction
+	To    *common.Address // Recipient of the transaction, nil for a contract creation
+	Mint  *big.Int        // Value minted to the sender
+	Value *big.Int        // Value transferred by the transaction
+	Gas   uint64          // Gas limit of the transaction
+	Data  []byte          // Transaction input data
+}
+
+// IsDepositEvent returns whether the given log is a deposit event emitted by the
+// given portal contract.
+func IsDepositEvent(log *types.Log, portal common.Address) bool {
+	return !log.Removed && log.Address == portal && len(log.Topics) > 0 && log.Topics[0] == DepositEventTopic
+}
+
+// ParseDeposit decodes the deposit carried by the given log, which is expected
+// to be a deposit event according to IsDepositEvent.
+func ParseDeposit(log *types.Log) (*Deposit, error) {
+	if len(log.Topics) != 3 {
+		return nil, errMalformedDeposit
+	}
+	for _, topic := range log.Topics[1:] {
+		if common.BytesToHash(topic[:common.HashLength-common.AddressLength]) != (common.Hash{}) {
+			return nil, errMalformedDeposit
+		}
+	}
+	var (
+		mint       = abiWord(log.Data, 0)
+		value      = abiWord(log.Data, 1)
+		gas        = abiWord(log.Data, 2)
+		isCreation = abiWord(log.Data, 3)
+	)
+	if mint == nil || value == nil || gas == nil || !gas.IsUint64() || isCreation == nil || isCreation.Cmp(common.Big1) > 0 {
+		return nil, errMalformedDeposit
+	}
+	data, ok := abiBytes(log.Data, 4, 5)
+	if !ok {
+		return nil, errMalformedDeposit
+	}
+	deposit := &Deposit{
+		From:  common.BytesToAddress(log.Topics[1][:]),
+		Mint:  mint,
+		Value: value,
+		Gas:   gas.Uint64(),
+		Data:  data,
+	}
+	if isCreation.Sign() == 0 {
+		to := common.BytesToAddress(log.Topics[2][:])
+		deposit.To = &to
+	}
+	return deposit, nil
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// BeaconEventTopic is the topic of the event emitted by the beacon contract for
+// every payload it originates, letting contracts submit Mive transactions:
+//
+//	event MiveTransaction(address indexed sender, bytes payload)
+//
+// The payload is a versioned payload, as carried by the calldata of a beacon
+// transaction, executed as the sender given by the event.
+var BeaconEventTopic = crypto.Keccak256Hash([]byte("MiveTransaction(address,bytes)"))
+
+var errMalformedEvent = errors.New("malformed beacon event")
+
+// BeaconEvent is a payload originated by the beacon contract.
+type BeaconEvent struct {
+	Sender  common.Address // Account the payload executes as
+	Payload []byte         // Versioned payload
+}
+
+// IsBeaconEvent returns whether the given log is a beacon event emitted by the
+// given beacon contract.
+func IsBeaconEvent(log *types.Log, contract common.Address) bool {
+	return !log.Removed && log.Address == contract && len(log.Topics) > 0 && log.Topics[0] == BeaconEventTopic
+}
+
+// ParseBeaconEvent decodes the beacon event carried by the given log, which is
+// expected to be one according to IsBeaconEvent.
+func ParseBeaconEvent(log *types.Log) (*BeaconEvent, error) {
+	if len(log.Topics) != 2 || common.BytesToHash(log.Topics[1][:common.HashLength-common.AddressLength]) != (common.Hash{}) {
+		return nil, errMalformedEvent
+	}
+	// The data is the ABI encoding of the payload
+	payload, ok := abiBytes(log.Data, 0, 1)
+	if !ok {
+		return nil, errMalformedEvent
+	}
+	return &BeaconEvent{
+		Sender:  common.BytesToAddress(log.Topics[1][:]),
+		Payload: payload,
+	}, nil
+}
+
+// abiWord returns the word at the given index of the given ABI encoded data, nil
+// if the data is too short.
+func abiWord(data []byte, index int) *big.Int {
+	if len(data) < (index+1)*common.HashLength {
+		return nil
+	}
+	return new(big.Int).SetBytes(data[index*common.HashLength : (index+1)*common.HashLength])
+}
+
+// abiBytes returns a copy of the dynamic bytes argument at the given index of
+// the given ABI encoded data, whose head has the given number of words. Only
+// the canonical encoding is accepted, with the bytes following the head.
+func abiBytes(data []byte, index int, head int) ([]byte, bool) {
+	offset := abiWord(data, index)
+	if offset == nil || offset.Cmp(big.NewInt(int64(head*common.HashLength))) != 0 {
+		return nil, false
+	}
+	size := abiWord(data, head)
+	start := (head + 1) * common.HashLength
+	if size == nil || !size.IsUint64() || size.Uint64() > uint64(len(data)-start) {
+		return nil, false
+	}
+	return common.CopyBytes(data[start : start+int(size.Uint64())]), true
+}
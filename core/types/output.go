@@ -0,0 +1,18 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// OutputVersionV0 is the version of the output roots committing to the state
+// root, the withdrawals root and the hash of a Mive block.
+var OutputVersionV0 = common.Hash{}
+
+// OutputRoot returns the output root of the Mive block with the given header,
+// the commitment published on L1 to prove withdrawals against:
+//
+//	keccak256(version ++ stateRoot ++ withdrawalsRoot ++ blockHash)
+func OutputRoot(header *Header) common.Hash {
+	return crypto.Keccak256Hash(OutputVersionV0[:], header.Root[:], header.WithdrawalsHash[:], header.Hash[:])
+}
@@ -0,0 +1,23 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Rejection is a record of a beacon transaction that didn't produce a Mive
+// transaction, either because its payload could not be decoded or because its
+// message could not be applied to the state (e.g. the sender can't pay for the
+// gas). The transactions of a batch are rejected individually.
+type Rejection struct {
+	Origin common.Hash    // Hash of the wrapping L1 transaction
+	From   common.Address // Sender of the wrapping L1 transaction
+	Reason string         // Error the transaction was rejected with
+
+	// Position of the rejected transaction in the batch carried by the beacon
+	// transaction, zero if the entire payload was rejected. For the payloads of
+	// beacon events, the position the rejected event would have started at.
+	BatchIndex uint64 `rlp:"optional"`
+}
+
+// Rejections is a list of rejection records.
+type Rejections []*Rejection
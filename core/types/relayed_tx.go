@@ -0,0 +1,18 @@
+package types
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// RelayedTx is a Mive transaction wrapped and submitted to L1 by a relayer,
+// tracked until its beacon transaction is included. The beacon transaction may
+// be replaced with a better priced one while pending, which changes the hash
+// of the Mive transaction, so it is identified by a stable handle instead.
+type RelayedTx struct {
+	Handle common.Hash        // Hash of the Mive transaction as first submitted
+	Tx     *types.Transaction // Latest submitted beacon transaction
+	Time   uint64             // Time the latest beacon transaction was submitted
+
+	Replaced []common.Hash `rlp:"optional"` // Hashes of the beacon transactions replaced by the latest one
+}
@@ -0,0 +1,177 @@
+package types
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ErrMissingAuth is returned when signing a Mive transaction, or recovering its
+// sender, without the sender authorization fields set.
+var ErrMissingAuth = errors.New("missing transaction authorization")
+
+// TxAuth is the authorization of a Mive transaction by its sender. A signed
+// transaction executes as its signer rather than as the sender of the wrapping
+// beacon transaction, who only pays for its inclusion on L1. This lets anyone
+// sponsor the inclusion of the transactions of others.
+//
+// Unlike the transactions executing as the L1 sender, which are protected from
+// replays by the L1 nonce, signed transactions carry the Mive nonce of their
+// sender, which has to match the sender's account when they execute. The Mive
+// fee per gas of the wrapping transaction is capped by the one of the sender,
+// so the sponsor can't make the sender pay arbitrary fees.
+type TxAuth struct {
+	Nonce     uint64   // Mive nonce of the sender
+	GasFeeCap *big.Int // Maximum (reduced) fee per gas the sender pays
+
+	// Signature values
+	V *big.Int
+	R *big.Int
+	S *big.Int
+}
+
+// signedTxRLP is the RLP representation of a signed Tx in the payload of a
+// beacon transaction.
+type signedTxRLP struct {
+	Nonce      uint64
+	GasFeeCap  *big.Int
+	Gas        uint64
+	To         *common.Address `rlp:"nil"`
+	Value      *big.Int
+	Data       []byte
+	AccessList types.AccessList
+	V, R, S    *big.Int
+}
+
+// newSignedTxRLP converts a signed Tx to its RLP representation.
+func newSignedTxRLP(tx *Tx) *signedTxRLP {
+	return &signedTxRLP{
+		Nonce:      tx.Auth.Nonce,
+		GasFeeCap:  tx.Auth.GasFeeCap,
+		Gas:        tx.Gas,
+		To:         tx.To,
+		Value:      tx.Value,
+		Data:       tx.Data,
+		AccessList: tx.AccessList,
+		V:          tx.Auth.V,
+		R:          tx.Auth.R,
+		S:          tx.Auth.S,
+	}
+}
+
+// tx converts the RLP representation of a signed Tx back to it.
+func (stx *signedTxRLP) tx() *Tx {
+	return &Tx{
+		Gas:        stx.Gas,
+		To:         stx.To,
+		Value:      stx.Value,
+		Data:       stx.Data,
+		AccessList: stx.AccessList,
+		Auth: &TxAuth{
+			Nonce:     stx.Nonce,
+			GasFeeCap: stx.GasFeeCap,
+			V:         stx.V,
+			R:         stx.R,
+			S:         stx.S,
+		},
+	}
+}
+
+// Signer signs Mive transactions and recovers their senders. The signatures
+// are bound to the L1 chain and the beacon address of a Mive network, so that
+// they can't be replayed on another one, nor be mistaken for the signature of
+// an L1 transaction.
+type Signer struct {
+	chainID *big.Int
+	beacon  common.Address
+}
+
+// NewSigner returns a signer for the Mive network deriving from the L1 chain
+// with the given ID and watching the given beacon address.
+func NewSigner(chainID *big.Int, beacon common.Address) Signer {
+	return Signer{chainID: new(big.Int).Set(chainID), beacon: beacon}
+}
+
+// Hash returns the hash signed by the sender of the given transaction, i.e. the
+// Keccak256 hash of its signing data.
+func (s Signer) Hash(tx *Tx) common.Hash {
+	return crypto.Keccak256Hash(s.SigningData(tx))
+}
+
+// SigningData returns the data signed by the sender of the given transaction:
+// the signed payload version followed by the RLP encoding of the signer domain
+// and the transaction fields, excluding the signature.
+func (s Signer) SigningData(tx *Tx) []byte {
+	enc, _ := rlp.EncodeToBytes([]interface{}{
+		s.chainID,
+		s.beacon,
+		tx.Auth.Nonce,
+		tx.Auth.GasFeeCap,
+		tx.Gas,
+		tx.To,
+		tx.Value,
+		tx.Data,
+		tx.AccessList,
+	})
+	return append([]byte{SignedPayloadVersion}, enc...)
+}
+
+// Sender recovers the address of the sender who signed the given transaction.
+func (s Signer) Sender(tx *Tx) (common.Address, error) {
+	if tx.Auth == nil || tx.Auth.GasFeeCap == nil || tx.Auth.V == nil || tx.Auth.R == nil || tx.Auth.S == nil {
+		return common.Address{}, ErrMissingAuth
+	}
+	v, r, sig := tx.Auth.V, tx.Auth.R, tx.Auth.S
+	if v.BitLen() > 8 || !crypto.ValidateSignatureValues(byte(v.Uint64()), r, sig, true) {
+		return common.Address{}, types.ErrInvalidSig
+	}
+	enc := make([]byte, crypto.SignatureLength)
+	r.FillBytes(enc[:32])
+	sig.FillBytes(enc[32:64])
+	enc[64] = byte(v.Uint64())
+
+	hash := s.Hash(tx)
+	pub, err := crypto.SigToPub(hash[:], enc)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// SignTx returns a copy of the given transaction signed with the given key.
+// The nonce and fee cap of its authorization have to be set.
+func SignTx(tx *Tx, s Signer, prv *ecdsa.PrivateKey) (*Tx, error) {
+	if tx.Auth == nil || tx.Auth.GasFeeCap == nil {
+		return nil, ErrMissingAuth
+	}
+	hash := s.Hash(tx)
+	sig, err := crypto.Sign(hash[:], prv)
+	if err != nil {
+		return nil, err
+	}
+	return tx.WithSignature(sig)
+}
+
+// WithSignature returns a copy of the given transaction with the given
+// signature, in the [R || S || V] format where V is 0 or 1. The nonce and fee
+// cap of its authorization have to be set.
+func (tx *Tx) WithSignature(sig []byte) (*Tx, error) {
+	if tx.Auth == nil || tx.Auth.GasFeeCap == nil {
+		return nil, ErrMissingAuth
+	}
+	if len(sig) != crypto.SignatureLength {
+		return nil, fmt.Errorf("wrong size for signature: got %d, want %d", len(sig), crypto.SignatureLength)
+	}
+	cpy, auth := *tx, *tx.Auth
+	auth.R = new(big.Int).SetBytes(sig[:32])
+	auth.S = new(big.Int).SetBytes(sig[32:64])
+	auth.V = new(big.Int).SetUint64(uint64(sig[64]))
+	cpy.Auth = &auth
+	return &cpy, nil
+}
@@ -0,0 +1,111 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Transaction is an executed Mive transaction: the Tx payload of a beacon
+// transaction along with the fields it inherits from the wrapping L1
+// transaction.
+type Transaction struct {
+	Tx          Tx             // Payload decoded from the L1 transaction data
+	Origin      common.Hash    // Hash of the wrapping L1 transaction
+	OriginIndex uint64         // Index of the wrapping L1 transaction in its block
+	From        common.Address // Signer of a signed transaction, otherwise sender of the wrapping L1 transaction
+	Nonce       uint64         // Mive nonce of the sender at the time of execution
+
+	// Fee parameters of the wrapping L1 transaction, divided by the fee
+	// reduction denominator.
+	GasPrice  *big.Int
+	GasTipCap *big.Int
+	GasFeeCap *big.Int
+
+	// Position of the transaction among the ones carried by the wrapping L1
+	// transaction, first in its payload then in the beacon events it emitted,
+	// zero if it's the only one.
+	BatchIndex uint64 `rlp:"optional"`
+
+	// Sender of the wrapping L1 transaction if it sponsored the inclusion of
+	// a transaction signed by another account, zero otherwise.
+	Sponsor common.Address `rlp:"optional"`
+
+	// Value minted to the sender before executing a deposit, nil if the
+	// transaction isn't a deposit. The position of a deposit is counted among
+	// the deposits of the wrapping L1 transaction.
+	Mint *big.Int `rlp:"optional"`
+}
+
+// Hash returns the canonical hash of the Mive transaction, which is the hash
+// of the RLP encoding of the L1 origin and the payload. Including the origin
+// makes the hash unique even if the same payload is wrapped multiple times.
+// The transactions of a batch past the first one also include their position
+// in the batch, as the same payload may appear multiple times in a batch.
+// Deposits also include the minted value, which sets them apart from the
+// transactions of the payload.
+func (tx *Transaction) Hash() common.Hash {
+	if tx.Mint != nil {
+		return rlpHash([]interface{}{tx.Origin, tx.BatchIndex, tx.Mint, &tx.Tx})
+	}
+	if tx.BatchIndex == 0 {
+		return rlpHash([]interface{}{tx.Origin, &tx.Tx})
+	}
+	return rlpHash([]interface{}{tx.Origin, tx.BatchIndex, &tx.Tx})
+}
+
+// IsDeposit returns whether the transaction is a deposit made through the portal
+// contract.
+func (tx *Transaction) IsDeposit() bool {
+	return tx.Mint != nil
+}
+
+// L1Sender returns the sender of the wrapping L1 transaction, which is the
+// sponsor of a sponsored transaction, the sender otherwise.
+func (tx *Transaction) L1Sender() common.Address {
+	if tx.Sponsor != (common.Address{}) {
+		return tx.Sponsor
+	}
+	return tx.From
+}
+
+// EffectiveGasTip returns the tip per gas the transaction paid on top of the
+// given Mive base fee, both of them being reduced L1 fees.
+func (tx *Transaction) EffectiveGasTip(baseFee *big.Int) *big.Int {
+	if baseFee == nil {
+		return new(big.Int).Set(tx.GasTipCap)
+	}
+	return math.BigMin(tx.GasTipCap, new(big.Int).Sub(tx.GasFeeCap, baseFee))
+}
+
+// Transactions implements DerivableList for transactions.
+type Transactions []*Transaction
+
+// Len returns the length of s.
+func (s Transactions) Len() int { return len(s) }
+
+// Hashes returns the hashes of all transactions in s.
+func (s Transactions) Hashes() []common.Hash {
+	hashes := make([]common.Hash, len(s))
+	for i, tx := range s {
+		hashes[i] = tx.Hash()
+	}
+	return hashes
+}
+
+// Body is a simple (mutable, non-safe) data container for storing and moving
+// a Mive block's data contents (transactions) together.
+type Body struct {
+	Transactions []*Transaction
+}
+
+// rlpHash encodes x and hashes the encoded bytes.
+func rlpHash(x interface{}) (h common.Hash) {
+	sha := crypto.NewKeccakState()
+	rlp.Encode(sha, x)
+	sha.Read(h[:])
+	return h
+}
@@ -1,12 +1,44 @@
 package types
 
 import (
+	"errors"
+	"fmt"
 	"io"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+)
+
+// Versions of the payload envelope of beacon transactions. Following EIP-2718,
+// a versioned payload is the version byte followed by the version specific
+// encoding. Legacy payloads, as in the initial beacon transactions, are bare
+// RLP encoded Tx lists, which start with a byte of at least 0xc0, so they can't
+// be mistaken for a versioned one.
+const (
+	TxPayloadVersion         = 0x01 // RLP encoded Tx
+	BatchPayloadVersion      = 0x02 // RLP encoded list of Tx, executed in order
+	CompressedPayloadVersion = 0x03 // Snappy compressed payload of another version
+	SignedPayloadVersion     = 0x04 // RLP encoded list of signed Tx, executed in order as their signers
+)
+
+// MaxDecompressedPayloadSize is the maximum size of the payload a compressed
+// payload may decompress to, so that a small beacon transaction can't make the
+// nodes allocate arbitrary amounts of memory.
+const MaxDecompressedPayloadSize = 1024 * 1024
+
+var (
+	// ErrPayloadVersionNotSupported is returned if the payload of a beacon
+	// transaction has a version this node doesn't know about.
+	ErrPayloadVersionNotSupported = errors.New("payload version not supported")
+
+	errEmptyPayload      = errors.New("empty payload")
+	errEmptyBatch        = errors.New("empty batch")
+	errMixedBatch        = errors.New("batch mixing signed and unsigned transactions")
+	errNestedCompression = errors.New("nested payload compression")
+	errPayloadTooLarge   = errors.New("decompressed payload too large")
 )
 
 // Tx represents a Mive transaction.
@@ -16,14 +48,156 @@ type Tx struct {
 	Value      *big.Int         // wei amount
 	Data       []byte           // contract invocation input data
 	AccessList types.AccessList // EIP-2930 access list
+
+	// Authorization of the sender of a signed transaction, nil if it executes
+	// as the sender of the wrapping beacon transaction. It is not part of the
+	// RLP encoding of Tx, so signing doesn't change the Mive transaction hash.
+	Auth *TxAuth `rlp:"-"`
 }
 
+// txRLP is the RLP representation of Tx. Converting to it drops the methods of
+// Tx, so the encoders below don't recurse into themselves.
+type txRLP Tx
+
 // EncodeRLP implements rlp.Encoder
 func (tx *Tx) EncodeRLP(w io.Writer) error {
-	return rlp.Encode(w, tx)
+	return rlp.Encode(w, (*txRLP)(tx))
 }
 
 // DecodeRLP implements rlp.Decoder
 func (tx *Tx) DecodeRLP(s *rlp.Stream) error {
-	return s.Decode(tx)
+	return s.Decode((*txRLP)(tx))
+}
+
+// EncodePayload encodes the given Mive transactions into the payload of a
+// beacon transaction, in the latest envelope version: a single transaction is
+// encoded on its own, several ones as a batch. Signed transactions are always
+// encoded as a batch, which may not contain unsigned ones.
+func EncodePayload(txs ...*Tx) ([]byte, error) {
+	var (
+		version byte
+		enc     []byte
+		err     error
+	)
+	switch {
+	case len(txs) == 0:
+		return nil, errEmptyBatch
+	case txs[0].Auth != nil:
+		signed := make([]*signedTxRLP, len(txs))
+		for i, tx := range txs {
+			if tx.Auth == nil {
+				return nil, errMixedBatch
+			}
+			signed[i] = newSignedTxRLP(tx)
+		}
+		version = SignedPayloadVersion
+		enc, err = rlp.EncodeToBytes(signed)
+	case len(txs) == 1:
+		version = TxPayloadVersion
+		enc, err = rlp.EncodeToBytes(txs[0])
+	default:
+		for _, tx := range txs {
+			if tx.Auth != nil {
+				return nil, errMixedBatch
+			}
+		}
+		version = BatchPayloadVersion
+		enc, err = rlp.EncodeToBytes(txs)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{version}, enc...), nil
+}
+
+// DecodePayload decodes the payload of a beacon transaction into the Mive
+// transactions it carries, in execution order, dispatching on the envelope
+// version. Payloads of unknown versions are reported with
+// ErrPayloadVersionNotSupported, so that new versions only need a new case
+// here.
+func DecodePayload(data []byte) ([]*Tx, error) {
+	if len(data) == 0 {
+		return nil, errEmptyPayload
+	}
+	if data[0] >= 0xc0 {
+		return decodeTx(data)
+	}
+	switch data[0] {
+	case TxPayloadVersion:
+		return decodeTx(data[1:])
+	case BatchPayloadVersion:
+		var txs []*Tx
+		if err := rlp.DecodeBytes(data[1:], &txs); err != nil {
+			return nil, err
+		}
+		if len(txs) == 0 {
+			return nil, errEmptyBatch
+		}
+		return txs, nil
+	case CompressedPayloadVersion:
+		payload, err := decompressPayload(data[1:])
+		if err != nil {
+			return nil, err
+		}
+		return DecodePayload(payload)
+	case SignedPayloadVersion:
+		var signed []*signedTxRLP
+		if err := rlp.DecodeBytes(data[1:], &signed); err != nil {
+			return nil, err
+		}
+		if len(signed) == 0 {
+			return nil, errEmptyBatch
+		}
+		txs := make([]*Tx, len(signed))
+		for i, stx := range signed {
+			txs[i] = stx.tx()
+		}
+		return txs, nil
+	default:
+		return nil, fmt.Errorf("%w: %#x", ErrPayloadVersionNotSupported, data[0])
+	}
+}
+
+// CompressPayload compresses the given payload of a beacon transaction into a
+// compressed payload, which decodes to the same Mive transactions.
+func CompressPayload(payload []byte) ([]byte, error) {
+	if len(payload) == 0 {
+		return nil, errEmptyPayload
+	}
+	if payload[0] == CompressedPayloadVersion {
+		return nil, errNestedCompression
+	}
+	if len(payload) > MaxDecompressedPayloadSize {
+		return nil, fmt.Errorf("%w: %d bytes", errPayloadTooLarge, len(payload))
+	}
+	return append([]byte{CompressedPayloadVersion}, snappy.Encode(nil, payload)...), nil
+}
+
+// decompressPayload decompresses the given snappy compressed payload, checking
+// its size before allocating it.
+func decompressPayload(enc []byte) ([]byte, error) {
+	size, err := snappy.DecodedLen(enc)
+	if err != nil {
+		return nil, err
+	}
+	if size > MaxDecompressedPayloadSize {
+		return nil, fmt.Errorf("%w: %d bytes", errPayloadTooLarge, size)
+	}
+	payload, err := snappy.Decode(nil, enc)
+	if err != nil {
+		return nil, err
+	}
+	if len(payload) > 0 && payload[0] == CompressedPayloadVersion {
+		return nil, errNestedCompression
+	}
+	return payload, nil
+}
+
+// decodeTx decodes an RLP encoded Mive transaction.
+func decodeTx(enc []byte) ([]*Tx, error) {
+	tx := new(Tx)
+	if err := rlp.DecodeBytes(enc, tx); err != nil {
+		return nil, err
+	}
+	return []*Tx{tx}, nil
 }
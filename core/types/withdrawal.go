@@ -0,0 +1,55 @@
+package types
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Withdrawals from Mive to L1 are initiated through the withdrawal contract,
+// predeployed at params.WithdrawalContractAddress. The contract records the
+// hash of every withdrawal it initiates in a mapping at its first storage slot:
+//
+//	mapping(bytes32 => bool) public sentWithdrawals;
+//
+// From the withdrawals fork on, the storage root of the contract is committed
+// to in the Mive headers, so a withdrawal is proven on L1 by a Merkle proof of
+// its slot against that root, itself proven against a published output.
+const withdrawalsSlot = 0
+
+// Withdrawal is a transfer from Mive to L1 initiated through the withdrawal
+// contract.
+type Withdrawal struct {
+	Nonce    *big.Int       // Sequence number of the withdrawal in the contract
+	Sender   common.Address // Account initiating the withdrawal on Mive
+	Target   common.Address // Account called on L1
+	Value    *big.Int       // Value withdrawn to the target
+	GasLimit *big.Int       // Gas limit of the call to the target on L1
+	Data     []byte         // Input data of the call to the target on L1
+}
+
+// Hash returns the hash the withdrawal is recorded under, the Keccak256 hash of
+// the ABI encoding of its fields, in order.
+func (w *Withdrawal) Hash() common.Hash {
+	var (
+		head = 6 * common.HashLength
+		size = len(w.Data)
+		enc  = make([]byte, head+common.HashLength+(size+common.HashLength-1)/common.HashLength*common.HashLength)
+	)
+	w.Nonce.FillBytes(enc[0:common.HashLength])
+	copy(enc[2*common.HashLength-common.AddressLength:], w.Sender[:])
+	copy(enc[3*common.HashLength-common.AddressLength:], w.Target[:])
+	w.Value.FillBytes(enc[3*common.HashLength : 4*common.HashLength])
+	w.GasLimit.FillBytes(enc[4*common.HashLength : 5*common.HashLength])
+	big.NewInt(int64(head)).FillBytes(enc[5*common.HashLength : head])
+	big.NewInt(int64(size)).FillBytes(enc[head : head+common.HashLength])
+	copy(enc[head+common.HashLength:], w.Data)
+	return crypto.Keccak256Hash(enc)
+}
+
+// WithdrawalStorageKey returns the storage slot of the withdrawal contract the
+// withdrawal with the given hash is recorded in.
+func WithdrawalStorageKey(hash common.Hash) common.Hash {
+	return crypto.Keccak256Hash(hash[:], common.BigToHash(big.NewInt(withdrawalsSlot)).Bytes())
+}
@@ -0,0 +1,178 @@
+package ethclient
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/kzg4844"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// beaconRequestTimeout is the timeout of a request to the beacon node.
+const beaconRequestTimeout = 30 * time.Second
+
+// ErrBlobsUnavailable is returned if the beacon node can't serve the blobs of
+// an L1 block, e.g. because they are older than its blob retention period.
+var ErrBlobsUnavailable = errors.New("blobs unavailable")
+
+// errBeaconNotFound is returned if the beacon node doesn't know the requested
+// resource.
+var errBeaconNotFound = errors.New("not found")
+
+// BeaconClient retrieves the blobs of L1 transactions from a beacon node (L1
+// consensus client) over its REST API. The blobs are not part of the execution
+// layer, so they can't be retrieved from the regular L1 endpoints.
+type BeaconClient struct {
+	url    string
+	client *http.Client
+
+	lock        sync.Mutex
+	genesisTime uint64 // Time of the beacon chain genesis, zero until retrieved
+	slotTime    uint64 // Seconds per slot of the beacon chain
+}
+
+// DialBeacon creates a client for the beacon node REST API at the given URL.
+func DialBeacon(rawurl string) (*BeaconClient, error) {
+	if !strings.HasPrefix(rawurl, "http://") && !strings.HasPrefix(rawurl, "https://") {
+		return nil, fmt.Errorf("invalid beacon endpoint %s, only HTTP is supported", redactURL(rawurl))
+	}
+	return &BeaconClient{
+		url:    strings.TrimSuffix(rawurl, "/"),
+		client: &http.Client{Timeout: beaconRequestTimeout},
+	}, nil
+}
+
+// beaconBlobSidecar is a blob sidecar as returned by the beacon node API.
+type beaconBlobSidecar struct {
+	Blob          hexutil.Bytes `json:"blob"`
+	KZGCommitment hexutil.Bytes `json:"kzg_commitment"`
+	KZGProof      hexutil.Bytes `json:"kzg_proof"`
+}
+
+// BlobSidecar retrieves the blobs with the given versioned hashes, in order,
+// included in the L1 block with the given header. The blobs are verified
+// against their commitments, so a beacon node can't make the client accept
+// anything but the blobs committed to by the hashes.
+func (c *BeaconClient) BlobSidecar(ctx context.Context, header *types.Header, hashes []common.Hash) (*types.BlobTxSidecar, error) {
+	slot, err := c.slot(ctx, header.Time)
+	if err != nil {
+		return nil, err
+	}
+	var sidecars []*beaconBlobSidecar
+	err = c.get(ctx, fmt.Sprintf("/eth/v1/beacon/blob_sidecars/%d", slot), &sidecars)
+	if errors.Is(err, errBeaconNotFound) {
+		err = ErrBlobsUnavailable
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve blobs of L1 block #%d (slot %d): %w", header.Number, slot, err)
+	}
+	available := make(map[common.Hash]*beaconBlobSidecar, len(sidecars))
+	for _, sidecar := range sidecars {
+		if len(sidecar.Blob) != len(kzg4844.Blob{}) || len(sidecar.KZGCommitment) != len(kzg4844.Commitment{}) || len(sidecar.KZGProof) != len(kzg4844.Proof{}) {
+			return nil, fmt.Errorf("malformed blob sidecar of slot %d", slot)
+		}
+		available[blobHash(sidecar.KZGCommitment)] = sidecar
+	}
+	result := new(types.BlobTxSidecar)
+	for _, hash := range hashes {
+		sidecar := available[hash]
+		if sidecar == nil {
+			return nil, fmt.Errorf("%w: blob %v of L1 block #%d not found in slot %d", ErrBlobsUnavailable, hash, header.Number, slot)
+		}
+		var (
+			blob       = kzg4844.Blob(sidecar.Blob)
+			commitment = kzg4844.Commitment(sidecar.KZGCommitment)
+			proof      = kzg4844.Proof(sidecar.KZGProof)
+		)
+		if err := kzg4844.VerifyBlobProof(blob, commitment, proof); err != nil {
+			return nil, fmt.Errorf("invalid blob %v of L1 block #%d: %w", hash, header.Number, err)
+		}
+		result.Blobs = append(result.Blobs, blob)
+		result.Commitments = append(result.Commitments, commitment)
+		result.Proofs = append(result.Proofs, proof)
+	}
+	return result, nil
+}
+
+// blobHash returns the versioned hash of the blob with the given commitment.
+func blobHash(commitment []byte) common.Hash {
+	hash := common.Hash(sha256.Sum256(commitment))
+	hash[0] = params.BlobTxHashVersion
+	return hash
+}
+
+// slot returns the beacon chain slot of the L1 block with the given time.
+func (c *BeaconClient) slot(ctx context.Context, time uint64) (uint64, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.genesisTime == 0 {
+		var genesis struct {
+			GenesisTime string `json:"genesis_time"`
+		}
+		if err := c.get(ctx, "/eth/v1/beacon/genesis", &genesis); err != nil {
+			return 0, fmt.Errorf("failed to retrieve beacon genesis: %w", err)
+		}
+		var spec struct {
+			SecondsPerSlot string `json:"SECONDS_PER_SLOT"`
+		}
+		if err := c.get(ctx, "/eth/v1/config/spec", &spec); err != nil {
+			return 0, fmt.Errorf("failed to retrieve beacon spec: %w", err)
+		}
+		genesisTime, err := strconv.ParseUint(genesis.GenesisTime, 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid beacon genesis time %q", genesis.GenesisTime)
+		}
+		slotTime, err := strconv.ParseUint(spec.SecondsPerSlot, 10, 64)
+		if err != nil || slotTime == 0 {
+			return 0, fmt.Errorf("invalid beacon slot time %q", spec.SecondsPerSlot)
+		}
+		c.genesisTime, c.slotTime = genesisTime, slotTime
+	}
+	if time < c.genesisTime {
+		return 0, fmt.Errorf("L1 block time %d predates the beacon genesis", time)
+	}
+	return (time - c.genesisTime) / c.slotTime, nil
+}
+
+// get requests the given path of the beacon node API and decodes the data
+// field of the response into result.
+func (c *BeaconClient) get(ctx context.Context, path string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+path, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errBeaconNotFound
+	case resp.StatusCode != http.StatusOK:
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("beacon node returned %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		return err
+	}
+	return json.Unmarshal(envelope.Data, result)
+}
@@ -0,0 +1,606 @@
+// Package ethclient provides the client used by Mive to retrieve data from
+// the Ethereum (L1) chain it derives from.
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ethereum-mive/mive/internal/telemetry"
+)
+
+// HistoryGapError is returned if a historical L1 block (or data belonging to
+// it) is older than the head of the L1 chain, but none of the configured
+// endpoints are able to serve it anymore. This usually means the provider has
+// pruned its chain history.
+type HistoryGapError struct {
+	Number uint64      // Number of the missing block
+	Hash   common.Hash // Hash of the missing block, if known
+	Head   uint64      // Head of the L1 chain at the time of the request
+	Err    error       // Error returned by the last endpoint queried
+}
+
+func (e *HistoryGapError) Error() string {
+	var what string
+	if e.Hash != (common.Hash{}) {
+		what = fmt.Sprintf("#%d [%x..]", e.Number, e.Hash.Bytes()[:4])
+	} else {
+		what = fmt.Sprintf("#%d", e.Number)
+	}
+	return fmt.Sprintf("L1 history unavailable for block %s (head #%d): %v", what, e.Head, e.Err)
+}
+
+func (e *HistoryGapError) Unwrap() error {
+	return e.Err
+}
+
+// Config contains the settings of the L1 client.
+type Config struct {
+	URLs       []string // L1 endpoints used to retrieve chain data
+	ArchiveURL string   // Optional endpoint only used for historical ranges
+	Policy     Policy   // Distribution of read requests across the endpoints
+
+	HealthCheckInterval time.Duration // Interval between endpoint health probes
+	MaxHeadLag          uint64        // Number of blocks an endpoint may trail the best head and still be healthy
+
+	RateLimit       float64       // Maximum number of requests per second sent to each endpoint (0 = unlimited)
+	MaxRetries      int           // Number of times a request failing with a transient error is retried
+	RetryBackoff    time.Duration // Delay before the first retry, doubled for every subsequent one
+	MaxRetryBackoff time.Duration // Maximum delay between two retries
+}
+
+// DefaultConfig contains the default settings of the L1 client.
+var DefaultConfig = Config{
+	Policy:              PolicyFailover,
+	HealthCheckInterval: 15 * time.Second,
+	MaxHeadLag:          8,
+	MaxRetries:          5,
+	RetryBackoff:        500 * time.Millisecond,
+	MaxRetryBackoff:     30 * time.Second,
+}
+
+// Client retrieves L1 chain data from a set of endpoints. Requests are
+// distributed according to the configured policy and fail over to the next
+// endpoint if one misbehaves. Historical data that none of the endpoints can
+// serve anymore is retrieved from an optional archive endpoint, which is not
+// used for anything else.
+type Client struct {
+	config    Config
+	endpoints []*endpoint
+	archive   *endpoint     // Optional endpoint only used for historical ranges
+	next      atomic.Uint32 // Cursor of the round-robin policy
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// Dial connects a client to the L1 endpoints in the given config.
+func Dial(config Config) (*Client, error) {
+	return DialContext(context.Background(), config)
+}
+
+// DialContext connects a client to the L1 endpoints in the given config.
+func DialContext(ctx context.Context, config Config) (*Client, error) {
+	if len(config.URLs) == 0 {
+		return nil, errors.New("no L1 endpoints configured")
+	}
+	switch config.Policy {
+	case "":
+		config.Policy = DefaultConfig.Policy
+	case PolicyFailover, PolicyScore, PolicyRoundRobin:
+	default:
+		return nil, fmt.Errorf("unknown L1 endpoint policy %q", config.Policy)
+	}
+	if config.HealthCheckInterval <= 0 {
+		config.HealthCheckInterval = DefaultConfig.HealthCheckInterval
+	}
+	if config.RetryBackoff <= 0 {
+		config.RetryBackoff = DefaultConfig.RetryBackoff
+	}
+	if config.MaxRetryBackoff < config.RetryBackoff {
+		config.MaxRetryBackoff = DefaultConfig.MaxRetryBackoff
+	}
+	c := &Client{
+		config: config,
+		quit:   make(chan struct{}),
+	}
+	for _, rawurl := range config.URLs {
+		ec, err := ethclient.DialContext(ctx, rawurl)
+		if err != nil {
+			c.closeEndpoints()
+			return nil, fmt.Errorf("failed to dial L1 endpoint %s: %w", redactURL(rawurl), err)
+		}
+		c.endpoints = append(c.endpoints, newEndpoint(redactURL(rawurl), ec, config.RateLimit))
+	}
+	if config.ArchiveURL != "" {
+		ec, err := ethclient.DialContext(ctx, config.ArchiveURL)
+		if err != nil {
+			c.closeEndpoints()
+			return nil, fmt.Errorf("failed to dial archive endpoint: %w", err)
+		}
+		c.archive = newEndpoint(redactURL(config.ArchiveURL), ec, config.RateLimit)
+	}
+	// Probe all endpoints once so the first requests are already routed
+	// according to their health, then keep monitoring them.
+	c.probe()
+
+	c.wg.Add(1)
+	go c.healthLoop()
+
+	return c, nil
+}
+
+// Close stops monitoring the endpoints and closes the underlying RPC connections.
+func (c *Client) Close() {
+	close(c.quit)
+	c.wg.Wait()
+	c.closeEndpoints()
+}
+
+func (c *Client) closeEndpoints() {
+	for _, e := range c.endpoints {
+		e.client.Close()
+	}
+	if c.archive != nil {
+		c.archive.client.Close()
+	}
+}
+
+// HasArchive reports whether an archive endpoint is configured.
+func (c *Client) HasArchive() bool {
+	return c.archive != nil
+}
+
+// Endpoints returns the health of the configured L1 endpoints, excluding the
+// archive endpoint.
+func (c *Client) Endpoints() []EndpointStatus {
+	best := c.bestHead()
+	statuses := make([]EndpointStatus, len(c.endpoints))
+	for i, e := range c.endpoints {
+		statuses[i] = e.status(best, c.config.MaxHeadLag)
+	}
+	return statuses
+}
+
+// healthLoop periodically probes the endpoints until the client is closed.
+func (c *Client) healthLoop() {
+	defer c.wg.Done()
+
+	ticker := time.NewTicker(c.config.HealthCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.probe()
+		case <-c.quit:
+			return
+		}
+	}
+}
+
+// probe concurrently probes all endpoints, logging health changes.
+func (c *Client) probe() {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-c.quit:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	var (
+		before = c.Endpoints()
+		wg     sync.WaitGroup
+	)
+	for _, e := range c.endpoints {
+		wg.Add(1)
+		go func(e *endpoint) {
+			defer wg.Done()
+			e.probe(ctx)
+		}(e)
+	}
+	wg.Wait()
+
+	for i, status := range c.Endpoints() {
+		switch {
+		case before[i].Healthy && !status.Healthy:
+			log.Warn("L1 endpoint became unhealthy", "url", status.URL, "lag", status.HeadLag,
+				"errrate", status.ErrRate, "err", status.Error)
+		case !before[i].Healthy && status.Healthy:
+			log.Info("L1 endpoint recovered", "url", status.URL, "latency", common.PrettyDuration(status.Latency))
+		}
+	}
+}
+
+// bestHead returns the highest L1 head reported by any of the endpoints.
+func (c *Client) bestHead() uint64 {
+	var best uint64
+	for _, e := range c.endpoints {
+		if head := e.headNumber(); head > best {
+			best = head
+		}
+	}
+	return best
+}
+
+// order returns the endpoints in the order they should be tried for the next
+// request. Healthy endpoints are ordered according to the configured policy,
+// unhealthy ones are appended in their configured order as a last resort.
+func (c *Client) order() []*endpoint {
+	var (
+		best      = c.bestHead()
+		healthy   = make([]*endpoint, 0, len(c.endpoints))
+		unhealthy []*endpoint
+		scores    = make(map[*endpoint]float64)
+	)
+	for _, e := range c.endpoints {
+		status := e.status(best, c.config.MaxHeadLag)
+		if status.Healthy {
+			healthy = append(healthy, e)
+			scores[e] = status.Score
+		} else {
+			unhealthy = append(unhealthy, e)
+		}
+	}
+	switch c.config.Policy {
+	case PolicyScore:
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return scores[healthy[i]] > scores[healthy[j]]
+		})
+	case PolicyRoundRobin:
+		if len(healthy) > 1 {
+			n := int(c.next.Add(1) % uint32(len(healthy)))
+			healthy = append(healthy[n:], healthy[:n]...)
+		}
+	}
+	return append(healthy, unhealthy...)
+}
+
+// callEndpoints executes the given request against the endpoints in the order
+// given by the read policy, failing over to the next endpoint if one returns an
+// error. If any endpoint reported the data as unavailable, that error is
+// returned in favour of other failures so that callers can detect history
+// gaps. Transient failures are preferred over hard errors, as the request may
+// succeed on the affected endpoints when retried.
+func callEndpoints[T any](ctx context.Context, c *Client, fn func(*ethclient.Client) (T, error)) (T, error) {
+	var (
+		nilT        T
+		unavailable error
+		transient   error
+		hard        error
+	)
+	for _, e := range c.order() {
+		if err := e.limiter.Wait(ctx); err != nil {
+			return nilT, err
+		}
+		_, span := telemetry.StartSpan(ctx, "l1.request", attribute.String("l1.endpoint", e.url))
+		start := time.Now()
+		res, err := fn(e.client)
+		telemetry.EndSpan(span, err)
+		if err == nil {
+			e.record(time.Since(start), nil)
+			return res, nil
+		}
+		if ctx.Err() != nil {
+			return nilT, err
+		}
+		switch {
+		case IsHistoryUnavailable(err):
+			// The endpoint works, but doesn't have the data. Another one may.
+			e.record(time.Since(start), nil)
+			if unavailable == nil {
+				unavailable = err
+			}
+			continue
+		case isTransient(err):
+			transient = err
+		default:
+			hard = err
+		}
+		log.Debug("L1 endpoint request failed", "url", e.url, "err", err)
+		e.record(time.Since(start), err)
+	}
+	switch {
+	case unavailable != nil:
+		return nilT, unavailable
+	case transient != nil:
+		return nilT, transient
+	default:
+		return nilT, hard
+	}
+}
+
+// BlockNumber returns the most recent L1 block number.
+func (c *Client) BlockNumber(ctx context.Context) (uint64, error) {
+	return call(ctx, c, func(ec *ethclient.Client) (uint64, error) {
+		return ec.BlockNumber(ctx)
+	})
+}
+
+// HeaderByHash returns the L1 block header with the given hash.
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	header, err := call(ctx, c, func(ec *ethclient.Client) (*types.Header, error) {
+		return ec.HeaderByHash(ctx, hash)
+	})
+	if err == nil || !IsHistoryUnavailable(err) || c.archive == nil {
+		return header, err
+	}
+	return c.archive.client.HeaderByHash(ctx, hash)
+}
+
+// HeaderByNumber returns an L1 block header from the current canonical chain.
+// If number is nil, the latest known header is returned.
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	header, err := call(ctx, c, func(ec *ethclient.Client) (*types.Header, error) {
+		return ec.HeaderByNumber(ctx, number)
+	})
+	if err == nil || number == nil || number.Sign() < 0 || !IsHistoryUnavailable(err) {
+		return header, err
+	}
+	return fetchHistorical(ctx, c, number.Uint64(), common.Hash{}, err, func(ec *ethclient.Client) (*types.Header, error) {
+		return ec.HeaderByNumber(ctx, number)
+	})
+}
+
+// BlockByHash returns the given full L1 block.
+func (c *Client) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	block, err := call(ctx, c, func(ec *ethclient.Client) (*types.Block, error) {
+		return ec.BlockByHash(ctx, hash)
+	})
+	if err == nil || !IsHistoryUnavailable(err) {
+		return block, err
+	}
+	// The number is not known, only a header lookup can tell whether the block
+	// exists at all or whether its body got pruned.
+	header, herr := c.HeaderByHash(ctx, hash)
+	if herr != nil {
+		return nil, err
+	}
+	return fetchHistorical(ctx, c, header.Number.Uint64(), hash, err, func(ec *ethclient.Client) (*types.Block, error) {
+		return ec.BlockByHash(ctx, hash)
+	})
+}
+
+// BlockByNumber returns an L1 block from the current canonical chain. If
+// number is nil, the latest known block is returned.
+func (c *Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	block, err := call(ctx, c, func(ec *ethclient.Client) (*types.Block, error) {
+		return ec.BlockByNumber(ctx, number)
+	})
+	if err == nil || number == nil || number.Sign() < 0 || !IsHistoryUnavailable(err) {
+		return block, err
+	}
+	return fetchHistorical(ctx, c, number.Uint64(), common.Hash{}, err, func(ec *ethclient.Client) (*types.Block, error) {
+		return ec.BlockByNumber(ctx, number)
+	})
+}
+
+// BlockReceipts returns the receipts of the given L1 block.
+func (c *Client) BlockReceipts(ctx context.Context, hash common.Hash, number uint64) ([]*types.Receipt, error) {
+	blockNrOrHash := rpc.BlockNumberOrHashWithHash(hash, false)
+	fetch := func(ec *ethclient.Client) ([]*types.Receipt, error) {
+		receipts, err := ec.BlockReceipts(ctx, blockNrOrHash)
+		if err == nil && receipts == nil {
+			err = ethereum.NotFound
+		}
+		return receipts, err
+	}
+	receipts, err := call(ctx, c, fetch)
+	if err == nil || !IsHistoryUnavailable(err) {
+		return receipts, err
+	}
+	return fetchHistorical(ctx, c, number, hash, err, fetch)
+}
+
+// SendTransaction injects a signed transaction into the pending pool of the L1
+// endpoints for execution.
+func (c *Client) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	_, err := call(ctx, c, func(ec *ethclient.Client) (struct{}, error) {
+		return struct{}{}, ec.SendTransaction(ctx, tx)
+	})
+	return err
+}
+
+// BalanceAt returns the L1 balance of the given account at the given block
+// number. If number is nil, the latest known block is used.
+func (c *Client) BalanceAt(ctx context.Context, account common.Address, number *big.Int) (*big.Int, error) {
+	return call(ctx, c, func(ec *ethclient.Client) (*big.Int, error) {
+		return ec.BalanceAt(ctx, account, number)
+	})
+}
+
+// StorageAt returns the value of the given key in the L1 storage of the given
+// account at the given block number. If number is nil, the latest known block
+// is used.
+func (c *Client) StorageAt(ctx context.Context, account common.Address, key common.Hash, number *big.Int) ([]byte, error) {
+	return call(ctx, c, func(ec *ethclient.Client) ([]byte, error) {
+		return ec.StorageAt(ctx, account, key, number)
+	})
+}
+
+// CodeAt returns the L1 contract code of the given account at the given block
+// number. If number is nil, the latest known block is used.
+func (c *Client) CodeAt(ctx context.Context, account common.Address, number *big.Int) ([]byte, error) {
+	return call(ctx, c, func(ec *ethclient.Client) ([]byte, error) {
+		return ec.CodeAt(ctx, account, number)
+	})
+}
+
+// NonceAt returns the L1 account nonce of the given account at the given
+// block number. If number is nil, the latest known block is used.
+func (c *Client) NonceAt(ctx context.Context, account common.Address, number *big.Int) (uint64, error) {
+	return call(ctx, c, func(ec *ethclient.Client) (uint64, error) {
+		return ec.NonceAt(ctx, account, number)
+	})
+}
+
+// PendingNonceAt returns the L1 account nonce of the given account in the
+// pending state, i.e. the nonce to use for the next transaction.
+func (c *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return call(ctx, c, func(ec *ethclient.Client) (uint64, error) {
+		return ec.PendingNonceAt(ctx, account)
+	})
+}
+
+// SuggestGasTipCap retrieves the L1 gas tip cap suggested by the endpoints to
+// allow a timely execution of a transaction.
+func (c *Client) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return call(ctx, c, func(ec *ethclient.Client) (*big.Int, error) {
+		return ec.SuggestGasTipCap(ctx)
+	})
+}
+
+// EstimateGas estimates the L1 gas needed to execute the given call.
+func (c *Client) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	return call(ctx, c, func(ec *ethclient.Client) (uint64, error) {
+		return ec.EstimateGas(ctx, msg)
+	})
+}
+
+// TransactionByHash returns the L1 transaction with the given hash, and
+// whether it is still pending.
+func (c *Client) TransactionByHash(ctx context.Context, hash common.Hash) (*types.Transaction, bool, error) {
+	type result struct {
+		tx      *types.Transaction
+		pending bool
+	}
+	res, err := call(ctx, c, func(ec *ethclient.Client) (result, error) {
+		tx, pending, err := ec.TransactionByHash(ctx, hash)
+		return result{tx, pending}, err
+	})
+	return res.tx, res.pending, err
+}
+
+// TransactionReceipt returns the receipt of the L1 transaction with the given
+// hash, or ethereum.NotFound if it is not included in the canonical chain.
+func (c *Client) TransactionReceipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
+	return call(ctx, c, func(ec *ethclient.Client) (*types.Receipt, error) {
+		return ec.TransactionReceipt(ctx, hash)
+	})
+}
+
+// SubscribePendingTransactions subscribes to the transactions entering the
+// pending pool of the L1 endpoints, trying the endpoints in the order given by
+// the read policy until one accepts the subscription. It requires an endpoint
+// supporting subscriptions (e.g. over websocket) and streaming full pending
+// transactions.
+func (c *Client) SubscribePendingTransactions(ctx context.Context, ch chan<- *types.Transaction) (ethereum.Subscription, error) {
+	var err error
+	for _, e := range c.order() {
+		var sub ethereum.Subscription
+		if sub, err = e.client.Client().EthSubscribe(ctx, ch, "newPendingTransactions", true); err == nil {
+			return sub, nil
+		}
+		log.Debug("L1 endpoint rejected pending transactions subscription", "url", e.url, "err", err)
+	}
+	return nil, err
+}
+
+// PendingTransactions returns the transactions in the pending pool of the L1
+// endpoints, as returned by txpool_content.
+func (c *Client) PendingTransactions(ctx context.Context) ([]*types.Transaction, error) {
+	return call(ctx, c, func(ec *ethclient.Client) ([]*types.Transaction, error) {
+		var content map[string]map[string]map[string]*types.Transaction
+		if err := ec.Client().CallContext(ctx, &content, "txpool_content"); err != nil {
+			return nil, err
+		}
+		var txs []*types.Transaction
+		for _, txsByNonce := range content["pending"] {
+			for _, tx := range txsByNonce {
+				txs = append(txs, tx)
+			}
+		}
+		return txs, nil
+	})
+}
+
+// fetchHistorical is called after the endpoints failed to serve data
+// belonging to the given block. If the block is already part of the L1 chain
+// the request is retried against the archive endpoint; a *HistoryGapError is
+// returned if that is not possible either.
+func fetchHistorical[T any](ctx context.Context, c *Client, number uint64, hash common.Hash, err error, fetch func(*ethclient.Client) (T, error)) (T, error) {
+	var nilT T
+
+	head, herr := c.BlockNumber(ctx)
+	if herr != nil {
+		return nilT, err
+	}
+	if number > head {
+		// Not a gap, the block simply doesn't exist yet
+		return nilT, err
+	}
+	if c.archive != nil {
+		if err := c.archive.limiter.Wait(ctx); err != nil {
+			return nilT, err
+		}
+		start := time.Now()
+		res, aerr := fetch(c.archive.client)
+		if aerr == nil {
+			c.archive.record(time.Since(start), nil)
+			log.Debug("Retrieved historical data from archive endpoint", "number", number)
+			return res, nil
+		}
+		if !IsHistoryUnavailable(aerr) {
+			c.archive.record(time.Since(start), aerr)
+			return nilT, aerr
+		}
+		err = aerr
+	}
+	return nilT, &HistoryGapError{Number: number, Hash: hash, Head: head, Err: err}
+}
+
+// historyErrors are error messages returned by common L1 clients when the
+// requested data has been pruned.
+var historyErrors = []string{
+	"pruned history unavailable",
+	"history has been pruned",
+	"block not found",
+	"header not found",
+}
+
+// IsHistoryUnavailable reports whether the given error signals that an L1
+// endpoint does not (or no longer) have the requested data.
+func IsHistoryUnavailable(err error) bool {
+	if errors.Is(err, ethereum.NotFound) {
+		return true
+	}
+	var gap *HistoryGapError
+	if errors.As(err, &gap) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range historyErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactURL strips the path, query and credentials from an endpoint URL, as
+// providers commonly embed API keys in them.
+func redactURL(rawurl string) string {
+	u, err := url.Parse(rawurl)
+	if err != nil || u.Host == "" {
+		return rawurl
+	}
+	return u.Scheme + "://" + u.Host
+}
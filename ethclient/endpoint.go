@@ -0,0 +1,141 @@
+package ethclient
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/metrics"
+	"golang.org/x/time/rate"
+)
+
+var (
+	requestTimer      = metrics.NewRegisteredTimer("mive/l1/requests", nil) // Latency of the requests sent to the L1 endpoints
+	requestErrorMeter = metrics.NewRegisteredMeter("mive/l1/errors", nil)   // Requests failed by the L1 endpoints
+)
+
+const (
+	healthAlpha       = 0.2             // Weight of a new sample in the moving averages
+	healthProbeTimout = 5 * time.Second // Timeout of a single health probe
+	maxHealthyErrRate = 0.5             // Error rate above which an endpoint is unhealthy
+)
+
+// Policy defines how read requests are distributed across the L1 endpoints.
+type Policy string
+
+const (
+	// PolicyFailover sends all requests to the first healthy endpoint in the
+	// configured order, only switching to the next one if it becomes unhealthy.
+	PolicyFailover Policy = "failover"
+
+	// PolicyScore sends requests to the healthy endpoint with the best health
+	// score (latency, error rate and head lag).
+	PolicyScore Policy = "score"
+
+	// PolicyRoundRobin distributes requests evenly across healthy endpoints.
+	PolicyRoundRobin Policy = "roundrobin"
+)
+
+// EndpointStatus is a snapshot of the health of an L1 endpoint.
+type EndpointStatus struct {
+	URL     string        `json:"url"`
+	Healthy bool          `json:"healthy"`
+	Score   float64       `json:"score"`
+	Latency time.Duration `json:"latency"`
+	ErrRate float64       `json:"errorRate"`
+	Head    uint64        `json:"head"`
+	HeadLag uint64        `json:"headLag"`
+	Error   string        `json:"error,omitempty"`
+}
+
+// endpoint is a single L1 RPC endpoint along with its health statistics.
+type endpoint struct {
+	url     string
+	client  *ethclient.Client
+	limiter *rate.Limiter // Limits the request rate to the endpoint
+
+	lock    sync.Mutex
+	latency time.Duration // Moving average of the request latency
+	errRate float64       // Moving average of the request error rate
+	head    uint64        // Last L1 head reported by the endpoint
+	lastErr error         // Last error returned by the endpoint
+}
+
+func newEndpoint(url string, client *ethclient.Client, rateLimit float64) *endpoint {
+	return &endpoint{url: url, client: client, limiter: newLimiter(rateLimit)}
+}
+
+// record updates the health statistics with the outcome of a request.
+func (e *endpoint) record(elapsed time.Duration, err error) {
+	requestTimer.Update(elapsed)
+	if err != nil {
+		requestErrorMeter.Mark(1)
+	}
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if e.latency == 0 {
+		e.latency = elapsed
+	} else {
+		e.latency = time.Duration((1-healthAlpha)*float64(e.latency) + healthAlpha*float64(elapsed))
+	}
+	var failed float64
+	if err != nil {
+		failed = 1
+		e.lastErr = err
+	}
+	e.errRate = (1-healthAlpha)*e.errRate + healthAlpha*failed
+}
+
+// probe queries the head of the endpoint, updating its health statistics.
+func (e *endpoint) probe(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimout)
+	defer cancel()
+
+	if err := e.limiter.Wait(ctx); err != nil {
+		return
+	}
+	start := time.Now()
+	head, err := e.client.BlockNumber(ctx)
+	if ctx.Err() == context.Canceled {
+		return // Shutting down, don't penalize the endpoint
+	}
+	e.record(time.Since(start), err)
+	if err == nil {
+		e.lock.Lock()
+		e.head = head
+		e.lock.Unlock()
+	}
+}
+
+// headNumber returns the last L1 head reported by the endpoint.
+func (e *endpoint) headNumber() uint64 {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	return e.head
+}
+
+// status returns the health of the endpoint relative to the best known head.
+func (e *endpoint) status(bestHead uint64, maxLag uint64) EndpointStatus {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	var lag uint64
+	if bestHead > e.head {
+		lag = bestHead - e.head
+	}
+	status := EndpointStatus{
+		URL:     e.url,
+		Healthy: e.errRate <= maxHealthyErrRate && lag <= maxLag,
+		Score:   (1 - e.errRate) / (1 + float64(lag)) / (1 + e.latency.Seconds()),
+		Latency: e.latency,
+		ErrRate: e.errRate,
+		Head:    e.head,
+		HeadLag: lag,
+	}
+	if e.lastErr != nil {
+		status.Error = e.lastErr.Error()
+	}
+	return status
+}
@@ -0,0 +1,116 @@
+package ethclient
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/time/rate"
+)
+
+// newLimiter creates a limiter enforcing the given number of requests per
+// second. A non-positive limit disables rate limiting.
+func newLimiter(limit float64) *rate.Limiter {
+	if limit <= 0 {
+		return rate.NewLimiter(rate.Inf, 1)
+	}
+	burst := int(limit)
+	if burst < 1 {
+		burst = 1
+	}
+	return rate.NewLimiter(rate.Limit(limit), burst)
+}
+
+// call executes the given request against the endpoints, see callEndpoints.
+// If the request fails with a transient error on all of them, it is retried
+// with a jittered exponential backoff until the retry limit is reached. Hard
+// errors are returned to the caller right away.
+func call[T any](ctx context.Context, c *Client, fn func(*ethclient.Client) (T, error)) (T, error) {
+	for attempt := 0; ; attempt++ {
+		res, err := callEndpoints(ctx, c, fn)
+		if err == nil || ctx.Err() != nil || !isTransient(err) || attempt >= c.config.MaxRetries {
+			return res, err
+		}
+		delay := c.backoff(attempt)
+		log.Debug("Retrying L1 request", "attempt", attempt+1, "delay", delay, "err", err)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return res, err
+		}
+	}
+}
+
+// backoff returns the delay before the given retry attempt. The delay doubles
+// with every attempt up to the configured maximum and is randomized by up to
+// half of its length, so that clients don't retry in lockstep.
+func (c *Client) backoff(attempt int) time.Duration {
+	delay := c.config.RetryBackoff
+	for i := 0; i < attempt && delay < c.config.MaxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > c.config.MaxRetryBackoff {
+		delay = c.config.MaxRetryBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + time.Duration(rand.Int63n(int64(delay/2)+1))
+}
+
+// transientErrors are error messages returned by common L1 clients and RPC
+// providers for failures that are likely to go away when retried.
+var transientErrors = []string{
+	"rate limit",
+	"too many requests",
+	"limit exceeded",
+	"timeout",
+	"timed out",
+	"try again",
+	"temporarily unavailable",
+	"service unavailable",
+	"bad gateway",
+	"connection reset",
+	"connection refused",
+	"unexpected eof",
+}
+
+// isTransient reports whether the given error is a temporary failure of the
+// endpoint (network issues, rate limiting, overload), as opposed to a hard
+// error that will be returned again if the request is retried.
+func isTransient(err error) bool {
+	if IsHistoryUnavailable(err) {
+		return false
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.StatusCode == 429 || httpErr.StatusCode >= 500
+	}
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == -32005 { // Limit exceeded
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, s := range transientErrors {
+		if strings.Contains(msg, s) {
+			return true
+		}
+	}
+	return false
+}
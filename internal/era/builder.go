@@ -0,0 +1,198 @@
+package era
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/golang/snappy"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/internal/era/e2store"
+)
+
+// Builder is used to create Era1 archives of Mive block data.
+//
+// Era1 files are themselves e2store files. For more information on this format,
+// see https://github.com/status-im/nimbus-eth2/blob/stable/docs/e2store.md.
+//
+// The structure can be summarized through this definition:
+//
+//	era1 := Version | block-tuple* | Accumulator | BlockIndex
+//	block-tuple :=  CompressedHeader | CompressedBody | CompressedReceipts | CompressedRejections
+//
+// Each basic element is its own entry:
+//
+//	Version              = { type: [0x65, 0x32], data: nil }
+//	CompressedHeader     = { type: [0x03, 0x00], data: snappyFramed(rlp(header)) }
+//	CompressedBody       = { type: [0x04, 0x00], data: snappyFramed(rlp(body)) }
+//	CompressedReceipts   = { type: [0x05, 0x00], data: snappyFramed(rlp(receipts)) }
+//	CompressedRejections = { type: [0x06, 0x00], data: snappyFramed(rlp(rejections)) }
+//	Accumulator          = { type: [0x07, 0x00], data: accumulator-root }
+//	BlockIndex           = { type: [0x32, 0x66], data: block-index }
+//
+// The receipts are encoded in their storage form. The accumulator is the root
+// of the trie of the block hashes, keyed by the RLP-encoded index of the block
+// in the file.
+//
+// BlockIndex stores relative offsets to each compressed block entry. The
+// format is:
+//
+//	block-index := starting-number | index | index | index ... | count
+//
+// starting-number is the first block number in the archive. Every index is a
+// defined relative to index's location in the file. The total number of block
+// entries in the file is recorded in count.
+//
+// Era1 batches are aligned to epochs of 8192 blocks, so an Era1 file holds at
+// most 8192 blocks.
+type Builder struct {
+	w        *e2store.Writer
+	startNum *uint64
+	hashes   []common.Hash
+	indexes  []uint64
+	written  int
+
+	buf    *bytes.Buffer
+	snappy *snappy.Writer
+}
+
+// NewBuilder returns a new Builder instance.
+func NewBuilder(w io.Writer) *Builder {
+	buf := bytes.NewBuffer(nil)
+	return &Builder{
+		w:      e2store.NewWriter(w),
+		buf:    buf,
+		snappy: snappy.NewBufferedWriter(buf),
+	}
+}
+
+// Add writes a compressed block entry, compressed receipts entry and
+// compressed rejections entry to the underlying e2store file.
+func (b *Builder) Add(block *mivetypes.Block, receipts types.Receipts, rejections mivetypes.Rejections) error {
+	eh, err := rlp.EncodeToBytes(block.Header())
+	if err != nil {
+		return err
+	}
+	eb, err := rlp.EncodeToBytes(block.Body())
+	if err != nil {
+		return err
+	}
+	storageReceipts := make([]*types.ReceiptForStorage, len(receipts))
+	for i, receipt := range receipts {
+		storageReceipts[i] = (*types.ReceiptForStorage)(receipt)
+	}
+	er, err := rlp.EncodeToBytes(storageReceipts)
+	if err != nil {
+		return err
+	}
+	ej, err := rlp.EncodeToBytes(rejections)
+	if err != nil {
+		return err
+	}
+	return b.AddRLP(eh, eb, er, ej, block.NumberU64(), block.Hash())
+}
+
+// AddRLP writes a compressed block entry, compressed receipts entry and
+// compressed rejections entry to the underlying e2store file.
+func (b *Builder) AddRLP(header, body, receipts, rejections []byte, number uint64, hash common.Hash) error {
+	// Write Era1 version entry before first block.
+	if b.startNum == nil {
+		n, err := b.w.Write(TypeVersion, nil)
+		if err != nil {
+			return err
+		}
+		startNum := number
+		b.startNum = &startNum
+		b.written += n
+	}
+	if len(b.indexes) >= MaxEra1Size {
+		return fmt.Errorf("exceeds maximum batch size of %d", MaxEra1Size)
+	}
+	if want := *b.startNum + uint64(len(b.indexes)); number != want {
+		return fmt.Errorf("non contiguous block #%d, want #%d", number, want)
+	}
+
+	b.indexes = append(b.indexes, uint64(b.written))
+	b.hashes = append(b.hashes, hash)
+
+	// Write block data.
+	if err := b.snappyWrite(TypeCompressedHeader, header); err != nil {
+		return err
+	}
+	if err := b.snappyWrite(TypeCompressedBody, body); err != nil {
+		return err
+	}
+	if err := b.snappyWrite(TypeCompressedReceipts, receipts); err != nil {
+		return err
+	}
+	return b.snappyWrite(TypeCompressedRejections, rejections)
+}
+
+// Finalize computes the accumulator and block index values, then writes the
+// corresponding e2store entries.
+func (b *Builder) Finalize() (common.Hash, error) {
+	if b.startNum == nil {
+		return common.Hash{}, fmt.Errorf("finalize called on empty builder")
+	}
+	// Compute accumulator root and write entry.
+	root := ComputeAccumulator(b.hashes)
+	n, err := b.w.Write(TypeAccumulator, root[:])
+	b.written += n
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("write accumulator: %w", err)
+	}
+	// Get beginning of index entry to calculate block relative offset.
+	base := int64(b.written)
+
+	// Construct block index. Detailed format described in Builder
+	// documentation, but it is essentially encoded as:
+	// "start | index | index | ... | count"
+	var (
+		count = len(b.indexes)
+		index = make([]byte, 16+count*8)
+	)
+	binary.LittleEndian.PutUint64(index, *b.startNum)
+	// Each offset is relative from the position it is encoded in the
+	// index. This means that even if the same block was to be included in
+	// the index twice (this would be invalid anyways), the relative offset
+	// would be different. The idea with this is to make it possible to
+	// lift a single block out of the index and prove it.
+	for i, offset := range b.indexes {
+		relative := int64(offset) - base
+		binary.LittleEndian.PutUint64(index[8+i*8:], uint64(relative))
+	}
+	binary.LittleEndian.PutUint64(index[8+count*8:], uint64(count))
+
+	// Finally, write the block index entry.
+	if _, err := b.w.Write(TypeBlockIndex, index); err != nil {
+		return common.Hash{}, fmt.Errorf("unable to write block index: %w", err)
+	}
+	return root, nil
+}
+
+// snappyWrite is a small helper to take care snappy encoding and writing an e2store entry.
+func (b *Builder) snappyWrite(typ uint16, in []byte) error {
+	var (
+		buf = b.buf
+		s   = b.snappy
+	)
+	buf.Reset()
+	s.Reset(buf)
+	if _, err := b.snappy.Write(in); err != nil {
+		return fmt.Errorf("error snappy encoding: %w", err)
+	}
+	if err := s.Flush(); err != nil {
+		return fmt.Errorf("error flushing snappy encoding: %w", err)
+	}
+	n, err := b.w.Write(typ, b.buf.Bytes())
+	b.written += n
+	if err != nil {
+		return fmt.Errorf("error writing e2store entry: %w", err)
+	}
+	return nil
+}
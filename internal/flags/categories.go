@@ -19,14 +19,19 @@ package flags
 import "github.com/urfave/cli/v2"
 
 const (
-	EthCategory     = "ETHEREUM"
-	PerfCategory    = "PERFORMANCE TUNING"
-	AccountCategory = "ACCOUNT"
-	APICategory     = "API AND CONSOLE"
-	VMCategory      = "VIRTUAL MACHINE"
-	LoggingCategory = "LOGGING AND DEBUGGING"
-	MetricsCategory = "METRICS AND STATS"
-	MiscCategory    = "MISC"
+	EthCategory        = "ETHEREUM"
+	MiveCategory       = "MIVE"
+	DevCategory        = "DEVELOPER CHAIN"
+	PerfCategory       = "PERFORMANCE TUNING"
+	StateCategory      = "STATE HISTORY MANAGEMENT"
+	AccountCategory    = "ACCOUNT"
+	APICategory        = "API AND CONSOLE"
+	NetworkingCategory = "NETWORKING"
+	GasPriceCategory   = "GAS PRICE ORACLE"
+	VMCategory         = "VIRTUAL MACHINE"
+	LoggingCategory    = "LOGGING AND DEBUGGING"
+	MetricsCategory    = "METRICS AND STATS"
+	MiscCategory       = "MISC"
 )
 
 func init() {
@@ -0,0 +1,1193 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miveapi
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	"github.com/ethereum-mive/mive/accounts/external"
+	mivecore "github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+const (
+	// estimateGasErrorRatio is the amount of overestimation eth_estimateGas is
+	// allowed to produce in order to speed up calculations.
+	estimateGasErrorRatio = 0.015
+
+	// describeDataLimit is the number of bytes of the Mive calldata shown to
+	// external signers.
+	describeDataLimit = 64
+)
+
+// EthereumAPI provides an API to access the fee market of the Mive chain. The
+// fees are the ones the Mive EVM sees, i.e. the fees of the wrapping L1
+// transactions reduced by the fee reduction denominator.
+type EthereumAPI struct {
+	b Backend
+}
+
+// NewEthereumAPI creates a new Mive fee market API.
+func NewEthereumAPI(b Backend) *EthereumAPI {
+	return &EthereumAPI{b}
+}
+
+// GasPrice returns a suggestion for a gas price for legacy transactions.
+func (s *EthereumAPI) GasPrice(ctx context.Context) (*hexutil.Big, error) {
+	tipcap, err := s.b.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	blockCtx, err := s.b.BlockContext(ctx, s.b.CurrentBlock())
+	if err != nil {
+		return nil, err
+	}
+	if blockCtx.BaseFee != nil {
+		tipcap.Add(tipcap, blockCtx.BaseFee)
+	}
+	return (*hexutil.Big)(tipcap), err
+}
+
+// MaxPriorityFeePerGas returns a suggestion for a gas tip cap for dynamic fee transactions.
+func (s *EthereumAPI) MaxPriorityFeePerGas(ctx context.Context) (*hexutil.Big, error) {
+	tipcap, err := s.b.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(tipcap), err
+}
+
+type feeHistoryResult struct {
+	OldestBlock  *hexutil.Big     `json:"oldestBlock"`
+	Reward       [][]*hexutil.Big `json:"reward,omitempty"`
+	BaseFee      []*hexutil.Big   `json:"baseFeePerGas,omitempty"`
+	GasUsedRatio []float64        `json:"gasUsedRatio"`
+}
+
+// FeeHistory returns the fee market history.
+func (s *EthereumAPI) FeeHistory(ctx context.Context, blockCount math.HexOrDecimal64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*feeHistoryResult, error) {
+	oldest, reward, baseFee, gasUsed, err := s.b.FeeHistory(ctx, uint64(blockCount), lastBlock, rewardPercentiles)
+	if err != nil {
+		return nil, err
+	}
+	results := &feeHistoryResult{
+		OldestBlock:  (*hexutil.Big)(oldest),
+		GasUsedRatio: gasUsed,
+	}
+	if reward != nil {
+		results.Reward = make([][]*hexutil.Big, len(reward))
+		for i, w := range reward {
+			results.Reward[i] = make([]*hexutil.Big, len(w))
+			for j, v := range w {
+				results.Reward[i][j] = (*hexutil.Big)(v)
+			}
+		}
+	}
+	if baseFee != nil {
+		results.BaseFee = make([]*hexutil.Big, len(baseFee))
+		for i, v := range baseFee {
+			results.BaseFee[i] = (*hexutil.Big)(v)
+		}
+	}
+	return results, nil
+}
+
+// EthereumAccountAPI provides an API to access accounts managed by this node.
+// It offers only methods that can retrieve accounts.
+type EthereumAccountAPI struct {
+	am *accounts.Manager
+}
+
+// NewEthereumAccountAPI creates a new EthereumAccountAPI.
+func NewEthereumAccountAPI(am *accounts.Manager) *EthereumAccountAPI {
+	return &EthereumAccountAPI{am: am}
+}
+
+// Accounts returns the collection of accounts this node manages.
+func (s *EthereumAccountAPI) Accounts() []common.Address {
+	return s.am.Accounts()
+}
+
+// BlockChainAPI provides an API to access the Mive chain and its state.
+type BlockChainAPI struct {
+	b Backend
+}
+
+// NewBlockChainAPI creates a new Mive blockchain API.
+func NewBlockChainAPI(b Backend) *BlockChainAPI {
+	return &BlockChainAPI{b}
+}
+
+// ChainId returns the Mive chain ID, distinct from the L1 one if configured.
+func (s *BlockChainAPI) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(s.b.ChainConfig().ChainID())
+}
+
+// BlockNumber returns the block number of the chain head.
+func (s *BlockChainAPI) BlockNumber() hexutil.Uint64 {
+	return hexutil.Uint64(s.b.CurrentBlock().NumberU64())
+}
+
+// GetBalance returns the amount of wei for the given address in the state of the
+// given block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta
+// block numbers are also allowed.
+func (s *BlockChainAPI) GetBalance(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	return (*hexutil.Big)(state.GetBalance(address)), state.Error()
+}
+
+// decodeHash parses a hex-encoded 32-byte hash. The input may optionally
+// be prefixed by 0x and can have a byte length up to 32.
+func decodeHash(s string) (h common.Hash, inputLength int, err error) {
+	if strings.HasPrefix(s, "0x") || strings.HasPrefix(s, "0X") {
+		s = s[2:]
+	}
+	if (len(s) & 1) > 0 {
+		s = "0" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return common.Hash{}, 0, errors.New("hex string invalid")
+	}
+	if len(b) > 32 {
+		return common.Hash{}, len(b), errors.New("hex string too long, want at most 32 bytes")
+	}
+	return common.BytesToHash(b), len(b), nil
+}
+
+// GetBlockByNumber returns the requested canonical Mive block.
+//   - When blockNr is -1 the pending block built from the observed L1 pool is
+//     returned, or the chain latest block if there is none.
+//   - When blockNr is -2 the chain latest block is returned.
+//   - When blockNr is -3 the chain finalized block is returned.
+//   - When blockNr is -4 the chain safe block is returned.
+//   - When fullTx is true all transactions in the block are returned, otherwise
+//     only the transaction hash is returned.
+func (s *BlockChainAPI) GetBlockByNumber(ctx context.Context, number rpc.BlockNumber, fullTx bool) (map[string]interface{}, error) {
+	block, err := s.b.BlockByNumber(ctx, number)
+	if block != nil && err == nil {
+		return RPCMarshalBlock(block, true, fullTx), nil
+	}
+	return nil, err
+}
+
+// GetBlockByHash returns the requested Mive block. When fullTx is true all
+// transactions in the block are returned in full detail, otherwise only the
+// transaction hash is returned.
+func (s *BlockChainAPI) GetBlockByHash(ctx context.Context, hash common.Hash, fullTx bool) (map[string]interface{}, error) {
+	block, err := s.b.BlockByHash(ctx, hash)
+	if block != nil {
+		return RPCMarshalBlock(block, true, fullTx), nil
+	}
+	return nil, err
+}
+
+// GetCode returns the code stored at the given address in the state for the given block number.
+func (s *BlockChainAPI) GetCode(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	code := state.GetCode(address)
+	return code, state.Error()
+}
+
+// GetStorageAt returns the storage from the state at the given address, key and
+// block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta block
+// numbers are also allowed.
+func (s *BlockChainAPI) GetStorageAt(ctx context.Context, address common.Address, hexKey string, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	key, _, err := decodeHash(hexKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode storage key: %s", err)
+	}
+	res := state.GetState(address, key)
+	return res[:], state.Error()
+}
+
+// GetBlockReceipts returns the block receipts for the given block hash or number or tag.
+// The receipts link the Mive transactions back to the beacon transactions on L1
+// they were wrapped in, as in eth_getTransactionReceipt.
+func (s *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
+	block, err := s.b.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if block == nil || err != nil {
+		// When the block doesn't exist, the RPC method should return JSON null
+		// as per specification.
+		return nil, nil
+	}
+	receipts, err := s.b.GetReceipts(ctx, block.Hash())
+	if err != nil {
+		return nil, err
+	}
+	txs := block.Transactions()
+	if len(txs) != len(receipts) {
+		return nil, fmt.Errorf("receipts length mismatch: %d vs %d", len(txs), len(receipts))
+	}
+	result := make([]map[string]interface{}, len(receipts))
+	for i, receipt := range receipts {
+		result[i] = marshalReceipt(receipt, block.Hash(), block.NumberU64(), txs[i], i)
+	}
+	return result, nil
+}
+
+// BlockOverrides is a set of header fields to override. The fees are expressed
+// the way the Mive EVM sees them, i.e. already reduced.
+type BlockOverrides struct {
+	Number      *hexutil.Big
+	Difficulty  *hexutil.Big
+	Time        *hexutil.Uint64
+	GasLimit    *hexutil.Uint64
+	Coinbase    *common.Address
+	Random      *common.Hash
+	BaseFee     *hexutil.Big
+	BlobBaseFee *hexutil.Big
+}
+
+// Apply overrides the given header fields into the given block context.
+func (diff *BlockOverrides) Apply(blockCtx *vm.BlockContext) {
+	if diff == nil {
+		return
+	}
+	if diff.Number != nil {
+		blockCtx.BlockNumber = diff.Number.ToInt()
+	}
+	if diff.Difficulty != nil {
+		blockCtx.Difficulty = diff.Difficulty.ToInt()
+	}
+	if diff.Time != nil {
+		blockCtx.Time = uint64(*diff.Time)
+	}
+	if diff.GasLimit != nil {
+		blockCtx.GasLimit = uint64(*diff.GasLimit)
+	}
+	if diff.Coinbase != nil {
+		blockCtx.Coinbase = *diff.Coinbase
+	}
+	if diff.Random != nil {
+		blockCtx.Random = diff.Random
+	}
+	if diff.BaseFee != nil {
+		blockCtx.BaseFee = diff.BaseFee.ToInt()
+	}
+	if diff.BlobBaseFee != nil {
+		blockCtx.BlobBaseFee = diff.BlobBaseFee.ToInt()
+	}
+}
+
+func doCall(ctx context.Context, b Backend, args TransactionArgs, state *state.StateDB, header *mivetypes.Header, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
+	// Mive messages execute in the context of the L1 block the Mive block was
+	// derived from, resolve it before the timeout starts ticking.
+	blockCtx, err := b.BlockContext(ctx, header)
+	if err != nil {
+		return nil, err
+	}
+	// Setup context so it may be cancelled the call has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var cancel context.CancelFunc
+	if timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	// Make sure the context is cancelled when the call has completed
+	// this makes sure resources are cleaned up.
+	defer cancel()
+
+	// Get a new instance of the EVM.
+	msg, err := args.ToMessage(globalGasCap, blockCtx.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+	evm := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), state, b.ChainConfig().EVMConfig(), vm.Config{NoBaseFee: true})
+
+	// Wait for the context to be done and cancel the evm. Even if the
+	// EVM has finished, cancelling may be done (repeatedly)
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+
+	// Execute the message.
+	gp := new(core.GasPool).AddGas(math.MaxUint64)
+	result, err := core.ApplyMessage(evm, msg, gp)
+	if err := state.Error(); err != nil {
+		return nil, err
+	}
+
+	// If the timer caused an abort, return an appropriate error message
+	if evm.Cancelled() {
+		return nil, fmt.Errorf("execution aborted (timeout = %v)", timeout)
+	}
+	if err != nil {
+		return result, fmt.Errorf("err: %w (supplied gas %d)", err, msg.GasLimit)
+	}
+	return result, nil
+}
+
+func DoCall(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, timeout time.Duration, globalGasCap uint64) (*core.ExecutionResult, error) {
+	defer func(start time.Time) { log.Debug("Executing EVM call finished", "runtime", time.Since(start)) }(time.Now())
+
+	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+
+	return doCall(ctx, b, args, state, header, timeout, globalGasCap)
+}
+
+func newRevertError(revert []byte) *revertError {
+	err := vm.ErrExecutionReverted
+
+	reason, errUnpack := abi.UnpackRevert(revert)
+	if errUnpack == nil {
+		err = fmt.Errorf("%w: %v", vm.ErrExecutionReverted, reason)
+	}
+	return &revertError{
+		error:  err,
+		reason: hexutil.Encode(revert),
+	}
+}
+
+// revertError is an API error that encompasses an EVM revertal with JSON error
+// code and a binary data blob.
+type revertError struct {
+	error
+	reason string // revert reason hex encoded
+}
+
+// ErrorCode returns the JSON error code for a revertal.
+// See: https://github.com/ethereum/wiki/wiki/JSON-RPC-Error-Codes-Improvement-Proposal
+func (e *revertError) ErrorCode() int {
+	return 3
+}
+
+// ErrorData returns the hex encoded revert reason.
+func (e *revertError) ErrorData() interface{} {
+	return e.reason
+}
+
+// Call executes the given message on the state of the given Mive block, as if
+// it was wrapped in a beacon transaction of the L1 block the Mive block was
+// derived from.
+//
+// Note, this function doesn't make and changes in the state/blockchain and is
+// useful to execute and retrieve values.
+func (s *BlockChainAPI) Call(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	if blockNrOrHash == nil {
+		latest := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+		blockNrOrHash = &latest
+	}
+	result, err := DoCall(ctx, s.b, args, *blockNrOrHash, s.b.RPCEVMTimeout(), s.b.RPCGasCap())
+	if err != nil {
+		return nil, err
+	}
+	// If the result contains a revert reason, try to unpack and return it.
+	if len(result.Revert()) > 0 {
+		return nil, newRevertError(result.Revert())
+	}
+	return result.Return(), result.Err
+}
+
+// DoEstimateGas returns the lowest possible gas limit that allows the transaction to run
+// successfully at block `blockNrOrHash`. It returns error if the transaction would revert, or if
+// there are unexpected failures. The gas limit is capped by both `args.Gas` (if non-nil &
+// non-zero) and `gasCap` (if non-zero).
+func DoEstimateGas(ctx context.Context, b Backend, args TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, gasCap uint64) (hexutil.Uint64, error) {
+	// Retrieve the base state and the context to execute in
+	state, header, err := b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return 0, err
+	}
+	blockCtx, err := b.BlockContext(ctx, header)
+	if err != nil {
+		return 0, err
+	}
+	// Construct the gas estimator option from the user input
+	opts := &estimateOptions{
+		Config:       b.ChainConfig(),
+		BlockContext: blockCtx,
+		State:        state,
+		ErrorRatio:   estimateGasErrorRatio,
+	}
+	// Run the gas estimation andwrap any revertals into a custom return
+	call, err := args.ToMessage(gasCap, blockCtx.BaseFee)
+	if err != nil {
+		return 0, err
+	}
+	estimate, revert, err := estimate(ctx, call, opts, gasCap)
+	if err != nil {
+		if len(revert) > 0 {
+			return 0, newRevertError(revert)
+		}
+		return 0, err
+	}
+	return hexutil.Uint64(estimate), nil
+}
+
+// EstimateGas returns the lowest possible gas limit that allows the transaction to run
+// successfully at block `blockNrOrHash`, or the latest block if `blockNrOrHash` is unspecified. It
+// returns error if the transaction would revert or if there are unexpected failures. The returned
+// value is capped by both `args.Gas` (if non-nil & non-zero) and the backend's RPCGasCap
+// configuration (if non-zero).
+func (s *BlockChainAPI) EstimateGas(ctx context.Context, args TransactionArgs, blockNrOrHash *rpc.BlockNumberOrHash) (hexutil.Uint64, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	return DoEstimateGas(ctx, s.b, args, bNrOrHash, s.b.RPCGasCap())
+}
+
+// RPCMarshalHeader converts the given Mive header to the RPC output.
+func RPCMarshalHeader(head *mivetypes.Header) map[string]interface{} {
+	result := map[string]interface{}{
+		"number":       (*hexutil.Big)(head.Number),
+		"hash":         head.Hash,
+		"parentHash":   head.ParentHash,
+		"timestamp":    hexutil.Uint64(head.Time),
+		"stateRoot":    head.Root,
+		"receiptsRoot": head.ReceiptHash,
+		"logsBloom":    head.Bloom,
+		"gasUsed":      hexutil.Uint64(head.GasUsed),
+	}
+	if head.WithdrawalsHash != (common.Hash{}) {
+		result["withdrawalsRoot"] = head.WithdrawalsHash
+	}
+	if head.BaseFee != nil {
+		result["baseFeePerGas"] = (*hexutil.Big)(head.BaseFee)
+	}
+	return result
+}
+
+// RPCMarshalBlock converts the given Mive block to the RPC output which depends
+// on fullTx. If inclTx is true transactions are returned. When fullTx is true the
+// returned block contains full transaction details, otherwise it will only
+// contain transaction hashes.
+func RPCMarshalBlock(block *mivetypes.Block, inclTx bool, fullTx bool) map[string]interface{} {
+	fields := RPCMarshalHeader(block.Header())
+
+	if inclTx {
+		formatTx := func(idx int, tx *mivetypes.Transaction) interface{} {
+			return tx.Hash()
+		}
+		if fullTx {
+			formatTx = func(idx int, tx *mivetypes.Transaction) interface{} {
+				return newRPCTransactionFromBlockIndex(block, uint64(idx))
+			}
+		}
+		txs := block.Transactions()
+		transactions := make([]interface{}, len(txs))
+		for i, tx := range txs {
+			transactions[i] = formatTx(i, tx)
+		}
+		fields["transactions"] = transactions
+	}
+	return fields
+}
+
+// RPCTransaction represents a Mive transaction that will serialize to the RPC
+// representation of a transaction. Next to the standard fields, it links the
+// transaction back to the beacon transaction on L1 it was wrapped in.
+type RPCTransaction struct {
+	BlockHash        *common.Hash      `json:"blockHash"`
+	BlockNumber      *hexutil.Big      `json:"blockNumber"`
+	From             common.Address    `json:"from"`
+	Gas              hexutil.Uint64    `json:"gas"`
+	GasPrice         *hexutil.Big      `json:"gasPrice"`
+	GasFeeCap        *hexutil.Big      `json:"maxFeePerGas,omitempty"`
+	GasTipCap        *hexutil.Big      `json:"maxPriorityFeePerGas,omitempty"`
+	Hash             common.Hash       `json:"hash"`
+	Input            hexutil.Bytes     `json:"input"`
+	Nonce            hexutil.Uint64    `json:"nonce"`
+	To               *common.Address   `json:"to"`
+	TransactionIndex *hexutil.Uint64   `json:"transactionIndex"`
+	Value            *hexutil.Big      `json:"value"`
+	Type             hexutil.Uint64    `json:"type"`
+	Accesses         *types.AccessList `json:"accessList,omitempty"`
+
+	L1TxHash   common.Hash     `json:"l1TransactionHash"`
+	L1TxIndex  *hexutil.Uint64 `json:"l1TransactionIndex"`
+	BatchIndex hexutil.Uint64  `json:"batchIndex,omitempty"`
+	Sponsor    *common.Address `json:"sponsor,omitempty"`
+	Mint       *hexutil.Big    `json:"mint,omitempty"`
+}
+
+// NewRPCTransaction returns a Mive transaction that will serialize to the RPC
+// representation, with the given location metadata set.
+func NewRPCTransaction(tx *mivetypes.Transaction, blockHash common.Hash, blockNumber uint64, index uint64) *RPCTransaction {
+	var (
+		result    = NewRPCPendingTransaction(tx)
+		l1TxIndex = hexutil.Uint64(tx.OriginIndex)
+	)
+	result.BlockHash = &blockHash
+	result.BlockNumber = (*hexutil.Big)(new(big.Int).SetUint64(blockNumber))
+	result.TransactionIndex = (*hexutil.Uint64)(&index)
+	result.L1TxIndex = &l1TxIndex
+	return result
+}
+
+// NewRPCPendingTransaction returns a pending Mive transaction, observed in the
+// pending pool of L1, that will serialize to the RPC representation. Its
+// location fields, in the Mive and in the L1 block, are left empty.
+func NewRPCPendingTransaction(tx *mivetypes.Transaction) *RPCTransaction {
+	result := &RPCTransaction{
+		From:       tx.From,
+		Gas:        hexutil.Uint64(tx.Tx.Gas),
+		GasPrice:   (*hexutil.Big)(tx.GasPrice),
+		GasFeeCap:  (*hexutil.Big)(tx.GasFeeCap),
+		GasTipCap:  (*hexutil.Big)(tx.GasTipCap),
+		Hash:       tx.Hash(),
+		Input:      hexutil.Bytes(tx.Tx.Data),
+		Nonce:      hexutil.Uint64(tx.Nonce),
+		To:         tx.Tx.To,
+		Value:      (*hexutil.Big)(tx.Tx.Value),
+		Type:       hexutil.Uint64(types.LegacyTxType), // Mive transactions are untyped
+		L1TxHash:   tx.Origin,
+		BatchIndex: hexutil.Uint64(tx.BatchIndex),
+	}
+	if tx.Tx.AccessList != nil {
+		al := tx.Tx.AccessList
+		result.Accesses = &al
+	}
+	if tx.Sponsor != (common.Address{}) {
+		sponsor := tx.Sponsor
+		result.Sponsor = &sponsor
+	}
+	if tx.IsDeposit() {
+		result.Mint = (*hexutil.Big)(tx.Mint)
+	}
+	return result
+}
+
+// newRPCTransactionFromBlockIndex returns a transaction that will serialize to the RPC representation.
+func newRPCTransactionFromBlockIndex(b *mivetypes.Block, index uint64) *RPCTransaction {
+	txs := b.Transactions()
+	if index >= uint64(len(txs)) {
+		return nil
+	}
+	return NewRPCTransaction(txs[index], b.Hash(), b.NumberU64(), index)
+}
+
+// TransactionAPI exposes methods for reading and submitting Mive transactions.
+type TransactionAPI struct {
+	b         Backend
+	nonceLock *AddrLocker
+}
+
+// NewTransactionAPI creates a new RPC service with methods for interacting with
+// Mive transactions.
+func NewTransactionAPI(b Backend, nonceLock *AddrLocker) *TransactionAPI {
+	return &TransactionAPI{b, nonceLock}
+}
+
+// GetBlockTransactionCountByNumber returns the number of transactions in the block with the given block number.
+func (s *TransactionAPI) GetBlockTransactionCountByNumber(ctx context.Context, blockNr rpc.BlockNumber) *hexutil.Uint {
+	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
+		n := hexutil.Uint(len(block.Transactions()))
+		return &n
+	}
+	return nil
+}
+
+// GetBlockTransactionCountByHash returns the number of transactions in the block with the given hash.
+func (s *TransactionAPI) GetBlockTransactionCountByHash(ctx context.Context, blockHash common.Hash) *hexutil.Uint {
+	if block, _ := s.b.BlockByHash(ctx, blockHash); block != nil {
+		n := hexutil.Uint(len(block.Transactions()))
+		return &n
+	}
+	return nil
+}
+
+// GetTransactionByBlockNumberAndIndex returns the transaction for the given block number and index.
+func (s *TransactionAPI) GetTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) *RPCTransaction {
+	if block, _ := s.b.BlockByNumber(ctx, blockNr); block != nil {
+		return newRPCTransactionFromBlockIndex(block, uint64(index))
+	}
+	return nil
+}
+
+// GetTransactionByBlockHashAndIndex returns the transaction for the given block hash and index.
+func (s *TransactionAPI) GetTransactionByBlockHashAndIndex(ctx context.Context, blockHash common.Hash, index hexutil.Uint) *RPCTransaction {
+	if block, _ := s.b.BlockByHash(ctx, blockHash); block != nil {
+		return newRPCTransactionFromBlockIndex(block, uint64(index))
+	}
+	return nil
+}
+
+// GetTransactionCount returns the Mive nonce of the given address in the state
+// of the given block. Mive has no transaction pool, the pending nonce is the
+// one of the latest block.
+func (s *TransactionAPI) GetTransactionCount(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*hexutil.Uint64, error) {
+	state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	nonce := state.GetNonce(address)
+	return (*hexutil.Uint64)(&nonce), state.Error()
+}
+
+// GetTransactionByHash returns the Mive transaction for the given hash.
+func (s *TransactionAPI) GetTransactionByHash(ctx context.Context, hash common.Hash) (*RPCTransaction, error) {
+	tx, blockHash, blockNumber, index, err := s.b.GetTransaction(ctx, hash)
+	if tx == nil || err != nil {
+		// Transaction unknown, return as such
+		return nil, err
+	}
+	return NewRPCTransaction(tx, blockHash, blockNumber, index), nil
+}
+
+// GetTransactionReceipt returns the transaction receipt for the given transaction hash.
+func (s *TransactionAPI) GetTransactionReceipt(ctx context.Context, hash common.Hash) (map[string]interface{}, error) {
+	tx, blockHash, blockNumber, index, err := s.b.GetTransaction(ctx, hash)
+	if tx == nil || err != nil {
+		// When the transaction doesn't exist, the RPC method should return JSON null
+		// as per specification.
+		return nil, nil
+	}
+	receipts, err := s.b.GetReceipts(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(receipts)) <= index {
+		return nil, nil
+	}
+	return marshalReceipt(receipts[index], blockHash, blockNumber, tx, int(index)), nil
+}
+
+// marshalReceipt marshals a transaction receipt into a JSON object. Next to the
+// standard fields, the receipt links the Mive transaction back to the beacon
+// transaction on L1 it was wrapped in.
+func marshalReceipt(receipt *types.Receipt, blockHash common.Hash, blockNumber uint64, tx *mivetypes.Transaction, txIndex int) map[string]interface{} {
+	fields := map[string]interface{}{
+		"blockHash":         blockHash,
+		"blockNumber":       hexutil.Uint64(blockNumber),
+		"transactionHash":   tx.Hash(),
+		"transactionIndex":  hexutil.Uint64(txIndex),
+		"from":              tx.From,
+		"to":                tx.Tx.To,
+		"gasUsed":           hexutil.Uint64(receipt.GasUsed),
+		"cumulativeGasUsed": hexutil.Uint64(receipt.CumulativeGasUsed),
+		"contractAddress":   nil,
+		"logs":              receipt.Logs,
+		"logsBloom":         receipt.Bloom,
+		"type":              hexutil.Uint(receipt.Type),
+		"effectiveGasPrice": (*hexutil.Big)(receipt.EffectiveGasPrice),
+
+		// Every Mive block is derived from the L1 block with the same hash
+		"l1BlockHash":        blockHash,
+		"l1TransactionHash":  tx.Origin,
+		"l1TransactionIndex": hexutil.Uint64(tx.OriginIndex),
+	}
+
+	// Assign receipt status or post state.
+	if len(receipt.PostState) > 0 {
+		fields["root"] = hexutil.Bytes(receipt.PostState)
+	} else {
+		fields["status"] = hexutil.Uint(receipt.Status)
+	}
+	if receipt.Logs == nil {
+		fields["logs"] = []*types.Log{}
+	}
+
+	// If the ContractAddress is 20 0x0 bytes, assume it is not a contract creation
+	if receipt.ContractAddress != (common.Address{}) {
+		fields["contractAddress"] = receipt.ContractAddress
+	}
+	if tx.BatchIndex != 0 {
+		fields["batchIndex"] = hexutil.Uint64(tx.BatchIndex)
+	}
+	if tx.Sponsor != (common.Address{}) {
+		fields["sponsor"] = tx.Sponsor
+	}
+	if tx.IsDeposit() {
+		fields["mint"] = (*hexutil.Big)(tx.Mint)
+	}
+	return fields
+}
+
+// SubmitTransaction is a helper function that submits a beacon transaction to
+// L1 and logs a message. It returns the hash of the Mive transaction the beacon
+// transaction executes as once it's included in an L1 block, the first one for
+// a batch.
+func SubmitTransaction(ctx context.Context, b Backend, tx *types.Transaction) (common.Hash, error) {
+	// If the transaction fee cap is already specified, ensure the
+	// fee of the given transaction is _reasonable_.
+	if err := checkTxFee(tx.GasPrice(), tx.Gas(), b.RPCTxFeeCap()); err != nil {
+		return common.Hash{}, err
+	}
+	if !b.UnprotectedAllowed() && !tx.Protected() {
+		// Ensure only eip155 signed transactions are submitted if EIP155Required is set.
+		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+	}
+	// Anything but a well-formed beacon transaction would be included on L1
+	// without being executed in Mive, reject it upfront.
+	var (
+		config = b.ChainConfig()
+		next   = new(big.Int).Add(b.CurrentBlock().Number, common.Big1)
+	)
+	if tx.Type() == types.BlobTxType {
+		if !config.IsBlobCarrier(next) {
+			return common.Hash{}, errors.New("blob-carrying beacon transactions not enabled")
+		}
+		if tx.BlobTxSidecar() == nil {
+			return common.Hash{}, errors.New("blob-carrying beacon transaction without blobs")
+		}
+	}
+	msgs, err := mivecore.TransactionToMessages(tx, next, types.LatestSigner(config.Eth), nil, config)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if len(msgs) == 0 {
+		return common.Hash{}, errors.New("not a Mive beacon transaction")
+	}
+	data, err := mivetypes.TxPayload(tx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	payload, err := mivetypes.DecodePayload(data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	mtx := &mivetypes.Transaction{Tx: *payload[0], Origin: tx.Hash()}
+	if err := b.SendTx(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	// Print a log with full tx details for manual investigations and interventions
+	if msg := msgs[0]; len(msgs) == 1 {
+		log.Info("Submitted beacon transaction", "hash", mtx.Hash().Hex(), "l1hash", tx.Hash().Hex(), "from", msg.From, "recipient", msg.To, "value", msg.Value)
+	} else {
+		log.Info("Submitted beacon transaction batch", "hash", mtx.Hash().Hex(), "l1hash", tx.Hash().Hex(), "from", msg.From, "txs", len(msgs))
+	}
+	return mtx.Hash(), nil
+}
+
+// SignBeaconTx signs the given beacon transaction with the given account of
+// the wallet. External signers are shown the Mive transaction it carries as
+// the origin of the request. Hardware wallets only sign legacy transactions,
+// so dynamic fee transactions are priced at their fee cap for them.
+func SignBeaconTx(wallet accounts.Wallet, account accounts.Account, tx *types.Transaction, chainID *big.Int) (*types.Transaction, error) {
+	switch wallet.URL().Scheme {
+	case usbwallet.LedgerScheme, usbwallet.TrezorScheme:
+		if tx.Type() != types.LegacyTxType {
+			to := *tx.To()
+			tx = types.NewTx(&types.LegacyTx{
+				Nonce:    tx.Nonce(),
+				GasPrice: tx.GasFeeCap(),
+				Gas:      tx.Gas(),
+				To:       &to,
+				Value:    tx.Value(),
+				Data:     tx.Data(),
+			})
+		}
+	}
+	if signer, ok := wallet.(*external.ExternalSigner); ok {
+		return signer.SignTxWithOrigin(account, tx, chainID, describePayload(tx.Data()))
+	}
+	return wallet.SignTx(account, tx, chainID)
+}
+
+// describePayload returns a single line description of the Mive transactions
+// carried by the given beacon transaction payload.
+func describePayload(payload []byte) string {
+	txs, err := mivetypes.DecodePayload(payload)
+	if err != nil {
+		return fmt.Sprintf("mive: invalid payload: %v", err)
+	}
+	descs := make([]string, len(txs))
+	for i, tx := range txs {
+		to := "contract creation"
+		if tx.To != nil {
+			to = tx.To.Hex()
+		}
+		data := hexutil.Encode(tx.Data)
+		if len(tx.Data) > describeDataLimit {
+			data = fmt.Sprintf("%s... (%d bytes)", hexutil.Encode(tx.Data[:describeDataLimit]), len(tx.Data))
+		}
+		descs[i] = fmt.Sprintf("to=%s value=%v gas=%d data=%s accesslist=%d", to, tx.Value, tx.Gas, data, len(tx.AccessList))
+	}
+	if len(txs) == 1 {
+		return "mive: " + descs[0]
+	}
+	return fmt.Sprintf("mive: batch of %d: %s", len(txs), strings.Join(descs, "; "))
+}
+
+// SendTransaction wraps the given Mive transaction into a beacon transaction
+// from the sender account, signs it with the wallet holding the account and
+// submits it to L1. The fees are given in Mive terms and scaled up by the fee
+// reduction denominator on L1, the nonce is the L1 one.
+func (s *TransactionAPI) SendTransaction(ctx context.Context, args TransactionArgs) (common.Hash, error) {
+	// Look up the wallet containing the requested signer
+	account := accounts.Account{Address: args.from()}
+
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	if args.Nonce == nil {
+		// Hold the mutex around signing to prevent concurrent assignment of
+		// the same nonce to multiple accounts.
+		s.nonceLock.LockAddr(args.from())
+		defer s.nonceLock.UnlockAddr(args.from())
+	}
+
+	// Set some sanity defaults and terminate on failure
+	if err := args.setBeaconDefaults(ctx, s.b); err != nil {
+		return common.Hash{}, err
+	}
+	// Assemble the beacon transaction and sign with the wallet
+	tx, err := args.toBeaconTransaction(ctx, s.b)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	signed, err := SignBeaconTx(wallet, account, tx, s.b.ChainConfig().Eth.ChainID)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return SubmitTransaction(ctx, s.b, signed)
+}
+
+// SendRawTransaction submits the signed beacon transaction to L1. The sender
+// is responsible for signing the transaction and using the correct L1 nonce.
+//
+// If the node runs a relayer, the input may also be an encoded Mive transaction
+// or batch, in any of the beacon transaction payload versions, which is wrapped
+// into a beacon transaction signed by the relayer account. Unsigned transactions
+// are executed as the relayer account, signed ones (see mive_signTransaction)
+// as their signers. The relayer may replace the beacon transaction with a
+// better priced one if it gets stuck, changing the Mive transaction hash, so
+// the returned hash is the one of the first submission.
+func (s *TransactionAPI) SendRawTransaction(ctx context.Context, input hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		mtxs, perr := mivetypes.DecodePayload(input)
+		if perr != nil {
+			return common.Hash{}, err
+		}
+		return s.b.RelayTx(ctx, mtxs)
+	}
+	return SubmitTransaction(ctx, s.b, tx)
+}
+
+// checkTxFee is an internal function used to check whether the fee of
+// the given transaction is _reasonable_(under the cap).
+func checkTxFee(gasPrice *big.Int, gas uint64, cap float64) error {
+	// Short circuit if there is no cap for transaction fee at all.
+	if cap == 0 {
+		return nil
+	}
+	feeEth := new(big.Float).Quo(new(big.Float).SetInt(new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gas))), new(big.Float).SetInt(big.NewInt(params.Ether)))
+	feeFloat, _ := feeEth.Float64()
+	if feeFloat > cap {
+		return fmt.Errorf("tx fee (%.2f ether) exceeds the configured cap (%.2f ether)", feeFloat, cap)
+	}
+	return nil
+}
+
+// BeaconAPI exposes methods for wrapping Mive transactions into beacon
+// transactions.
+type BeaconAPI struct {
+	b Backend
+}
+
+// NewBeaconAPI creates a new RPC service with methods for wrapping Mive
+// transactions.
+func NewBeaconAPI(b Backend) *BeaconAPI {
+	return &BeaconAPI{b}
+}
+
+// WrappedFees is the cost of submitting a Mive transaction through the beacon
+// address. The fees of the beacon transaction are paid on L1, the ones of the
+// Mive transaction, at the same gas price reduced by the fee reduction
+// denominator, on Mive.
+type WrappedFees struct {
+	Gas                  hexutil.Uint64 `json:"gas"`                  // Gas limit of the Mive transaction
+	L1Gas                hexutil.Uint64 `json:"l1Gas"`                // Gas limit of the beacon transaction
+	L1DataGas            hexutil.Uint64 `json:"l1DataGas"`            // Calldata gas of the beacon transaction
+	L1BaseFee            *hexutil.Big   `json:"l1BaseFee"`            // Current L1 base fee
+	MaxPriorityFeePerGas *hexutil.Big   `json:"maxPriorityFeePerGas"` // L1 tip of the beacon transaction
+	MaxFeePerGas         *hexutil.Big   `json:"maxFeePerGas"`         // L1 fee cap of the beacon transaction
+	L1Cost               *hexutil.Big   `json:"l1Cost"`               // L1 fees of the beacon transaction at the current base fee
+	Cost                 *hexutil.Big   `json:"cost"`                 // Maximum Mive fees of the Mive transaction at the current base fee
+}
+
+// EstimateWrappedFees estimates the cost of submitting the given Mive
+// transaction through the beacon address at the current L1 fees: the gas limit
+// of the Mive transaction, estimated on top of the pending block, the gas of the
+// beacon transaction wrapping it, and the fees of both. The fees are given and
+// defaulted as in eth_sendTransaction.
+func (s *BeaconAPI) EstimateWrappedFees(ctx context.Context, args TransactionArgs) (*WrappedFees, error) {
+	if err := args.setBeaconDefaults(ctx, s.b); err != nil {
+		return nil, err
+	}
+	tx, err := args.toBeaconTransaction(ctx, s.b)
+	if err != nil {
+		return nil, err
+	}
+	dataGas, err := core.IntrinsicGas(tx.Data(), nil, false, true, true, true)
+	if err != nil {
+		return nil, err
+	}
+	_, baseFee, err := s.b.SuggestL1Fees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		denom    = new(big.Int).SetUint64(s.b.ChainConfig().FeeReductionDenominator())
+		price    = math.BigMin(new(big.Int).Add(tx.GasTipCap(), baseFee), tx.GasFeeCap())
+		l1Cost   = new(big.Int).Mul(price, new(big.Int).SetUint64(tx.Gas()))
+		miveCost = new(big.Int).Mul(new(big.Int).Div(price, denom), new(big.Int).SetUint64(uint64(*args.Gas)))
+	)
+	return &WrappedFees{
+		Gas:                  *args.Gas,
+		L1Gas:                hexutil.Uint64(tx.Gas()),
+		L1DataGas:            hexutil.Uint64(dataGas - params.TxGas),
+		L1BaseFee:            (*hexutil.Big)(baseFee),
+		MaxPriorityFeePerGas: (*hexutil.Big)(tx.GasTipCap()),
+		MaxFeePerGas:         (*hexutil.Big)(tx.GasFeeCap()),
+		L1Cost:               (*hexutil.Big)(l1Cost),
+		Cost:                 (*hexutil.Big)(miveCost),
+	}, nil
+}
+
+// CompressPayload compresses the given beacon transaction payload, which must
+// decode to valid Mive transactions, into a compressed payload. Compression
+// mostly pays off for large payloads, e.g. contract deployments, the calldata
+// gas of both payloads should be compared before using the compressed one.
+func (s *BeaconAPI) CompressPayload(payload hexutil.Bytes) (hexutil.Bytes, error) {
+	if _, err := mivetypes.DecodePayload(payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", mivecore.ErrInvalidPayload, err)
+	}
+	return mivetypes.CompressPayload(payload)
+}
+
+// SignTransaction signs the given Mive transaction with the sender account and
+// returns it as the payload of a beacon transaction. As a signed transaction
+// executes as its signer, anyone can wrap the payload into a beacon transaction
+// to sponsor its inclusion on L1, e.g. a relayer through eth_sendRawTransaction.
+// The nonce is the Mive one of the sender, defaulting to the one at the latest
+// block, and the fee cap of the sender defaults as in eth_sendTransaction.
+func (s *BeaconAPI) SignTransaction(ctx context.Context, args TransactionArgs) (hexutil.Bytes, error) {
+	account := accounts.Account{Address: args.from()}
+
+	wallet, err := s.b.AccountManager().Find(account)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := wallet.(*external.ExternalSigner); ok {
+		// External signers apply their own encoding to the signed data
+		return nil, errors.New("external signers can't sign Mive transactions")
+	}
+	if args.Nonce == nil {
+		state, _, err := s.b.StateAndHeaderByNumberOrHash(ctx, rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber))
+		if state == nil || err != nil {
+			return nil, err
+		}
+		nonce := state.GetNonce(account.Address)
+		args.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	if err := args.setBeaconDefaults(ctx, s.b); err != nil {
+		return nil, err
+	}
+	feeCap := args.MaxFeePerGas
+	if args.GasPrice != nil {
+		feeCap = args.GasPrice
+	}
+	tx := args.toMiveTx()
+	tx.Auth = &mivetypes.TxAuth{Nonce: uint64(*args.Nonce), GasFeeCap: feeCap.ToInt()}
+
+	config := s.b.ChainConfig()
+	signer := mivetypes.NewSigner(config.ChainID(), config.Mive.BeaconAddress)
+	sig, err := wallet.SignData(account, accounts.MimetypeTypedData, signer.SigningData(tx))
+	if err != nil {
+		return nil, err
+	}
+	signed, err := tx.WithSignature(sig)
+	if err != nil {
+		return nil, err
+	}
+	return mivetypes.EncodePayload(signed)
+}
+
+// DebugAPI is the collection of Mive APIs exposed over the debugging
+// namespace.
+type DebugAPI struct {
+	b Backend
+}
+
+// NewDebugAPI creates a new instance of DebugAPI.
+func NewDebugAPI(b Backend) *DebugAPI {
+	return &DebugAPI{b: b}
+}
+
+// GetRawHeader retrieves the RLP encoding for a single Mive header.
+func (api *DebugAPI) GetRawHeader(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	header, err := api.b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("header %s not found", blockNrOrHash.String())
+	}
+	return rlp.EncodeToBytes(header)
+}
+
+// GetRawBlock retrieves the RLP encoding for a single Mive block, i.e. its
+// header and body.
+func (api *DebugAPI) GetRawBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (hexutil.Bytes, error) {
+	block, err := api.b.BlockByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %s not found", blockNrOrHash.String())
+	}
+	return rlp.EncodeToBytes(block)
+}
+
+// GetRawReceipts retrieves the binary-encoded receipts of a single Mive block,
+// the encoding the receipts root of the block commits to.
+func (api *DebugAPI) GetRawReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]hexutil.Bytes, error) {
+	header, err := api.b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, fmt.Errorf("block %s not found", blockNrOrHash.String())
+	}
+	receipts, err := api.b.GetReceipts(ctx, header.Hash)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]hexutil.Bytes, len(receipts))
+	for i, receipt := range receipts {
+		b, err := receipt.MarshalBinary()
+		if err != nil {
+			return nil, err
+		}
+		result[i] = b
+	}
+	return result, nil
+}
+
+// GetRawTransaction returns the RLP encoding of the executed Mive transaction
+// for the given hash, as stored in the body of its block.
+func (api *DebugAPI) GetRawTransaction(ctx context.Context, hash common.Hash) (hexutil.Bytes, error) {
+	tx, _, _, _, err := api.b.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if tx == nil {
+		// Transaction not found anywhere, abort
+		return nil, nil
+	}
+	return rlp.EncodeToBytes(tx)
+}
+
+// BadBlockArgs represents the entries in the list returned when bad blocks are
+// queried: the L1 block the derivation failed on, both as its header and RLP
+// encoding, along with the error and the summary of the execution results if
+// they failed validation.
+type BadBlockArgs struct {
+	Hash     common.Hash        `json:"hash"`
+	Number   hexutil.Uint64     `json:"number"`
+	Origin   *types.Header      `json:"origin"`
+	RLP      hexutil.Bytes      `json:"rlp"`
+	Error    string             `json:"error"`
+	Header   *mivetypes.Header  `json:"header,omitempty"`
+	Accounts []*BadBlockAccount `json:"accounts,omitempty"`
+}
+
+// BadBlockAccount represents the state of an account after executing a bad
+// block.
+type BadBlockAccount struct {
+	Address     common.Address `json:"address"`
+	Nonce       hexutil.Uint64 `json:"nonce"`
+	Balance     *hexutil.Big   `json:"balance"`
+	CodeHash    common.Hash    `json:"codeHash"`
+	StorageRoot common.Hash    `json:"storageRoot"`
+}
+
+// NewBadBlockArgs converts the record of a bad block to its RPC representation.
+func NewBadBlockArgs(bad *mivetypes.BadBlock) (*BadBlockArgs, error) {
+	blockRlp, err := rlp.EncodeToBytes(bad.Origin)
+	if err != nil {
+		return nil, err
+	}
+	result := &BadBlockArgs{
+		Hash:   bad.Origin.Hash(),
+		Number: hexutil.Uint64(bad.Origin.NumberU64()),
+		Origin: bad.Origin.Header(),
+		RLP:    blockRlp,
+		Error:  bad.Error,
+		Header: bad.Header,
+	}
+	for _, account := range bad.Accounts {
+		result.Accounts = append(result.Accounts, &BadBlockAccount{
+			Address:     account.Address,
+			Nonce:       hexutil.Uint64(account.Nonce),
+			Balance:     (*hexutil.Big)(account.Balance),
+			CodeHash:    account.CodeHash,
+			StorageRoot: account.Root,
+		})
+	}
+	return result, nil
+}
+
+// GetBadBlocks returns a list of the last L1 blocks the derivation of a Mive
+// block failed on, e.g. because the results of their execution did not match
+// the stored Mive block.
+func (api *DebugAPI) GetBadBlocks(ctx context.Context) ([]*BadBlockArgs, error) {
+	var (
+		blocks  = api.b.BadBlocks()
+		results = make([]*BadBlockArgs, 0, len(blocks))
+	)
+	for _, block := range blocks {
+		result, err := NewBadBlockArgs(block)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package miveapi implements the general Mive API functions.
+package miveapi
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	miveparams "github.com/ethereum-mive/mive/params"
+)
+
+// Backend interface provides the common API services (that are provided by
+// the Mive service) with access to the derived chain.
+type Backend interface {
+	// General Mive API
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	FeeHistory(ctx context.Context, blockCount uint64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (*big.Int, [][]*big.Int, []*big.Int, []float64, error)
+	RPCGasCap() uint64            // global gas cap for eth_call over rpc: DoS protection
+	RPCEVMTimeout() time.Duration // global timeout for eth_call over rpc: DoS protection
+	RPCTxFeeCap() float64         // global tx fee cap for all transaction related APIs
+	UnprotectedAllowed() bool     // allows only for EIP155 transactions.
+	AccountManager() *accounts.Manager
+
+	ChainConfig() *miveparams.ChainConfig
+
+	// Blockchain API
+	CurrentBlock() *mivetypes.Header
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*mivetypes.Header, error)
+	HeaderByHash(ctx context.Context, hash common.Hash) (*mivetypes.Header, error)
+	HeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*mivetypes.Header, error)
+	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*mivetypes.Block, error)
+	BlockByHash(ctx context.Context, hash common.Hash) (*mivetypes.Block, error)
+	BlockByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*mivetypes.Block, error)
+	StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *mivetypes.Header, error)
+	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
+	BadBlocks() []*mivetypes.BadBlock
+
+	// BlockContext returns the context the EVM executes Mive messages in on top
+	// of the given block, which is derived from the L1 block with the same hash.
+	BlockContext(ctx context.Context, header *mivetypes.Header) (vm.BlockContext, error)
+
+	// Transaction API
+	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	// RelayTx wraps the given Mive transactions, as a batch if there are more
+	// than one, into a beacon transaction signed by the relayer account of the
+	// node and submits it, returning the handle tracking it across replacements.
+	RelayTx(ctx context.Context, txs []*mivetypes.Tx) (common.Hash, error)
+	GetTransaction(ctx context.Context, txHash common.Hash) (*mivetypes.Transaction, common.Hash, uint64, uint64, error)
+
+	// L1 API, used to wrap Mive transactions into beacon transactions
+	L1PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	// SuggestL1Fees returns the L1 tip and base fee beacon transactions are
+	// priced at. The base fee is zero before London.
+	SuggestL1Fees(ctx context.Context) (*big.Int, *big.Int, error)
+	EstimateL1Gas(ctx context.Context, call ethereum.CallMsg) (uint64, error)
+}
+
+func GetAPIs(apiBackend Backend) []rpc.API {
+	return []rpc.API{
+		{
+			Namespace: "eth",
+			Service:   NewEthereumAPI(apiBackend),
+		}, {
+			Namespace: "eth",
+			Service:   NewBlockChainAPI(apiBackend),
+		}, {
+			Namespace: "eth",
+			Service:   NewTransactionAPI(apiBackend, new(AddrLocker)),
+		}, {
+			Namespace: "eth",
+			Service:   NewEthereumAccountAPI(apiBackend.AccountManager()),
+		}, {
+			Namespace: "mive",
+			Service:   NewBeaconAPI(apiBackend),
+		}, {
+			Namespace: "debug",
+			Service:   NewDebugAPI(apiBackend),
+		},
+	}
+}
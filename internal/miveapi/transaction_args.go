@@ -0,0 +1,296 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miveapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+// TransactionArgs represents the arguments to construct a Mive message call.
+// Fees are given in Mive terms, i.e. already divided by the fee reduction
+// denominator.
+type TransactionArgs struct {
+	From                 *common.Address `json:"from"`
+	To                   *common.Address `json:"to"`
+	Gas                  *hexutil.Uint64 `json:"gas"`
+	GasPrice             *hexutil.Big    `json:"gasPrice"`
+	MaxFeePerGas         *hexutil.Big    `json:"maxFeePerGas"`
+	MaxPriorityFeePerGas *hexutil.Big    `json:"maxPriorityFeePerGas"`
+	Value                *hexutil.Big    `json:"value"`
+
+	// Nonce of the wrapping L1 transaction, only used when submitting.
+	Nonce *hexutil.Uint64 `json:"nonce"`
+
+	// We accept "data" and "input" for backwards-compatibility reasons.
+	// "input" is the newer name and should be preferred by clients.
+	// Issue detail: https://github.com/ethereum/go-ethereum/issues/15628
+	Data  *hexutil.Bytes `json:"data"`
+	Input *hexutil.Bytes `json:"input"`
+
+	AccessList *types.AccessList `json:"accessList,omitempty"`
+}
+
+// from retrieves the transaction sender address.
+func (args *TransactionArgs) from() common.Address {
+	if args.From == nil {
+		return common.Address{}
+	}
+	return *args.From
+}
+
+// data retrieves the transaction calldata. Input field is preferred.
+func (args *TransactionArgs) data() []byte {
+	if args.Input != nil {
+		return *args.Input
+	}
+	if args.Data != nil {
+		return *args.Data
+	}
+	return nil
+}
+
+// setBeaconDefaults fills in the defaults of the fields needed to wrap the
+// arguments into a beacon transaction. The gas limit is estimated on top of the
+// pending block, the fees default to the current L1 ones and the nonce to the
+// next one of the sender in the L1 pool.
+func (args *TransactionArgs) setBeaconDefaults(ctx context.Context, b Backend) error {
+	if args.GasPrice != nil && (args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil) {
+		return errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+	}
+	if args.Data != nil && args.Input != nil && !bytes.Equal(*args.Data, *args.Input) {
+		return errors.New(`both "data" and "input" are set and not equal. Please use "input" to pass transaction call data`)
+	}
+	if args.To == nil && len(args.data()) == 0 {
+		return errors.New(`contract creation without any data provided`)
+	}
+	if args.Value == nil {
+		args.Value = new(hexutil.Big)
+	}
+	if args.Gas == nil {
+		// These fields are immutable during the estimation, safe to
+		// pass the pointer directly.
+		data := args.data()
+		callArgs := TransactionArgs{
+			From:       args.From,
+			To:         args.To,
+			Value:      args.Value,
+			Data:       (*hexutil.Bytes)(&data),
+			AccessList: args.AccessList,
+		}
+		pending := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+		estimated, err := DoEstimateGas(ctx, b, callArgs, pending, b.RPCGasCap())
+		if err != nil {
+			return err
+		}
+		args.Gas = &estimated
+		log.Trace("Estimate gas usage automatically", "gas", args.Gas)
+	}
+	if err := args.setBeaconFeeDefaults(ctx, b); err != nil {
+		return err
+	}
+	if args.Nonce == nil {
+		nonce, err := b.L1PendingNonceAt(ctx, args.from())
+		if err != nil {
+			return err
+		}
+		args.Nonce = (*hexutil.Uint64)(&nonce)
+	}
+	return nil
+}
+
+// setBeaconFeeDefaults fills in the missing fees from the current L1 ones,
+// scaled down to Mive terms and rounded up. The fee cap leaves room for the L1
+// base fee to rise over the next few blocks.
+func (args *TransactionArgs) setBeaconFeeDefaults(ctx context.Context, b Backend) error {
+	if args.GasPrice != nil {
+		return nil
+	}
+	if args.MaxFeePerGas == nil || args.MaxPriorityFeePerGas == nil {
+		tip, baseFee, err := b.SuggestL1Fees(ctx)
+		if err != nil {
+			return err
+		}
+		denom := new(big.Int).SetUint64(b.ChainConfig().FeeReductionDenominator())
+		if args.MaxPriorityFeePerGas == nil {
+			args.MaxPriorityFeePerGas = (*hexutil.Big)(divRoundUp(tip, denom))
+		}
+		if args.MaxFeePerGas == nil {
+			feeCap := divRoundUp(new(big.Int).Mul(baseFee, common.Big2), denom)
+			args.MaxFeePerGas = (*hexutil.Big)(feeCap.Add(feeCap, args.MaxPriorityFeePerGas.ToInt()))
+		}
+	}
+	if args.MaxFeePerGas.ToInt().Cmp(args.MaxPriorityFeePerGas.ToInt()) < 0 {
+		return fmt.Errorf("maxFeePerGas (%v) < maxPriorityFeePerGas (%v)", args.MaxFeePerGas, args.MaxPriorityFeePerGas)
+	}
+	return nil
+}
+
+// toMiveTx converts the arguments to the Mive transaction carried by a beacon
+// transaction. This assumes that setBeaconDefaults has been called.
+func (args *TransactionArgs) toMiveTx() *mivetypes.Tx {
+	tx := &mivetypes.Tx{
+		Gas:   uint64(*args.Gas),
+		To:    args.To,
+		Value: (*big.Int)(args.Value),
+		Data:  args.data(),
+	}
+	if args.AccessList != nil {
+		tx.AccessList = *args.AccessList
+	}
+	return tx
+}
+
+// toBeaconTransaction wraps the Mive transaction into an unsigned beacon
+// transaction, with the fees scaled up to L1 terms and the gas limit estimated
+// on L1. This assumes that setBeaconDefaults has been called.
+func (args *TransactionArgs) toBeaconTransaction(ctx context.Context, b Backend) (*types.Transaction, error) {
+	payload, err := mivetypes.EncodePayload(args.toMiveTx())
+	if err != nil {
+		return nil, err
+	}
+	var (
+		config = b.ChainConfig()
+		beacon = config.Beacon(new(big.Int).Add(b.CurrentBlock().Number, common.Big1))
+		denom  = new(big.Int).SetUint64(config.FeeReductionDenominator())
+	)
+	gas, err := b.EstimateL1Gas(ctx, ethereum.CallMsg{From: args.from(), To: &beacon, Data: payload})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate L1 gas: %w", err)
+	}
+	if args.GasPrice != nil {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    uint64(*args.Nonce),
+			GasPrice: new(big.Int).Mul(args.GasPrice.ToInt(), denom),
+			Gas:      gas,
+			To:       &beacon,
+			Data:     payload,
+		}), nil
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:   config.Eth.ChainID,
+		Nonce:     uint64(*args.Nonce),
+		GasTipCap: new(big.Int).Mul(args.MaxPriorityFeePerGas.ToInt(), denom),
+		GasFeeCap: new(big.Int).Mul(args.MaxFeePerGas.ToInt(), denom),
+		Gas:       gas,
+		To:        &beacon,
+		Data:      payload,
+	}), nil
+}
+
+// divRoundUp returns x / y rounded up.
+func divRoundUp(x, y *big.Int) *big.Int {
+	z := new(big.Int).Add(x, y)
+	z.Sub(z, common.Big1)
+	return z.Div(z, y)
+}
+
+// ToMessage converts the transaction arguments to the Message type used by the
+// core evm. This method is used in calls and traces that do not require a real
+// live transaction. The base fee is the one of the Mive block context.
+func (args *TransactionArgs) ToMessage(globalGasCap uint64, baseFee *big.Int) (*core.Message, error) {
+	// Reject invalid combinations of pre- and post-1559 fee styles
+	if args.GasPrice != nil && (args.MaxFeePerGas != nil || args.MaxPriorityFeePerGas != nil) {
+		return nil, errors.New("both gasPrice and (maxFeePerGas or maxPriorityFeePerGas) specified")
+	}
+	// Set sender address or use zero address if none specified.
+	addr := args.from()
+
+	// Set default gas & gas price if none were set
+	gas := globalGasCap
+	if gas == 0 {
+		gas = uint64(math.MaxUint64 / 2)
+	}
+	if args.Gas != nil {
+		gas = uint64(*args.Gas)
+	}
+	if globalGasCap != 0 && globalGasCap < gas {
+		log.Warn("Caller gas above allowance, capping", "requested", gas, "cap", globalGasCap)
+		gas = globalGasCap
+	}
+	var (
+		gasPrice  *big.Int
+		gasFeeCap *big.Int
+		gasTipCap *big.Int
+	)
+	if baseFee == nil {
+		// If there's no basefee, then it must be a non-1559 execution
+		gasPrice = new(big.Int)
+		if args.GasPrice != nil {
+			gasPrice = args.GasPrice.ToInt()
+		}
+		gasFeeCap, gasTipCap = gasPrice, gasPrice
+	} else {
+		// A basefee is provided, necessitating 1559-type execution
+		if args.GasPrice != nil {
+			// User specified the legacy gas field, convert to 1559 gas typing
+			gasPrice = args.GasPrice.ToInt()
+			gasFeeCap, gasTipCap = gasPrice, gasPrice
+		} else {
+			// User specified 1559 gas fields (or none), use those
+			gasFeeCap = new(big.Int)
+			if args.MaxFeePerGas != nil {
+				gasFeeCap = args.MaxFeePerGas.ToInt()
+			}
+			gasTipCap = new(big.Int)
+			if args.MaxPriorityFeePerGas != nil {
+				gasTipCap = args.MaxPriorityFeePerGas.ToInt()
+			}
+			// Backfill the legacy gasPrice for EVM execution, unless we're all zeroes
+			gasPrice = new(big.Int)
+			if gasFeeCap.BitLen() > 0 || gasTipCap.BitLen() > 0 {
+				gasPrice = math.BigMin(new(big.Int).Add(gasTipCap, baseFee), gasFeeCap)
+			}
+		}
+	}
+	value := new(big.Int)
+	if args.Value != nil {
+		value = args.Value.ToInt()
+	}
+	data := args.data()
+	var accessList types.AccessList
+	if args.AccessList != nil {
+		accessList = *args.AccessList
+	}
+	msg := &core.Message{
+		From:              addr,
+		To:                args.To,
+		Value:             value,
+		GasLimit:          gas,
+		GasPrice:          gasPrice,
+		GasFeeCap:         gasFeeCap,
+		GasTipCap:         gasTipCap,
+		Data:              data,
+		AccessList:        accessList,
+		SkipAccountChecks: true,
+	}
+	return msg, nil
+}
@@ -17,6 +17,7 @@
 package shutdowncheck
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -31,6 +32,9 @@ import (
 type ShutdownTracker struct {
 	db     ethdb.Database
 	stopCh chan struct{}
+
+	unclean   []uint64 // Unclean shutdowns found on startup
+	discarded uint64   // Number of older unclean shutdowns no longer recorded
 }
 
 // NewShutdownTracker creates a new ShutdownTracker instance and has
@@ -49,6 +53,7 @@ func (t *ShutdownTracker) MarkStartup() {
 	if uncleanShutdowns, discards, err := rawdb.PushUncleanShutdownMarker(t.db); err != nil {
 		log.Error("Could not update unclean-shutdown-marker list", "error", err)
 	} else {
+		t.unclean, t.discarded = uncleanShutdowns, discards
 		if discards > 0 {
 			log.Warn("Old unclean shutdowns found", "count", discards)
 		}
@@ -60,6 +65,30 @@ func (t *ShutdownTracker) MarkStartup() {
 	}
 }
 
+// UncleanShutdowns returns the timestamps of the unclean shutdowns found on
+// startup, and the number of older ones which are no longer recorded.
+func (t *ShutdownTracker) UncleanShutdowns() ([]uint64, uint64) {
+	return t.unclean, t.discarded
+}
+
+// Description returns a human-readable summary of the unclean shutdowns found on
+// startup, for the startup banner. The time of an unclean shutdown is the last
+// time the node was seen running, at most 5 minutes before it went down.
+func (t *ShutdownTracker) Description() string {
+	if len(t.unclean) == 0 && t.discarded == 0 {
+		return "Unclean shutdowns: none\n"
+	}
+	banner := fmt.Sprintf("Unclean shutdowns: %d\n", uint64(len(t.unclean))+t.discarded)
+	for i := len(t.unclean) - 1; i >= 0; i-- {
+		seen := time.Unix(int64(t.unclean[i]), 0)
+		banner += fmt.Sprintf(" - %v (%v ago)\n", seen.UTC().Format(time.RFC3339), common.PrettyAge(seen))
+	}
+	if t.discarded > 0 {
+		banner += fmt.Sprintf(" - %d older, no longer recorded\n", t.discarded)
+	}
+	return banner
+}
+
 // Start runs an event loop that updates the current marker's timestamp every 5 minutes.
 func (t *ShutdownTracker) Start() {
 	go func() {
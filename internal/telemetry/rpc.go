@@ -0,0 +1,103 @@
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/exp/slog"
+
+	"github.com/ethereum-mive/mive/internal/debug"
+)
+
+// rpcHandler turns the records logged by the RPC server for every request it
+// served into spans, as the server can't be instrumented otherwise. The records
+// are passed on to the wrapped handler as usual.
+type rpcHandler struct {
+	inner slog.Handler
+	attrs []slog.Attr // Attributes of the logger, e.g. the connection of the server
+}
+
+// traceRPC starts creating the spans of the RPC requests served.
+func traceRPC() {
+	debug.WrapLogHandler(func(inner slog.Handler) slog.Handler {
+		return &rpcHandler{inner: inner}
+	})
+}
+
+// Enabled implements slog.Handler. The requests served successfully are logged
+// at debug level, which has to reach the handler regardless of the verbosity.
+func (h *rpcHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= log.LevelDebug || h.inner.Enabled(ctx, level)
+}
+
+// Handle implements slog.Handler, creating the span of a request served and
+// logging the record if the verbosity allows it.
+func (h *rpcHandler) Handle(ctx context.Context, r slog.Record) error {
+	if method, ok := strings.CutPrefix(r.Message, "Served "); ok {
+		h.span(method, r)
+	}
+	if !h.inner.Enabled(ctx, r.Level) {
+		return nil
+	}
+	return h.inner.Handle(ctx, r)
+}
+
+// WithAttrs implements slog.Handler.
+func (h *rpcHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &rpcHandler{
+		inner: h.inner.WithAttrs(attrs),
+		attrs: append(h.attrs[:len(h.attrs):len(h.attrs)], attrs...),
+	}
+}
+
+// WithGroup implements slog.Handler.
+func (h *rpcHandler) WithGroup(name string) slog.Handler {
+	return &rpcHandler{inner: h.inner.WithGroup(name), attrs: h.attrs}
+}
+
+// span records the span of the request served the given record was logged for.
+// The record is logged once the request was served, along with its duration.
+func (h *rpcHandler) span(method string, r slog.Record) {
+	var (
+		duration time.Duration
+		failure  string
+		attrs    = []attribute.KeyValue{
+			attribute.String("rpc.system", "jsonrpc"),
+			attribute.String("rpc.method", method),
+		}
+	)
+	collect := func(a slog.Attr) bool {
+		switch a.Key {
+		case "duration":
+			if a.Value.Kind() == slog.KindDuration {
+				duration = a.Value.Duration()
+			}
+		case "reqid":
+			attrs = append(attrs, attribute.String("rpc.jsonrpc.request_id", a.Value.String()))
+		case "conn":
+			attrs = append(attrs, attribute.String("client.address", a.Value.String()))
+		case "err":
+			failure = a.Value.String()
+		}
+		return true
+	}
+	for _, a := range h.attrs {
+		collect(a)
+	}
+	r.Attrs(collect)
+
+	_, span := tracer.Start(context.Background(), method,
+		trace.WithSpanKind(trace.SpanKindServer),
+		trace.WithTimestamp(r.Time.Add(-duration)),
+		trace.WithAttributes(attrs...),
+	)
+	if failure != "" {
+		span.SetStatus(codes.Error, failure)
+	}
+	span.End(trace.WithTimestamp(r.Time))
+}
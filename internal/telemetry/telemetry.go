@@ -0,0 +1,105 @@
+// Package telemetry exports OpenTelemetry traces of the derivation of the Mive
+// chain and of the RPC requests served, so that slow Mive blocks can be
+// correlated with slow L1 endpoints or disk stalls.
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// shutdownTimeout is the time given to the exporter to flush the pending spans
+// when the node is stopped.
+const shutdownTimeout = 5 * time.Second
+
+// tracer creates the spans of the node. Until the export is set up, the spans
+// are no-ops.
+var tracer = otel.Tracer("github.com/ethereum-mive/mive")
+
+// Config defines where and how the traces are exported.
+type Config struct {
+	Endpoint   string  // URL of the OTLP/HTTP collector, e.g. http://localhost:4318
+	SampleRate float64 // Fraction of the traces exported, between 0 and 1
+	Service    string  // Name of the service reported to the collector
+	Version    string  // Version of the service reported to the collector
+}
+
+// Exporter exports the spans of the node to an OTLP collector. It implements
+// node.Lifecycle, so that the pending spans are flushed when the node stops.
+type Exporter struct {
+	provider *sdktrace.TracerProvider
+}
+
+// Setup starts exporting the spans of the node to the configured collector,
+// along with the spans of the RPC requests served.
+func Setup(config Config) (*Exporter, error) {
+	u, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OTLP endpoint: %v", err)
+	}
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(u.Host)}
+	switch u.Scheme {
+	case "http":
+		options = append(options, otlptracehttp.WithInsecure())
+	case "https":
+	default:
+		return nil, fmt.Errorf("invalid OTLP endpoint %q: scheme must be http or https", config.Endpoint)
+	}
+	if u.Path != "" && u.Path != "/" {
+		options = append(options, otlptracehttp.WithURLPath(u.Path))
+	}
+	exporter, err := otlptracehttp.New(context.Background(), options...)
+	if err != nil {
+		return nil, err
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRate))),
+		sdktrace.WithResource(resource.NewSchemaless(
+			attribute.String("service.name", config.Service),
+			attribute.String("service.version", config.Version),
+		)),
+	)
+	otel.SetTracerProvider(provider)
+	traceRPC()
+
+	return &Exporter{provider: provider}, nil
+}
+
+// Start implements node.Lifecycle, the export is already running.
+func (e *Exporter) Start() error {
+	return nil
+}
+
+// Stop implements node.Lifecycle, flushing the pending spans.
+func (e *Exporter) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	return e.provider.Shutdown(ctx)
+}
+
+// StartSpan starts a span with the given name and attributes, as a child of the
+// span carried by the context if any.
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// EndSpan ends the given span, marking it as failed if an error is given.
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,404 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package web3ext contains mive specific web3.js extensions.
+package web3ext
+
+var Modules = map[string]string{
+	"admin":  AdminJs,
+	"debug":  DebugJs,
+	"eth":    EthJs,
+	"mive":   MiveJs,
+	"trace":  TraceJs,
+	"txpool": TxpoolJs,
+}
+
+const AdminJs = `
+web3._extend({
+	property: 'admin',
+	methods: [
+		new web3._extend.Method({
+			name: 'addPeer',
+			call: 'admin_addPeer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'removePeer',
+			call: 'admin_removePeer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'addTrustedPeer',
+			call: 'admin_addTrustedPeer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'removeTrustedPeer',
+			call: 'admin_removeTrustedPeer',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'startHTTP',
+			call: 'admin_startHTTP',
+			params: 5,
+			inputFormatter: [null, null, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'stopHTTP',
+			call: 'admin_stopHTTP'
+		}),
+		// This method is deprecated.
+		new web3._extend.Method({
+			name: 'startRPC',
+			call: 'admin_startRPC',
+			params: 5,
+			inputFormatter: [null, null, null, null, null]
+		}),
+		// This method is deprecated.
+		new web3._extend.Method({
+			name: 'stopRPC',
+			call: 'admin_stopRPC'
+		}),
+		new web3._extend.Method({
+			name: 'startWS',
+			call: 'admin_startWS',
+			params: 4,
+			inputFormatter: [null, null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'stopWS',
+			call: 'admin_stopWS'
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'nodeInfo',
+			getter: 'admin_nodeInfo'
+		}),
+		new web3._extend.Property({
+			name: 'peers',
+			getter: 'admin_peers'
+		}),
+		new web3._extend.Property({
+			name: 'datadir',
+			getter: 'admin_datadir'
+		}),
+	]
+});
+`
+
+const DebugJs = `
+web3._extend({
+	property: 'debug',
+	methods: [
+		new web3._extend.Method({
+			name: 'getRawHeader',
+			call: 'debug_getRawHeader',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getRawBlock',
+			call: 'debug_getRawBlock',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getRawReceipts',
+			call: 'debug_getRawReceipts',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getRawTransaction',
+			call: 'debug_getRawTransaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setHead',
+			call: 'debug_setHead',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setHeadWithTimestamp',
+			call: 'debug_setHeadWithTimestamp',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'setTrieFlushInterval',
+			call: 'debug_setTrieFlushInterval',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getTrieFlushInterval',
+			call: 'debug_getTrieFlushInterval',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'cpuProfile',
+			call: 'debug_cpuProfile',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'startCPUProfile',
+			call: 'debug_startCPUProfile',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'stopCPUProfile',
+			call: 'debug_stopCPUProfile',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'goTrace',
+			call: 'debug_goTrace',
+			params: 2
+		}),
+		new web3._extend.Method({
+			name: 'startGoTrace',
+			call: 'debug_startGoTrace',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'stopGoTrace',
+			call: 'debug_stopGoTrace',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'traceBlockByNumber',
+			call: 'debug_traceBlockByNumber',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'traceBlockByHash',
+			call: 'debug_traceBlockByHash',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'traceTransaction',
+			call: 'debug_traceTransaction',
+			params: 2,
+			inputFormatter: [null, null]
+		}),
+		new web3._extend.Method({
+			name: 'traceCall',
+			call: 'debug_traceCall',
+			params: 3,
+			inputFormatter: [null, null, null]
+		}),
+		new web3._extend.Method({
+			name: 'getBadBlocks',
+			call: 'debug_getBadBlocks',
+			params: 0,
+		}),
+	],
+	properties: []
+});
+`
+
+const EthJs = `
+web3._extend({
+	property: 'eth',
+	methods: [
+		new web3._extend.Method({
+			name: 'chainId',
+			call: 'eth_chainId',
+			params: 0
+		}),
+		new web3._extend.Method({
+			name: 'estimateGas',
+			call: 'eth_estimateGas',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputBlockNumberFormatter],
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
+		new web3._extend.Method({
+			name: 'feeHistory',
+			call: 'eth_feeHistory',
+			params: 3,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter, null]
+		}),
+		new web3._extend.Method({
+			name: 'getLogs',
+			call: 'eth_getLogs',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'call',
+			call: 'eth_call',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputCallFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'getBlockReceipts',
+			call: 'eth_getBlockReceipts',
+			params: 1,
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'maxPriorityFeePerGas',
+			getter: 'eth_maxPriorityFeePerGas',
+			outputFormatter: web3._extend.utils.toBigNumber
+		}),
+	]
+});
+`
+
+const MiveJs = `
+web3._extend({
+	property: 'mive',
+	methods: [
+		new web3._extend.Method({
+			name: 'getRejectedTransactions',
+			call: 'mive_getRejectedTransactions',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getRejectedTransaction',
+			call: 'mive_getRejectedTransaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'getWrappedTransactionStatus',
+			call: 'mive_getWrappedTransactionStatus',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'estimateWrappedFees',
+			call: 'mive_estimateWrappedFees',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'compressPayload',
+			call: 'mive_compressPayload',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'signTransaction',
+			call: 'mive_signTransaction',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputTransactionFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getWithdrawalProof',
+			call: 'mive_getWithdrawalProof',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'outputAtBlock',
+			call: 'mive_outputAtBlock',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'getBlockFees',
+			call: 'mive_getBlockFees',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+	],
+	properties: [
+		new web3._extend.Property({
+			name: 'chainConfig',
+			getter: 'mive_chainConfig'
+		}),
+		new web3._extend.Property({
+			name: 'forkId',
+			getter: 'mive_forkId'
+		}),
+		new web3._extend.Property({
+			name: 'stateRetention',
+			getter: 'mive_stateRetention'
+		}),
+		new web3._extend.Property({
+			name: 'syncStatus',
+			getter: 'mive_syncStatus'
+		}),
+		new web3._extend.Property({
+			name: 'txStats',
+			getter: 'mive_txStats'
+		}),
+		new web3._extend.Property({
+			name: 'l1Status',
+			getter: 'mive_l1Status'
+		}),
+		new web3._extend.Property({
+			name: 'health',
+			getter: 'mive_health'
+		}),
+		new web3._extend.Property({
+			name: 'uncleanShutdowns',
+			getter: 'mive_uncleanShutdowns'
+		}),
+	]
+});
+`
+
+const TraceJs = `
+web3._extend({
+	property: 'trace',
+	methods: [
+		new web3._extend.Method({
+			name: 'block',
+			call: 'trace_block',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
+		}),
+		new web3._extend.Method({
+			name: 'transaction',
+			call: 'trace_transaction',
+			params: 1
+		}),
+		new web3._extend.Method({
+			name: 'filter',
+			call: 'trace_filter',
+			params: 1
+		}),
+	],
+	properties: []
+});
+`
+
+const TxpoolJs = `
+web3._extend({
+	property: 'txpool',
+	methods: [],
+	properties:
+	[
+		new web3._extend.Property({
+			name: 'content',
+			getter: 'txpool_content'
+		}),
+		new web3._extend.Property({
+			name: 'status',
+			getter: 'txpool_status',
+			outputFormatter: function(status) {
+				status.pending = web3._extend.utils.toDecimal(status.pending);
+				status.queued = web3._extend.utils.toDecimal(status.queued);
+				return status;
+			}
+		}),
+		new web3._extend.Method({
+			name: 'contentFrom',
+			call: 'txpool_contentFrom',
+			params: 1,
+		}),
+	]
+});
+`
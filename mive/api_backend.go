@@ -0,0 +1,350 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package mive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	miverawdb "github.com/ethereum-mive/mive/core/rawdb"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/mive/gasprice"
+	"github.com/ethereum-mive/mive/mive/tracers"
+	miveparams "github.com/ethereum-mive/mive/params"
+)
+
+// MiveAPIBackend implements miveapi.Backend for full nodes
+type MiveAPIBackend struct {
+	allowUnprotectedTxs bool
+	mive                *Mive
+	gpo                 *gasprice.Oracle
+}
+
+// ChainConfig returns the active chain configuration.
+func (b *MiveAPIBackend) ChainConfig() *miveparams.ChainConfig {
+	return b.mive.blockchain.Config()
+}
+
+func (b *MiveAPIBackend) CurrentBlock() *mivetypes.Header {
+	return b.mive.blockchain.CurrentBlock()
+}
+
+func (b *MiveAPIBackend) HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*mivetypes.Header, error) {
+	// Pending block is only known by the observed pool, fall back to the latest
+	// block if no pending block is built
+	if number == rpc.PendingBlockNumber {
+		block, _, _, _, err := b.pending()
+		if err != nil {
+			return nil, err
+		}
+		if block != nil {
+			return block.Header(), nil
+		}
+	}
+	if number == rpc.PendingBlockNumber || number == rpc.LatestBlockNumber {
+		return b.mive.blockchain.CurrentBlock(), nil
+	}
+	if number == rpc.FinalizedBlockNumber {
+		block := b.mive.blockchain.CurrentFinalBlock()
+		if block == nil {
+			return nil, errors.New("finalized block not found")
+		}
+		return block, nil
+	}
+	if number == rpc.SafeBlockNumber {
+		block := b.mive.blockchain.CurrentSafeBlock()
+		if block == nil {
+			return nil, errors.New("safe block not found")
+		}
+		return block, nil
+	}
+	if number == rpc.EarliestBlockNumber {
+		return b.mive.blockchain.Genesis(), nil
+	}
+	return b.mive.blockchain.GetHeaderByNumber(uint64(number)), nil
+}
+
+func (b *MiveAPIBackend) HeaderByHash(ctx context.Context, hash common.Hash) (*mivetypes.Header, error) {
+	return b.mive.blockchain.GetHeaderByHash(hash), nil
+}
+
+func (b *MiveAPIBackend) HeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*mivetypes.Header, error) {
+	if blockNr, ok := blockNrOrHash.Number(); ok {
+		return b.HeaderByNumber(ctx, blockNr)
+	}
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		header := b.mive.blockchain.GetHeaderByHash(hash)
+		if header == nil {
+			return nil, errors.New("header for hash not found")
+		}
+		if blockNrOrHash.RequireCanonical && b.mive.blockchain.GetCanonicalHash(header.NumberU64()) != hash {
+			return nil, errors.New("hash is not currently canonical")
+		}
+		return header, nil
+	}
+	return nil, errors.New("invalid arguments; neither block nor hash specified")
+}
+
+func (b *MiveAPIBackend) BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*mivetypes.Block, error) {
+	// Pending block is only known by the observed pool
+	if number == rpc.PendingBlockNumber {
+		block, _, _, _, err := b.pending()
+		if err != nil {
+			return nil, err
+		}
+		if block != nil {
+			return block, nil
+		}
+	}
+	header, err := b.HeaderByNumber(ctx, number)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return b.mive.blockchain.GetMiveBlock(header.Hash, header.NumberU64()), nil
+}
+
+func (b *MiveAPIBackend) BlockByHash(ctx context.Context, hash common.Hash) (*mivetypes.Block, error) {
+	return b.mive.blockchain.GetMiveBlockByHash(hash), nil
+}
+
+func (b *MiveAPIBackend) BlockByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*mivetypes.Block, error) {
+	header, err := b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if header == nil || err != nil {
+		return nil, err
+	}
+	return b.mive.blockchain.GetMiveBlock(header.Hash, header.NumberU64()), nil
+}
+
+func (b *MiveAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *mivetypes.Header, error) {
+	// Pending state is only known by the observed pool
+	if blockNr, ok := blockNrOrHash.Number(); ok && blockNr == rpc.PendingBlockNumber {
+		block, _, state, _, err := b.pending()
+		if err != nil {
+			return nil, nil, err
+		}
+		if block != nil {
+			return state, block.Header(), nil
+		}
+	}
+	header, err := b.HeaderByNumberOrHash(ctx, blockNrOrHash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if header == nil {
+		return nil, nil, errors.New("header not found")
+	}
+	stateDb, err := b.mive.blockchain.StateAt(header.Root)
+	if err != nil {
+		return nil, nil, err
+	}
+	return stateDb, header, nil
+}
+
+func (b *MiveAPIBackend) BlockContext(ctx context.Context, header *mivetypes.Header) (vm.BlockContext, error) {
+	l1Header, err := b.EthHeader(ctx, header)
+	if err != nil {
+		return vm.BlockContext{}, err
+	}
+	bc := b.mive.blockchain
+	return mivecore.NewEVMBlockContext(l1Header, bc, nil, bc.Config())
+}
+
+// EthHeader retrieves the header of the L1 block the given Mive block was
+// derived from, which is the predicted next L1 block for the pending block.
+func (b *MiveAPIBackend) EthHeader(ctx context.Context, header *mivetypes.Header) (*types.Header, error) {
+	if header.NumberU64() > b.mive.blockchain.CurrentBlock().NumberU64() {
+		block, _, _, l1Header, err := b.pending()
+		if err != nil {
+			return nil, err
+		}
+		if block != nil && block.Hash() == header.Hash {
+			return l1Header, nil
+		}
+	}
+	return b.mive.blockchain.EthGetHeader(header.Hash, header.NumberU64())
+}
+
+// pending returns the pending block built by the observed pool along with its
+// receipts, state and predicted L1 header, or nil values if there is none.
+func (b *MiveAPIBackend) pending() (*mivetypes.Block, types.Receipts, *state.StateDB, *types.Header, error) {
+	if b.mive.txPool == nil {
+		return nil, nil, nil, nil, nil
+	}
+	return b.mive.txPool.Pending()
+}
+
+func (b *MiveAPIBackend) GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error) {
+	return b.mive.blockchain.GetReceiptsByHash(hash), nil
+}
+
+func (b *MiveAPIBackend) GetLogs(ctx context.Context, hash common.Hash, number uint64) ([][]*types.Log, error) {
+	receipts := b.mive.blockchain.GetReceiptsByHash(hash)
+	if receipts == nil {
+		return nil, nil
+	}
+	logs := make([][]*types.Log, len(receipts))
+	for i, receipt := range receipts {
+		logs[i] = receipt.Logs
+	}
+	return logs, nil
+}
+
+func (b *MiveAPIBackend) GetTransaction(ctx context.Context, txHash common.Hash) (*mivetypes.Transaction, common.Hash, uint64, uint64, error) {
+	tx, blockHash, blockNumber, index := miverawdb.ReadTransaction(b.mive.chainDb, txHash)
+	return tx, blockHash, blockNumber, index, nil
+}
+
+func (b *MiveAPIBackend) GetTransactionByOrigin(ctx context.Context, l1TxHash common.Hash) (*mivetypes.Transaction, *mivetypes.Rejection, common.Hash, uint64, error) {
+	tx, rejection, blockHash, blockNumber := b.mive.blockchain.GetTransactionByOrigin(l1TxHash)
+	return tx, rejection, blockHash, blockNumber, nil
+}
+
+func (b *MiveAPIBackend) BadBlocks() []*mivetypes.BadBlock {
+	return b.mive.blockchain.BadBlocks()
+}
+
+func (b *MiveAPIBackend) SendTx(ctx context.Context, signedTx *types.Transaction) error {
+	return b.mive.ethClient.SendTransaction(ctx, signedTx)
+}
+
+func (b *MiveAPIBackend) RelayTx(ctx context.Context, txs []*mivetypes.Tx) (common.Hash, error) {
+	if b.mive.relayer == nil {
+		return common.Hash{}, errors.New("no relayer account configured")
+	}
+	return b.mive.relayer.relay(ctx, txs)
+}
+
+func (b *MiveAPIBackend) L1PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	return b.mive.ethClient.PendingNonceAt(ctx, account)
+}
+
+func (b *MiveAPIBackend) SuggestL1Fees(ctx context.Context) (*big.Int, *big.Int, error) {
+	tip, err := b.mive.ethClient.SuggestGasTipCap(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to suggest L1 gas tip: %w", err)
+	}
+	head, err := b.mive.ethClient.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to retrieve L1 head: %w", err)
+	}
+	if head.BaseFee == nil {
+		return tip, new(big.Int), nil
+	}
+	return tip, head.BaseFee, nil
+}
+
+func (b *MiveAPIBackend) EstimateL1Gas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return b.mive.ethClient.EstimateGas(ctx, call)
+}
+
+func (b *MiveAPIBackend) SuggestGasTipCap(ctx context.Context) (*big.Int, error) {
+	return b.gpo.SuggestTipCap(ctx)
+}
+
+func (b *MiveAPIBackend) FeeHistory(ctx context.Context, blockCount uint64, lastBlock rpc.BlockNumber, rewardPercentiles []float64) (firstBlock *big.Int, reward [][]*big.Int, baseFee []*big.Int, gasUsedRatio []float64, err error) {
+	return b.gpo.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
+}
+
+func (b *MiveAPIBackend) UnprotectedAllowed() bool {
+	return b.allowUnprotectedTxs
+}
+
+func (b *MiveAPIBackend) AccountManager() *accounts.Manager {
+	return b.mive.accountManager
+}
+
+func (b *MiveAPIBackend) RPCGasCap() uint64 {
+	return b.mive.config.RPCGasCap
+}
+
+func (b *MiveAPIBackend) RPCEVMTimeout() time.Duration {
+	return b.mive.config.RPCEVMTimeout
+}
+
+func (b *MiveAPIBackend) RPCReexec() uint64 {
+	return b.mive.config.RPCReexec
+}
+
+func (b *MiveAPIBackend) RPCTxFeeCap() float64 {
+	return b.mive.config.RPCTxFeeCap
+}
+
+func (b *MiveAPIBackend) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return b.mive.blockchain.SubscribeChainHeadEvent(ch)
+}
+
+func (b *MiveAPIBackend) SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription {
+	return b.mive.blockchain.SubscribeRemovedLogsEvent(ch)
+}
+
+func (b *MiveAPIBackend) SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription {
+	return b.mive.blockchain.SubscribeChainEvent(ch)
+}
+
+func (b *MiveAPIBackend) SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription {
+	return b.mive.blockchain.SubscribeLogsEvent(ch)
+}
+
+func (b *MiveAPIBackend) SubscribeNewMiveTxsEvent(ch chan<- mivecore.NewMiveTxsEvent) event.Subscription {
+	return b.mive.blockchain.SubscribeNewMiveTxsEvent(ch)
+}
+
+// SubscribeNewPendingTxsEvent subscribes to the pending Mive transactions
+// observed on L1. If the observed pool is disabled the subscription never fires.
+func (b *MiveAPIBackend) SubscribeNewPendingTxsEvent(ch chan<- mivecore.NewPendingTxsEvent) event.Subscription {
+	if b.mive.txPool == nil {
+		return event.NewSubscription(func(quit <-chan struct{}) error {
+			<-quit
+			return nil
+		})
+	}
+	return b.mive.txPool.SubscribeNewPendingTxsEvent(ch)
+}
+
+func (b *MiveAPIBackend) BloomStatus() (uint64, uint64) {
+	sections, _, _ := b.mive.bloomIndexer.Sections()
+	return b.mive.config.BloomBitsBlocks, sections
+}
+
+func (b *MiveAPIBackend) ServiceFilter(ctx context.Context, session *bloombits.MatcherSession) {
+	for i := 0; i < bloomFilterThreads; i++ {
+		go session.Multiplex(bloomRetrievalBatch, bloomRetrievalWait, b.mive.bloomRequests)
+	}
+}
+
+func (b *MiveAPIBackend) StateAtBlock(ctx context.Context, block *mivetypes.Block, reexec uint64) (*state.StateDB, tracers.StateReleaseFunc, error) {
+	return b.mive.stateAtBlock(ctx, block, reexec)
+}
+
+func (b *MiveAPIBackend) StateAtTransaction(ctx context.Context, block *mivetypes.Block, txIndex int, reexec uint64) (*core.Message, vm.BlockContext, *state.StateDB, tracers.StateReleaseFunc, error) {
+	return b.mive.stateAtTransaction(ctx, block, txIndex, reexec)
+}
@@ -0,0 +1,94 @@
+package mive
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// DebugAPI is the collection of Mive administrative debug APIs, only exposed
+// over the authenticated endpoint.
+type DebugAPI struct {
+	m *Mive
+}
+
+// NewDebugAPI creates a new API definition for the administrative debug methods
+// of the Mive service.
+func NewDebugAPI(m *Mive) *DebugAPI {
+	return &DebugAPI{m: m}
+}
+
+// SetHead rewinds the head of the Mive chain to the given block, e.g. to repair
+// a corrupted or wrongly derived chain. The blocks above the new head are
+// re-derived from L1 right away. Note the chain may be rewound further, down to
+// the first block whose state is available.
+func (api *DebugAPI) SetHead(number hexutil.Uint64) error {
+	var (
+		bc   = api.m.blockchain
+		head = bc.CurrentBlock().NumberU64()
+	)
+	if uint64(number) > head {
+		return fmt.Errorf("block #%d is above the current head #%d", number, head)
+	}
+	if genesis := bc.Genesis().NumberU64(); uint64(number) < genesis {
+		return fmt.Errorf("block #%d is below the genesis block #%d", number, genesis)
+	}
+	// Checkpoint synced nodes have no blocks below the checkpoint, rewinding
+	// into the gap would drop the chain back to the genesis.
+	if bc.GetHeaderByNumber(uint64(number)) == nil {
+		return fmt.Errorf("block #%d not found", number)
+	}
+	log.Warn("Rewinding Mive chain", "target", uint64(number), "head", head)
+	if err := bc.SetHead(uint64(number)); err != nil {
+		return err
+	}
+	api.m.follower.resync()
+	return nil
+}
+
+// SetHeadWithTimestamp rewinds the head of the Mive chain to the last block
+// with a timestamp not above the given one. The blocks above the new head are
+// re-derived from L1 right away.
+func (api *DebugAPI) SetHeadWithTimestamp(timestamp hexutil.Uint64) error {
+	var (
+		bc      = api.m.blockchain
+		genesis = bc.Genesis()
+	)
+	if uint64(timestamp) < genesis.Time {
+		return fmt.Errorf("timestamp %d is before the genesis block timestamp %d", timestamp, genesis.Time)
+	}
+	log.Warn("Rewinding Mive chain", "timestamp", uint64(timestamp), "head", bc.CurrentBlock().NumberU64())
+	if err := bc.SetHeadWithTimestamp(uint64(timestamp)); err != nil {
+		return err
+	}
+	api.m.follower.resync()
+	return nil
+}
+
+// SetTrieFlushInterval configures how often in-memory tries are persisted
+// to disk. The value is in terms of block processing time, not wall clock.
+// If the value is shorter than the block generation time, or even 0 or negative,
+// the node will flush trie after processing each block (effectively archive mode).
+func (api *DebugAPI) SetTrieFlushInterval(interval string) error {
+	if api.m.blockchain.TrieDB().Scheme() == rawdb.PathScheme {
+		return errors.New("trie flush interval is undefined for path-based scheme")
+	}
+	t, err := time.ParseDuration(interval)
+	if err != nil {
+		return err
+	}
+	api.m.blockchain.SetTrieFlushInterval(t)
+	return nil
+}
+
+// GetTrieFlushInterval gets the current value of in-memory trie flush interval
+func (api *DebugAPI) GetTrieFlushInterval() (string, error) {
+	if api.m.blockchain.TrieDB().Scheme() == rawdb.PathScheme {
+		return "", errors.New("trie flush interval is undefined for path-based scheme")
+	}
+	return api.m.blockchain.GetTrieFlushInterval().String(), nil
+}
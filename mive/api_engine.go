@@ -0,0 +1,131 @@
+package mive
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/beacon/engine"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	miveconsensus "github.com/ethereum-mive/mive/consensus"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+// EngineAPI lets an external derivation driver push the L1 blocks the Mive
+// chain is derived from, and set its head, safe and finalized markers, like the
+// Engine API lets a consensus client drive an execution client. It's only
+// exposed over the authenticated endpoint, if the node is configured with an
+// external driver.
+type EngineAPI struct {
+	m    *Mive
+	lock sync.Mutex // Serializes the updates of the chain
+}
+
+// NewEngineAPI creates a new API definition for the driver methods of the Mive
+// service.
+func NewEngineAPI(m *Mive) *EngineAPI {
+	return &EngineAPI{m: m}
+}
+
+// NewPayload derives the Mive block from the given RLP encoded L1 block, and
+// inserts it on top of the head of the Mive chain. A block whose parent is
+// unknown is not derived, the driver has to push its ancestors first.
+func (api *EngineAPI) NewPayload(payload hexutil.Bytes) (engine.PayloadStatusV1, error) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(payload, block); err != nil {
+		return engine.PayloadStatusV1{}, engine.InvalidParams.With(err)
+	}
+	var (
+		bc   = api.m.blockchain
+		hash = block.Hash()
+	)
+	if bc.GetHeader(hash, block.NumberU64()) != nil {
+		return engine.PayloadStatusV1{Status: engine.VALID, LatestValidHash: &hash}, nil
+	}
+	head := bc.CurrentBlock()
+	if block.ParentHash() != head.Hash {
+		if bc.GetHeader(block.ParentHash(), block.NumberU64()-1) == nil {
+			return engine.PayloadStatusV1{Status: engine.SYNCING}, nil
+		}
+		// The parent is known but not the head, the driver has to rewind the
+		// chain to it first
+		return engine.PayloadStatusV1{Status: engine.ACCEPTED}, nil
+	}
+	if err := api.m.follower.archiveBlobs(types.Blocks{block}); err != nil {
+		return engine.PayloadStatusV1{}, err
+	}
+	if _, err := bc.InsertChain(types.Blocks{block}); err != nil {
+		// Only validation failures make the block invalid, others (e.g. the
+		// L1 receipts failing to be retrieved) may be retried
+		var invalid *miveconsensus.ValidationError
+		if !errors.As(err, &invalid) {
+			return engine.PayloadStatusV1{}, err
+		}
+		log.Warn("Invalid payload pushed by the driver", "number", block.Number(), "hash", hash, "err", err)
+		msg := err.Error()
+		return engine.PayloadStatusV1{Status: engine.INVALID, LatestValidHash: &head.Hash, ValidationError: &msg}, nil
+	}
+	return engine.PayloadStatusV1{Status: engine.VALID, LatestValidHash: &hash}, nil
+}
+
+// ForkchoiceUpdated sets the head, safe and finalized markers of the Mive chain.
+// The head has to be a canonical block, the chain being rewound to it if it's
+// below the current head. The safe and finalized blocks have to be canonical
+// as well, at or below the head, and are left as they are if zero.
+func (api *EngineAPI) ForkchoiceUpdated(update engine.ForkchoiceStateV1) (engine.ForkChoiceResponse, error) {
+	api.lock.Lock()
+	defer api.lock.Unlock()
+
+	bc := api.m.blockchain
+	if update.HeadBlockHash == (common.Hash{}) {
+		return engine.STATUS_INVALID, nil
+	}
+	head := bc.GetHeaderByHash(update.HeadBlockHash)
+	if head == nil {
+		return engine.STATUS_SYNCING, nil
+	}
+	if bc.GetCanonicalHash(head.NumberU64()) != head.Hash {
+		return engine.STATUS_INVALID, engine.InvalidForkChoiceState.With(errors.New("head is not canonical"))
+	}
+	// Resolve the markers before rewinding, so an invalid update is rejected
+	// as a whole
+	marker := func(hash common.Hash, what string) (*mivetypes.Header, error) {
+		if hash == (common.Hash{}) {
+			return nil, nil
+		}
+		header := bc.GetHeaderByHash(hash)
+		if header == nil || bc.GetCanonicalHash(header.NumberU64()) != hash || header.Number.Cmp(head.Number) > 0 {
+			return nil, engine.InvalidForkChoiceState.With(errors.New(what + " block not canonical at or below the head"))
+		}
+		return header, nil
+	}
+	safe, err := marker(update.SafeBlockHash, "safe")
+	if err != nil {
+		return engine.STATUS_INVALID, err
+	}
+	finalized, err := marker(update.FinalizedBlockHash, "finalized")
+	if err != nil {
+		return engine.STATUS_INVALID, err
+	}
+	if current := bc.CurrentBlock(); head.Hash != current.Hash {
+		log.Warn("Rewinding Mive chain on driver request", "target", head.Number, "head", current.Number)
+		if err := bc.SetHead(head.NumberU64()); err != nil {
+			return engine.STATUS_INVALID, err
+		}
+	}
+	if safe != nil {
+		bc.SetSafe(safe)
+	}
+	if finalized != nil {
+		bc.SetFinalized(finalized)
+	}
+	valid := bc.CurrentBlock().Hash
+	return engine.ForkChoiceResponse{PayloadStatus: engine.PayloadStatusV1{Status: engine.VALID, LatestValidHash: &valid}}, nil
+}
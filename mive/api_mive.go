@@ -0,0 +1,649 @@
+package mive
+
+import (
+	"context"
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/ethereum/go-ethereum/trie"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	"github.com/ethereum-mive/mive/core/forkid"
+	miverawdb "github.com/ethereum-mive/mive/core/rawdb"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	miveethclient "github.com/ethereum-mive/mive/ethclient"
+	miveparams "github.com/ethereum-mive/mive/params"
+)
+
+// MiveAPI provides an API to access Mive specific information, like the
+// outcome of beacon transactions, the chain parameters and the progress of
+// the derivation.
+type MiveAPI struct {
+	m *Mive
+}
+
+// NewMiveAPI creates a new Mive protocol API.
+func NewMiveAPI(m *Mive) *MiveAPI {
+	return &MiveAPI{m}
+}
+
+// RPCRejection represents a rejected beacon transaction that will serialize to
+// the RPC representation of a rejection.
+type RPCRejection struct {
+	BlockHash   common.Hash    `json:"blockHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	L1TxHash    common.Hash    `json:"l1TransactionHash"`
+	From        common.Address `json:"from"`
+	Reason      string         `json:"reason"`
+	BatchIndex  hexutil.Uint64 `json:"batchIndex,omitempty"`
+}
+
+func newRPCRejection(rejection *mivetypes.Rejection, blockHash common.Hash, blockNumber uint64) *RPCRejection {
+	return &RPCRejection{
+		BlockHash:   blockHash,
+		BlockNumber: hexutil.Uint64(blockNumber),
+		L1TxHash:    rejection.Origin,
+		From:        rejection.From,
+		Reason:      rejection.Reason,
+		BatchIndex:  hexutil.Uint64(rejection.BatchIndex),
+	}
+}
+
+// GetRejectedTransactions returns the beacon transactions that were rejected
+// while deriving the given Mive block, along with the reason of the rejection.
+func (api *MiveAPI) GetRejectedTransactions(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]*RPCRejection, error) {
+	header, err := api.headerByNumberOrHash(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	rejections := api.m.blockchain.GetRejections(header.Hash)
+	result := make([]*RPCRejection, len(rejections))
+	for i, rejection := range rejections {
+		result[i] = newRPCRejection(rejection, header.Hash, header.NumberU64())
+	}
+	return result, nil
+}
+
+// GetRejectedTransaction returns the rejection record of the beacon transaction
+// with the given L1 hash, or nil if the transaction was not rejected (or not
+// processed yet).
+func (api *MiveAPI) GetRejectedTransaction(ctx context.Context, hash common.Hash) (*RPCRejection, error) {
+	_, rejection, blockHash, blockNumber := api.m.blockchain.GetTransactionByOrigin(hash)
+	if rejection == nil {
+		return nil, nil
+	}
+	return newRPCRejection(rejection, blockHash, blockNumber), nil
+}
+
+// Statuses of a wrapped transaction, along its lifecycle on L1 and Mive.
+const (
+	wrappedTxPending  = "pending"  // Waiting in the L1 pool
+	wrappedTxIncluded = "included" // Included in an L1 block not derived yet
+	wrappedTxDerived  = "derived"  // Executed as a Mive transaction
+	wrappedTxRejected = "rejected" // Rejected by the derivation, e.g. malformed payload
+	wrappedTxIgnored  = "ignored"  // Included in a derived L1 block, but not a beacon transaction
+)
+
+// WrappedTxStatus describes the status of a beacon transaction, from the L1
+// pool to its execution as a Mive transaction. The L1 fields are set once the
+// transaction is included, the Mive ones once it is derived, the error if it
+// was rejected.
+type WrappedTxStatus struct {
+	Status        string          `json:"status"`
+	L1TxHash      common.Hash     `json:"l1TransactionHash"`
+	L1BlockHash   *common.Hash    `json:"l1BlockHash,omitempty"`
+	L1BlockNumber *hexutil.Uint64 `json:"l1BlockNumber,omitempty"`
+	TxHash        *common.Hash    `json:"transactionHash,omitempty"`
+	ReceiptStatus *hexutil.Uint64 `json:"receiptStatus,omitempty"`
+	Error         string          `json:"error,omitempty"`
+}
+
+// GetWrappedTransactionStatus returns the status of a beacon transaction,
+// identified by its L1 hash, by the hash of the Mive transaction it produced
+// or by the handle returned by the relayer for a relayed transaction. For the
+// latter, the replacements of the beacon transaction are followed. Nil is
+// returned if the transaction is unknown.
+func (api *MiveAPI) GetWrappedTransactionStatus(ctx context.Context, hash common.Hash) (*WrappedTxStatus, error) {
+	bc := api.m.blockchain
+
+	// The hash of a derived Mive transaction, including the handle of a relayed
+	// transaction whose first submission got included
+	if tx, blockHash, blockNumber, index := miverawdb.ReadTransaction(api.m.chainDb, hash); tx != nil {
+		return api.derivedStatus(tx, blockHash, blockNumber, index), nil
+	}
+	var (
+		hashes   = []common.Hash{hash}
+		inFlight bool
+	)
+	if api.m.relayer != nil {
+		var rtx *mivetypes.RelayedTx
+		if rtx, inFlight = api.m.relayer.lookup(hash); rtx != nil {
+			hashes = append([]common.Hash{rtx.Tx.Hash()}, rtx.Replaced...)
+		}
+	}
+	// Derived beacon transactions, the replacements being mutually exclusive
+	for _, l1Hash := range hashes {
+		tx, rejection, blockHash, blockNumber := bc.GetTransactionByOrigin(l1Hash)
+		switch {
+		case tx != nil:
+			_, _, _, index := miverawdb.ReadTransaction(api.m.chainDb, tx.Hash())
+			return api.derivedStatus(tx, blockHash, blockNumber, index), nil
+		case rejection != nil:
+			number := hexutil.Uint64(blockNumber)
+			return &WrappedTxStatus{
+				Status:        wrappedTxRejected,
+				L1TxHash:      l1Hash,
+				L1BlockHash:   &blockHash,
+				L1BlockNumber: &number,
+				Error:         rejection.Reason,
+			}, nil
+		}
+	}
+	// Beacon transactions not derived yet, or L1 transactions ignored by the
+	// derivation
+	for _, l1Hash := range hashes {
+		receipt, err := api.m.ethClient.TransactionReceipt(ctx, l1Hash)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var (
+			number = hexutil.Uint64(receipt.BlockNumber.Uint64())
+			status = &WrappedTxStatus{
+				Status:        wrappedTxIncluded,
+				L1TxHash:      l1Hash,
+				L1BlockHash:   &receipt.BlockHash,
+				L1BlockNumber: &number,
+			}
+		)
+		if bc.GetCanonicalHash(uint64(number)) == receipt.BlockHash {
+			status.Status = wrappedTxIgnored
+		}
+		return status, nil
+	}
+	for _, l1Hash := range hashes {
+		tx, pending, err := api.m.ethClient.TransactionByHash(ctx, l1Hash)
+		if errors.Is(err, ethereum.NotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if pending {
+			return &WrappedTxStatus{Status: wrappedTxPending, L1TxHash: tx.Hash()}, nil
+		}
+	}
+	// In-flight relayed transactions dropped by the L1 pool are rebroadcast
+	if inFlight {
+		return &WrappedTxStatus{Status: wrappedTxPending, L1TxHash: hashes[0]}, nil
+	}
+	return nil, nil
+}
+
+// derivedStatus returns the status of a beacon transaction derived as the
+// given Mive transaction.
+func (api *MiveAPI) derivedStatus(tx *mivetypes.Transaction, blockHash common.Hash, blockNumber uint64, index uint64) *WrappedTxStatus {
+	var (
+		number = hexutil.Uint64(blockNumber)
+		txHash = tx.Hash()
+		status = &WrappedTxStatus{
+			Status:        wrappedTxDerived,
+			L1TxHash:      tx.Origin,
+			L1BlockHash:   &blockHash,
+			L1BlockNumber: &number,
+			TxHash:        &txHash,
+		}
+	)
+	if receipts := api.m.blockchain.GetReceiptsByHash(blockHash); index < uint64(len(receipts)) {
+		receiptStatus := hexutil.Uint64(receipts[index].Status)
+		status.ReceiptStatus = &receiptStatus
+	}
+	return status
+}
+
+// StateRetention describes the window of recent Mive states the node keeps
+// queryable.
+type StateRetention struct {
+	Scheme string         `json:"scheme"`
+	Head   hexutil.Uint64 `json:"head"`
+
+	Archive bool `json:"archive"`
+
+	// History is the number of blocks from head whose state histories are
+	// retained (0 means the entire chain), only reported in path scheme as
+	// hash scheme doesn't retain state histories. OldestBlock is the oldest
+	// block whose state can be recovered, reported in path scheme and in
+	// archive mode.
+	History     *hexutil.Uint64 `json:"history,omitempty"`
+	OldestBlock *hexutil.Uint64 `json:"oldestBlock,omitempty"`
+}
+
+// StateRetention reports the state scheme of the node and, in path scheme or
+// archive mode, the range of recent blocks whose state remains queryable.
+func (api *MiveAPI) StateRetention() *StateRetention {
+	var (
+		bc     = api.m.blockchain
+		head   = bc.CurrentBlock().NumberU64()
+		result = &StateRetention{
+			Scheme:  bc.TrieDB().Scheme(),
+			Head:    hexutil.Uint64(head),
+			Archive: api.m.ArchiveMode(),
+		}
+		oldest = bc.Genesis().NumberU64()
+	)
+	if result.Archive {
+		result.OldestBlock = (*hexutil.Uint64)(&oldest)
+	}
+	if result.Scheme != rawdb.PathScheme {
+		return result
+	}
+	history := api.m.config.StateHistory
+	if history != 0 && head-oldest > history {
+		oldest = head - history
+	}
+	result.History = (*hexutil.Uint64)(&history)
+	result.OldestBlock = (*hexutil.Uint64)(&oldest)
+	return result
+}
+
+// RPCChainConfig describes the Mive specific parameters of the chain.
+type RPCChainConfig struct {
+	ChainID                 *hexutil.Big    `json:"chainId"`
+	L1ChainID               *hexutil.Big    `json:"l1ChainId"`
+	BeaconAddress           common.Address  `json:"beaconAddress"`
+	GenesisBlock            hexutil.Uint64  `json:"genesisBlock"`
+	FeeReductionDenominator hexutil.Uint64  `json:"feeReductionDenominator"`
+	BlockGasLimitMultiplier hexutil.Uint64  `json:"blockGasLimitMultiplier"`
+	MinBlockGasLimit        hexutil.Uint64  `json:"minBlockGasLimit"`
+	M1Block                 *hexutil.Big    `json:"m1Block,omitempty"`
+	M2Time                  *hexutil.Uint64 `json:"m2Time,omitempty"`
+}
+
+// ChainConfig returns the Mive specific parameters of the chain: the chain IDs
+// of Mive and L1, the beacon address the Mive transactions are currently sent
+// to, the L1 block the chain starts at, the fee parameters and the activation
+// of the Mive upgrades.
+func (api *MiveAPI) ChainConfig() *RPCChainConfig {
+	var (
+		config = api.m.blockchain.Config()
+		next   = new(big.Int).Add(api.m.blockchain.CurrentBlock().Number, common.Big1)
+	)
+	return &RPCChainConfig{
+		ChainID:                 (*hexutil.Big)(config.ChainID()),
+		L1ChainID:               (*hexutil.Big)(config.Eth.ChainID),
+		BeaconAddress:           config.Beacon(next),
+		GenesisBlock:            hexutil.Uint64(config.Mive.GenesisBlock.Uint64()),
+		FeeReductionDenominator: hexutil.Uint64(config.FeeReductionDenominator()),
+		BlockGasLimitMultiplier: hexutil.Uint64(config.BlockGasLimitMultiplier()),
+		MinBlockGasLimit:        hexutil.Uint64(config.MinBlockGasLimit()),
+		M1Block:                 (*hexutil.Big)(config.Mive.M1Block),
+		M2Time:                  (*hexutil.Uint64)(config.Mive.M2Time),
+	}
+}
+
+// RPCForkID is the fork identifier of the Mive chain, as defined by EIP-2124.
+type RPCForkID struct {
+	Hash hexutil.Bytes  `json:"hash"` // Checksum of the genesis and the passed forks
+	Next hexutil.Uint64 `json:"next"` // Block number or timestamp of the next fork, zero if none
+}
+
+// ForkId returns the fork identifier of the Mive chain at its current head, the
+// nodes of a network having to agree on it to exchange data.
+func (api *MiveAPI) ForkId() *RPCForkID {
+	id := forkid.NewIDWithChain(api.m.blockchain)
+	return &RPCForkID{Hash: id.Hash[:], Next: hexutil.Uint64(id.Next)}
+}
+
+// RPCOrigin identifies the L1 block a Mive block was derived from.
+type RPCOrigin struct {
+	Number    hexutil.Uint64 `json:"number"`
+	Hash      common.Hash    `json:"hash"`
+	Timestamp hexutil.Uint64 `json:"timestamp"`
+}
+
+// SyncStatus describes the progress of the derivation relative to L1.
+type SyncStatus struct {
+	// Origin is the L1 block the Mive head was derived from, which shares its
+	// number and hash.
+	Origin RPCOrigin `json:"origin"`
+
+	// L1Head is the head of the L1 chain and DerivationLag the number of L1
+	// blocks not derived yet.
+	L1Head        hexutil.Uint64 `json:"l1Head"`
+	DerivationLag hexutil.Uint64 `json:"derivationLag"`
+
+	// L1Finalized is the latest finalized L1 block and ConfirmationLag the
+	// number of derived blocks not finalized yet, both only reported if the
+	// L1 endpoints serve the finalized block.
+	L1Finalized     *hexutil.Uint64 `json:"l1Finalized,omitempty"`
+	ConfirmationLag *hexutil.Uint64 `json:"confirmationLag,omitempty"`
+}
+
+// SyncStatus returns the L1 origin of the Mive head along with the number of
+// L1 blocks not derived yet and the number of derived blocks not finalized on
+// L1 yet.
+func (api *MiveAPI) SyncStatus(ctx context.Context) (*SyncStatus, error) {
+	head := api.m.blockchain.CurrentBlock()
+	l1Head, err := api.m.ethClient.BlockNumber(ctx)
+	if err != nil {
+		return nil, err
+	}
+	status := &SyncStatus{
+		Origin: RPCOrigin{
+			Number:    hexutil.Uint64(head.NumberU64()),
+			Hash:      head.Hash,
+			Timestamp: hexutil.Uint64(head.Time),
+		},
+		L1Head: hexutil.Uint64(l1Head),
+	}
+	if l1Head > head.NumberU64() {
+		status.DerivationLag = hexutil.Uint64(l1Head - head.NumberU64())
+	}
+	// Pre-merge L1 chains have no finalized blocks, which is not an error.
+	finalized, err := api.m.ethClient.HeaderByNumber(ctx, big.NewInt(int64(rpc.FinalizedBlockNumber)))
+	if err != nil {
+		log.Debug("Failed to retrieve finalized L1 block", "err", err)
+		return status, nil
+	}
+	var (
+		number = hexutil.Uint64(finalized.Number.Uint64())
+		lag    hexutil.Uint64
+	)
+	if head.NumberU64() > finalized.Number.Uint64() {
+		lag = hexutil.Uint64(head.NumberU64() - finalized.Number.Uint64())
+	}
+	status.L1Finalized, status.ConfirmationLag = &number, &lag
+	return status, nil
+}
+
+// TxStats counts the Mive transactions derived and the beacon transactions
+// rejected since the node was started.
+type TxStats struct {
+	Derived  hexutil.Uint64 `json:"derived"`
+	Rejected hexutil.Uint64 `json:"rejected"`
+}
+
+// TxStats returns the number of Mive transactions derived and the number of
+// beacon transactions rejected since the node was started.
+func (api *MiveAPI) TxStats() *TxStats {
+	derived, rejected := api.m.blockchain.TxStats()
+	return &TxStats{
+		Derived:  hexutil.Uint64(derived),
+		Rejected: hexutil.Uint64(rejected),
+	}
+}
+
+// L1Status describes the connection of the node to the L1 endpoints it derives
+// the Mive chain from.
+type L1Status struct {
+	Endpoints []miveethclient.EndpointStatus `json:"endpoints"`
+	Archive   bool                           `json:"archive"`
+
+	// LastSync is the time the chain last caught up with L1 and Error the
+	// error the last attempt to follow L1 failed with, if any.
+	LastSync *hexutil.Uint64 `json:"lastSync,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// L1Status returns the health of the L1 endpoints and the outcome of the latest
+// attempt to follow the L1 chain.
+func (api *MiveAPI) L1Status() *L1Status {
+	var (
+		client = api.m.ethClient
+		status = api.m.follower.status()
+		result = &L1Status{
+			Endpoints: client.Endpoints(),
+			Archive:   client.HasArchive(),
+		}
+	)
+	if !status.LastSync.IsZero() {
+		lastSync := hexutil.Uint64(status.LastSync.Unix())
+		result.LastSync = &lastSync
+	}
+	if status.Err != nil {
+		result.Error = status.Err.Error()
+	}
+	return result
+}
+
+// Health reports the ability of the node to derive and serve the Mive chain:
+// the connection to L1, the lag of the Mive head, the time a block was last
+// derived, the writability of the database and the unclean shutdowns.
+func (api *MiveAPI) Health() *Health {
+	return api.m.health()
+}
+
+// UncleanShutdowns is the record of the unclean shutdowns of the node found on
+// startup. The time of an unclean shutdown is the last time the node was seen
+// running, at most 5 minutes before it went down.
+type UncleanShutdowns struct {
+	Count      hexutil.Uint64   `json:"count"`      // Number of unclean shutdowns, including the ones no longer recorded
+	Timestamps []hexutil.Uint64 `json:"timestamps"` // Times of the recorded unclean shutdowns, oldest first
+	Discarded  hexutil.Uint64   `json:"discarded"`  // Number of older unclean shutdowns no longer recorded
+}
+
+// UncleanShutdowns returns the unclean shutdowns of the node found on startup.
+// They are not tracked in read-only mode.
+func (api *MiveAPI) UncleanShutdowns() (*UncleanShutdowns, error) {
+	if api.m.shutdownTracker == nil {
+		return nil, errors.New("unclean shutdowns are not tracked in read-only mode")
+	}
+	unclean, discarded := api.m.shutdownTracker.UncleanShutdowns()
+	result := &UncleanShutdowns{
+		Count:      hexutil.Uint64(uint64(len(unclean)) + discarded),
+		Timestamps: make([]hexutil.Uint64, len(unclean)),
+		Discarded:  hexutil.Uint64(discarded),
+	}
+	for i, timestamp := range unclean {
+		result.Timestamps[i] = hexutil.Uint64(timestamp)
+	}
+	return result, nil
+}
+
+// WithdrawalProof is a Merkle proof of a withdrawal initiated on Mive, to be
+// verified on L1 against the withdrawals root of the Mive header, or against
+// the state root through the account of the withdrawal contract.
+type WithdrawalProof struct {
+	WithdrawalHash  common.Hash     `json:"withdrawalHash"`
+	BlockHash       common.Hash     `json:"blockHash"`
+	BlockNumber     hexutil.Uint64  `json:"blockNumber"`
+	StateRoot       common.Hash     `json:"stateRoot"`
+	WithdrawalsRoot common.Hash     `json:"withdrawalsRoot"`
+	StorageKey      common.Hash     `json:"storageKey"`
+	AccountProof    []hexutil.Bytes `json:"accountProof"`
+	StorageProof    []hexutil.Bytes `json:"storageProof"`
+}
+
+// proofList implements ethdb.KeyValueWriter and collects the nodes of a proof.
+type proofList []hexutil.Bytes
+
+func (n *proofList) Put(key []byte, value []byte) error {
+	*n = append(*n, common.CopyBytes(value))
+	return nil
+}
+
+func (n *proofList) Delete(key []byte) error {
+	panic("not supported")
+}
+
+// GetWithdrawalProof returns the Merkle proof of the withdrawal with the given
+// hash in the state of the given Mive block, which has to be past the
+// withdrawals fork. An error is returned if the withdrawal wasn't initiated by
+// that block.
+func (api *MiveAPI) GetWithdrawalProof(ctx context.Context, hash common.Hash, blockNrOrHash rpc.BlockNumberOrHash) (*WithdrawalProof, error) {
+	header, err := api.headerByNumberOrHash(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	bc := api.m.blockchain
+	if !bc.Config().IsWithdrawals(header.Number, header.Time) {
+		return nil, errors.New("withdrawals not committed to before the withdrawals fork")
+	}
+	statedb, err := bc.StateAt(header.Root)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		contract = miveparams.WithdrawalContractAddress
+		key      = mivetypes.WithdrawalStorageKey(hash)
+	)
+	if statedb.GetState(contract, key) == (common.Hash{}) {
+		return nil, errors.New("withdrawal not found")
+	}
+	triedb := statedb.Database().TrieDB()
+	storageTrie, err := trie.NewStateTrie(trie.StorageTrieID(header.Root, crypto.Keccak256Hash(contract.Bytes()), header.WithdrawalsHash), triedb)
+	if err != nil {
+		return nil, err
+	}
+	var storageProof proofList
+	if err := storageTrie.Prove(crypto.Keccak256(key.Bytes()), &storageProof); err != nil {
+		return nil, err
+	}
+	accountTrie, err := trie.NewStateTrie(trie.StateTrieID(header.Root), triedb)
+	if err != nil {
+		return nil, err
+	}
+	var accountProof proofList
+	if err := accountTrie.Prove(crypto.Keccak256(contract.Bytes()), &accountProof); err != nil {
+		return nil, err
+	}
+	return &WithdrawalProof{
+		WithdrawalHash:  hash,
+		BlockHash:       header.Hash,
+		BlockNumber:     hexutil.Uint64(header.NumberU64()),
+		StateRoot:       header.Root,
+		WithdrawalsRoot: header.WithdrawalsHash,
+		StorageKey:      key,
+		AccountProof:    accountProof,
+		StorageProof:    storageProof,
+	}, statedb.Error()
+}
+
+// Output is the output root of a Mive block, along with the values it commits
+// to.
+type Output struct {
+	Version         common.Hash    `json:"version"`
+	OutputRoot      common.Hash    `json:"outputRoot"`
+	BlockHash       common.Hash    `json:"blockHash"`
+	BlockNumber     hexutil.Uint64 `json:"blockNumber"`
+	StateRoot       common.Hash    `json:"stateRoot"`
+	WithdrawalsRoot common.Hash    `json:"withdrawalsRoot"`
+}
+
+// OutputAtBlock returns the output root of the given Mive block.
+func (api *MiveAPI) OutputAtBlock(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*Output, error) {
+	header, err := api.headerByNumberOrHash(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	return &Output{
+		Version:         mivetypes.OutputVersionV0,
+		OutputRoot:      mivetypes.OutputRoot(header),
+		BlockHash:       header.Hash,
+		BlockNumber:     hexutil.Uint64(header.NumberU64()),
+		StateRoot:       header.Root,
+		WithdrawalsRoot: header.WithdrawalsHash,
+	}, nil
+}
+
+// BlockFees is the distribution of the fees paid by the transactions of a Mive
+// block according to the fee policy.
+type BlockFees struct {
+	BlockHash   common.Hash    `json:"blockHash"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	Beneficiary common.Address `json:"beneficiary"`
+	Total       *hexutil.Big   `json:"total"`
+	Credited    *hexutil.Big   `json:"credited"` // Credited to the beneficiary
+	Treasury    *hexutil.Big   `json:"treasury"`
+	Refunded    *hexutil.Big   `json:"refunded"`
+	Burned      *hexutil.Big   `json:"burned"`
+}
+
+// GetBlockFees returns the fees paid by the transactions of the given Mive block
+// and how they were split between the beneficiary, the treasury, the senders of
+// the beacon transactions and the burn.
+func (api *MiveAPI) GetBlockFees(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*BlockFees, error) {
+	header, err := api.headerByNumberOrHash(blockNrOrHash)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		bc       = api.m.blockchain
+		config   = bc.Config()
+		body     = bc.GetBody(header.Hash)
+		receipts = bc.GetReceiptsByHash(header.Hash)
+	)
+	if body == nil || len(body.Transactions) != len(receipts) {
+		return nil, errors.New("block body not found")
+	}
+	l1Header, err := bc.EthGetHeader(header.Hash, header.NumberU64())
+	if err != nil {
+		return nil, err
+	}
+	context, err := mivecore.NewEVMBlockContext(l1Header, bc, nil, config)
+	if err != nil {
+		return nil, err
+	}
+	fees := &mivecore.FeeSplit{Total: new(big.Int), Beneficiary: new(big.Int), Treasury: new(big.Int), Refund: new(big.Int), Burned: new(big.Int)}
+	for i, tx := range body.Transactions {
+		msg := mivecore.MiveTransactionToMessage(tx, context.BaseFee)
+		split := mivecore.SplitFees(msg, receipts[i].GasUsed, header.Number, context.BaseFee, config)
+		fees.Total.Add(fees.Total, split.Total)
+		fees.Beneficiary.Add(fees.Beneficiary, split.Beneficiary)
+		fees.Treasury.Add(fees.Treasury, split.Treasury)
+		fees.Refund.Add(fees.Refund, split.Refund)
+		fees.Burned.Add(fees.Burned, split.Burned)
+	}
+	return &BlockFees{
+		BlockHash:   header.Hash,
+		BlockNumber: hexutil.Uint64(header.NumberU64()),
+		Beneficiary: context.Coinbase,
+		Total:       (*hexutil.Big)(fees.Total),
+		Credited:    (*hexutil.Big)(fees.Beneficiary),
+		Treasury:    (*hexutil.Big)(fees.Treasury),
+		Refunded:    (*hexutil.Big)(fees.Refund),
+		Burned:      (*hexutil.Big)(fees.Burned),
+	}, nil
+}
+
+// headerByNumberOrHash resolves the given block specifier to a Mive header.
+func (api *MiveAPI) headerByNumberOrHash(blockNrOrHash rpc.BlockNumberOrHash) (*mivetypes.Header, error) {
+	bc := api.m.blockchain
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		header := bc.GetHeaderByHash(hash)
+		if header == nil {
+			return nil, errors.New("header for hash not found")
+		}
+		if blockNrOrHash.RequireCanonical && bc.GetCanonicalHash(header.NumberU64()) != hash {
+			return nil, errors.New("hash is not currently canonical")
+		}
+		return header, nil
+	}
+	number, _ := blockNrOrHash.Number()
+	var header *mivetypes.Header
+	switch number {
+	case rpc.LatestBlockNumber, rpc.PendingBlockNumber:
+		header = bc.CurrentBlock()
+	case rpc.FinalizedBlockNumber:
+		header = bc.CurrentFinalBlock()
+	case rpc.SafeBlockNumber:
+		header = bc.CurrentSafeBlock()
+	case rpc.EarliestBlockNumber:
+		header = bc.Genesis()
+	default:
+		header = bc.GetHeaderByNumber(uint64(number))
+	}
+	if header == nil {
+		return nil, errors.New("header not found")
+	}
+	return header, nil
+}
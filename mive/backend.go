@@ -1,32 +1,160 @@
 package mive
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/bloombits"
 	"github.com/ethereum/go-ethereum/core/rawdb"
-	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/vm"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/dnsdisc"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
 
+	miveconsensus "github.com/ethereum-mive/mive/consensus"
+	"github.com/ethereum-mive/mive/consensus/beacon"
+	mivecore "github.com/ethereum-mive/mive/core"
+	"github.com/ethereum-mive/mive/core/state/pruner"
+	miveethclient "github.com/ethereum-mive/mive/ethclient"
+	"github.com/ethereum-mive/mive/internal/miveapi"
 	"github.com/ethereum-mive/mive/internal/shutdowncheck"
+	"github.com/ethereum-mive/mive/mive/filters"
+	"github.com/ethereum-mive/mive/mive/gasprice"
 	"github.com/ethereum-mive/mive/mive/miveconfig"
+	"github.com/ethereum-mive/mive/mive/protocols/mive"
+	"github.com/ethereum-mive/mive/mive/tracers"
+	"github.com/ethereum-mive/mive/mive/txpool"
 	"github.com/ethereum-mive/mive/node"
+	miveparams "github.com/ethereum-mive/mive/params"
 )
 
+// discmixTimeout is the time the mix of dial candidates waits for a source
+// before moving on to the next one.
+const discmixTimeout = 100 * time.Millisecond
+
 // Mive implements the Mive indexer and execution layer service.
 type Mive struct {
 	config *miveconfig.Config
 
-	ethClient *ethclient.Client
+	ethClient      *miveethclient.Client
+	accountManager *accounts.Manager
+
+	// Handlers
+	engine     miveconsensus.Engine
+	blockchain *mivecore.BlockChain
+	follower   *follower
+	handler    *handler
+	reporter   *badBlockReporter // Reports bad blocks to a remote URL, nil if not configured
+	txPool     *txpool.TxPool    // Pending Mive transactions observed on L1, nil if not configured
+	relayer    *relayer          // Wraps Mive transactions submitted over RPC, nil if not configured
+	proposer   *proposer         // Proposes output roots to L1, nil if not configured
+	disk       *diskMonitor      // Shuts the node down on low disk space, nil if disabled
+	wallets    *walletOpener     // Opens the wallets signing beacon transactions
 
 	// DB interfaces
 	chainDb ethdb.Database // Block chain database
 
-	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully
+	bloomRequests     chan chan *bloombits.Retrieval // Channel receiving bloom data retrieval requests
+	bloomIndexer      *mivecore.ChainIndexer         // Bloom indexer operating during block imports
+	closeBloomHandler chan struct{}
+
+	APIBackend *MiveAPIBackend
+
+	p2pServer *p2p.Server
+	discmix   *enode.FairMix // Dial candidates of the `mive` protocol, from DNS and discv5
+
+	shutdownTracker *shutdowncheck.ShutdownTracker // Tracks if and when the node has shutdown ungracefully, nil if read-only
+}
+
+// chainOverrides returns the overrides of the chain config to apply on startup.
+func chainOverrides(config *miveconfig.Config) *mivecore.ChainOverrides {
+	var overrides mivecore.ChainOverrides
+	if config.OverrideCancun != nil {
+		overrides.OverrideCancun = config.OverrideCancun
+	}
+	if config.OverrideVerkle != nil {
+		overrides.OverrideVerkle = config.OverrideVerkle
+	}
+	if config.OverrideMiveM1 != nil {
+		overrides.OverrideMiveM1 = new(big.Int).SetUint64(*config.OverrideMiveM1)
+	}
+	if config.OverrideMiveM2 != nil {
+		overrides.OverrideMiveM2 = config.OverrideMiveM2
+	}
+	return &overrides
+}
+
+// deploymentGenesis returns the genesis specification of the configured Mive
+// deployment: the configured one, or the main net one if nil, with the genesis
+// block and the initial beacon address overridden if requested.
+func deploymentGenesis(config *miveconfig.Config) *mivecore.Genesis {
+	genesis := config.Genesis
+	if config.GenesisBlock == nil && config.BeaconAddress == nil {
+		return genesis
+	}
+	if genesis == nil {
+		genesis = mivecore.DefaultGenesisBlock()
+	}
+	if genesis.Config == nil {
+		return genesis // Rejected when setting up the genesis
+	}
+	// Copy the chain configuration, it may be shared with the presets
+	var (
+		overridden  = *genesis
+		chainConfig = *genesis.Config
+		miveConfig  miveparams.MiveChainConfig
+	)
+	if chainConfig.Mive != nil {
+		miveConfig = *chainConfig.Mive
+	}
+	if config.GenesisBlock != nil {
+		miveConfig.GenesisBlock = new(big.Int).SetUint64(*config.GenesisBlock)
+	}
+	if config.BeaconAddress != nil {
+		miveConfig.BeaconAddress = *config.BeaconAddress
+	}
+	chainConfig.Mive = &miveConfig
+	overridden.Config = &chainConfig
+	return &overridden
 }
 
 func New(stack *node.Node, config *miveconfig.Config) (*Mive, error) {
-	ethClient, err := ethclient.Dial(config.EthRpcUrl)
+	config.Genesis = deploymentGenesis(config)
+
+	// A read-only node only serves the stored chain, reject anything writing
+	if config.ReadOnly {
+		if config.SyncMode == "checkpoint" {
+			return nil, errors.New("checkpoint sync is not supported in read-only mode")
+		}
+		if config.ExternalDriver {
+			return nil, errors.New("external driver is not supported in read-only mode")
+		}
+		if config.Relayer != (common.Address{}) || config.Proposer != (common.Address{}) {
+			return nil, errors.New("relayer and output proposer are not supported in read-only mode")
+		}
+	}
+
+	clientConfig := miveethclient.DefaultConfig
+	clientConfig.URLs = config.EthRpcUrls
+	clientConfig.ArchiveURL = config.EthArchiveRpcUrl
+	clientConfig.RateLimit = config.EthRpcRateLimit
+	if config.EthRpcMaxRetries > 0 {
+		clientConfig.MaxRetries = config.EthRpcMaxRetries
+	}
+	if config.EthRpcPolicy != "" {
+		clientConfig.Policy = miveethclient.Policy(config.EthRpcPolicy)
+	}
+	ethClient, err := miveethclient.Dial(clientConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -37,7 +165,7 @@ func New(stack *node.Node, config *miveconfig.Config) (*Mive, error) {
 		config.DatabaseHandles,
 		config.DatabaseFreezer,
 		"eth/db/chaindata/",
-		false,
+		config.ReadOnly,
 	)
 	if err != nil {
 		return nil, err
@@ -46,40 +174,258 @@ func New(stack *node.Node, config *miveconfig.Config) (*Mive, error) {
 	if err != nil {
 		return nil, err
 	}
+	if config.NoPruning && scheme == rawdb.PathScheme {
+		return nil, errors.New("archive mode is not supported in path scheme, use --history.state=0 instead")
+	}
 	// Try to recover offline state pruning only in hash-based.
-	if scheme == rawdb.HashScheme {
+	if scheme == rawdb.HashScheme && !config.ReadOnly {
 		if err := pruner.RecoverPruning(stack.ResolvePath(""), chainDb); err != nil {
 			log.Error("Failed to recover state", "error", err)
 		}
 	}
+	if config.SyncMode == "checkpoint" {
+		if err := syncCheckpoint(config, chainDb, scheme, ethClient); err != nil {
+			return nil, fmt.Errorf("checkpoint sync failed: %w", err)
+		}
+	}
 
 	mive := &Mive{
-		config:          config,
-		ethClient:       ethClient,
-		chainDb:         chainDb,
-		shutdownTracker: shutdowncheck.NewShutdownTracker(chainDb),
+		config:            config,
+		ethClient:         ethClient,
+		accountManager:    stack.AccountManager(),
+		chainDb:           chainDb,
+		bloomRequests:     make(chan chan *bloombits.Retrieval),
+		bloomIndexer:      mivecore.NewBloomIndexer(chainDb, config.BloomBitsBlocks, params.BloomConfirms),
+		closeBloomHandler: make(chan struct{}),
+		p2pServer:         stack.Server(),
+		discmix:           enode.NewFairMix(discmixTimeout),
+	}
+	if !config.ReadOnly {
+		mive.shutdownTracker = shutdowncheck.NewShutdownTracker(chainDb)
 	}
 
 	var (
 		vmConfig = vm.Config{
 			EnablePreimageRecording: config.EnablePreimageRecording,
 		}
-		_ = vmConfig
+		cacheConfig = core.DefaultCacheConfigWithScheme(scheme)
 	)
+	cacheConfig.SnapshotLimit = config.SnapshotCache
+	cacheConfig.SnapshotWait = false
+	cacheConfig.StateHistory = config.StateHistory
+	cacheConfig.TrieDirtyDisabled = config.NoPruning
+	cacheConfig.TrieTimeLimit = config.TrieTimeout
+	cacheConfig.Preimages = config.Preimages
+	miveparams.AddBadHashes(config.BadHashes...)
+	mive.engine = beacon.New(ethClient, false)
+	if config.ReadOnly {
+		mive.blockchain, err = mivecore.NewReadOnlyBlockChain(chainDb, cacheConfig, mive.engine, vmConfig, ethClient)
+	} else {
+		mive.blockchain, err = mivecore.NewBlockChain(chainDb, cacheConfig, config.Genesis, chainOverrides(config), mive.engine, vmConfig, ethClient, &config.TxLookupLimit)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !config.ReadOnly {
+		mive.bloomIndexer.Start(mive.blockchain)
+	}
+	var beaconClient *miveethclient.BeaconClient
+	if config.BeaconRpcUrl != "" {
+		if beaconClient, err = miveethclient.DialBeacon(config.BeaconRpcUrl); err != nil {
+			return nil, err
+		}
+	}
+	mive.follower = newFollower(mive.blockchain, ethClient, beaconClient, chainDb, config.Confirmations, config.FollowInterval)
+	if mive.handler, err = newHandler(&handlerConfig{
+		Chain:    mive.blockchain,
+		MaxPeers: stack.Config().P2P.MaxPeers,
+		Resync:   mive.follower.resync,
+	}); err != nil {
+		return nil, err
+	}
+	if config.BadBlockReportURL != "" {
+		mive.reporter = newBadBlockReporter(mive.blockchain, config.BadBlockReportURL)
+	}
+	if config.ObserveTxPool {
+		mive.txPool = txpool.New(config.TxPool, mive.blockchain, ethClient)
+	}
+
+	mive.APIBackend = &MiveAPIBackend{stack.Config().AllowUnprotectedTxs, mive, nil}
+	mive.APIBackend.gpo = gasprice.NewOracle(mive.APIBackend, config.GPO)
 
+	mive.wallets = newWalletOpener(mive.accountManager, ethClient)
+	if config.Relayer != (common.Address{}) {
+		mive.relayer = newRelayer(mive.APIBackend, ethClient, mive.accountManager, chainDb, config.Relayer, config.RelayerFeeCap, config.RelayerBumpInterval)
+	}
+	if config.Proposer != (common.Address{}) {
+		if mive.relayer == nil {
+			return nil, errors.New("output proposer requires a relayer account")
+		}
+		if config.ProposerInterval == 0 {
+			return nil, errors.New("output proposer interval must be positive")
+		}
+		mive.proposer = newProposer(mive.blockchain, mive.relayer, config.Proposer, config.ProposerInterval)
+	}
+
+	// Monitor the disk space of the data directory, the node is shut down in
+	// the background so the chain flushes its state once derivation stopped.
+	if dir := stack.InstanceDir(); dir != "" && config.MinFreeDiskSpace > 0 && !config.ReadOnly {
+		mive.disk = newDiskMonitor(dir, config.MinFreeDiskSpace*1024*1024, func() { go stack.Close() })
+	}
+
+	// Serve the liveness and readiness probes over plain HTTP if requested
+	if config.HealthEndpoints {
+		stack.RegisterHandler("Mive liveness probe", "/healthz", &healthHandler{mive, func(h *Health) bool { return h.Healthy }})
+		stack.RegisterHandler("Mive readiness probe", "/readyz", &healthHandler{mive, func(h *Health) bool { return h.Ready }})
+	}
+	stack.RegisterAPIs(mive.APIs())
+	stack.RegisterProtocols(mive.Protocols())
 	stack.RegisterLifecycle(mive)
 
 	// Successful startup; push a marker and check previous unclean shutdowns.
-	mive.shutdownTracker.MarkStartup()
+	if mive.shutdownTracker != nil {
+		mive.shutdownTracker.MarkStartup()
+
+		log.Info("")
+		log.Info(strings.Repeat("-", 153))
+		for _, line := range strings.Split(mive.shutdownTracker.Description(), "\n") {
+			log.Info(line)
+		}
+		log.Info(strings.Repeat("-", 153))
+		log.Info("")
+	}
 
 	return mive, nil
 }
 
+// APIs return the collection of RPC services the Mive package offers.
+func (s *Mive) APIs() []rpc.API {
+	apis := miveapi.GetAPIs(s.APIBackend)
+
+	// Append any APIs exposed explicitly by the consensus engine
+	apis = append(apis, s.engine.APIs(s.BlockChain())...)
+
+	// Append the driver API if the chain is derived by an external driver
+	if s.config.ExternalDriver {
+		apis = append(apis, rpc.API{
+			Namespace:     "engine",
+			Service:       NewEngineAPI(s),
+			Authenticated: true,
+		})
+	}
+
+	// Append any APIs exposed explicitly by the tracers
+	apis = append(apis, tracers.APIs(s.APIBackend)...)
+
+	// Append the observed pool APIs if enabled
+	if s.txPool != nil {
+		apis = append(apis, rpc.API{
+			Namespace: "txpool",
+			Service:   txpool.NewAPI(s.txPool),
+		})
+	}
+
+	// Append all the local APIs and return
+	return append(apis, []rpc.API{
+		{
+			Namespace: "eth",
+			Service: filters.NewFilterAPI(filters.NewFilterSystem(s.APIBackend, filters.Config{
+				LogCacheSize: s.config.FilterLogCacheSize,
+				RangeLimit:   s.config.RPCLogsRangeLimit,
+			})),
+		}, {
+			Namespace: "mive",
+			Service:   NewMiveAPI(s),
+		}, {
+			Namespace:     "debug",
+			Service:       NewDebugAPI(s),
+			Authenticated: true,
+		},
+	}...)
+}
+
+// BlockChain returns the Mive chain derived by the service.
+func (s *Mive) BlockChain() *mivecore.BlockChain { return s.blockchain }
+
+// ArchiveMode reports whether the state of every Mive block is retained.
+func (s *Mive) ArchiveMode() bool { return s.config.NoPruning }
+
+// Protocols returns all the currently configured network protocols to start.
+func (s *Mive) Protocols() []p2p.Protocol {
+	// A read-only chain can't be synced, and may lag behind the primary node
+	if s.config.ReadOnly {
+		return nil
+	}
+	return mive.MakeProtocols(s.handler, s.discmix)
+}
+
+// setupDiscovery advertises the `mive` protocol on the discovery, and feeds the
+// Mive nodes found from the DNS lists and discv5 to the dial candidates.
+func (s *Mive) setupDiscovery() error {
+	mive.StartENRUpdater(s.blockchain, s.p2pServer.LocalNode())
+
+	// Add the Mive nodes from DNS
+	if len(s.config.DiscoveryURLs) > 0 {
+		iter, err := dnsdisc.NewClient(dnsdisc.Config{}).NewIterator(s.config.DiscoveryURLs...)
+		if err != nil {
+			return err
+		}
+		s.discmix.AddSource(iter)
+	}
+	// Add the DHT nodes from discv5 advertising a compatible Mive chain
+	if s.p2pServer.DiscV5 != nil {
+		iter := enode.Filter(s.p2pServer.DiscV5.RandomNodes(), mive.NewNodeFilter(s.blockchain))
+		s.discmix.AddSource(iter)
+	}
+	return nil
+}
+
 // Start implements node.Lifecycle, starting all internal goroutines needed by the
 // Mive protocol implementation.
 func (s *Mive) Start() error {
-	// Regularly update shutdown marker
-	s.shutdownTracker.Start()
+	// Start the bloom bits servicing goroutines
+	s.startBloomHandlers(s.config.BloomBitsBlocks)
+
+	// A read-only node only serves the stored chain, it neither derives nor
+	// syncs it, and doesn't write anything
+	if !s.config.ReadOnly {
+		if err := s.setupDiscovery(); err != nil {
+			return err
+		}
+		// Regularly update shutdown marker
+		s.shutdownTracker.Start()
+
+		// Start reporting bad blocks before any can be derived
+		if s.reporter != nil {
+			s.reporter.start()
+		}
+		// Start serving the Mive chain to the peers and syncing from them
+		s.handler.Start()
+
+		// Start deriving the Mive chain from L1, unless an external driver does
+		if !s.config.ExternalDriver {
+			s.follower.start()
+		}
+	}
+	// Start observing the pending Mive transactions if enabled
+	if s.txPool != nil {
+		s.txPool.Start()
+	}
+	// Open the wallets before any beacon transaction needs signing
+	s.wallets.start()
+
+	// Start tracking the relayed transactions if enabled
+	if s.relayer != nil {
+		s.relayer.start()
+	}
+	// Start proposing output roots if enabled
+	if s.proposer != nil {
+		s.proposer.start()
+	}
+	// Start monitoring the disk space if enabled
+	if s.disk != nil {
+		s.disk.start()
+	}
 
 	return nil
 }
@@ -87,5 +433,40 @@ func (s *Mive) Start() error {
 // Stop implements node.Lifecycle, terminating all internal goroutines used by the
 // Mive protocol.
 func (s *Mive) Stop() error {
+	// Stop feeding new L1 blocks and syncing from the peers first, draining the
+	// block being processed, then wait for the chain to persist its state before
+	// tearing down the L1 connections.
+	if s.disk != nil {
+		s.disk.stop()
+	}
+	s.discmix.Close()
+	s.follower.stop()
+	s.handler.Stop()
+	if s.txPool != nil {
+		s.txPool.Stop()
+	}
+	if s.proposer != nil {
+		s.proposer.stop()
+	}
+	if s.relayer != nil {
+		s.relayer.stop()
+	}
+	s.wallets.stop()
+	if s.reporter != nil {
+		s.reporter.stop()
+	}
+	s.bloomIndexer.Close()
+	close(s.closeBloomHandler)
+	s.blockchain.Stop()
+	s.engine.Close()
+	s.ethClient.Close()
+
+	// Clean shutdown marker as the last thing before closing db
+	if s.shutdownTracker != nil {
+		s.shutdownTracker.Stop()
+	}
+
+	s.chainDb.Close()
+
 	return nil
 }
@@ -0,0 +1,110 @@
+package mive
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-mive/mive/core"
+	"github.com/ethereum-mive/mive/internal/miveapi"
+)
+
+const (
+	badBlockReportTimeout = 10 * time.Second // Timeout of the request reporting a bad block
+	badBlockChanSize      = 16               // Size of the channel receiving bad block events
+)
+
+// badBlockReporter posts the bad blocks encountered by the derivation as JSON
+// to a configured URL, e.g. a collector aggregating the reports of several
+// nodes to diagnose consensus divergences.
+type badBlockReporter struct {
+	chain  *core.BlockChain
+	url    string
+	client *http.Client
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newBadBlockReporter creates a reporter posting the bad blocks of the given
+// chain to the given URL.
+func newBadBlockReporter(chain *core.BlockChain, url string) *badBlockReporter {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &badBlockReporter{
+		chain:  chain,
+		url:    url,
+		client: &http.Client{Timeout: badBlockReportTimeout},
+		ctx:    ctx,
+		cancel: cancel,
+	}
+}
+
+// start launches the reporting loop.
+func (r *badBlockReporter) start() {
+	r.wg.Add(1)
+	go r.loop()
+}
+
+// stop terminates the reporting loop, aborting any in-flight report.
+func (r *badBlockReporter) stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// loop reports the bad blocks as they are encountered. Failing reports are
+// only logged, the bad blocks remain available through debug_getBadBlocks.
+func (r *badBlockReporter) loop() {
+	defer r.wg.Done()
+
+	badCh := make(chan core.BadBlockEvent, badBlockChanSize)
+	sub := r.chain.SubscribeBadBlockEvent(badCh)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-badCh:
+			if err := r.report(ev); err != nil {
+				log.Warn("Failed to report bad block", "number", ev.Block.Origin.Number(), "hash", ev.Block.Origin.Hash(), "url", r.url, "err", err)
+			}
+		case <-sub.Err():
+			return
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// report posts a single bad block to the configured URL.
+func (r *badBlockReporter) report(ev core.BadBlockEvent) error {
+	args, err := miveapi.NewBadBlockArgs(ev.Block)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(r.ctx, http.MethodPost, r.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
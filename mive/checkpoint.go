@@ -0,0 +1,154 @@
+package mive
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/triedb/pathdb"
+
+	"github.com/ethereum-mive/mive/core"
+	miverawdb "github.com/ethereum-mive/mive/core/rawdb"
+	"github.com/ethereum-mive/mive/core/state/snapshot"
+	miveethclient "github.com/ethereum-mive/mive/ethclient"
+	"github.com/ethereum-mive/mive/mive/miveconfig"
+)
+
+// checkpointFetchTimeout is the timeout of the request retrieving the trusted
+// checkpoint from the configured URL.
+const checkpointFetchTimeout = 30 * time.Second
+
+// trustedCheckpoint is a Mive block number and state root pair the chain can be
+// bootstrapped from.
+type trustedCheckpoint struct {
+	Number hexutil.Uint64 `json:"number"`
+	Root   common.Hash    `json:"root"`
+}
+
+// syncCheckpoint bootstraps the chain stored in the database from the state
+// snapshot archive of the trusted checkpoint, unless the chain already reached
+// it. The archive is only imported if its block matches the trusted checkpoint
+// and the L1 chain, and its state is verified against the trusted root before
+// the chain is moved to it.
+func syncCheckpoint(config *miveconfig.Config, db ethdb.Database, scheme string, ethClient *miveethclient.Client) error {
+	trusted, err := resolveCheckpoint(config)
+	if err != nil {
+		return err
+	}
+	number := uint64(trusted.Number)
+
+	triedb := trie.NewDatabase(db, &trie.Config{PathDB: pathdb.Defaults})
+	if scheme == rawdb.HashScheme {
+		triedb = trie.NewDatabase(db, trie.HashDefaults)
+	}
+	defer triedb.Close()
+
+	// The checkpoint is applied on top of the genesis
+	ctx := context.Background()
+	if _, _, err := core.SetupGenesisBlockWithOverride(ctx, db, triedb, config.Genesis, chainOverrides(config), ethClient); err != nil {
+		return err
+	}
+	if head := miverawdb.ReadHeadBlock(db); head != nil && head.NumberU64() >= number {
+		log.Info("Chain already past the checkpoint", "number", head.Number, "checkpoint", number)
+		return nil
+	}
+	r, err := openCheckpointArchive(config.CheckpointArchive)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	archive, err := snapshot.OpenArchive(r)
+	if err != nil {
+		return fmt.Errorf("failed to open checkpoint archive: %w", err)
+	}
+	block := archive.Checkpoint.Block
+	if block.NumberU64() != number || block.Root() != trusted.Root {
+		return fmt.Errorf("checkpoint archive at #%d with root %x, want #%d with root %x", block.NumberU64(), block.Root(), number, trusted.Root)
+	}
+	// The hash of a Mive block is the hash of the L1 block it's derived from
+	header, err := ethClient.HeaderByNumber(ctx, block.Number())
+	if err != nil {
+		return err
+	}
+	if header.Hash() != block.Hash() {
+		return fmt.Errorf("checkpoint archive block #%d [%x..] is not on the L1 chain [%x..]", number, block.Hash().Bytes()[:4], header.Hash().Bytes()[:4])
+	}
+	if err := core.CheckCheckpoint(db, archive.Checkpoint); err != nil {
+		return err
+	}
+	log.Info("Importing checkpoint state", "number", number, "hash", block.Hash(), "root", block.Root())
+	if err := archive.Import(db, triedb); err != nil {
+		return err
+	}
+	if err := core.WriteCheckpoint(db, archive.Checkpoint); err != nil {
+		return err
+	}
+	log.Info("Synced to checkpoint", "number", number, "hash", block.Hash())
+	return nil
+}
+
+// resolveCheckpoint returns the configured trusted checkpoint, or retrieves it
+// from the configured URL.
+func resolveCheckpoint(config *miveconfig.Config) (*trustedCheckpoint, error) {
+	if config.CheckpointRoot != (common.Hash{}) {
+		return &trustedCheckpoint{
+			Number: hexutil.Uint64(config.CheckpointNumber),
+			Root:   config.CheckpointRoot,
+		}, nil
+	}
+	if config.CheckpointURL == "" {
+		return nil, errors.New("checkpoint sync requires a trusted checkpoint root or URL")
+	}
+	client := &http.Client{Timeout: checkpointFetchTimeout}
+	resp, err := client.Get(config.CheckpointURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve checkpoint: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to retrieve checkpoint: %s", resp.Status)
+	}
+	var checkpoint trustedCheckpoint
+	if err := json.NewDecoder(resp.Body).Decode(&checkpoint); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	if checkpoint.Root == (common.Hash{}) {
+		return nil, errors.New("invalid checkpoint: missing root")
+	}
+	log.Info("Retrieved trusted checkpoint", "url", config.CheckpointURL, "number", uint64(checkpoint.Number), "root", checkpoint.Root)
+	return &checkpoint, nil
+}
+
+// openCheckpointArchive opens the state snapshot archive at the given location,
+// either a local file or an HTTP URL.
+func openCheckpointArchive(location string) (io.ReadCloser, error) {
+	if location == "" {
+		return nil, errors.New("checkpoint sync requires a checkpoint archive")
+	}
+	if !strings.HasPrefix(location, "http://") && !strings.HasPrefix(location, "https://") {
+		return os.Open(location)
+	}
+	resp, err := http.Get(location)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download checkpoint archive: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("failed to download checkpoint archive: %s", resp.Status)
+	}
+	return resp.Body, nil
+}
@@ -0,0 +1,146 @@
+// Package devnet runs a development Mive deployment, deriving the Mive chain
+// from an in-process simulated L1 chain, so that Mive contracts can be tested
+// end-to-end without any external infrastructure.
+package devnet
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/eth"
+	"github.com/ethereum/go-ethereum/eth/catalyst"
+	"github.com/ethereum/go-ethereum/eth/downloader"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/params"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	miveparams "github.com/ethereum-mive/mive/params"
+)
+
+// ipcPath is the name of the IPC endpoint of the simulated L1 chain, in its
+// data directory or in the temporary directory if ephemeral.
+const ipcPath = "mive-l1.ipc"
+
+var (
+	// BeaconContract is the address of the beacon contract deployed on the
+	// simulated L1 chain, whose events are executed as Mive transactions.
+	BeaconContract = common.HexToAddress("0x000000000000000000000000000000000000315d")
+
+	// BeaconContractCode is the code of the beacon contract, emitting the call
+	// data as the payload of a MiveTransaction event sent by the caller.
+	BeaconContractCode = hexutil.MustDecode("0x602060005236602052366000604037337fae26c0c12c13d8ff0fa39bcace23f9c926daf32d2c41baf31d47c1564769fb9b601f3601601f19166040016000a200")
+
+	// developerBalance is the balance of the developer account on both chains.
+	developerBalance = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 256), big.NewInt(9))
+)
+
+// Config contains the settings of the simulated L1 chain.
+type Config struct {
+	DataDir   string         // Data directory of the L1 chain, empty for in-memory databases
+	Developer common.Address // Account funded on L1 and Mive
+	Period    uint64         // Seconds between the L1 blocks, 0 to only seal them when transactions are pending
+	GasLimit  uint64         // Gas limit of the L1 blocks
+}
+
+// L1 is an in-process simulated L1 chain, sealing blocks like a beacon node
+// would. It implements node.Lifecycle, so that it's stopped after the Mive
+// node deriving from it.
+type L1 struct {
+	stack *node.Node
+}
+
+// NewL1 creates and starts a simulated L1 chain, whose genesis funds the
+// developer account and deploys the beacon contract. The chain is served on an
+// IPC endpoint only, as the Mive node has to dial it before it's started.
+func NewL1(config Config) (*L1, error) {
+	stack, err := node.New(&node.Config{
+		Name:    "geth",
+		Version: params.VersionWithMeta,
+		DataDir: config.DataDir,
+		IPCPath: ipcPath,
+		P2P: p2p.Config{
+			MaxPeers:    0,
+			NoDial:      true,
+			NoDiscovery: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	ethConfig := ethconfig.Defaults
+	ethConfig.Genesis = L1Genesis(config)
+	ethConfig.NetworkId = ethConfig.Genesis.Config.ChainID.Uint64()
+	ethConfig.SyncMode = downloader.FullSync
+	ethConfig.Miner.Etherbase = config.Developer
+	ethConfig.Miner.GasCeil = config.GasLimit
+	ethConfig.Miner.GasPrice = big.NewInt(1)
+
+	backend, err := eth.New(stack, &ethConfig)
+	if err != nil {
+		stack.Close()
+		return nil, err
+	}
+	beacon, err := catalyst.NewSimulatedBeacon(config.Period, backend)
+	if err != nil {
+		stack.Close()
+		return nil, err
+	}
+	catalyst.RegisterSimulatedBeaconAPIs(stack, beacon)
+	stack.RegisterLifecycle(beacon)
+
+	if err := stack.Start(); err != nil {
+		stack.Close()
+		return nil, err
+	}
+	return &L1{stack: stack}, nil
+}
+
+// Endpoint returns the IPC endpoint the simulated L1 chain is served on.
+func (l *L1) Endpoint() string {
+	return l.stack.IPCEndpoint()
+}
+
+// Start implements node.Lifecycle, the chain is already running.
+func (l *L1) Start() error {
+	return nil
+}
+
+// Stop implements node.Lifecycle, stopping the chain and closing its databases.
+func (l *L1) Stop() error {
+	return l.stack.Close()
+}
+
+// L1Genesis returns the genesis of the simulated L1 chain, with all the
+// protocol changes active, funding the developer account and deploying the
+// beacon contract.
+func L1Genesis(config Config) *core.Genesis {
+	genesis := core.DeveloperGenesisBlock(config.GasLimit, &config.Developer)
+	genesis.Alloc[BeaconContract] = core.GenesisAccount{Code: BeaconContractCode, Balance: new(big.Int)}
+	return genesis
+}
+
+// Genesis returns the genesis of the Mive chain derived from the simulated L1
+// chain, starting at its genesis block and funding the developer account. Both
+// the beacon transactions sent to the default beacon address and the events of
+// the beacon contract are executed.
+func Genesis(developer common.Address) *mivecore.Genesis {
+	ethConfig := *params.AllDevChainProtocolChanges
+	return &mivecore.Genesis{
+		Config: &miveparams.ChainConfig{
+			Eth: &ethConfig,
+			Mive: &miveparams.MiveChainConfig{
+				GenesisBlock:        new(big.Int),
+				BeaconAddress:       miveparams.DefaultBeaconAddress,
+				BeaconContract:      BeaconContract,
+				BeaconContractBlock: new(big.Int),
+			},
+		},
+		Alloc: mivecore.GenesisAlloc{
+			developer: {Balance: developerBalance},
+		},
+	}
+}
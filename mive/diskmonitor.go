@@ -0,0 +1,77 @@
+package mive
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// diskCheckInterval is the interval between checks of the free disk space.
+const diskCheckInterval = 30 * time.Second
+
+// diskMonitor periodically checks the free space of the filesystem holding the
+// data directory, and shuts the node down once it drops below the critical
+// level, before failing writes corrupt the database.
+type diskMonitor struct {
+	path     string // Directory whose filesystem is checked
+	critical uint64 // Free space (bytes) below which the node is shut down
+	shutdown func() // Shuts the node down gracefully, without blocking
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newDiskMonitor creates a monitor of the filesystem holding the given path,
+// calling shutdown once its free space drops below the critical level.
+func newDiskMonitor(path string, critical uint64, shutdown func()) *diskMonitor {
+	return &diskMonitor{
+		path:     path,
+		critical: critical,
+		shutdown: shutdown,
+		quit:     make(chan struct{}),
+	}
+}
+
+// start launches the monitoring loop.
+func (m *diskMonitor) start() {
+	m.wg.Add(1)
+	go m.loop()
+}
+
+// stop terminates the monitoring loop.
+func (m *diskMonitor) stop() {
+	close(m.quit)
+	m.wg.Wait()
+}
+
+// loop checks the free disk space until it drops below the critical level or
+// the monitor is stopped.
+func (m *diskMonitor) loop() {
+	defer m.wg.Done()
+
+	ticker := time.NewTicker(diskCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		free, err := getFreeDiskSpace(m.path)
+		if err != nil {
+			log.Warn("Failed to get free disk space, monitoring disabled", "path", m.path, "err", err)
+			return
+		}
+		if free < m.critical {
+			log.Error("Low disk space, shutting down gracefully to prevent database corruption", "available", common.StorageSize(free), "path", m.path)
+			m.shutdown()
+			return
+		}
+		if free < 2*m.critical {
+			log.Warn("Disk space is running low, the node will shut down below the critical level", "available", common.StorageSize(free), "critical", common.StorageSize(m.critical), "path", m.path)
+		}
+		select {
+		case <-ticker.C:
+		case <-m.quit:
+			return
+		}
+	}
+}
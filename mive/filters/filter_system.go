@@ -0,0 +1,461 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package filters implements a filtering system for the logs of the Mive chain.
+package filters
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/bloombits"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+// Config represents the configuration of the filter system.
+type Config struct {
+	LogCacheSize int           // maximum number of cached blocks (default: 32)
+	RangeLimit   uint64        // maximum number of blocks a range query may span (0: unlimited)
+	Timeout      time.Duration // how long filters stay active (default: 5min)
+}
+
+func (cfg Config) withDefaults() Config {
+	if cfg.Timeout == 0 {
+		cfg.Timeout = 5 * time.Minute
+	}
+	if cfg.LogCacheSize == 0 {
+		cfg.LogCacheSize = 32
+	}
+	return cfg
+}
+
+type Backend interface {
+	HeaderByNumber(ctx context.Context, blockNr rpc.BlockNumber) (*mivetypes.Header, error)
+	HeaderByHash(ctx context.Context, blockHash common.Hash) (*mivetypes.Header, error)
+	GetLogs(ctx context.Context, blockHash common.Hash, number uint64) ([][]*types.Log, error)
+
+	SubscribeChainEvent(ch chan<- core.ChainEvent) event.Subscription
+	SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription
+	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
+	SubscribeNewMiveTxsEvent(ch chan<- mivecore.NewMiveTxsEvent) event.Subscription
+	SubscribeNewPendingTxsEvent(ch chan<- mivecore.NewPendingTxsEvent) event.Subscription
+
+	BloomStatus() (uint64, uint64)
+	ServiceFilter(ctx context.Context, session *bloombits.MatcherSession)
+}
+
+// FilterSystem holds resources shared by all filters.
+type FilterSystem struct {
+	backend   Backend
+	logsCache *lru.Cache[common.Hash, []*types.Log]
+	cfg       *Config
+}
+
+// NewFilterSystem creates a filter system.
+func NewFilterSystem(backend Backend, config Config) *FilterSystem {
+	config = config.withDefaults()
+	return &FilterSystem{
+		backend:   backend,
+		logsCache: lru.NewCache[common.Hash, []*types.Log](config.LogCacheSize),
+		cfg:       &config,
+	}
+}
+
+// cachedLogs loads block logs from the backend and caches the result. Unlike
+// in Ethereum, the logs are fully derived, including the transaction hashes.
+func (sys *FilterSystem) cachedLogs(ctx context.Context, blockHash common.Hash, number uint64) ([]*types.Log, error) {
+	cached, ok := sys.logsCache.Get(blockHash)
+	if ok {
+		return cached, nil
+	}
+
+	logs, err := sys.backend.GetLogs(ctx, blockHash, number)
+	if err != nil {
+		return nil, err
+	}
+	if logs == nil {
+		return nil, fmt.Errorf("failed to get logs for block #%d (0x%s)", number, blockHash.TerminalString())
+	}
+	flattened := make([]*types.Log, 0)
+	for _, txLogs := range logs {
+		flattened = append(flattened, txLogs...)
+	}
+	sys.logsCache.Add(blockHash, flattened)
+	return flattened, nil
+}
+
+// Type determines the kind of filter and is used to put the filter in to
+// the correct bucket when added.
+type Type byte
+
+const (
+	// UnknownSubscription indicates an unknown subscription type
+	UnknownSubscription Type = iota
+	// LogsSubscription queries for new or removed (chain rewind) logs
+	LogsSubscription
+	// BlocksSubscription queries hashes for Mive blocks that are imported
+	BlocksSubscription
+	// MiveTransactionsSubscription queries for the Mive transactions executed
+	// in the imported blocks
+	MiveTransactionsSubscription
+	// PendingTransactionsSubscription queries for the pending Mive transactions
+	// observed in the L1 mempool
+	PendingTransactionsSubscription
+	// LastIndexSubscription keeps track of the last index
+	LastIndexSubscription
+)
+
+const (
+	// miveTxsChanSize is the size of channel listening to NewMiveTxsEvent.
+	miveTxsChanSize = 10
+	// pendingTxsChanSize is the size of channel listening to NewPendingTxsEvent.
+	pendingTxsChanSize = 4096
+	// rmLogsChanSize is the size of channel listening to RemovedLogsEvent.
+	rmLogsChanSize = 10
+	// logsChanSize is the size of channel listening to LogsEvent.
+	logsChanSize = 10
+	// chainEvChanSize is the size of channel listening to ChainEvent.
+	chainEvChanSize = 10
+)
+
+type subscription struct {
+	id        rpc.ID
+	typ       Type
+	created   time.Time
+	logsCrit  ethereum.FilterQuery
+	logs      chan []*types.Log
+	headers   chan *mivetypes.Header
+	blocks    chan *mivetypes.Block
+	txs       chan []*mivetypes.Transaction
+	installed chan struct{} // closed when the filter is installed
+	err       chan error    // closed when the filter is uninstalled
+}
+
+// EventSystem creates subscriptions, processes events and broadcasts them to the
+// subscription which match the subscription criteria.
+type EventSystem struct {
+	backend Backend
+	sys     *FilterSystem
+
+	// Subscriptions
+	miveTxsSub    event.Subscription // Subscription for executed Mive transactions event
+	pendingTxsSub event.Subscription // Subscription for pending Mive transactions event
+	logsSub       event.Subscription // Subscription for new log event
+	rmLogsSub     event.Subscription // Subscription for removed log event
+	chainSub      event.Subscription // Subscription for new chain event
+
+	// Channels
+	install      chan *subscription               // install filter for event notification
+	uninstall    chan *subscription               // remove filter for event notification
+	miveTxsCh    chan mivecore.NewMiveTxsEvent    // Channel to receive executed Mive transactions event
+	pendingTxsCh chan mivecore.NewPendingTxsEvent // Channel to receive pending Mive transactions event
+	logsCh       chan []*types.Log                // Channel to receive new log event
+	rmLogsCh     chan core.RemovedLogsEvent       // Channel to receive removed log event
+	chainCh      chan core.ChainEvent             // Channel to receive new chain event
+}
+
+// NewEventSystem creates a new manager that listens for event on the given mux,
+// parses and filters them. It uses the all map to retrieve filter changes. The
+// work loop holds its own index that is used to forward events to filters.
+//
+// The returned manager has a loop that needs to be stopped with the Stop function
+// or by stopping the given mux.
+func NewEventSystem(sys *FilterSystem) *EventSystem {
+	m := &EventSystem{
+		sys:          sys,
+		backend:      sys.backend,
+		install:      make(chan *subscription),
+		uninstall:    make(chan *subscription),
+		miveTxsCh:    make(chan mivecore.NewMiveTxsEvent, miveTxsChanSize),
+		pendingTxsCh: make(chan mivecore.NewPendingTxsEvent, pendingTxsChanSize),
+		logsCh:       make(chan []*types.Log, logsChanSize),
+		rmLogsCh:     make(chan core.RemovedLogsEvent, rmLogsChanSize),
+		chainCh:      make(chan core.ChainEvent, chainEvChanSize),
+	}
+
+	// Subscribe events
+	m.miveTxsSub = m.backend.SubscribeNewMiveTxsEvent(m.miveTxsCh)
+	m.pendingTxsSub = m.backend.SubscribeNewPendingTxsEvent(m.pendingTxsCh)
+	m.logsSub = m.backend.SubscribeLogsEvent(m.logsCh)
+	m.rmLogsSub = m.backend.SubscribeRemovedLogsEvent(m.rmLogsCh)
+	m.chainSub = m.backend.SubscribeChainEvent(m.chainCh)
+
+	// Make sure none of the subscriptions are empty
+	if m.miveTxsSub == nil || m.pendingTxsSub == nil || m.logsSub == nil || m.rmLogsSub == nil || m.chainSub == nil {
+		log.Crit("Subscribe for event system failed")
+	}
+
+	go m.eventLoop()
+	return m
+}
+
+// Subscription is created when the client registers itself for a particular event.
+type Subscription struct {
+	ID        rpc.ID
+	f         *subscription
+	es        *EventSystem
+	unsubOnce sync.Once
+}
+
+// Err returns a channel that is closed when unsubscribed.
+func (sub *Subscription) Err() <-chan error {
+	return sub.f.err
+}
+
+// Unsubscribe uninstalls the subscription from the event broadcast loop.
+func (sub *Subscription) Unsubscribe() {
+	sub.unsubOnce.Do(func() {
+	uninstallLoop:
+		for {
+			// write uninstall request and consume logs/hashes. This prevents
+			// the eventLoop broadcast method to deadlock when writing to the
+			// filter event channel while the subscription loop is waiting for
+			// this method to return (and thus not reading these events).
+			select {
+			case sub.es.uninstall <- sub.f:
+				break uninstallLoop
+			case <-sub.f.logs:
+			case <-sub.f.headers:
+			case <-sub.f.blocks:
+			case <-sub.f.txs:
+			}
+		}
+
+		// wait for filter to be uninstalled in work loop before returning
+		// this ensures that the manager won't use the event channel which
+		// will probably be closed by the client asap after this method returns.
+		<-sub.Err()
+	})
+}
+
+// subscribe installs the subscription in the event broadcast loop.
+func (es *EventSystem) subscribe(sub *subscription) *Subscription {
+	es.install <- sub
+	<-sub.installed
+	return &Subscription{ID: sub.id, f: sub, es: es}
+}
+
+// SubscribeLogs creates a subscription that will write all logs matching the
+// given criteria to the given logs channel. Default value for the from and to
+// block is "latest". If the fromBlock > toBlock an error is returned. As the
+// Mive chain has no pending block, "pending" is treated as "latest".
+func (es *EventSystem) SubscribeLogs(crit ethereum.FilterQuery, logs chan []*types.Log) (*Subscription, error) {
+	if len(crit.Topics) > maxTopics {
+		return nil, errExceedMaxTopics
+	}
+	from, to := rpc.LatestBlockNumber, rpc.LatestBlockNumber
+	if crit.FromBlock != nil {
+		from = rpc.BlockNumber(crit.FromBlock.Int64())
+	}
+	if crit.ToBlock != nil {
+		to = rpc.BlockNumber(crit.ToBlock.Int64())
+	}
+	if from == rpc.PendingBlockNumber {
+		from = rpc.LatestBlockNumber
+	}
+	if to == rpc.PendingBlockNumber {
+		to = rpc.LatestBlockNumber
+	}
+
+	// only interested in new mined logs
+	if from == rpc.LatestBlockNumber && to == rpc.LatestBlockNumber {
+		return es.subscribeLogs(crit, logs), nil
+	}
+	// only interested in mined logs within a specific block range
+	if from >= 0 && to >= 0 && to >= from {
+		return es.subscribeLogs(crit, logs), nil
+	}
+	// interested in logs from a specific block number to new mined blocks
+	if from >= 0 && to == rpc.LatestBlockNumber {
+		return es.subscribeLogs(crit, logs), nil
+	}
+	return nil, errInvalidBlockRange
+}
+
+// subscribeLogs creates a subscription that will write all logs matching the
+// given criteria to the given logs channel.
+func (es *EventSystem) subscribeLogs(crit ethereum.FilterQuery, logs chan []*types.Log) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       LogsSubscription,
+		logsCrit:  crit,
+		created:   time.Now(),
+		logs:      logs,
+		headers:   make(chan *mivetypes.Header),
+		blocks:    make(chan *mivetypes.Block),
+		txs:       make(chan []*mivetypes.Transaction),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+// SubscribeNewHeads creates a subscription that writes the header of a Mive
+// block that is imported in the chain.
+func (es *EventSystem) SubscribeNewHeads(headers chan *mivetypes.Header) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       BlocksSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		headers:   headers,
+		blocks:    make(chan *mivetypes.Block),
+		txs:       make(chan []*mivetypes.Transaction),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+// SubscribeMiveTxs creates a subscription that writes the Mive blocks imported
+// in the chain, for the transactions executed in them.
+func (es *EventSystem) SubscribeMiveTxs(blocks chan *mivetypes.Block) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       MiveTransactionsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		headers:   make(chan *mivetypes.Header),
+		blocks:    blocks,
+		txs:       make(chan []*mivetypes.Transaction),
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+// SubscribePendingTxs creates a subscription that writes the pending Mive
+// transactions observed in the L1 mempool.
+func (es *EventSystem) SubscribePendingTxs(txs chan []*mivetypes.Transaction) *Subscription {
+	sub := &subscription{
+		id:        rpc.NewID(),
+		typ:       PendingTransactionsSubscription,
+		created:   time.Now(),
+		logs:      make(chan []*types.Log),
+		headers:   make(chan *mivetypes.Header),
+		blocks:    make(chan *mivetypes.Block),
+		txs:       txs,
+		installed: make(chan struct{}),
+		err:       make(chan error),
+	}
+	return es.subscribe(sub)
+}
+
+type filterIndex map[Type]map[rpc.ID]*subscription
+
+func (es *EventSystem) handleLogs(filters filterIndex, ev []*types.Log) {
+	if len(ev) == 0 {
+		return
+	}
+	for _, f := range filters[LogsSubscription] {
+		matchedLogs := filterLogs(ev, f.logsCrit.FromBlock, f.logsCrit.ToBlock, f.logsCrit.Addresses, f.logsCrit.Topics)
+		if len(matchedLogs) > 0 {
+			f.logs <- matchedLogs
+		}
+	}
+}
+
+func (es *EventSystem) handleMiveTxsEvent(filters filterIndex, ev mivecore.NewMiveTxsEvent) {
+	for _, f := range filters[MiveTransactionsSubscription] {
+		f.blocks <- ev.Block
+	}
+}
+
+func (es *EventSystem) handlePendingTxsEvent(filters filterIndex, ev mivecore.NewPendingTxsEvent) {
+	for _, f := range filters[PendingTransactionsSubscription] {
+		f.txs <- ev.Txs
+	}
+}
+
+func (es *EventSystem) handleChainEvent(filters filterIndex, ev core.ChainEvent) {
+	if len(filters[BlocksSubscription]) == 0 {
+		return
+	}
+	// The event carries the L1 block the Mive block was derived from, the Mive
+	// header itself shares its hash.
+	header, err := es.backend.HeaderByHash(context.Background(), ev.Hash)
+	if err != nil || header == nil {
+		log.Warn("Mive header of chain event missing", "number", ev.Block.Number(), "hash", ev.Hash, "err", err)
+		return
+	}
+	for _, f := range filters[BlocksSubscription] {
+		f.headers <- header
+	}
+}
+
+// eventLoop (un)installs filters and processes mux events.
+func (es *EventSystem) eventLoop() {
+	// Ensure all subscriptions get cleaned up
+	defer func() {
+		es.miveTxsSub.Unsubscribe()
+		es.pendingTxsSub.Unsubscribe()
+		es.logsSub.Unsubscribe()
+		es.rmLogsSub.Unsubscribe()
+		es.chainSub.Unsubscribe()
+	}()
+
+	index := make(filterIndex)
+	for i := UnknownSubscription; i < LastIndexSubscription; i++ {
+		index[i] = make(map[rpc.ID]*subscription)
+	}
+
+	for {
+		select {
+		case ev := <-es.miveTxsCh:
+			es.handleMiveTxsEvent(index, ev)
+		case ev := <-es.pendingTxsCh:
+			es.handlePendingTxsEvent(index, ev)
+		case ev := <-es.logsCh:
+			es.handleLogs(index, ev)
+		case ev := <-es.rmLogsCh:
+			es.handleLogs(index, ev.Logs)
+		case ev := <-es.chainCh:
+			es.handleChainEvent(index, ev)
+
+		case f := <-es.install:
+			index[f.typ][f.id] = f
+			close(f.installed)
+
+		case f := <-es.uninstall:
+			delete(index[f.typ], f.id)
+			close(f.err)
+
+		// System stopped
+		case <-es.miveTxsSub.Err():
+			return
+		case <-es.pendingTxsSub.Err():
+			return
+		case <-es.logsSub.Err():
+			return
+		case <-es.rmLogsSub.Err():
+			return
+		case <-es.chainSub.Err():
+			return
+		}
+	}
+}
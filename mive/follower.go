@@ -0,0 +1,336 @@
+package mive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"go.opentelemetry.io/otel/attribute"
+
+	miveconsensus "github.com/ethereum-mive/mive/consensus"
+	"github.com/ethereum-mive/mive/core"
+	miverawdb "github.com/ethereum-mive/mive/core/rawdb"
+	miveethclient "github.com/ethereum-mive/mive/ethclient"
+	"github.com/ethereum-mive/mive/internal/telemetry"
+)
+
+const (
+	followInterval   = 12 * time.Second // Default interval between checks for new L1 blocks
+	followBatchSize  = 64               // Maximum number of L1 blocks inserted at once
+	gapRetryInterval = time.Minute      // Initial delay before retrying to backfill a history gap
+	gapRetryMax      = 30 * time.Minute // Maximum delay between retries to backfill a history gap
+)
+
+var (
+	l1HeadGauge = metrics.NewRegisteredGauge("mive/follower/l1head", nil) // Latest L1 block number seen
+	l1LagGauge  = metrics.NewRegisteredGauge("mive/follower/l1lag", nil)  // Number of L1 blocks the Mive head is behind
+
+	fetchTimer = metrics.NewRegisteredTimer("mive/follower/fetch", nil) // Time spent retrieving a batch of L1 blocks
+	blobsTimer = metrics.NewRegisteredTimer("mive/follower/blobs", nil) // Time spent archiving the blobs of a batch of L1 blocks
+
+	reorgDepthHistogram = metrics.NewRegisteredHistogram("mive/follower/reorg/depth", nil, metrics.NewExpDecaySample(1028, 0.015)) // Number of Mive blocks rewound by L1 reorgs
+)
+
+// follower derives the Mive chain by continuously feeding the L1 blocks that
+// follow the current Mive head into the blockchain.
+type follower struct {
+	chain  *core.BlockChain
+	client *miveethclient.Client
+	beacon *miveethclient.BeaconClient // Beacon node to retrieve blobs from, nil if not configured
+	db     ethdb.KeyValueStore         // Database archiving the retrieved blobs
+	wake   chan struct{}               // Notification channel to sync right away
+
+	confirmations uint64        // Number of L1 blocks to stay behind the L1 head
+	interval      time.Duration // Interval between checks for new L1 blocks
+
+	lock       sync.Mutex
+	lastSync   time.Time // Time the chain last caught up with L1
+	lastDerive time.Time // Time a block was last derived
+	lastErr    error     // Error the last sync round failed with, nil if it succeeded
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newFollower creates a follower deriving the given chain from L1, retrieving
+// the blobs of the blob-carrying beacon transactions from the given beacon node.
+// The L1 blocks are derived once they have the given number of confirmations,
+// checking for new ones at the given interval (0 for the default).
+func newFollower(chain *core.BlockChain, client *miveethclient.Client, beacon *miveethclient.BeaconClient, db ethdb.KeyValueStore, confirmations uint64, interval time.Duration) *follower {
+	if interval <= 0 {
+		interval = followInterval
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	return &follower{
+		chain:         chain,
+		client:        client,
+		beacon:        beacon,
+		db:            db,
+		wake:          make(chan struct{}, 1),
+		confirmations: confirmations,
+		interval:      interval,
+		ctx:           ctx,
+		cancel:        cancel,
+	}
+}
+
+// start launches the derivation loop.
+func (f *follower) start() {
+	f.wg.Add(1)
+	go f.loop()
+}
+
+// stop terminates the derivation loop, aborting any in-flight L1 requests. The
+// insertion of the current batch is interrupted once the block being processed
+// is committed, which permanently disables any further chain insertion.
+func (f *follower) stop() {
+	f.cancel()
+	f.chain.StopInsert()
+	f.wg.Wait()
+}
+
+// resync makes the derivation loop sync right away instead of waiting for its
+// next round, e.g. after the chain was rewound.
+func (f *follower) resync() {
+	select {
+	case f.wake <- struct{}{}:
+	default:
+	}
+}
+
+// loop periodically catches the Mive chain up with L1. If the L1 endpoints
+// can't serve a historical block or a derived block fails validation,
+// derivation stalls at that block and is retried with an increasing delay.
+func (f *follower) loop() {
+	defer f.wg.Done()
+
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	retry := gapRetryInterval
+	for {
+		select {
+		case <-timer.C:
+		case <-f.wake:
+			retry = gapRetryInterval
+		case <-f.ctx.Done():
+			return
+		}
+		err := f.sync()
+		f.record(err)
+
+		var (
+			gap     *miveethclient.HistoryGapError
+			invalid *miveconsensus.ValidationError
+		)
+		switch {
+		case errors.As(err, &gap):
+			log.Error("L1 history gap detected, derivation stalled", "number", gap.Number, "head", gap.Head,
+				"archive", f.client.HasArchive(), "retry", common.PrettyDuration(retry), "err", gap.Err)
+			if !f.client.HasArchive() {
+				log.Warn("The L1 endpoint prunes history, configure an archive endpoint via --mive.eth.archive")
+			}
+			timer.Reset(retry)
+			if retry *= 2; retry > gapRetryMax {
+				retry = gapRetryMax
+			}
+		case errors.As(err, &invalid):
+			// Retrying re-fetches the L1 block, which helps if an endpoint
+			// served corrupt data. Otherwise derivation stays stalled until
+			// the issue is resolved manually.
+			log.Error("Invalid Mive block, derivation stalled", "number", invalid.Number, "hash", invalid.Hash,
+				"retry", common.PrettyDuration(retry), "err", invalid)
+			timer.Reset(retry)
+			if retry *= 2; retry > gapRetryMax {
+				retry = gapRetryMax
+			}
+		case err != nil && f.ctx.Err() == nil:
+			log.Warn("Failed to follow L1 chain", "err", err)
+			timer.Reset(f.interval)
+		default:
+			retry = gapRetryInterval
+			timer.Reset(f.interval)
+		}
+	}
+}
+
+// followerStatus is the outcome of the latest sync rounds of the follower.
+type followerStatus struct {
+	LastSync   time.Time // Time the chain last caught up with L1, zero if never
+	LastDerive time.Time // Time a block was last derived, zero if never
+	Err        error     // Error the last sync round failed with, nil if it succeeded
+}
+
+// record keeps track of the outcome of a sync round.
+func (f *follower) record(err error) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	if f.lastErr = err; err == nil {
+		f.lastSync = time.Now()
+	}
+}
+
+// status returns the outcome of the latest sync rounds.
+func (f *follower) status() followerStatus {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return followerStatus{LastSync: f.lastSync, LastDerive: f.lastDerive, Err: f.lastErr}
+}
+
+// derived keeps track of the last time the chain progressed.
+func (f *follower) derived() {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	f.lastDerive = time.Now()
+}
+
+// sync inserts the L1 blocks between the current Mive head and the L1 head
+// (minus the confirmations) into the chain, in batches. It returns once the
+// chain caught up with L1.
+func (f *follower) sync() error {
+	head, err := f.client.BlockNumber(f.ctx)
+	if err != nil {
+		return err
+	}
+	l1HeadGauge.Update(int64(head))
+
+	l1Head := head
+	if head < f.confirmations {
+		return nil
+	}
+	head -= f.confirmations
+	for {
+		current := f.chain.CurrentBlock().NumberU64()
+		if current < l1Head {
+			l1LagGauge.Update(int64(l1Head - current))
+		} else {
+			l1LagGauge.Update(0)
+		}
+		if current >= head {
+			return nil
+		}
+		blocks, err := f.fetch(current+1, head)
+		if err != nil {
+			return err
+		}
+
+		// The L1 chain was reorganised below the Mive head, rewind to the last
+		// block still on it and derive the new L1 blocks from there.
+		if blocks[0].ParentHash() != f.chain.CurrentBlock().Hash {
+			if err := f.reorg(); err != nil {
+				return err
+			}
+			continue
+		}
+		bstart := time.Now()
+		if err := f.archiveBlobs(blocks); err != nil {
+			return err
+		}
+		blobsTimer.UpdateSince(bstart)
+
+		n, err := f.chain.InsertChain(blocks)
+		if n > 0 {
+			f.derived()
+		}
+		if err != nil {
+			return err
+		}
+		if f.ctx.Err() != nil {
+			return nil
+		}
+		// No progress was made (e.g. the insertion was interrupted), wait for
+		// the next round instead of fetching the same blocks again.
+		if f.chain.CurrentBlock().NumberU64() == current {
+			return nil
+		}
+	}
+}
+
+// fetch retrieves the next batch of L1 blocks to derive, from the given number
+// up to the given head at most.
+func (f *follower) fetch(from uint64, head uint64) (blocks types.Blocks, err error) {
+	ctx, span := telemetry.StartSpan(f.ctx, "follower.fetch", attribute.Int64("l1.from", int64(from)))
+	defer func() {
+		span.SetAttributes(attribute.Int("l1.blocks", len(blocks)))
+		telemetry.EndSpan(span, err)
+	}()
+	start := time.Now()
+	for number := from; number <= head && len(blocks) < followBatchSize; number++ {
+		block, err := f.client.BlockByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, block)
+	}
+	fetchTimer.UpdateSince(start)
+	return blocks, nil
+}
+
+// reorg rewinds the Mive chain to the highest block still part of the canonical
+// L1 chain, after the L1 chain was reorganised below the Mive head.
+func (f *follower) reorg() error {
+	var (
+		head    = f.chain.CurrentBlock()
+		genesis = f.chain.Genesis().NumberU64()
+	)
+	for number := head.NumberU64(); ; number-- {
+		header, err := f.client.HeaderByNumber(f.ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		}
+		if header.Hash() != f.chain.GetCanonicalHash(number) {
+			if number == genesis {
+				return fmt.Errorf("L1 chain reorganised below the Mive genesis #%d", genesis)
+			}
+			continue
+		}
+		// The Mive head is still canonical, the L1 chain was only reorganised
+		// above it while the batch was being retrieved (or the endpoints serve
+		// inconsistent blocks). Retry in the next round.
+		depth := head.NumberU64() - number
+		if depth == 0 {
+			return fmt.Errorf("L1 block #%d does not build on the Mive head %v", number+1, header.Hash())
+		}
+		reorgDepthHistogram.Update(int64(depth))
+		log.Warn("L1 chain reorganised, rewinding Mive chain", "number", number, "hash", header.Hash(), "depth", depth)
+
+		return f.chain.SetHead(number)
+	}
+}
+
+// archiveBlobs retrieves the blobs of the blob-carrying beacon transactions of
+// the given L1 blocks from the beacon node and archives them locally. Beacon
+// nodes only serve blobs for a limited time (about 18 days), while the blocks
+// may have to be re-executed at any later time.
+func (f *follower) archiveBlobs(blocks types.Blocks) error {
+	config := f.chain.Config()
+	for _, block := range blocks {
+		for _, tx := range block.Transactions() {
+			if !core.IsBlobBeaconTx(tx, block.Number(), config) || miverawdb.HasBlobSidecar(f.db, tx.Hash()) {
+				continue
+			}
+			if f.beacon == nil {
+				return fmt.Errorf("blob-carrying beacon transaction %v in L1 block #%d, configure a beacon node via --mive.beacon", tx.Hash(), block.Number())
+			}
+			sidecar, err := f.beacon.BlobSidecar(f.ctx, block.Header(), tx.BlobHashes())
+			if err != nil {
+				return err
+			}
+			miverawdb.WriteBlobSidecar(f.db, tx.Hash(), sidecar)
+			log.Debug("Archived blobs of beacon transaction", "block", block.Number(), "hash", tx.Hash(), "blobs", len(sidecar.Blobs))
+		}
+	}
+	return nil
+}
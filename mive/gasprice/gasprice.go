@@ -0,0 +1,322 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package gasprice implements the oracle suggesting fees for Mive transactions.
+// All the fees it deals with are in Mive terms, i.e. the fees of the wrapping L1
+// transactions reduced by the fee reduction denominator.
+package gasprice
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+	"golang.org/x/exp/slices"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	miveparams "github.com/ethereum-mive/mive/params"
+)
+
+const sampleNumber = 3 // Number of transactions sampled in a block
+
+var (
+	DefaultPrice       = big.NewInt(params.GWei / miveparams.DefaultFeeReductionDenominator)
+	DefaultMaxPrice    = big.NewInt(500 * params.GWei / miveparams.DefaultFeeReductionDenominator)
+	DefaultIgnorePrice = big.NewInt(2 * params.Wei)
+)
+
+type Config struct {
+	Blocks           int
+	Percentile       int
+	MaxHeaderHistory uint64
+	MaxBlockHistory  uint64
+	Default          *big.Int `toml:",omitempty"`
+	MaxPrice         *big.Int `toml:",omitempty"`
+	IgnorePrice      *big.Int `toml:",omitempty"`
+}
+
+// OracleBackend includes all necessary background APIs for oracle.
+type OracleBackend interface {
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*mivetypes.Header, error)
+	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*mivetypes.Block, error)
+	GetReceipts(ctx context.Context, hash common.Hash) (types.Receipts, error)
+	ChainConfig() *miveparams.ChainConfig
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+
+	// EthHeader retrieves the header of the L1 block the given Mive block was
+	// derived from, which carries its base fee and gas limit.
+	EthHeader(ctx context.Context, header *mivetypes.Header) (*types.Header, error)
+}
+
+// Oracle recommends gas prices based on the content of recent Mive blocks.
+type Oracle struct {
+	backend     OracleBackend
+	lastHead    common.Hash
+	lastPrice   *big.Int
+	maxPrice    *big.Int
+	ignorePrice *big.Int
+	cacheLock   sync.RWMutex
+	fetchLock   sync.Mutex
+
+	checkBlocks, percentile           int
+	maxHeaderHistory, maxBlockHistory uint64
+
+	historyCache *lru.Cache[cacheKey, processedFees]
+}
+
+// NewOracle returns a new gasprice oracle which can recommend suitable
+// gasprice for newly created transaction.
+func NewOracle(backend OracleBackend, params Config) *Oracle {
+	blocks := params.Blocks
+	if blocks < 1 {
+		blocks = 1
+		log.Warn("Sanitizing invalid gasprice oracle sample blocks", "provided", params.Blocks, "updated", blocks)
+	}
+	percent := params.Percentile
+	if percent < 0 {
+		percent = 0
+		log.Warn("Sanitizing invalid gasprice oracle sample percentile", "provided", params.Percentile, "updated", percent)
+	} else if percent > 100 {
+		percent = 100
+		log.Warn("Sanitizing invalid gasprice oracle sample percentile", "provided", params.Percentile, "updated", percent)
+	}
+	defaultPrice := params.Default
+	if defaultPrice == nil || defaultPrice.Sign() < 0 {
+		defaultPrice = DefaultPrice
+		log.Warn("Sanitizing invalid gasprice oracle default price", "provided", params.Default, "updated", defaultPrice)
+	}
+	maxPrice := params.MaxPrice
+	if maxPrice == nil || maxPrice.Int64() <= 0 {
+		maxPrice = DefaultMaxPrice
+		log.Warn("Sanitizing invalid gasprice oracle price cap", "provided", params.MaxPrice, "updated", maxPrice)
+	}
+	ignorePrice := params.IgnorePrice
+	if ignorePrice == nil || ignorePrice.Int64() <= 0 {
+		ignorePrice = DefaultIgnorePrice
+		log.Warn("Sanitizing invalid gasprice oracle ignore price", "provided", params.IgnorePrice, "updated", ignorePrice)
+	} else if ignorePrice.Int64() > 0 {
+		log.Info("Gasprice oracle is ignoring threshold set", "threshold", ignorePrice)
+	}
+	maxHeaderHistory := params.MaxHeaderHistory
+	if maxHeaderHistory < 1 {
+		maxHeaderHistory = 1
+		log.Warn("Sanitizing invalid gasprice oracle max header history", "provided", params.MaxHeaderHistory, "updated", maxHeaderHistory)
+	}
+	maxBlockHistory := params.MaxBlockHistory
+	if maxBlockHistory < 1 {
+		maxBlockHistory = 1
+		log.Warn("Sanitizing invalid gasprice oracle max block history", "provided", params.MaxBlockHistory, "updated", maxBlockHistory)
+	}
+
+	cache := lru.NewCache[cacheKey, processedFees](2048)
+	headEvent := make(chan core.ChainHeadEvent, 1)
+	backend.SubscribeChainHeadEvent(headEvent)
+	go func() {
+		var lastHead common.Hash
+		for ev := range headEvent {
+			if ev.Block.ParentHash() != lastHead {
+				cache.Purge()
+			}
+			lastHead = ev.Block.Hash()
+		}
+	}()
+
+	return &Oracle{
+		backend:          backend,
+		lastPrice:        defaultPrice,
+		maxPrice:         maxPrice,
+		ignorePrice:      ignorePrice,
+		checkBlocks:      blocks,
+		percentile:       percent,
+		maxHeaderHistory: maxHeaderHistory,
+		maxBlockHistory:  maxBlockHistory,
+		historyCache:     cache,
+	}
+}
+
+// SuggestTipCap returns a tip cap so that newly created transaction can have a
+// very high chance to be included in the following blocks.
+//
+// Note, for legacy transactions and the legacy eth_gasPrice RPC call, it will be
+// necessary to add the basefee to the returned number to fall back to the legacy
+// behavior.
+func (oracle *Oracle) SuggestTipCap(ctx context.Context) (*big.Int, error) {
+	head, _ := oracle.backend.HeaderByNumber(ctx, rpc.LatestBlockNumber)
+	headHash := head.Hash
+
+	// If the latest gasprice is still available, return it.
+	oracle.cacheLock.RLock()
+	lastHead, lastPrice := oracle.lastHead, oracle.lastPrice
+	oracle.cacheLock.RUnlock()
+	if headHash == lastHead {
+		return new(big.Int).Set(lastPrice), nil
+	}
+	oracle.fetchLock.Lock()
+	defer oracle.fetchLock.Unlock()
+
+	// Try checking the cache again, maybe the last fetch fetched what we need
+	oracle.cacheLock.RLock()
+	lastHead, lastPrice = oracle.lastHead, oracle.lastPrice
+	oracle.cacheLock.RUnlock()
+	if headHash == lastHead {
+		return new(big.Int).Set(lastPrice), nil
+	}
+	var (
+		sent, exp int
+		number    = head.Number.Uint64()
+		genesis   = oracle.backend.ChainConfig().Mive.GenesisBlock.Uint64()
+		result    = make(chan results, oracle.checkBlocks)
+		quit      = make(chan struct{})
+		results   []*big.Int
+	)
+	for sent < oracle.checkBlocks && number > genesis {
+		go oracle.getBlockValues(ctx, number, sampleNumber, oracle.ignorePrice, result, quit)
+		sent++
+		exp++
+		number--
+	}
+	for exp > 0 {
+		res := <-result
+		if res.err != nil {
+			close(quit)
+			return new(big.Int).Set(lastPrice), res.err
+		}
+		exp--
+		// Nothing returned, the block is empty. In this case, use the latest
+		// calculated price for sampling.
+		if len(res.values) == 0 {
+			res.values = []*big.Int{lastPrice}
+		}
+		// Besides, in order to collect enough data for sampling, if nothing
+		// meaningful returned, try to query more blocks. But the maximum
+		// is 2*checkBlocks.
+		if len(res.values) == 1 && len(results)+1+exp < oracle.checkBlocks*2 && number > genesis {
+			go oracle.getBlockValues(ctx, number, sampleNumber, oracle.ignorePrice, result, quit)
+			sent++
+			exp++
+			number--
+		}
+		results = append(results, res.values...)
+	}
+	price := lastPrice
+	if len(results) > 0 {
+		slices.SortFunc(results, func(a, b *big.Int) int { return a.Cmp(b) })
+		price = results[(len(results)-1)*oracle.percentile/100]
+	}
+	if price.Cmp(oracle.maxPrice) > 0 {
+		price = new(big.Int).Set(oracle.maxPrice)
+	}
+	oracle.cacheLock.Lock()
+	oracle.lastHead = headHash
+	oracle.lastPrice = price
+	oracle.cacheLock.Unlock()
+
+	return new(big.Int).Set(price), nil
+}
+
+type results struct {
+	values []*big.Int
+	err    error
+}
+
+// getBlockValues calculates the lowest transaction gas price in a given block
+// and sends it to the result channel. If the block is empty or missing from the
+// chain, nil gasprice is returned.
+func (oracle *Oracle) getBlockValues(ctx context.Context, blockNum uint64, limit int, ignoreUnder *big.Int, result chan results, quit chan struct{}) {
+	block, err := oracle.backend.BlockByNumber(ctx, rpc.BlockNumber(blockNum))
+	if block == nil || len(block.Transactions()) == 0 {
+		select {
+		case result <- results{nil, err}:
+		case <-quit:
+		}
+		return
+	}
+	baseFee, _, _, err := oracle.l1Fees(ctx, block.Header())
+	if err != nil {
+		select {
+		case result <- results{nil, err}:
+		case <-quit:
+		}
+		return
+	}
+
+	// Sort the transaction by effective tip in ascending sort.
+	txs := block.Transactions()
+	sortedTxs := make([]*mivetypes.Transaction, len(txs))
+	copy(sortedTxs, txs)
+	slices.SortFunc(sortedTxs, func(a, b *mivetypes.Transaction) int {
+		return a.EffectiveGasTip(baseFee).Cmp(b.EffectiveGasTip(baseFee))
+	})
+
+	var prices []*big.Int
+	for _, tx := range sortedTxs {
+		tip := tx.EffectiveGasTip(baseFee)
+		if ignoreUnder != nil && tip.Cmp(ignoreUnder) == -1 {
+			continue
+		}
+		prices = append(prices, tip)
+		if len(prices) >= limit {
+			break
+		}
+	}
+	select {
+	case result <- results{prices, nil}:
+	case <-quit:
+	}
+}
+
+// l1Fees returns the base fee of the given Mive block and of the next one,
+// along with its gas limit. They all derive from the L1 block with the same
+// hash, the fees being reduced by the fee reduction denominator, unless the
+// blocks have a Mive base fee. The next one is then estimated assuming the
+// next L1 block keeps the same gas limit.
+func (oracle *Oracle) l1Fees(ctx context.Context, header *mivetypes.Header) (*big.Int, *big.Int, uint64, error) {
+	l1Header, err := oracle.backend.EthHeader(ctx, header)
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	var (
+		config            = oracle.backend.ChainConfig()
+		feeReductionDenom = new(big.Int).SetUint64(config.FeeReductionDenominator())
+		baseFee           = new(big.Int)
+		nextBaseFee       = new(big.Int)
+		next              = new(big.Int).Add(l1Header.Number, common.Big1)
+	)
+	if header.BaseFee != nil {
+		baseFee.Set(header.BaseFee)
+	} else if l1Header.BaseFee != nil {
+		baseFee.Div(l1Header.BaseFee, feeReductionDenom)
+	}
+	if config.IsMiveBaseFee(next) && header.BaseFee != nil {
+		nextHeader := &types.Header{Number: next, GasLimit: l1Header.GasLimit}
+		if nextBaseFee, err = mivecore.CalcBaseFee(config, header, nextHeader); err != nil {
+			return nil, nil, 0, err
+		}
+	} else if config.Eth.IsLondon(next) {
+		nextBaseFee.Div(eip1559.CalcBaseFee(config.Eth, l1Header), feeReductionDenom)
+	}
+	return baseFee, nextBaseFee, mivecore.BlockGasLimit(l1Header.GasLimit, config), nil
+}
@@ -1,28 +1,230 @@
 package mive
 
 import (
-	"github.com/ethereum/go-ethereum/ethclient"
-	"github.com/ethereum/go-ethereum/ethdb"
+	"math"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	"github.com/ethereum-mive/mive/core/forkid"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/mive/protocols/mive"
 )
 
 // handlerConfig is the collection of initialization parameters to create a full
 // node network handler.
 type handlerConfig struct {
+	Chain    *mivecore.BlockChain // Mive chain to serve data from and sync into
+	MaxPeers int                  // Maximum number of peers to accept
+	Resync   func()               // Wakes the derivation up to catch up with the peers
 }
 
+// handler is the network handler of the `mive` protocol: it serves the Mive
+// chain to the peers, and syncs the local chain with the peers ahead of it.
 type handler struct {
-	ethClient *ethclient.Client
+	chain      *mivecore.BlockChain
+	forkFilter forkid.Filter // Fork ID filter, constant across the lifetime of the node
+	maxPeers   int
+	resync     func()
 
-	database ethdb.Database
+	peers  *peerSet
+	syncer *chainSyncer
+
+	quitSync chan struct{}
+	closed   bool       // Whether the handler stopped accepting peers
+	lock     sync.Mutex // Protects the closed flag against the peer handlers starting
+	wg       sync.WaitGroup
 }
 
 // newHandler returns a handler for all Mive chain management protocol.
 func newHandler(config *handlerConfig) (*handler, error) {
-	return &handler{}, nil
+	h := &handler{
+		chain:      config.Chain,
+		forkFilter: forkid.NewFilter(config.Chain),
+		maxPeers:   config.MaxPeers,
+		resync:     config.Resync,
+		peers:      newPeerSet(),
+		quitSync:   make(chan struct{}),
+	}
+	h.syncer = newChainSyncer(h)
+	return h, nil
 }
 
+// Start starts the syncing of the local chain from the peers, and the
+// broadcasting of the new head blocks to them.
 func (h *handler) Start() {
+	h.wg.Add(2)
+	go h.syncer.loop()
+	go h.headBroadcastLoop()
 }
 
+// Stop terminates the syncing and disconnects the peers.
 func (h *handler) Stop() {
+	// Quit the syncer, after this is done no new peers will be accepted
+	h.lock.Lock()
+	h.closed = true
+	close(h.quitSync)
+	h.lock.Unlock()
+
+	// Disconnect existing sessions. This also closes the gate for any new
+	// registrations on the peer set.
+	h.peers.close()
+	h.wg.Wait()
+
+	log.Info("Mive protocol stopped")
+}
+
+// Chain implements mive.Backend, retrieving the Mive chain to serve data from.
+func (h *handler) Chain() *mivecore.BlockChain {
+	return h.chain
+}
+
+// RunPeer implements mive.Backend, registering the peer once the handshake
+// passed, and handling its messages until it disconnects.
+func (h *handler) RunPeer(peer *mive.Peer, hand mive.Handler) error {
+	if !h.incHandlers() {
+		return p2p.DiscQuitting
+	}
+	defer h.wg.Done()
+
+	// Execute the Mive handshake
+	var (
+		genesis = h.chain.Genesis()
+		head    = h.chain.CurrentHeader()
+		config  = h.chain.Config()
+		forkID  = forkid.NewID(config, genesis, head.NumberU64(), head.Time)
+	)
+	if err := peer.Handshake(config.ChainID().Uint64(), head.Hash, head.NumberU64(), genesis, config.Mive.BeaconAddress, forkID, h.forkFilter); err != nil {
+		peer.Log().Debug("Mive handshake failed", "err", err)
+		return err
+	}
+	// Ignore maxPeers if this is a trusted peer
+	if !peer.Peer.Info().Network.Trusted && h.peers.len() >= h.maxPeers {
+		return p2p.DiscTooManyPeers
+	}
+	peer.Log().Debug("Mive peer connected", "name", peer.Name())
+
+	// Register the peer locally
+	if err := h.peers.register(peer); err != nil {
+		peer.Log().Error("Mive peer registration failed", "err", err)
+		return err
+	}
+	defer h.peers.unregister(peer.ID())
+
+	// Check whether the new peer is worth syncing from
+	h.syncer.handlePeerEvent()
+
+	return hand(peer)
+}
+
+// BroadcastBlock will either propagate a block to a subset of its peers, or
+// will only announce its availability (depending what's requested). Only the
+// peers behind the block are notified.
+func (h *handler) BroadcastBlock(block *mivetypes.Block, propagate bool) {
+	var (
+		hash  = block.Hash()
+		peers []*mive.Peer
+	)
+	for _, peer := range h.peers.peersWithoutBlock(hash) {
+		if _, number := peer.Head(); number < block.NumberU64() {
+			peers = append(peers, peer)
+		}
+	}
+	// If propagation is requested, send to a subset of the peer
+	if propagate {
+		var (
+			receipts   = h.chain.GetReceiptsByHash(hash)
+			rejections = h.chain.GetRejections(hash)
+			transfer   = peers[:int(math.Sqrt(float64(len(peers))))]
+		)
+		for _, peer := range transfer {
+			peer.AsyncSendNewBlock(block, rejections, receipts)
+		}
+		log.Trace("Propagated block", "hash", hash, "recipients", len(transfer))
+		peers = peers[len(transfer):]
+	}
+	// Otherwise if the block is indeed in our own chain, announce it
+	for _, peer := range peers {
+		peer.AsyncSendNewBlockHash(block.Header())
+	}
+	log.Trace("Announced block", "hash", hash, "recipients", len(peers))
+}
+
+// headBroadcastLoop propagates the new head blocks of the local chain to the
+// peers behind them.
+func (h *handler) headBroadcastLoop() {
+	defer h.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, 10)
+	sub := h.chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-headCh:
+			if block := h.chain.GetMiveBlockByHash(ev.Block.Hash()); block != nil {
+				h.BroadcastBlock(block, true)
+			}
+		case <-sub.Err():
+			return
+		case <-h.quitSync:
+			return
+		}
+	}
+}
+
+// incHandlers tracks a new peer handler, unless the handler is stopping.
+func (h *handler) incHandlers() bool {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+
+	if h.closed {
+		return false
+	}
+	h.wg.Add(1)
+	return true
+}
+
+// mivePeerInfo represents a short summary of the `mive` sub-protocol metadata
+// known about a connected peer.
+type mivePeerInfo struct {
+	Version uint        `json:"version"` // Mive protocol version negotiated
+	Head    common.Hash `json:"head"`    // Hash of the peer's best owned block
+	Number  uint64      `json:"number"`  // Number of the peer's best owned block
+
+	ServedHeaders  uint64 `json:"servedHeaders"`  // Number of headers served to the peer
+	ServedBodies   uint64 `json:"servedBodies"`   // Number of block bodies served to the peer
+	ServedReceipts uint64 `json:"servedReceipts"` // Number of block receipts served to the peer
+	Syncing        bool   `json:"syncing"`        // Whether the local chain is being synced from the peer
+	Imported       uint64 `json:"imported"`       // Number of blocks of the peer confirmed by the local chain
+	Penalty        int32  `json:"penalty"`        // Accumulated penalty of the peer's misbehaviour
+}
+
+// PeerInfo implements mive.Backend, retrieving all known `mive` information
+// about a peer.
+func (h *handler) PeerInfo(id enode.ID) interface{} {
+	p := h.peers.peer(id.String())
+	if p == nil {
+		return nil
+	}
+	var (
+		hash, number              = p.Head()
+		headers, bodies, receipts = p.Served()
+	)
+	return &mivePeerInfo{
+		Version:        p.Version(),
+		Head:           hash,
+		Number:         number,
+		ServedHeaders:  headers,
+		ServedBodies:   bodies,
+		ServedReceipts: receipts,
+		Syncing:        h.syncer.syncPeer() == p,
+		Imported:       p.Imported(),
+		Penalty:        p.Penalty(),
+	}
 }
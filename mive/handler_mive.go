@@ -0,0 +1,118 @@
+package mive
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+
+	miveconsensus "github.com/ethereum-mive/mive/consensus"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/mive/protocols/mive"
+)
+
+const (
+	// maxPeerPenalty is the accumulated penalty a peer is dropped at.
+	maxPeerPenalty = 100
+
+	// conflictPenalty is the penalty of a peer propagating a block conflicting
+	// with the local L1 view. The L1 views of two honest nodes may disagree for
+	// a while during an L1 reorg, so a peer isn't dropped right away.
+	conflictPenalty = 50
+
+	// invalidBlockPenalty is the penalty of a peer serving a block which is
+	// derived from the canonical L1 chain, but differs from the derived one.
+	invalidBlockPenalty = maxPeerPenalty
+)
+
+// Handle implements mive.Backend, handling the block announcements and
+// propagations of the peers.
+func (h *handler) Handle(peer *mive.Peer, packet mive.Packet) error {
+	switch packet := packet.(type) {
+	case *mive.NewBlockHashesPacket:
+		hashes, numbers := packet.Unpack()
+		return h.handleBlockAnnounces(peer, hashes, numbers)
+
+	case *mive.NewBlockPacket:
+		return h.handleBlockBroadcast(peer, packet)
+
+	default:
+		return fmt.Errorf("unexpected mive packet type: %T", packet)
+	}
+}
+
+// handleBlockAnnounces is invoked from a peer's message handler when it
+// announces the availability of new blocks. The blocks aren't fetched directly,
+// the sync retrieves them from the best peer, verifying them against L1.
+func (h *handler) handleBlockAnnounces(peer *mive.Peer, hashes []common.Hash, numbers []uint64) error {
+	for i, hash := range hashes {
+		if _, number := peer.Head(); numbers[i] > number {
+			peer.SetHead(hash, numbers[i])
+		}
+	}
+	h.syncer.handlePeerEvent()
+	return nil
+}
+
+// handleBlockBroadcast is invoked from a peer's message handler when it
+// propagates a new block. The block is only checked to be derived from the
+// canonical L1 chain as seen by the local node, its contents can't be trusted
+// but by deriving it: the header is kept pending until the local chain reaches
+// it, see chainSyncer. Peers propagating blocks conflicting with L1 or with the
+// derived ones are penalized, and eventually dropped.
+func (h *handler) handleBlockBroadcast(peer *mive.Peer, packet *mive.NewBlockPacket) error {
+	var (
+		block  = packet.Block
+		hash   = block.Hash()
+		number = block.NumberU64()
+	)
+	// Ensure the block is derived from the canonical L1 chain
+	if h.chain.GetCanonicalHash(number) != hash {
+		if err := h.chain.Engine().VerifyHeader(h.chain, block.Header()); err != nil {
+			switch {
+			case errors.Is(err, miveconsensus.ErrUnknownOrigin), errors.Is(err, miveconsensus.ErrUnfinalizedOrigin):
+				// The local L1 view can't tell yet whether the block is valid
+				peer.Log().Debug("Unverifiable Mive block propagated", "number", number, "hash", hash, "err", err)
+				return nil
+
+			case errors.Is(err, consensus.ErrUnknownAncestor):
+				// The block is derived from L1, but the local chain lags behind it
+				h.updatePeerHead(peer, block.Header())
+				h.syncer.handlePeerEvent()
+				return nil
+
+			default:
+				return h.penalize(peer, conflictPenalty, fmt.Errorf("block #%d [%x..] conflicts with L1: %w", number, hash.Bytes()[:4], err))
+			}
+		}
+	}
+	h.updatePeerHead(peer, block.Header())
+
+	// Check the block once derived locally, deriving it right away if ahead
+	h.syncer.addPending(peer, block.Header())
+	if number > h.chain.CurrentHeader().NumberU64() {
+		h.resync()
+	}
+	return nil
+}
+
+// updatePeerHead moves the head of the peer to the given header if it's ahead
+// of the known one.
+func (h *handler) updatePeerHead(peer *mive.Peer, header *mivetypes.Header) {
+	if _, number := peer.Head(); header.NumberU64() > number {
+		peer.SetHead(header.Hash, header.NumberU64())
+	}
+}
+
+// penalize penalizes a misbehaving peer, returning the reason as an error to
+// drop the peer once its accumulated penalty reaches maxPeerPenalty.
+func (h *handler) penalize(peer *mive.Peer, points int32, reason error) error {
+	penalty := peer.Penalize(points)
+	if penalty >= maxPeerPenalty {
+		peer.Log().Debug("Dropping misbehaving Mive peer", "penalty", penalty, "reason", reason)
+		return reason
+	}
+	peer.Log().Debug("Penalized misbehaving Mive peer", "penalty", penalty, "reason", reason)
+	return nil
+}
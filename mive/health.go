@@ -0,0 +1,125 @@
+package mive
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/log"
+
+	miverawdb "github.com/ethereum-mive/mive/core/rawdb"
+)
+
+// Health summarizes the ability of the node to derive and serve the Mive chain,
+// for liveness and readiness probes and alerting.
+type Health struct {
+	// Healthy reports whether the node is live: an L1 endpoint is reachable
+	// and the database is writable. Ready additionally requires the Mive head
+	// to keep up with L1.
+	Healthy bool `json:"healthy"`
+	Ready   bool `json:"ready"`
+
+	L1Connected bool           `json:"l1Connected"` // Whether any L1 endpoint is healthy
+	L1Head      hexutil.Uint64 `json:"l1Head"`      // Highest L1 block reported by the endpoints
+	Head        hexutil.Uint64 `json:"head"`        // Number of the Mive head
+	HeadLag     hexutil.Uint64 `json:"headLag"`     // Number of L1 blocks not derived yet
+	HeadAge     hexutil.Uint64 `json:"headAge"`     // Seconds elapsed since the timestamp of the Mive head
+
+	// LastDerive is the time a block was last derived, omitted if none was
+	// derived since the node was started.
+	LastDerive *hexutil.Uint64 `json:"lastDerive,omitempty"`
+
+	DatabaseWritable bool           `json:"databaseWritable"` // Always false in read-only mode
+	UncleanShutdowns hexutil.Uint64 `json:"uncleanShutdowns"` // Number of unclean shutdowns found on startup
+
+	// LastUncleanShutdown is the time of the latest unclean shutdown found on
+	// startup, omitted if there was none (or it's no longer recorded).
+	LastUncleanShutdown *hexutil.Uint64 `json:"lastUncleanShutdown,omitempty"`
+
+	// Errors lists the reasons the node is not healthy or not ready.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// health checks the ability of the node to derive and serve the Mive chain.
+// Unless the node is read-only, the writability of the database is probed by
+// an actual write.
+func (s *Mive) health() *Health {
+	var (
+		now    = time.Now()
+		head   = s.blockchain.CurrentBlock()
+		health = &Health{Head: hexutil.Uint64(head.NumberU64())}
+		errs   []string
+	)
+	// Check the connection to L1, an endpoint which never answered has no head
+	for _, endpoint := range s.ethClient.Endpoints() {
+		if endpoint.Healthy && endpoint.Head != 0 {
+			health.L1Connected = true
+		}
+		if endpoint.Head > uint64(health.L1Head) {
+			health.L1Head = hexutil.Uint64(endpoint.Head)
+		}
+	}
+	if !health.L1Connected {
+		errs = append(errs, "no healthy L1 endpoint")
+	}
+	if uint64(health.L1Head) > head.NumberU64() {
+		health.HeadLag = health.L1Head - health.Head
+	}
+	if uint64(now.Unix()) > head.Time {
+		health.HeadAge = hexutil.Uint64(uint64(now.Unix()) - head.Time)
+	}
+	if status := s.follower.status(); !status.LastDerive.IsZero() {
+		lastDerive := hexutil.Uint64(status.LastDerive.Unix())
+		health.LastDerive = &lastDerive
+	}
+	// Check the database, a read-only node is expected not to write
+	if !s.config.ReadOnly {
+		if err := miverawdb.WriteHealthProbe(s.chainDb, uint64(now.Unix())); err != nil {
+			errs = append(errs, fmt.Sprintf("database not writable: %v", err))
+		} else {
+			health.DatabaseWritable = true
+		}
+	}
+	if s.shutdownTracker != nil {
+		unclean, discarded := s.shutdownTracker.UncleanShutdowns()
+		health.UncleanShutdowns = hexutil.Uint64(uint64(len(unclean)) + discarded)
+		if len(unclean) > 0 {
+			last := hexutil.Uint64(unclean[len(unclean)-1])
+			health.LastUncleanShutdown = &last
+		}
+	}
+	health.Healthy = len(errs) == 0
+
+	// Check the derivation keeps up with L1, a read-only node can't catch up
+	if maxLag := s.config.Confirmations + s.config.HealthMaxLag; !s.config.ReadOnly && uint64(health.HeadLag) > maxLag {
+		errs = append(errs, fmt.Sprintf("head lags %d blocks behind L1 (max %d)", health.HeadLag, maxLag))
+	}
+	health.Ready = len(errs) == 0
+	health.Errors = errs
+
+	return health
+}
+
+// healthHandler serves the health of the node over plain HTTP, responding with
+// 200 if the given condition holds and 503 otherwise, along with the JSON
+// encoded health.
+type healthHandler struct {
+	mive  *Mive
+	check func(*Health) bool
+}
+
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	health := h.mive.health()
+
+	w.Header().Set("Content-Type", "application/json")
+	if h.check(health) {
+		w.WriteHeader(http.StatusOK)
+	} else {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	if err := json.NewEncoder(w).Encode(health); err != nil {
+		log.Debug("Failed to write health response", "err", err)
+	}
+}
@@ -1,19 +1,234 @@
 package miveconfig
 
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+
+	"github.com/ethereum-mive/mive/core"
+	"github.com/ethereum-mive/mive/mive/gasprice"
+	"github.com/ethereum-mive/mive/mive/txpool"
+)
+
+// FullNodeGPO contains default gasprice oracle settings for full node.
+var FullNodeGPO = gasprice.Config{
+	Blocks:           20,
+	Percentile:       60,
+	MaxHeaderHistory: 1024,
+	MaxBlockHistory:  1024,
+	Default:          gasprice.DefaultPrice,
+	MaxPrice:         gasprice.DefaultMaxPrice,
+	IgnorePrice:      gasprice.DefaultIgnorePrice,
+}
+
+// Defaults contains default settings for use on the Mive main net.
+var Defaults = Config{
+	DatabaseCache:      512,
+	SnapshotCache:      102,
+	TrieTimeout:        5 * time.Minute,
+	MinFreeDiskSpace:   512,
+	StateHistory:       params.FullImmutabilityThreshold,
+	TxLookupLimit:      2350000,
+	SyncMode:           "full",
+	BloomBitsBlocks:    params.BloomBitsBlocks,
+	FilterLogCacheSize: 32,
+	GPO:                FullNodeGPO,
+	TxPool:             txpool.DefaultConfig,
+	RPCGasCap:          50000000,
+	RPCEVMTimeout:      5 * time.Second,
+	RPCReexec:          128,
+	RPCTxFeeCap:        1, // 1 ether
+
+	RelayerFeeCap:       big.NewInt(500 * params.GWei),
+	RelayerBumpInterval: time.Minute,
+	ProposerInterval:    1800,
+
+	HealthMaxLag: 8,
+}
+
 // Config contains configuration options for the Mive protocol.
 type Config struct {
-	EthRpcUrl string
+	// The genesis block, which is inserted if the database is empty.
+	// If nil, the Mive main net block is used.
+	Genesis *core.Genesis `toml:",omitempty"`
+
+	// Optional overrides of the L1 block the Mive chain starts at and of the
+	// initial beacon address of the genesis, to follow a custom Mive
+	// deployment. They can't be changed once the chain is initialized.
+	GenesisBlock  *uint64         `toml:",omitempty"`
+	BeaconAddress *common.Address `toml:",omitempty"`
+
+	// L1 endpoints the Mive chain is derived from. Requests fail over between
+	// them according to EthRpcPolicy ('failover', 'score' or 'roundrobin').
+	EthRpcUrls   []string
+	EthRpcPolicy string `toml:",omitempty"`
+
+	// Optional L1 archive endpoint. It is only used to retrieve historical
+	// blocks that the regular endpoints can no longer serve, e.g. because they
+	// prune their chain history.
+	EthArchiveRpcUrl string `toml:",omitempty"`
+
+	// Maximum number of requests per second sent to each L1 endpoint (0 means
+	// unlimited) and number of times a request failing with a transient error
+	// (timeouts, rate limiting, overload) is retried before giving up.
+	EthRpcRateLimit  float64 `toml:",omitempty"`
+	EthRpcMaxRetries int     `toml:",omitempty"`
+
+	// Number of L1 blocks the derivation stays behind the L1 head, so that the
+	// Mive chain is rarely rewound by shallow L1 reorgs. 0 derives the L1 head.
+	Confirmations uint64 `toml:",omitempty"`
+
+	// Interval between the checks for new L1 blocks to derive, 0 for the L1
+	// slot time. Shorter intervals suit chains sealing blocks on demand.
+	FollowInterval time.Duration `toml:",omitempty"`
+
+	// Optional beacon node (L1 consensus client) REST endpoint, needed to
+	// retrieve the blobs of the blob-carrying beacon transactions. The blobs
+	// are archived locally, as beacon nodes only serve them for a limited time.
+	BeaconRpcUrl string `toml:",omitempty"`
+
+	// Optional URL the L1 blocks the derivation fails on are posted to as JSON,
+	// in the format returned by debug_getBadBlocks.
+	BadBlockReportURL string `toml:",omitempty"`
+
+	// Sync mode of the node: 'full' derives the chain from its genesis, while
+	// 'checkpoint' first bootstraps it from the state snapshot archive of a
+	// trusted checkpoint.
+	SyncMode string
+
+	// Trusted checkpoint to bootstrap the chain from in checkpoint sync mode,
+	// given as a block number and state root pair or retrieved as JSON from
+	// CheckpointURL. CheckpointArchive is the file path or HTTP URL of the state
+	// snapshot archive taken at the checkpoint.
+	CheckpointNumber  uint64      `toml:",omitempty"`
+	CheckpointRoot    common.Hash `toml:",omitempty"`
+	CheckpointURL     string      `toml:",omitempty"`
+	CheckpointArchive string      `toml:",omitempty"`
 
 	// State scheme represents the scheme used to store ethereum states and trie
 	// nodes on top. It can be 'hash', 'path', or none which means use the scheme
 	// consistent with persistent state.
 	StateScheme string `toml:",omitempty"`
 
+	// The maximum number of blocks from head whose state histories are reserved,
+	// only relevant in path scheme. 0 means the entire chain.
+	StateHistory uint64 `toml:",omitempty"`
+
+	// The maximum number of blocks from head whose transaction indices are
+	// reserved. 0 means the entire chain.
+	TxLookupLimit uint64 `toml:",omitempty"`
+
+	// Whether to disable pruning and flush the state of every block to disk,
+	// only supported in hash scheme.
+	NoPruning bool
+
+	// Whether to store the preimages of trie keys, always enabled in archive
+	// mode.
+	Preimages bool
+
 	// Database options
 	DatabaseHandles int `toml:"-"`
 	DatabaseCache   int
 	DatabaseFreezer string
 
+	// Whether the chain database is opened without write access, e.g. on query
+	// replicas serving a copy of the data directory of a primary node. The
+	// chain is then neither derived nor synced, only the stored data is served.
+	ReadOnly bool `toml:",omitempty"`
+
+	// Free disk space (MB) of the data directory below which the node shuts
+	// down gracefully, before failing writes corrupt the database. 0 disables
+	// the monitoring.
+	MinFreeDiskSpace uint64
+
+	// Memory allowance (MB) for caching snapshot entries, 0 disables the
+	// state snapshot.
+	SnapshotCache int
+
+	// Block processing time after which the in-memory tries are flushed to
+	// disk, only relevant in hash scheme. Longer intervals save writes at the
+	// cost of more blocks to re-derive after a crash.
+	TrieTimeout time.Duration
+
+	// Number of blocks per section of the bloom bits index used to filter
+	// logs. Changing it regenerates the index.
+	BloomBitsBlocks uint64 `toml:",omitempty"`
+
+	// This is the number of blocks for which logs will be cached in the filter system.
+	FilterLogCacheSize int
+
+	// Gas Price Oracle options
+	GPO gasprice.Config
+
+	// Whether to observe the pending beacon transactions of the L1 mempool and
+	// serve them as pending Mive transactions, and the options of the pool.
+	ObserveTxPool bool
+	TxPool        txpool.Config
+
+	// Optional account wrapping the Mive transactions submitted over RPC into
+	// beacon transactions. The relayed transactions are executed as it. The
+	// ones pending for longer than RelayerBumpInterval are replaced with better
+	// priced ones, up to an L1 fee cap per gas of RelayerFeeCap.
+	Relayer             common.Address `toml:",omitempty"`
+	RelayerFeeCap       *big.Int
+	RelayerBumpInterval time.Duration
+
+	// Optional L1 contract the output roots of the Mive blocks are proposed to,
+	// every ProposerInterval blocks, from the relayer account.
+	Proposer         common.Address `toml:",omitempty"`
+	ProposerInterval uint64
+
+	// Whether the Mive chain is derived by an external driver pushing the L1
+	// blocks and the head, safe and finalized markers through the
+	// authenticated engine API, instead of by following L1 directly.
+	ExternalDriver bool
+
+	// Hashes of Mive blocks known to be invalid, rejected on import and rewound
+	// if already in the canonical chain, in addition to the ones shipped with
+	// the release.
+	BadHashes []common.Hash `toml:",omitempty"`
+
+	// Whether the /healthz and /readyz probes are served on the HTTP RPC server,
+	// in addition to mive_health. The node is reported ready while the Mive head
+	// lags at most HealthMaxLag blocks behind the confirmed L1 head.
+	HealthEndpoints bool   `toml:",omitempty"`
+	HealthMaxLag    uint64 `toml:",omitempty"`
+
+	// DNS discovery lists (enrtree:// URLs) the `mive` peers are found from, in
+	// addition to the discv5 nodes advertising the Mive chain.
+	DiscoveryURLs []string
+
 	// Enables tracking of SHA3 preimages in the VM
 	EnablePreimageRecording bool
+
+	// RPCGasCap is the global gas cap for eth-call variants.
+	RPCGasCap uint64
+
+	// RPCEVMTimeout is the global timeout for eth-call.
+	RPCEVMTimeout time.Duration
+
+	// RPCReexec is the default number of blocks re-executed to regenerate a
+	// historical state missing from the database, e.g. for tracing.
+	RPCReexec uint64
+
+	// RPCTxFeeCap is the global transaction fee(price * gaslimit) cap for
+	// send-transaction variants. The unit is ether.
+	RPCTxFeeCap float64
+
+	// RPCLogsRangeLimit is the maximum number of blocks a single eth_getLogs
+	// query may span, 0 means unlimited.
+	RPCLogsRangeLimit uint64 `toml:",omitempty"`
+
+	// OverrideCancun (TODO: remove after the fork)
+	OverrideCancun *uint64 `toml:",omitempty"`
+
+	// OverrideVerkle (TODO: remove after the fork)
+	OverrideVerkle *uint64 `toml:",omitempty"`
+
+	// OverrideMiveM1 and OverrideMiveM2 override the activation block of the
+	// M1 upgrade and the activation timestamp of the M2 upgrade.
+	OverrideMiveM1 *uint64 `toml:",omitempty"`
+	OverrideMiveM2 *uint64 `toml:",omitempty"`
 }
@@ -0,0 +1,124 @@
+package mive
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/log"
+
+	"github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+const (
+	proposerCheckInterval = 12 * time.Second // Interval between two checks for a block to propose
+	proposerSubmitTimeout = 30 * time.Second // Timeout of the submission of a proposal
+)
+
+// proposeOutputSelector is the selector of the function of the L1 contract the
+// output roots are proposed to:
+//
+//	function proposeOutput(bytes32 outputRoot, uint256 blockNumber, bytes32 blockHash)
+var proposeOutputSelector = crypto.Keccak256([]byte("proposeOutput(bytes32,uint256,bytes32)"))[:4]
+
+// proposer periodically proposes the output roots of the finalized Mive blocks
+// to an L1 contract, every given number of blocks, through the relayer so that
+// the L1 nonces of the shared account are serialized.
+//
+// The last proposed block isn't persisted, so the latest due output may be
+// proposed again after a restart. The contract is expected to ignore those.
+type proposer struct {
+	chain    *core.BlockChain
+	relayer  *relayer
+	contract common.Address
+	interval uint64 // Number of Mive blocks between two proposed outputs
+	last     uint64 // Number of the last proposed block, zero if none
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newProposer creates a proposer of the output roots of the given chain to the
+// given L1 contract, sending them from the relayer account.
+func newProposer(chain *core.BlockChain, relayer *relayer, contract common.Address, interval uint64) *proposer {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &proposer{
+		chain:    chain,
+		relayer:  relayer,
+		contract: contract,
+		interval: interval,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+}
+
+// start launches the proposing of the output roots.
+func (p *proposer) start() {
+	p.wg.Add(1)
+	go p.loop()
+}
+
+// stop terminates the proposing of the output roots.
+func (p *proposer) stop() {
+	p.cancel()
+	p.wg.Wait()
+}
+
+// loop periodically proposes the latest due output root.
+func (p *proposer) loop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(proposerCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.propose()
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
+
+// propose submits the output root of the latest finalized block at a multiple
+// of the interval, unless it was already proposed.
+func (p *proposer) propose() {
+	final := p.chain.CurrentFinalBlock()
+	if final == nil {
+		return
+	}
+	number := final.Number.Uint64() / p.interval * p.interval
+	if number <= p.last || number <= p.chain.Genesis().Number.Uint64() {
+		return
+	}
+	header := p.chain.GetHeaderByNumber(number)
+	if header == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(p.ctx, proposerSubmitTimeout)
+	defer cancel()
+
+	output := mivetypes.OutputRoot(header)
+	handle, err := p.relayer.submit(ctx, p.contract, proposeOutputData(output, header))
+	if err != nil {
+		log.Warn("Failed to propose output root", "number", number, "output", output, "err", err)
+		return
+	}
+	p.last = number
+	log.Info("Proposed output root", "number", number, "hash", header.Hash, "output", output, "l1hash", handle)
+}
+
+// proposeOutputData returns the calldata proposing the given output root of the
+// block with the given header.
+func proposeOutputData(output common.Hash, header *mivetypes.Header) []byte {
+	data := make([]byte, 0, len(proposeOutputSelector)+3*common.HashLength)
+	data = append(data, proposeOutputSelector...)
+	data = append(data, output[:]...)
+	data = append(data, common.BigToHash(header.Number).Bytes()...)
+	return append(data, header.Hash[:]...)
+}
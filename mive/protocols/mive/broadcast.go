@@ -0,0 +1,29 @@
+package mive
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// broadcastBlocks is a write loop that multiplexes blocks and block
+// announcements to the remote peer. The goal is to have an async writer that
+// does not lock up node internals and at the same time rate limits queued data.
+func (p *Peer) broadcastBlocks() {
+	for {
+		select {
+		case prop := <-p.queuedBlocks:
+			if err := p.SendNewBlock(prop); err != nil {
+				return
+			}
+			p.Log().Trace("Propagated block", "number", prop.Block.Number(), "hash", prop.Block.Hash())
+
+		case header := <-p.queuedBlockAnns:
+			if err := p.SendNewBlockHashes([]common.Hash{header.Hash}, []uint64{header.NumberU64()}); err != nil {
+				return
+			}
+			p.Log().Trace("Announced block", "number", header.Number, "hash", header.Hash)
+
+		case <-p.term:
+			return
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package mive
+
+import (
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	"github.com/ethereum-mive/mive/core/forkid"
+)
+
+// enrEntry is the ENR entry which advertises the `mive` protocol on the
+// discovery, along with the fork ID of the Mive chain served. Mive networks
+// sharing the discovery DHT with other networks are told apart by it.
+type enrEntry struct {
+	ForkID forkid.ID // Fork identifier of the Mive chain
+
+	// Ignore additional fields (for forward compatibility).
+	Rest []rlp.RawValue `rlp:"tail"`
+}
+
+// ENRKey implements enr.Entry.
+func (e enrEntry) ENRKey() string {
+	return "mive"
+}
+
+// StartENRUpdater starts the `mive` ENR updater loop, which listens for chain
+// head events and updates the requested node record whenever a fork is passed.
+func StartENRUpdater(chain *mivecore.BlockChain, ln *enode.LocalNode) {
+	var newHead = make(chan core.ChainHeadEvent, 10)
+	sub := chain.SubscribeChainHeadEvent(newHead)
+
+	go func() {
+		defer sub.Unsubscribe()
+		for {
+			select {
+			case <-newHead:
+				ln.Set(currentENREntry(chain))
+			case <-sub.Err():
+				// Would be nice to sync with Stop, but there is no
+				// good way to do that.
+				return
+			}
+		}
+	}()
+}
+
+// currentENREntry constructs a `mive` ENR entry based on the current state of
+// the chain.
+func currentENREntry(chain *mivecore.BlockChain) *enrEntry {
+	return &enrEntry{
+		ForkID: forkid.NewIDWithChain(chain),
+	}
+}
+
+// NewNodeFilter returns a filtering function that returns whether the provided
+// node advertises the `mive` protocol with a fork ID compatible with the local
+// chain.
+func NewNodeFilter(chain *mivecore.BlockChain) func(*enode.Node) bool {
+	filter := forkid.NewFilter(chain)
+	return func(n *enode.Node) bool {
+		var entry enrEntry
+		if err := n.Load(&entry); err != nil {
+			return false
+		}
+		return filter(entry.ForkID) == nil
+	}
+}
@@ -0,0 +1,292 @@
+package mive
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/p2p/enr"
+	"github.com/ethereum/go-ethereum/rlp"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+const (
+	// softResponseLimit is the target maximum size of replies to data retrievals.
+	softResponseLimit = 2 * 1024 * 1024
+
+	// maxHeadersServe is the maximum number of block headers to serve. This number
+	// is there to limit the number of disk lookups.
+	maxHeadersServe = 1024
+
+	// maxBodiesServe is the maximum number of block bodies to serve. This number
+	// is mostly there to limit the number of disk lookups. With 24KB block sizes
+	// nowadays, the practical limit will always be softResponseLimit.
+	maxBodiesServe = 1024
+
+	// maxReceiptsServe is the maximum number of block receipts to serve. This
+	// number is mostly there to limit the number of disk lookups. With block
+	// containing 200+ transactions nowadays, the practical limit will always
+	// be softResponseLimit.
+	maxReceiptsServe = 1024
+)
+
+// Handler is a callback to invoke from an outside runner after the boilerplate
+// exchanges have passed.
+type Handler func(peer *Peer) error
+
+// Backend defines the data retrieval methods to serve remote requests and the
+// callback methods to invoke on remote deliveries.
+type Backend interface {
+	// Chain retrieves the Mive chain object to serve data.
+	Chain() *mivecore.BlockChain
+
+	// RunPeer is invoked when a peer joins on the `mive` protocol. The handler
+	// should do any peer maintenance work, handshakes and validations. If all
+	// is passed, control should be given back to the `handler` to process the
+	// inbound messages going forward.
+	RunPeer(peer *Peer, handler Handler) error
+
+	// PeerInfo retrieves all known `mive` information about a peer.
+	PeerInfo(id enode.ID) interface{}
+
+	// Handle is a callback to be invoked when a block announcement or
+	// propagation is received from the remote peer. The remote connection is
+	// torn down upon returning any error.
+	Handle(peer *Peer, packet Packet) error
+}
+
+// MakeProtocols constructs the P2P protocol definitions for `mive`, dialing
+// the nodes returned by the given iterator in addition to the ones found on
+// the node-wide discovery.
+func MakeProtocols(backend Backend, dialCandidates enode.Iterator) []p2p.Protocol {
+	protocols := make([]p2p.Protocol, len(ProtocolVersions))
+	for i, version := range ProtocolVersions {
+		version := version // Closure
+
+		protocols[i] = p2p.Protocol{
+			Name:    ProtocolName,
+			Version: version,
+			Length:  protocolLengths[version],
+			Run: func(p *p2p.Peer, rw p2p.MsgReadWriter) error {
+				peer := NewPeer(version, p, rw)
+				defer peer.Close()
+
+				return backend.RunPeer(peer, func(peer *Peer) error {
+					return Handle(backend, peer)
+				})
+			},
+			NodeInfo: func() interface{} {
+				return nodeInfo(backend.Chain())
+			},
+			PeerInfo: func(id enode.ID) interface{} {
+				return backend.PeerInfo(id)
+			},
+			Attributes:     []enr.Entry{currentENREntry(backend.Chain())},
+			DialCandidates: dialCandidates,
+		}
+	}
+	return protocols
+}
+
+// NodeInfo represents a short summary of the `mive` sub-protocol metadata
+// known about the host peer.
+type NodeInfo struct {
+	ChainID       uint64         `json:"chainId"`       // Mive chain ID of the node
+	Genesis       common.Hash    `json:"genesis"`       // SHA3 hash of the host's genesis block
+	GenesisNumber uint64         `json:"genesisNumber"` // Number of the host's genesis block
+	Beacon        common.Address `json:"beacon"`        // Initial beacon address of the Mive deployment
+	Head          common.Hash    `json:"head"`          // Hash of the host's best owned block
+	Number        uint64         `json:"number"`        // Number of the host's best owned block
+}
+
+// nodeInfo retrieves some `mive` protocol metadata about the running host node.
+func nodeInfo(chain *mivecore.BlockChain) *NodeInfo {
+	var (
+		head    = chain.CurrentHeader()
+		genesis = chain.Genesis()
+	)
+	return &NodeInfo{
+		ChainID:       chain.Config().ChainID().Uint64(),
+		Genesis:       genesis.Hash,
+		GenesisNumber: genesis.NumberU64(),
+		Beacon:        chain.Config().Mive.BeaconAddress,
+		Head:          head.Hash,
+		Number:        head.NumberU64(),
+	}
+}
+
+// Handle is invoked whenever a `mive` connection is opened with a remote peer.
+// It serves the data requests of the peer and delivers the responses to the
+// local requests until the connection is torn down.
+func Handle(backend Backend, peer *Peer) error {
+	for {
+		if err := handleMessage(backend, peer); err != nil {
+			peer.Log().Debug("Message handling failed in `mive`", "err", err)
+			return err
+		}
+	}
+}
+
+// handleMessage is invoked whenever an inbound message is received from a
+// remote peer. The remote connection is torn down upon returning any error.
+func handleMessage(backend Backend, peer *Peer) error {
+	// Read the next message from the remote peer, and ensure it's fully consumed
+	msg, err := peer.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Size > maxMessageSize {
+		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	}
+	defer msg.Discard()
+
+	switch msg.Code {
+	case GetBlockHeadersMsg:
+		var query GetBlockHeadersPacket
+		if err := msg.Decode(&query); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		return peer.ReplyBlockHeaders(query.RequestId, serviceGetBlockHeadersQuery(backend.Chain(), query.GetBlockHeadersRequest))
+
+	case GetBlockBodiesMsg:
+		var query GetBlockBodiesPacket
+		if err := msg.Decode(&query); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		return peer.ReplyBlockBodies(query.RequestId, serviceGetBlockBodiesQuery(backend.Chain(), query.GetBlockBodiesRequest))
+
+	case GetReceiptsMsg:
+		var query GetReceiptsPacket
+		if err := msg.Decode(&query); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		return peer.ReplyReceipts(query.RequestId, serviceGetReceiptsQuery(backend.Chain(), query.GetReceiptsRequest))
+
+	case BlockHeadersMsg:
+		var res BlockHeadersPacket
+		if err := msg.Decode(&res); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		return peer.deliver(res.RequestId, msg.Code, res.BlockHeadersRequest)
+
+	case BlockBodiesMsg:
+		var res BlockBodiesPacket
+		if err := msg.Decode(&res); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		return peer.deliver(res.RequestId, msg.Code, res.BlockBodiesResponse)
+
+	case ReceiptsMsg:
+		var res ReceiptsPacket
+		if err := msg.Decode(&res); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		return peer.deliver(res.RequestId, msg.Code, res.ReceiptsResponse)
+
+	case NewBlockHashesMsg:
+		var ann NewBlockHashesPacket
+		if err := msg.Decode(&ann); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		// Mark the hashes as present at the remote node
+		hashes, _ := ann.Unpack()
+		for _, hash := range hashes {
+			peer.markBlock(hash)
+		}
+		return backend.Handle(peer, &ann)
+
+	case NewBlockMsg:
+		var ann NewBlockPacket
+		if err := msg.Decode(&ann); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		if err := ann.sanityCheck(); err != nil {
+			return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+		}
+		// Mark the block as present at the remote node
+		peer.markBlock(ann.Block.Hash())
+		return backend.Handle(peer, &ann)
+
+	default:
+		return fmt.Errorf("%w: %v", errInvalidMsgCode, msg.Code)
+	}
+}
+
+// serviceGetBlockHeadersQuery assembles the response to a header query, the
+// canonical headers from the requested number on, up to the local head.
+func serviceGetBlockHeadersQuery(chain *mivecore.BlockChain, query *GetBlockHeadersRequest) []*mivetypes.Header {
+	if query == nil {
+		return nil
+	}
+	amount := query.Amount
+	if amount > maxHeadersServe {
+		amount = maxHeadersServe
+	}
+	var headers []*mivetypes.Header
+	for number := query.Origin; uint64(len(headers)) < amount; number++ {
+		header := chain.GetHeaderByNumber(number)
+		if header == nil {
+			break
+		}
+		headers = append(headers, header)
+	}
+	return headers
+}
+
+// serviceGetBlockBodiesQuery assembles the response to a body query, stopping
+// at the first unknown block.
+func serviceGetBlockBodiesQuery(chain *mivecore.BlockChain, query GetBlockBodiesRequest) []*BlockBody {
+	var (
+		bytes  int
+		bodies []*BlockBody
+	)
+	for lookups, hash := range query {
+		if bytes >= softResponseLimit || len(bodies) >= maxBodiesServe || lookups >= 2*maxBodiesServe {
+			break
+		}
+		body := chain.GetBody(hash)
+		if body == nil {
+			break
+		}
+		entry := &BlockBody{
+			Transactions: body.Transactions,
+			Rejections:   chain.GetRejections(hash),
+		}
+		enc, err := rlp.EncodeToBytes(entry)
+		if err != nil {
+			break
+		}
+		bodies = append(bodies, entry)
+		bytes += len(enc)
+	}
+	return bodies
+}
+
+// serviceGetReceiptsQuery assembles the response to a receipt query, stopping
+// at the first block whose receipts are unknown.
+func serviceGetReceiptsQuery(chain *mivecore.BlockChain, query GetReceiptsRequest) [][]*types.Receipt {
+	var (
+		bytes    int
+		receipts [][]*types.Receipt
+	)
+	for lookups, hash := range query {
+		if bytes >= softResponseLimit || len(receipts) >= maxReceiptsServe || lookups >= 2*maxReceiptsServe {
+			break
+		}
+		results := chain.GetReceiptsByHash(hash)
+		if results == nil {
+			if header := chain.GetHeaderByHash(hash); header == nil || header.ReceiptHash != types.EmptyRootHash {
+				break
+			}
+		}
+		receipts = append(receipts, results)
+		for _, receipt := range results {
+			bytes += int(receipt.Size())
+		}
+	}
+	return receipts
+}
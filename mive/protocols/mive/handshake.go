@@ -0,0 +1,91 @@
+package mive
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/p2p"
+
+	"github.com/ethereum-mive/mive/core/forkid"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+const (
+	// handshakeTimeout is the maximum allowed time for the `mive` handshake to
+	// complete before dropping the connection as malicious.
+	handshakeTimeout = 5 * time.Second
+)
+
+// Handshake executes the mive protocol handshake, negotiating version number,
+// chain IDs, head and genesis blocks, beacon address and fork ID.
+func (p *Peer) Handshake(chainID uint64, head common.Hash, number uint64, genesis *mivetypes.Header, beacon common.Address, forkID forkid.ID, forkFilter forkid.Filter) error {
+	// Send out own handshake in a new thread
+	errc := make(chan error, 2)
+
+	var status StatusPacket // safe to read after two values have been received from errc
+
+	go func() {
+		errc <- p2p.Send(p.rw, StatusMsg, &StatusPacket{
+			ProtocolVersion: uint32(p.version),
+			ChainID:         chainID,
+			Head:            head,
+			Number:          number,
+			Genesis:         genesis.Hash,
+			GenesisNumber:   genesis.NumberU64(),
+			Beacon:          beacon,
+			ForkID:          forkID,
+		})
+	}()
+	go func() {
+		errc <- p.readStatus(chainID, &status, genesis, beacon, forkFilter)
+	}()
+	timeout := time.NewTimer(handshakeTimeout)
+	defer timeout.Stop()
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-errc:
+			if err != nil {
+				return err
+			}
+		case <-timeout.C:
+			return p2p.DiscReadTimeout
+		}
+	}
+	p.SetHead(status.Head, status.Number)
+	return nil
+}
+
+// readStatus reads the remote handshake message.
+func (p *Peer) readStatus(chainID uint64, status *StatusPacket, genesis *mivetypes.Header, beacon common.Address, forkFilter forkid.Filter) error {
+	msg, err := p.rw.ReadMsg()
+	if err != nil {
+		return err
+	}
+	if msg.Code != StatusMsg {
+		return fmt.Errorf("%w: first msg has code %x (!= %x)", errNoStatusMsg, msg.Code, StatusMsg)
+	}
+	if msg.Size > maxMessageSize {
+		return fmt.Errorf("%w: %v > %v", errMsgTooLarge, msg.Size, maxMessageSize)
+	}
+	// Decode the handshake and make sure everything matches
+	if err := msg.Decode(&status); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	if status.ChainID != chainID {
+		return fmt.Errorf("%w: %d (!= %d)", errChainIDMismatch, status.ChainID, chainID)
+	}
+	if uint(status.ProtocolVersion) != p.version {
+		return fmt.Errorf("%w: %d (!= %d)", errProtocolVersionMismatch, status.ProtocolVersion, p.version)
+	}
+	if status.Genesis != genesis.Hash || status.GenesisNumber != genesis.NumberU64() {
+		return fmt.Errorf("%w: #%d %x (!= #%d %x)", errGenesisMismatch, status.GenesisNumber, status.Genesis, genesis.NumberU64(), genesis.Hash)
+	}
+	if status.Beacon != beacon {
+		return fmt.Errorf("%w: %v (!= %v)", errBeaconMismatch, status.Beacon, beacon)
+	}
+	if err := forkFilter(status.ForkID); err != nil {
+		return fmt.Errorf("%w: %v", errForkIDRejected, err)
+	}
+	return nil
+}
@@ -0,0 +1,399 @@
+package mive
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/p2p"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+const (
+	// requestTimeout is the maximum time a peer may take to answer a request
+	// before the request is considered failed.
+	requestTimeout = 15 * time.Second
+
+	// maxKnownBlocks is the maximum block hashes to keep in the known list
+	// before starting to evict the oldest ones.
+	maxKnownBlocks = 1024
+
+	// maxQueuedBlocks is the maximum number of block propagations to queue up
+	// before dropping broadcasts. There's not much point in queueing stale
+	// blocks, so a few should be enough.
+	maxQueuedBlocks = 4
+
+	// maxQueuedBlockAnns is the maximum number of block announcements to queue
+	// up before dropping broadcasts.
+	maxQueuedBlockAnns = 4
+)
+
+var (
+	errPeerClosed       = errors.New("peer closed")
+	errRequestTimeout   = errors.New("request timed out")
+	errUnsolicitedReply = errors.New("unsolicited response")
+)
+
+// response is a reply of a peer to a request, along with the code of the
+// message it was delivered in.
+type response struct {
+	code uint64
+	data interface{}
+}
+
+// Peer is a collection of relevant information we have about a `mive` peer.
+type Peer struct {
+	id string // Unique ID for the peer, cached
+
+	*p2p.Peer                   // The embedded P2P package peer
+	rw        p2p.MsgReadWriter // Input/output streams for mive
+	version   uint              // Protocol version negotiated
+
+	head   common.Hash // Latest advertised head block hash
+	number uint64      // Latest advertised head block number
+	lock   sync.RWMutex
+
+	knownBlocks     *knownCache            // Set of block hashes known to be known by this peer
+	queuedBlocks    chan *NewBlockPacket   // Queue of blocks to broadcast to the peer
+	queuedBlockAnns chan *mivetypes.Header // Queue of blocks to announce to the peer
+
+	penalty atomic.Int32 // Accumulated penalty of the peer's misbehaviour
+
+	servedHeaders  atomic.Uint64 // Number of headers served to the peer
+	servedBodies   atomic.Uint64 // Number of block bodies served to the peer
+	servedReceipts atomic.Uint64 // Number of block receipts served to the peer
+	imported       atomic.Uint64 // Number of blocks of the peer confirmed by the local chain
+
+	pending map[uint64]chan *response // Requests awaiting a response, by request ID
+	pendMu  sync.Mutex
+
+	term chan struct{} // Termination channel to stop the pending requests
+	once sync.Once
+}
+
+// NewPeer creates a wrapper for a network connection and negotiated protocol
+// version.
+func NewPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
+	peer := &Peer{
+		id:              p.ID().String(),
+		Peer:            p,
+		rw:              rw,
+		version:         version,
+		knownBlocks:     newKnownCache(maxKnownBlocks),
+		queuedBlocks:    make(chan *NewBlockPacket, maxQueuedBlocks),
+		queuedBlockAnns: make(chan *mivetypes.Header, maxQueuedBlockAnns),
+		pending:         make(map[uint64]chan *response),
+		term:            make(chan struct{}),
+	}
+	// Start up the block broadcaster on the peer
+	go peer.broadcastBlocks()
+
+	return peer
+}
+
+// Close signals the pending requests to abort.
+func (p *Peer) Close() {
+	p.once.Do(func() { close(p.term) })
+}
+
+// ID retrieves the peer's unique identifier.
+func (p *Peer) ID() string {
+	return p.id
+}
+
+// Version retrieves the peer's negotiated `mive` protocol version.
+func (p *Peer) Version() uint {
+	return p.version
+}
+
+// Head retrieves the current head hash and number of the peer.
+func (p *Peer) Head() (hash common.Hash, number uint64) {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return p.head, p.number
+}
+
+// SetHead updates the head hash and number of the peer.
+func (p *Peer) SetHead(hash common.Hash, number uint64) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.head, p.number = hash, number
+}
+
+// KnownBlock returns whether peer is known to already have a block.
+func (p *Peer) KnownBlock(hash common.Hash) bool {
+	return p.knownBlocks.Contains(hash)
+}
+
+// markBlock marks a block as known for the peer, ensuring that the block will
+// never be propagated to this particular peer.
+func (p *Peer) markBlock(hash common.Hash) {
+	p.knownBlocks.Add(hash)
+}
+
+// Penalize adds the given points to the penalty of the peer for misbehaving,
+// and returns its accumulated penalty.
+func (p *Peer) Penalize(points int32) int32 {
+	return p.penalty.Add(points)
+}
+
+// Penalty returns the accumulated penalty of the peer.
+func (p *Peer) Penalty() int32 {
+	return p.penalty.Load()
+}
+
+// Served returns the number of headers, block bodies and block receipts served
+// to the peer.
+func (p *Peer) Served() (headers uint64, bodies uint64, receipts uint64) {
+	return p.servedHeaders.Load(), p.servedBodies.Load(), p.servedReceipts.Load()
+}
+
+// MarkImported accounts the given number of blocks of the peer as confirmed by
+// the local chain.
+func (p *Peer) MarkImported(count int) {
+	p.imported.Add(uint64(count))
+}
+
+// Imported returns the number of blocks of the peer confirmed by the local chain.
+func (p *Peer) Imported() uint64 {
+	return p.imported.Load()
+}
+
+// SendNewBlockHashes announces the availability of a number of blocks through
+// a hash notification.
+func (p *Peer) SendNewBlockHashes(hashes []common.Hash, numbers []uint64) error {
+	// Mark all the block hashes as known, but ensure we don't overflow our limits
+	p.knownBlocks.Add(hashes...)
+
+	request := make(NewBlockHashesPacket, len(hashes))
+	for i := 0; i < len(hashes); i++ {
+		request[i].Hash = hashes[i]
+		request[i].Number = numbers[i]
+	}
+	return p2p.Send(p.rw, NewBlockHashesMsg, request)
+}
+
+// AsyncSendNewBlockHash queues the availability of a block for propagation to a
+// remote peer. If the peer's broadcast queue is full, the event is silently
+// dropped.
+func (p *Peer) AsyncSendNewBlockHash(header *mivetypes.Header) {
+	select {
+	case p.queuedBlockAnns <- header:
+		// Mark all the block hash as known, but ensure we don't overflow our limits
+		p.knownBlocks.Add(header.Hash)
+	default:
+		p.Log().Debug("Dropping block announcement", "number", header.Number, "hash", header.Hash)
+	}
+}
+
+// SendNewBlock propagates an entire block to a remote peer.
+func (p *Peer) SendNewBlock(packet *NewBlockPacket) error {
+	// Mark all the block hash as known, but ensure we don't overflow our limits
+	p.knownBlocks.Add(packet.Block.Hash())
+	return p2p.Send(p.rw, NewBlockMsg, packet)
+}
+
+// AsyncSendNewBlock queues an entire block for propagation to a remote peer,
+// along with its rejections and receipts. If the peer's broadcast queue is
+// full, the event is silently dropped.
+func (p *Peer) AsyncSendNewBlock(block *mivetypes.Block, rejections mivetypes.Rejections, receipts []*types.Receipt) {
+	select {
+	case p.queuedBlocks <- &NewBlockPacket{Block: block, Rejections: rejections, Receipts: receipts}:
+		// Mark all the block hash as known, but ensure we don't overflow our limits
+		p.knownBlocks.Add(block.Hash())
+	default:
+		p.Log().Debug("Dropping block propagation", "number", block.NumberU64(), "hash", block.Hash())
+	}
+}
+
+// ReplyBlockHeaders is the response to GetBlockHeaders.
+func (p *Peer) ReplyBlockHeaders(id uint64, headers []*mivetypes.Header) error {
+	p.servedHeaders.Add(uint64(len(headers)))
+	return p2p.Send(p.rw, BlockHeadersMsg, &BlockHeadersPacket{
+		RequestId:           id,
+		BlockHeadersRequest: headers,
+	})
+}
+
+// ReplyBlockBodies is the response to GetBlockBodies.
+func (p *Peer) ReplyBlockBodies(id uint64, bodies []*BlockBody) error {
+	p.servedBodies.Add(uint64(len(bodies)))
+	return p2p.Send(p.rw, BlockBodiesMsg, &BlockBodiesPacket{
+		RequestId:           id,
+		BlockBodiesResponse: bodies,
+	})
+}
+
+// ReplyReceipts is the response to GetReceipts.
+func (p *Peer) ReplyReceipts(id uint64, receipts [][]*types.Receipt) error {
+	p.servedReceipts.Add(uint64(len(receipts)))
+	return p2p.Send(p.rw, ReceiptsMsg, &ReceiptsPacket{
+		RequestId:        id,
+		ReceiptsResponse: receipts,
+	})
+}
+
+// RequestHeaders fetches a contiguous segment of at most amount canonical
+// headers from the peer, starting at the given block number.
+func (p *Peer) RequestHeaders(origin uint64, amount uint64) ([]*mivetypes.Header, error) {
+	p.Log().Debug("Fetching batch of headers", "origin", origin, "count", amount)
+	res, err := p.request(GetBlockHeadersMsg, BlockHeadersMsg, func(id uint64) interface{} {
+		return &GetBlockHeadersPacket{
+			RequestId: id,
+			GetBlockHeadersRequest: &GetBlockHeadersRequest{
+				Origin: origin,
+				Amount: amount,
+			},
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	headers := res.(BlockHeadersRequest)
+	if uint64(len(headers)) > amount {
+		return nil, fmt.Errorf("%w: %d headers, requested %d", errDecode, len(headers), amount)
+	}
+	return headers, nil
+}
+
+// RequestBodies fetches a batch of block bodies from the peer, corresponding
+// to the hashes specified.
+func (p *Peer) RequestBodies(hashes []common.Hash) ([]*BlockBody, error) {
+	p.Log().Debug("Fetching batch of block bodies", "count", len(hashes))
+	res, err := p.request(GetBlockBodiesMsg, BlockBodiesMsg, func(id uint64) interface{} {
+		return &GetBlockBodiesPacket{
+			RequestId:             id,
+			GetBlockBodiesRequest: hashes,
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	bodies := res.(BlockBodiesResponse)
+	if len(bodies) > len(hashes) {
+		return nil, fmt.Errorf("%w: %d bodies, requested %d", errDecode, len(bodies), len(hashes))
+	}
+	return bodies, nil
+}
+
+// RequestReceipts fetches a batch of transaction receipts from the peer,
+// corresponding to the block hashes specified.
+func (p *Peer) RequestReceipts(hashes []common.Hash) ([][]*types.Receipt, error) {
+	p.Log().Debug("Fetching batch of receipts", "count", len(hashes))
+	res, err := p.request(GetReceiptsMsg, ReceiptsMsg, func(id uint64) interface{} {
+		return &GetReceiptsPacket{
+			RequestId:          id,
+			GetReceiptsRequest: hashes,
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	receipts := res.(ReceiptsResponse)
+	if len(receipts) > len(hashes) {
+		return nil, fmt.Errorf("%w: %d receipt sets, requested %d", errDecode, len(receipts), len(hashes))
+	}
+	return receipts, nil
+}
+
+// request sends the request built for a fresh request ID, and waits for the
+// peer to deliver the response of the expected kind.
+func (p *Peer) request(code uint64, want uint64, build func(id uint64) interface{}) (interface{}, error) {
+	id := rand.Uint64()
+	resCh := make(chan *response, 1)
+
+	p.pendMu.Lock()
+	for p.pending[id] != nil {
+		id = rand.Uint64()
+	}
+	p.pending[id] = resCh
+	p.pendMu.Unlock()
+
+	defer func() {
+		p.pendMu.Lock()
+		delete(p.pending, id)
+		p.pendMu.Unlock()
+	}()
+	if err := p2p.Send(p.rw, code, build(id)); err != nil {
+		return nil, err
+	}
+	timeout := time.NewTimer(requestTimeout)
+	defer timeout.Stop()
+
+	select {
+	case res := <-resCh:
+		if res.code != want {
+			return nil, fmt.Errorf("%w: code %x in response to %x", errInvalidMsgCode, res.code, code)
+		}
+		return res.data, nil
+	case <-timeout.C:
+		return nil, errRequestTimeout
+	case <-p.term:
+		return nil, errPeerClosed
+	}
+}
+
+// deliver hands the response to the pending request with the given ID.
+func (p *Peer) deliver(id uint64, code uint64, data interface{}) error {
+	p.pendMu.Lock()
+	defer p.pendMu.Unlock()
+
+	resCh, ok := p.pending[id]
+	if !ok {
+		return fmt.Errorf("%w: request %d", errUnsolicitedReply, id)
+	}
+	delete(p.pending, id)
+	resCh <- &response{code: code, data: data}
+	return nil
+}
+
+// knownCache is a cache for known hashes, evicting the oldest ones once full.
+type knownCache struct {
+	hashes map[common.Hash]struct{}
+	order  []common.Hash // Cached hashes, oldest first
+	max    int
+	lock   sync.Mutex
+}
+
+// newKnownCache creates a new knownCache with a max capacity.
+func newKnownCache(max int) *knownCache {
+	return &knownCache{
+		hashes: make(map[common.Hash]struct{}, max),
+		max:    max,
+	}
+}
+
+// Add adds a list of elements to the set.
+func (k *knownCache) Add(hashes ...common.Hash) {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	for _, hash := range hashes {
+		if _, ok := k.hashes[hash]; ok {
+			continue
+		}
+		if len(k.order) >= k.max {
+			delete(k.hashes, k.order[0])
+			k.order = k.order[1:]
+		}
+		k.hashes[hash] = struct{}{}
+		k.order = append(k.order, hash)
+	}
+}
+
+// Contains returns whether the given item is in the set.
+func (k *knownCache) Contains(hash common.Hash) bool {
+	k.lock.Lock()
+	defer k.lock.Unlock()
+
+	_, ok := k.hashes[hash]
+	return ok
+}
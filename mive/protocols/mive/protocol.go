@@ -0,0 +1,217 @@
+package mive
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+
+	"github.com/ethereum-mive/mive/core/forkid"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+// Constants to match up protocol versions and messages
+const (
+	MIVE1 = 1
+)
+
+// ProtocolName is the official short name of the `mive` protocol used during
+// devp2p capability negotiation.
+const ProtocolName = "mive"
+
+// ProtocolVersions are the supported versions of the `mive` protocol (first
+// is primary).
+var ProtocolVersions = []uint{MIVE1}
+
+// protocolLengths are the number of implemented message corresponding to
+// different protocol versions.
+var protocolLengths = map[uint]uint64{MIVE1: 9}
+
+// maxMessageSize is the maximum cap on the size of a protocol message.
+const maxMessageSize = 10 * 1024 * 1024
+
+const (
+	StatusMsg          = 0x00
+	GetBlockHeadersMsg = 0x01
+	BlockHeadersMsg    = 0x02
+	GetBlockBodiesMsg  = 0x03
+	BlockBodiesMsg     = 0x04
+	GetReceiptsMsg     = 0x05
+	ReceiptsMsg        = 0x06
+	NewBlockHashesMsg  = 0x07
+	NewBlockMsg        = 0x08
+)
+
+var (
+	errNoStatusMsg             = errors.New("no status message")
+	errMsgTooLarge             = errors.New("message too long")
+	errDecode                  = errors.New("invalid message")
+	errInvalidMsgCode          = errors.New("invalid message code")
+	errProtocolVersionMismatch = errors.New("protocol version mismatch")
+	errChainIDMismatch         = errors.New("chain ID mismatch")
+	errGenesisMismatch         = errors.New("genesis mismatch")
+	errBeaconMismatch          = errors.New("beacon address mismatch")
+	errForkIDRejected          = errors.New("fork ID rejected")
+)
+
+// Packet represents a p2p message in the `mive` protocol.
+type Packet interface {
+	Name() string // Name returns a string corresponding to the message type.
+	Kind() byte   // Kind returns the message type.
+}
+
+// StatusPacket is the network packet for the status message. Mive deployments
+// sharing the same L1 chain are told apart by their genesis block and beacon
+// address, and their compatibility by the fork ID.
+type StatusPacket struct {
+	ProtocolVersion uint32
+	ChainID         uint64
+	Head            common.Hash
+	Number          uint64
+	Genesis         common.Hash
+	GenesisNumber   uint64
+	Beacon          common.Address
+	ForkID          forkid.ID
+}
+
+// GetBlockHeadersRequest represents a request for a contiguous segment of the
+// canonical Mive chain, starting at the given block number.
+type GetBlockHeadersRequest struct {
+	Origin uint64 // Number of the first block to retrieve
+	Amount uint64 // Maximum number of headers to retrieve
+}
+
+// GetBlockHeadersPacket represents a block header query with request ID wrapping.
+type GetBlockHeadersPacket struct {
+	RequestId uint64
+	*GetBlockHeadersRequest
+}
+
+// BlockHeadersRequest represents a block header response.
+type BlockHeadersRequest []*mivetypes.Header
+
+// BlockHeadersPacket represents a block header response over with request ID wrapping.
+type BlockHeadersPacket struct {
+	RequestId uint64
+	BlockHeadersRequest
+}
+
+// GetBlockBodiesRequest represents a block body query.
+type GetBlockBodiesRequest []common.Hash
+
+// GetBlockBodiesPacket represents a block body query with request ID wrapping.
+type GetBlockBodiesPacket struct {
+	RequestId uint64
+	GetBlockBodiesRequest
+}
+
+// BlockBody represents the data content of a single Mive block: the Mive
+// transactions it executed, and the L1 transactions it rejected. The latter are
+// not committed to by the header, but are needed to serve the transactions by
+// their L1 origin.
+type BlockBody struct {
+	Transactions []*mivetypes.Transaction
+	Rejections   mivetypes.Rejections
+}
+
+// BlockBodiesResponse is the network packet for block content distribution.
+type BlockBodiesResponse []*BlockBody
+
+// BlockBodiesPacket is the network packet for block content distribution with
+// request ID wrapping.
+type BlockBodiesPacket struct {
+	RequestId uint64
+	BlockBodiesResponse
+}
+
+// GetReceiptsRequest represents a block receipts query.
+type GetReceiptsRequest []common.Hash
+
+// GetReceiptsPacket represents a block receipts query with request ID wrapping.
+type GetReceiptsPacket struct {
+	RequestId uint64
+	GetReceiptsRequest
+}
+
+// ReceiptsResponse is the network packet for block receipts distribution, in
+// their consensus encoding so they can be checked against the receipt roots.
+type ReceiptsResponse [][]*types.Receipt
+
+// ReceiptsPacket is the network packet for block receipts distribution with
+// request ID wrapping.
+type ReceiptsPacket struct {
+	RequestId uint64
+	ReceiptsResponse
+}
+
+// NewBlockHashesPacket is the network packet for the block announcements.
+type NewBlockHashesPacket []struct {
+	Hash   common.Hash // Hash of one particular block being announced
+	Number uint64      // Number of one particular block being announced
+}
+
+// Unpack retrieves the block hashes and numbers from the announcement packet
+// and returns them in a split flat format that's more consistent with the
+// internal data structures.
+func (p *NewBlockHashesPacket) Unpack() ([]common.Hash, []uint64) {
+	var (
+		hashes  = make([]common.Hash, len(*p))
+		numbers = make([]uint64, len(*p))
+	)
+	for i, body := range *p {
+		hashes[i], numbers[i] = body.Hash, body.Number
+	}
+	return hashes, numbers
+}
+
+// NewBlockPacket is the network packet for the block propagation message: the
+// block along with the data needed to import it without deriving it.
+type NewBlockPacket struct {
+	Block      *mivetypes.Block
+	Rejections mivetypes.Rejections
+	Receipts   []*types.Receipt
+}
+
+// sanityCheck verifies that the values are reasonable, as a DoS protection.
+func (request *NewBlockPacket) sanityCheck() error {
+	if request.Block == nil {
+		return errors.New("missing block")
+	}
+	if txs, receipts := len(request.Block.Transactions()), len(request.Receipts); txs != receipts {
+		return fmt.Errorf("%d transactions but %d receipts", txs, receipts)
+	}
+	return nil
+}
+
+func (*StatusPacket) Name() string { return "Status" }
+func (*StatusPacket) Kind() byte   { return StatusMsg }
+
+func (*GetBlockHeadersRequest) Name() string { return "GetBlockHeaders" }
+func (*GetBlockHeadersRequest) Kind() byte   { return GetBlockHeadersMsg }
+
+func (*BlockHeadersRequest) Name() string { return "BlockHeaders" }
+func (*BlockHeadersRequest) Kind() byte   { return BlockHeadersMsg }
+
+func (*GetBlockBodiesRequest) Name() string { return "GetBlockBodies" }
+func (*GetBlockBodiesRequest) Kind() byte   { return GetBlockBodiesMsg }
+
+func (*BlockBodiesResponse) Name() string { return "BlockBodies" }
+func (*BlockBodiesResponse) Kind() byte   { return BlockBodiesMsg }
+
+func (*GetReceiptsRequest) Name() string { return "GetReceipts" }
+func (*GetReceiptsRequest) Kind() byte   { return GetReceiptsMsg }
+
+func (*ReceiptsResponse) Name() string { return "Receipts" }
+func (*ReceiptsResponse) Kind() byte   { return ReceiptsMsg }
+
+func (*NewBlockHashesPacket) Name() string { return "NewBlockHashes" }
+func (*NewBlockHashesPacket) Kind() byte   { return NewBlockHashesMsg }
+
+func (*NewBlockPacket) Name() string { return "NewBlock" }
+func (*NewBlockPacket) Kind() byte   { return NewBlockMsg }
+
+// String implements fmt.Stringer.
+func (req *GetBlockHeadersRequest) String() string {
+	return fmt.Sprintf("#%d+%d", req.Origin, req.Amount)
+}
@@ -0,0 +1,334 @@
+package mive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+
+	miverawdb "github.com/ethereum-mive/mive/core/rawdb"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	miveethclient "github.com/ethereum-mive/mive/ethclient"
+	"github.com/ethereum-mive/mive/internal/miveapi"
+)
+
+const (
+	relayerCheckInterval = 12 * time.Second // Interval between two checks of the in-flight transactions
+	relayerCheckTimeout  = 30 * time.Second // Timeout of a check of the in-flight transactions
+	relayerPriceBump     = 10               // Minimum price bump (%) for replacing a stuck transaction
+	relayerIncludedLimit = 1024             // Number of included transactions kept for status lookups
+)
+
+// errFeeCapReached is returned if a stuck transaction can't be replaced as its
+// fees would exceed the fee cap of the relayer.
+var errFeeCapReached = errors.New("relayer fee cap reached")
+
+// relayer wraps Mive transactions into beacon transactions signed by a local
+// account, so that clients can submit Mive transactions without an L1 account
+// of their own.
+//
+// The relayer assigns the L1 nonces of its account, serializing submissions,
+// and tracks its transactions until they are included. Transactions pending for
+// too long are replaced with better priced ones, up to a fee cap. The in-flight
+// transactions are persisted, so that they are still tracked after a restart.
+//
+// Note, the Mive sender of a beacon transaction is the sender of the L1
+// transaction, so the relayed transactions are executed as the relayer
+// account, unless they are signed by their own sender.
+type relayer struct {
+	backend      miveapi.Backend
+	client       *miveethclient.Client
+	manager      *accounts.Manager
+	db           ethdb.KeyValueWriter
+	account      accounts.Account
+	feeCap       *big.Int      // Maximum L1 fee cap per gas of the relayed transactions
+	bumpInterval time.Duration // Time a transaction may be pending before being replaced
+
+	mu       sync.Mutex
+	nonce    uint64                                          // Next L1 nonce to assign, unless the L1 one is higher
+	txs      []*mivetypes.RelayedTx                          // In-flight transactions, sorted by nonce
+	included lru.BasicLRU[common.Hash, *mivetypes.RelayedTx] // Recently included transactions by handle
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newRelayer creates a relayer signing the beacon transactions with the given
+// account of the account manager, loading its in-flight transactions from the
+// database.
+func newRelayer(backend miveapi.Backend, client *miveethclient.Client, manager *accounts.Manager, db ethdb.KeyValueStore, addr common.Address, feeCap *big.Int, bumpInterval time.Duration) *relayer {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &relayer{
+		backend:      backend,
+		client:       client,
+		manager:      manager,
+		db:           db,
+		account:      accounts.Account{Address: addr},
+		feeCap:       feeCap,
+		bumpInterval: bumpInterval,
+		txs:          miverawdb.ReadRelayedTxs(db, addr),
+		included:     lru.NewBasicLRU[common.Hash, *mivetypes.RelayedTx](relayerIncludedLimit),
+		ctx:          ctx,
+		cancel:       cancel,
+	}
+	if n := len(r.txs); n > 0 {
+		r.nonce = r.txs[n-1].Tx.Nonce() + 1
+		log.Info("Loaded in-flight relayed transactions", "relayer", addr, "count", n)
+	}
+	return r
+}
+
+// start launches the tracking of the in-flight transactions.
+func (r *relayer) start() {
+	r.wg.Add(1)
+	go r.loop()
+}
+
+// stop terminates the tracking of the in-flight transactions.
+func (r *relayer) stop() {
+	r.cancel()
+	r.wg.Wait()
+}
+
+// loop periodically checks the in-flight transactions.
+func (r *relayer) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(relayerCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			r.check()
+		case <-r.ctx.Done():
+			return
+		}
+	}
+}
+
+// relay wraps the given Mive transactions, as a batch if there are more than
+// one, into a beacon transaction from the relayer account, priced at the current
+// L1 fees, signs and submits it. The returned handle identifies the transaction
+// across replacements.
+func (r *relayer) relay(ctx context.Context, txs []*mivetypes.Tx) (common.Hash, error) {
+	data, err := mivetypes.EncodePayload(txs...)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return r.submit(ctx, r.beacon(), data)
+}
+
+// submit sends an L1 transaction from the relayer account calling the given
+// address with the given data, priced at the current L1 fees, and tracks it
+// like the relayed ones. The returned handle is the hash of the Mive
+// transaction for beacon transactions, and the hash of the initial L1
+// transaction otherwise.
+func (r *relayer) submit(ctx context.Context, to common.Address, data []byte) (common.Hash, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	nonce, err := r.client.PendingNonceAt(ctx, r.account.Address)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to retrieve relayer nonce: %w", err)
+	}
+	if nonce < r.nonce {
+		nonce = r.nonce
+	}
+	gas, err := r.client.EstimateGas(ctx, ethereum.CallMsg{From: r.account.Address, To: &to, Data: data})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to estimate L1 gas: %w", err)
+	}
+	tip, baseFee, err := r.backend.SuggestL1Fees(ctx)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	// Leave room for the base fee to rise over the next few blocks
+	feeCap := new(big.Int).Add(tip, new(big.Int).Mul(baseFee, common.Big2))
+	if feeCap.Cmp(r.feeCap) > 0 {
+		if baseFee.Cmp(r.feeCap) >= 0 {
+			return common.Hash{}, fmt.Errorf("%w: L1 base fee %v", errFeeCapReached, baseFee)
+		}
+		feeCap.Set(r.feeCap)
+	}
+	if tip.Cmp(feeCap) > 0 {
+		tip.Set(feeCap)
+	}
+	signed, err := r.sign(nonce, tip, feeCap, gas, to, data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	handle, err := r.send(ctx, signed)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	r.nonce = nonce + 1
+	r.txs = append(r.txs, &mivetypes.RelayedTx{Handle: handle, Tx: signed, Time: uint64(time.Now().Unix())})
+	miverawdb.WriteRelayedTxs(r.db, r.account.Address, r.txs)
+	return handle, nil
+}
+
+// send submits the given signed transaction to L1, through the checks of the
+// Mive API if it is sent to the beacon address, and returns its handle.
+func (r *relayer) send(ctx context.Context, tx *types.Transaction) (common.Hash, error) {
+	if *tx.To() == r.beacon() {
+		return miveapi.SubmitTransaction(ctx, r.backend, tx)
+	}
+	if err := r.client.SendTransaction(ctx, tx); err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// sign creates a transaction from the relayer account with the given parameters
+// and signs it. The wallet of the relayer account is looked up on every call,
+// as it may be attached or unlocked after startup.
+func (r *relayer) sign(nonce uint64, tip, feeCap *big.Int, gas uint64, to common.Address, data []byte) (*types.Transaction, error) {
+	wallet, err := r.manager.Find(r.account)
+	if err != nil {
+		return nil, fmt.Errorf("relayer account %s: %w", r.account.Address, err)
+	}
+	config := r.backend.ChainConfig()
+	tx := types.NewTx(&types.DynamicFeeTx{
+		ChainID:   config.Eth.ChainID,
+		Nonce:     nonce,
+		GasTipCap: tip,
+		GasFeeCap: feeCap,
+		Gas:       gas,
+		To:        &to,
+		Data:      data,
+	})
+	return miveapi.SignBeaconTx(wallet, r.account, tx, config.Eth.ChainID)
+}
+
+// beacon returns the beacon address the relayed transactions are sent to, the
+// one observed by the next block.
+func (r *relayer) beacon() common.Address {
+	next := new(big.Int).Add(r.backend.CurrentBlock().Number, common.Big1)
+	return r.backend.ChainConfig().Beacon(next)
+}
+
+// check drops the in-flight transactions whose nonce got included on L1 and
+// replaces the ones pending for longer than the bump interval.
+func (r *relayer) check() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if len(r.txs) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(r.ctx, relayerCheckTimeout)
+	defer cancel()
+
+	included, err := r.client.NonceAt(ctx, r.account.Address, nil)
+	if err != nil {
+		log.Debug("Failed to retrieve relayer nonce", "err", err)
+		return
+	}
+	// Either the tracked transaction or one of its replacements got included
+	var done int
+	for done < len(r.txs) && r.txs[done].Tx.Nonce() < included {
+		log.Debug("Relayed transaction included", "handle", r.txs[done].Handle, "l1hash", r.txs[done].Tx.Hash())
+		r.included.Add(r.txs[done].Handle, r.txs[done])
+		done++
+	}
+	r.txs = r.txs[done:]
+	changed := done > 0
+
+	for _, rtx := range r.txs {
+		if time.Since(time.Unix(int64(rtx.Time), 0)) < r.bumpInterval {
+			continue
+		}
+		replacement, err := r.bump(ctx, rtx.Tx)
+		if err != nil {
+			log.Warn("Failed to replace stuck relayed transaction", "handle", rtx.Handle, "nonce", rtx.Tx.Nonce(), "err", err)
+			continue
+		}
+		if replacement.Hash() != rtx.Tx.Hash() {
+			rtx.Replaced = append(rtx.Replaced, rtx.Tx.Hash())
+		}
+		rtx.Tx, rtx.Time = replacement, uint64(time.Now().Unix())
+		changed = true
+	}
+	if changed {
+		miverawdb.WriteRelayedTxs(r.db, r.account.Address, r.txs)
+	}
+}
+
+// lookup returns the relayed transaction with the given handle, either in
+// flight or recently included, and whether it is in flight.
+func (r *relayer) lookup(handle common.Hash) (*mivetypes.RelayedTx, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, rtx := range r.txs {
+		if rtx.Handle == handle {
+			return rtx, true
+		}
+	}
+	if rtx, ok := r.included.Get(handle); ok {
+		return rtx, false
+	}
+	return nil, false
+}
+
+// bump replaces the given stuck transaction with one raising its fees by at
+// least the price bump, and at least to the current L1 fees, within the fee
+// cap. If the fees can't be raised anymore, the transaction is rebroadcast.
+func (r *relayer) bump(ctx context.Context, tx *types.Transaction) (*types.Transaction, error) {
+	tip, baseFee, err := r.backend.SuggestL1Fees(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		minTip    = bumpPrice(tx.GasTipCap())
+		minFeeCap = bumpPrice(tx.GasFeeCap())
+	)
+	if tip.Cmp(minTip) < 0 {
+		tip = minTip
+	}
+	feeCap := new(big.Int).Add(tip, new(big.Int).Mul(baseFee, common.Big2))
+	if feeCap.Cmp(minFeeCap) < 0 {
+		feeCap = minFeeCap
+	}
+	if feeCap.Cmp(r.feeCap) > 0 {
+		feeCap = new(big.Int).Set(r.feeCap)
+	}
+	if feeCap.Cmp(minFeeCap) < 0 || tip.Cmp(feeCap) > 0 {
+		// The transaction can't be replaced anymore, make sure the L1 pool
+		// didn't drop it meanwhile. The error of the L1 pool for a known
+		// transaction is only available as a message over RPC.
+		if err := r.client.SendTransaction(ctx, tx); err != nil && err.Error() != "already known" {
+			return nil, fmt.Errorf("%w: %v", errFeeCapReached, err)
+		}
+		log.Warn("Relayed transaction stuck at fee cap", "nonce", tx.Nonce(), "hash", tx.Hash(), "feecap", r.feeCap)
+		return tx, nil
+	}
+	replacement, err := r.sign(tx.Nonce(), tip, feeCap, tx.Gas(), *tx.To(), tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	if _, err := r.send(ctx, replacement); err != nil {
+		return nil, err
+	}
+	log.Info("Replaced stuck relayed transaction", "nonce", tx.Nonce(), "old", tx.Hash(), "new", replacement.Hash(), "tip", tip, "feecap", feeCap)
+	return replacement, nil
+}
+
+// bumpPrice returns the given price raised by the price bump, rounded up.
+func bumpPrice(price *big.Int) *big.Int {
+	bumped := new(big.Int).Mul(price, big.NewInt(100+relayerPriceBump))
+	bumped.Add(bumped, big.NewInt(99))
+	return bumped.Div(bumped, big.NewInt(100))
+}
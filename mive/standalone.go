@@ -0,0 +1,91 @@
+package mive
+
+import (
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	"github.com/ethereum-mive/mive/mive/miveconfig"
+	"github.com/ethereum-mive/mive/node"
+	miveparams "github.com/ethereum-mive/mive/params"
+)
+
+// StandaloneConfig contains the settings of a Mive execution layer embedded in
+// another program.
+type StandaloneConfig struct {
+	DataDir string             // Data directory of the chain, empty for in-memory databases
+	Mive    *miveconfig.Config // Settings of the Mive service, miveconfig.Defaults if nil
+}
+
+// Standalone is a Mive execution layer embedded in another program, e.g. an
+// indexer, a bridge or a simulator. It derives the Mive chain from the
+// configured L1 endpoints like the mive command does, but neither serves it
+// over RPC nor to peers: the chain and the RPC APIs are accessed in-process.
+//
+// It's backed by a node without any endpoint or networking, so that its data
+// directory can be shared with the mive command, although not concurrently.
+type Standalone struct {
+	stack *node.Node
+	mive  *Mive
+}
+
+// NewStandalone creates a Mive execution layer with the given settings, opening
+// its databases and dialing the L1 endpoints. The chain isn't derived until the
+// execution layer is started.
+func NewStandalone(config StandaloneConfig) (*Standalone, error) {
+	miveConfig := miveconfig.Defaults
+	if config.Mive != nil {
+		miveConfig = *config.Mive
+	}
+	// Nothing is served, neither to the peers nor over HTTP
+	miveConfig.DiscoveryURLs = []string{}
+	miveConfig.HealthEndpoints = false
+
+	stack, err := node.New(&node.Config{
+		Name:    "mive",
+		Version: miveparams.VersionWithMeta,
+		DataDir: config.DataDir,
+		P2P: p2p.Config{
+			MaxPeers:    0,
+			NoDial:      true,
+			NoDiscovery: true,
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	backend, err := New(stack, &miveConfig)
+	if err != nil {
+		stack.Close()
+		return nil, err
+	}
+	return &Standalone{stack: stack, mive: backend}, nil
+}
+
+// Start starts deriving the Mive chain from L1 in the background.
+func (s *Standalone) Start() error {
+	return s.stack.Start()
+}
+
+// Stop stops deriving the Mive chain, waiting for the block being processed,
+// and closes the databases. The execution layer can't be restarted.
+func (s *Standalone) Stop() error {
+	return s.stack.Close()
+}
+
+// Chain returns the Mive chain derived by the execution layer.
+func (s *Standalone) Chain() *mivecore.BlockChain {
+	return s.mive.BlockChain()
+}
+
+// APIs returns the RPC services of the execution layer, e.g. to register them
+// on an RPC server of the embedding program.
+func (s *Standalone) APIs() []rpc.API {
+	return s.mive.APIs()
+}
+
+// Attach creates an in-process RPC client to the execution layer, serving all
+// of its RPC services. It has to be started first.
+func (s *Standalone) Attach() *rpc.Client {
+	return s.stack.Attach()
+}
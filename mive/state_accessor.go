@@ -0,0 +1,240 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package mive
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/trie"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/mive/tracers"
+)
+
+// noopReleaser is returned in case there is no operation expected
+// for releasing state.
+var noopReleaser = tracers.StateReleaseFunc(func() {})
+
+func (m *Mive) hashState(ctx context.Context, block *mivetypes.Block, reexec uint64) (statedb *state.StateDB, release tracers.StateReleaseFunc, err error) {
+	var (
+		bc      = m.blockchain
+		current = block
+		origin  = block.NumberU64()
+		genesis = bc.Genesis().NumberU64()
+	)
+	// The state is available in live database, create a reference on top to
+	// prevent garbage collection and return a release function to deref it.
+	if statedb, err = bc.StateAt(block.Root()); err == nil {
+		bc.TrieDB().Reference(block.Root(), common.Hash{})
+		return statedb, func() {
+			bc.TrieDB().Dereference(block.Root())
+		}, nil
+	}
+	// The state is unavailable in disk, try to construct/recover the state over
+	// an ephemeral trie.Database for isolating the live one. Otherwise the
+	// internal junks created by the re-execution will be persisted into the disk.
+	var (
+		triedb   = trie.NewDatabase(m.chainDb, trie.HashDefaults)
+		database = state.NewDatabaseWithNodeDB(m.chainDb, triedb)
+	)
+	// Database does not have the state for the given block, try to regenerate
+	for i := uint64(0); i < reexec; i++ {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		if current.NumberU64() <= genesis {
+			return nil, nil, errors.New("genesis state is missing")
+		}
+		parent := bc.GetMiveBlock(current.ParentHash(), current.NumberU64()-1)
+		if parent == nil {
+			// Checkpoint synced nodes have no blocks below the checkpoint
+			return nil, nil, fmt.Errorf("missing block %v %d", current.ParentHash(), current.NumberU64()-1)
+		}
+		current = parent
+
+		statedb, err = state.New(current.Root(), database, nil)
+		if err == nil {
+			break
+		}
+	}
+	if err != nil {
+		switch err.(type) {
+		case *trie.MissingNodeError:
+			return nil, nil, fmt.Errorf("required historical state unavailable (reexec=%d)", reexec)
+		default:
+			return nil, nil, err
+		}
+	}
+	// State is available at historical point, re-derive the blocks on top for
+	// the desired state from the L1 blocks they were derived from.
+	var (
+		start  = time.Now()
+		logged time.Time
+		parent common.Hash
+	)
+	for current.NumberU64() < origin {
+		if err := ctx.Err(); err != nil {
+			return nil, nil, err
+		}
+		// Print progress logs if long enough time elapsed
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Regenerating historical state", "block", current.NumberU64()+1, "target", origin, "remaining", origin-current.NumberU64()-1, "elapsed", time.Since(start))
+			logged = time.Now()
+		}
+		// Retrieve the next block to regenerate and process it
+		next := current.NumberU64() + 1
+		if current = bc.GetMiveBlockByNumber(next); current == nil {
+			return nil, nil, fmt.Errorf("block #%d not found", next)
+		}
+		l1Block, err := bc.EthGetBlock(current.Hash(), next)
+		if err != nil {
+			return nil, nil, fmt.Errorf("retrieving L1 block %d failed: %v", next, err)
+		}
+		if _, _, _, _, _, err := bc.Processor().Process(l1Block, statedb, vm.Config{}); err != nil {
+			return nil, nil, fmt.Errorf("processing block %d failed: %v", next, err)
+		}
+		// Finalize the state so any modifications are written to the trie
+		root, err := statedb.Commit(next, bc.Config().Eth.IsEIP158(current.Number()))
+		if err != nil {
+			return nil, nil, fmt.Errorf("stateAtBlock commit failed, number %d root %v: %w",
+				next, current.Root().Hex(), err)
+		}
+		statedb, err = state.New(root, database, nil)
+		if err != nil {
+			return nil, nil, fmt.Errorf("state reset after block %d failed: %v", next, err)
+		}
+		// Hold the state reference and also drop the parent state
+		// to prevent accumulating too many nodes in memory.
+		triedb.Reference(root, common.Hash{})
+		if parent != (common.Hash{}) {
+			triedb.Dereference(parent)
+		}
+		parent = root
+	}
+	_, nodes, imgs := triedb.Size() // all memory is contained within the nodes return in hashdb
+	log.Info("Historical state regenerated", "block", current.NumberU64(), "elapsed", time.Since(start), "nodes", nodes, "preimages", imgs)
+	return statedb, func() { triedb.Dereference(block.Root()) }, nil
+}
+
+func (m *Mive) pathState(block *mivetypes.Block) (*state.StateDB, func(), error) {
+	// Check if the requested state is available in the live chain.
+	statedb, err := m.blockchain.StateAt(block.Root())
+	if err == nil {
+		return statedb, noopReleaser, nil
+	}
+	// Historic state is not supported in path-based scheme, the state
+	// histories can only roll the live state back.
+	return nil, nil, errors.New("historical state not available in path scheme yet")
+}
+
+// stateAtBlock retrieves the state database associated with a certain Mive
+// block, i.e. the state after all of its transactions were executed. If no
+// state is locally available for the given block, a number of blocks are
+// attempted to be re-derived from their L1 blocks to generate the desired
+// state. The returned state is only meant for reading, no mutation should be
+// persisted by the caller.
+//
+// An additional release function will be returned if the requested state is
+// available. Release is expected to be invoked when the returned state is no
+// longer needed. Its purpose is to prevent resource leaking. Though it can be
+// noop in some cases.
+func (m *Mive) stateAtBlock(ctx context.Context, block *mivetypes.Block, reexec uint64) (*state.StateDB, tracers.StateReleaseFunc, error) {
+	if m.blockchain.TrieDB().Scheme() == rawdb.HashScheme {
+		return m.hashState(ctx, block, reexec)
+	}
+	return m.pathState(block)
+}
+
+// stateAtTransaction returns the execution environment of a certain Mive
+// transaction: the message it was executed as, the block context of the L1
+// block it was derived from and the state right before its execution.
+func (m *Mive) stateAtTransaction(ctx context.Context, block *mivetypes.Block, txIndex int, reexec uint64) (*core.Message, vm.BlockContext, *state.StateDB, tracers.StateReleaseFunc, error) {
+	// Short circuit if it's the genesis block, its state isn't derived.
+	if block.NumberU64() <= m.blockchain.Genesis().NumberU64() {
+		return nil, vm.BlockContext{}, nil, nil, errors.New("no transaction in genesis")
+	}
+	// Create the parent state database
+	parent := m.blockchain.GetMiveBlock(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, vm.BlockContext{}, nil, nil, fmt.Errorf("parent %#x not found", block.ParentHash())
+	}
+	// Lookup the statedb of parent block from the live database,
+	// otherwise regenerate it on the flight.
+	statedb, release, err := m.stateAtBlock(ctx, parent, reexec)
+	if err != nil {
+		return nil, vm.BlockContext{}, nil, nil, err
+	}
+	if txIndex == 0 && len(block.Transactions()) == 0 {
+		return nil, vm.BlockContext{}, statedb, release, nil
+	}
+	// The block context and the state changes applied before the transactions
+	// both come from the L1 block the Mive block was derived from.
+	var (
+		bc     = m.blockchain
+		config = bc.Config()
+	)
+	l1Header, err := bc.EthGetHeader(block.Hash(), block.NumberU64())
+	if err != nil {
+		release()
+		return nil, vm.BlockContext{}, nil, nil, err
+	}
+	context, err := mivecore.NewEVMBlockContext(l1Header, bc, nil, config)
+	if err != nil {
+		release()
+		return nil, vm.BlockContext{}, nil, nil, err
+	}
+	mivecore.ApplyBlockStart(l1Header, vm.NewEVM(context, vm.TxContext{}, statedb, config.EVMConfig(), vm.Config{}), statedb, config)
+
+	// Recompute transactions up to the target index.
+	for idx, tx := range block.Transactions() {
+		msg := mivecore.MiveTransactionToMessage(tx, context.BaseFee)
+		if idx == txIndex {
+			return msg, context, statedb, release, nil
+		}
+		mivecore.ApplyDepositMint(statedb, tx)
+		// Not yet the searched for transaction, execute on top of the current state
+		vmenv := vm.NewEVM(context, core.NewEVMTxContext(msg), statedb, config.EVMConfig(), vm.Config{NoBaseFee: tx.IsDeposit()})
+		statedb.SetTxContext(tx.Hash(), idx)
+		snapshot := statedb.Snapshot()
+		result, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit))
+		if err != nil {
+			// Deposits are included even if they fail, having only minted
+			if !tx.IsDeposit() {
+				release()
+				return nil, vm.BlockContext{}, nil, nil, fmt.Errorf("transaction %#x failed: %v", tx.Hash(), err)
+			}
+			statedb.RevertToSnapshot(snapshot)
+		} else {
+			mivecore.ApplyFeePolicy(statedb, context, msg, tx, result.UsedGas, config)
+		}
+		// Ensure any modifications are committed to the state
+		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
+		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
+	}
+	release()
+	return nil, vm.BlockContext{}, nil, nil, fmt.Errorf("transaction index %d out of range for block %#x", txIndex, block.Hash())
+}
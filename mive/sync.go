@@ -0,0 +1,261 @@
+package mive
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/exp/slices"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/mive/protocols/mive"
+)
+
+const (
+	// forceSyncCycle is the interval the best peer is checked for blocks ahead
+	// of the local chain, regardless of the peer events.
+	forceSyncCycle = 10 * time.Second
+
+	// maxHeaderFetch is the number of headers requested from a peer at once.
+	maxHeaderFetch = 192
+)
+
+var (
+	// errBrokenChain is returned if a peer serves headers not extending the
+	// local chain.
+	errBrokenChain = errors.New("headers not extending the local chain")
+
+	// errMismatchingBlock is returned if a peer serves a block differing from
+	// the one derived locally from the same L1 block.
+	errMismatchingBlock = errors.New("block mismatching the local derivation")
+)
+
+// chainSyncer coordinates the syncing of the local chain with the peers. A Mive
+// header only commits to its L1 block, so the contents of the blocks served by
+// the peers can't be trusted but by deriving them: the Mive blocks are always
+// derived locally from L1, and the peers only tell how far the chain goes.
+//
+// The headers of the peer with the highest head are retrieved ahead of the
+// local chain, and verified against the L1 blocks they are derived from by the
+// consensus engine. They're then kept pending, the derivation being woken up to
+// catch up with them, and checked against the derived ones: the peers serving
+// headers differing from them are penalized.
+type chainSyncer struct {
+	handler     *handler
+	peerEventCh chan struct{}
+	peer        atomic.Pointer[mive.Peer] // Peer being synced from, nil if idle
+
+	pending map[uint64][]*pendingHeader // Peer headers waiting for the derivation, by number
+	lock    sync.Mutex                  // Protects the pending headers
+}
+
+// pendingHeader is a header served by a peer, waiting for the local derivation
+// to reach its block to be checked.
+type pendingHeader struct {
+	peer   *mive.Peer
+	header *mivetypes.Header
+}
+
+// newChainSyncer creates a chainSyncer.
+func newChainSyncer(handler *handler) *chainSyncer {
+	return &chainSyncer{
+		handler:     handler,
+		peerEventCh: make(chan struct{}, 1),
+		pending:     make(map[uint64][]*pendingHeader),
+	}
+}
+
+// handlePeerEvent notifies the syncer about a change in the peer set, without
+// blocking.
+func (cs *chainSyncer) handlePeerEvent() {
+	select {
+	case cs.peerEventCh <- struct{}{}:
+	default:
+	}
+}
+
+// loop runs in its own goroutine and launches the sync when necessary, checking
+// the pending headers as the local chain progresses.
+func (cs *chainSyncer) loop() {
+	defer cs.handler.wg.Done()
+
+	headCh := make(chan core.ChainHeadEvent, 10)
+	sub := cs.handler.chain.SubscribeChainHeadEvent(headCh)
+	defer sub.Unsubscribe()
+
+	force := time.NewTicker(forceSyncCycle)
+	defer force.Stop()
+
+	for {
+		if peer := cs.nextSyncPeer(); peer != nil {
+			if err := cs.sync(peer); err != nil {
+				peer.Log().Debug("Mive sync failed, dropping peer", "err", err)
+				peer.Disconnect(p2p.DiscUselessPeer)
+			}
+		}
+		select {
+		case <-headCh:
+			cs.checkPending()
+		case <-cs.peerEventCh:
+		case <-force.C:
+		case <-sub.Err():
+			return
+		case <-cs.handler.quitSync:
+			return
+		}
+	}
+}
+
+// nextSyncPeer returns the peer to sync from, nil if no peer is ahead of the
+// local chain or headers are still pending.
+func (cs *chainSyncer) nextSyncPeer() *mive.Peer {
+	if cs.pendingCount() > 0 {
+		return nil
+	}
+	peer := cs.handler.peers.peerWithHighestHead()
+	if peer == nil {
+		return nil
+	}
+	if _, number := peer.Head(); number <= cs.handler.chain.CurrentHeader().NumberU64() {
+		return nil
+	}
+	return peer
+}
+
+// syncPeer returns the peer being synced from, nil if no sync is running.
+func (cs *chainSyncer) syncPeer() *mive.Peer {
+	return cs.peer.Load()
+}
+
+// sync retrieves a batch of headers of the given peer ahead of the local chain,
+// verifies them against L1 and keeps them pending until the local derivation
+// reaches them, waking it up.
+func (cs *chainSyncer) sync(peer *mive.Peer) error {
+	cs.peer.Store(peer)
+	defer cs.peer.Store(nil)
+
+	var (
+		chain     = cs.handler.chain
+		local     = chain.CurrentHeader()
+		_, remote = peer.Head()
+	)
+	if remote <= local.NumberU64() {
+		return nil
+	}
+	amount := remote - local.NumberU64()
+	if amount > maxHeaderFetch {
+		amount = maxHeaderFetch
+	}
+	headers, err := peer.RequestHeaders(local.NumberU64()+1, amount)
+	if err != nil {
+		return err
+	}
+	if len(headers) == 0 {
+		// The peer may have been rewound since it announced its head
+		return nil
+	}
+	// Ensure the headers extend the local chain, and are derived from L1
+	parent := local
+	for _, header := range headers {
+		if header.ParentHash != parent.Hash || header.NumberU64() != parent.NumberU64()+1 {
+			return fmt.Errorf("%w: #%d [%x..]", errBrokenChain, header.Number, header.Hash.Bytes()[:4])
+		}
+		parent = header
+	}
+	abort, results := chain.Engine().VerifyHeaders(chain, headers)
+	defer close(abort)
+	for _, header := range headers {
+		if err := <-results; err != nil {
+			return fmt.Errorf("invalid header #%d [%x..]: %w", header.Number, header.Hash.Bytes()[:4], err)
+		}
+	}
+	cs.addPending(peer, headers...)
+	log.Debug("Retrieved Mive headers from peer", "peer", peer.ID(), "count", len(headers), "number", headers[len(headers)-1].Number)
+
+	cs.handler.resync()
+	return nil
+}
+
+// addPending keeps the given headers served by the given peer until the local
+// derivation reaches them, checking right away the ones it already reached. A
+// single header is kept per peer and number.
+func (cs *chainSyncer) addPending(peer *mive.Peer, headers ...*mivetypes.Header) {
+	cs.lock.Lock()
+	for _, header := range headers {
+		number := header.NumberU64()
+		if !slices.ContainsFunc(cs.pending[number], func(p *pendingHeader) bool { return p.peer == peer }) {
+			cs.pending[number] = append(cs.pending[number], &pendingHeader{peer: peer, header: header})
+		}
+	}
+	cs.lock.Unlock()
+
+	cs.checkPending()
+}
+
+// pendingCount returns the number of block numbers having pending headers.
+func (cs *chainSyncer) pendingCount() int {
+	cs.lock.Lock()
+	defer cs.lock.Unlock()
+
+	return len(cs.pending)
+}
+
+// checkPending checks the pending headers the local chain reached against the
+// local ones. The peers having served mismatching headers are penalized, unless
+// the L1 block they're derived from was reorganised out in the meantime.
+func (cs *chainSyncer) checkPending() {
+	var (
+		chain   = cs.handler.chain
+		head    = chain.CurrentHeader().NumberU64()
+		checked []*pendingHeader
+		locals  []*mivetypes.Header
+	)
+	cs.lock.Lock()
+	for number, pending := range cs.pending {
+		if number > head {
+			continue
+		}
+		local := chain.GetHeaderByNumber(number)
+		for _, p := range pending {
+			checked = append(checked, p)
+			locals = append(locals, local)
+		}
+		delete(cs.pending, number)
+	}
+	cs.lock.Unlock()
+
+	for i, p := range checked {
+		local := locals[i]
+		if local == nil || local.Hash != p.header.Hash {
+			continue
+		}
+		if !sameHeader(local, p.header) {
+			err := fmt.Errorf("%w: #%d [%x..]", errMismatchingBlock, p.header.Number, p.header.Hash.Bytes()[:4])
+			if err := cs.handler.penalize(p.peer, invalidBlockPenalty, err); err != nil {
+				p.peer.Disconnect(p2p.DiscUselessPeer)
+			}
+			continue
+		}
+		p.peer.MarkImported(1)
+	}
+}
+
+// sameHeader reports whether the given headers are identical.
+func sameHeader(a, b *mivetypes.Header) bool {
+	enca, err := rlp.EncodeToBytes(a)
+	if err != nil {
+		return false
+	}
+	encb, err := rlp.EncodeToBytes(b)
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(enca, encb)
+}
@@ -0,0 +1,491 @@
+// Copyright 2021 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package tracers implements the tracing APIs of the Mive chain on top of the
+// tracing engines of go-ethereum.
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/eth/tracers"
+	"github.com/ethereum/go-ethereum/eth/tracers/logger"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/internal/miveapi"
+	miveparams "github.com/ethereum-mive/mive/params"
+)
+
+const (
+	// defaultTraceTimeout is the amount of time a single transaction can execute
+	// by default before being forcefully aborted.
+	defaultTraceTimeout = 5 * time.Second
+)
+
+var errTxNotFound = errors.New("transaction not found")
+
+// StateReleaseFunc is used to deallocate resources held by constructing a
+// historical state for tracing purposes.
+type StateReleaseFunc func()
+
+// Backend interface provides the common API services (that are provided by
+// the Mive service) with access to necessary functions.
+type Backend interface {
+	HeaderByHash(ctx context.Context, hash common.Hash) (*mivetypes.Header, error)
+	HeaderByNumber(ctx context.Context, number rpc.BlockNumber) (*mivetypes.Header, error)
+	BlockByHash(ctx context.Context, hash common.Hash) (*mivetypes.Block, error)
+	BlockByNumber(ctx context.Context, number rpc.BlockNumber) (*mivetypes.Block, error)
+	GetTransaction(ctx context.Context, txHash common.Hash) (*mivetypes.Transaction, common.Hash, uint64, uint64, error)
+	GetTransactionByOrigin(ctx context.Context, l1TxHash common.Hash) (*mivetypes.Transaction, *mivetypes.Rejection, common.Hash, uint64, error)
+	RPCGasCap() uint64
+	RPCReexec() uint64
+	ChainConfig() *miveparams.ChainConfig
+	BlockContext(ctx context.Context, header *mivetypes.Header) (vm.BlockContext, error)
+	StateAtBlock(ctx context.Context, block *mivetypes.Block, reexec uint64) (*state.StateDB, StateReleaseFunc, error)
+	StateAtTransaction(ctx context.Context, block *mivetypes.Block, txIndex int, reexec uint64) (*core.Message, vm.BlockContext, *state.StateDB, StateReleaseFunc, error)
+}
+
+// API is the collection of tracing APIs exposed over the private debugging endpoint.
+type API struct {
+	backend Backend
+}
+
+// NewAPI creates a new API definition for the tracing methods of the Mive service.
+func NewAPI(backend Backend) *API {
+	return &API{backend: backend}
+}
+
+// blockByNumber is the wrapper of the chain access function offered by the backend.
+// It will return an error if the block is not found.
+func (api *API) blockByNumber(ctx context.Context, number rpc.BlockNumber) (*mivetypes.Block, error) {
+	block, err := api.backend.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block #%d not found", number)
+	}
+	return block, nil
+}
+
+// blockByHash is the wrapper of the chain access function offered by the backend.
+// It will return an error if the block is not found.
+func (api *API) blockByHash(ctx context.Context, hash common.Hash) (*mivetypes.Block, error) {
+	block, err := api.backend.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if block == nil {
+		return nil, fmt.Errorf("block %s not found", hash.Hex())
+	}
+	return block, nil
+}
+
+// reexec returns the maximum number of blocks to re-execute to regenerate the
+// state needed by a trace, falling back to the node-wide default.
+func (api *API) reexec(config *TraceConfig) uint64 {
+	if config != nil && config.Reexec != nil {
+		return *config.Reexec
+	}
+	return api.backend.RPCReexec()
+}
+
+// TraceConfig holds extra parameters to trace functions.
+type TraceConfig struct {
+	*logger.Config
+	Tracer  *string
+	Timeout *string
+	Reexec  *uint64
+	// Config specific to given tracer. Note struct logger
+	// config are historically embedded in main object.
+	TracerConfig json.RawMessage
+}
+
+// TraceCallConfig is the config for traceCall API. It holds one more
+// field to override the block context for tracing.
+type TraceCallConfig struct {
+	TraceConfig
+	BlockOverrides *miveapi.BlockOverrides
+	TxIndex        *hexutil.Uint
+}
+
+// txTraceResult is the result of a single transaction trace.
+type txTraceResult struct {
+	TxHash common.Hash `json:"txHash"`           // transaction hash
+	Result interface{} `json:"result,omitempty"` // Trace results produced by the tracer
+	Error  string      `json:"error,omitempty"`  // Trace failure produced by the tracer
+}
+
+// txTraceTask represents a single transaction trace task when an entire block
+// is being traced.
+type txTraceTask struct {
+	statedb *state.StateDB // Intermediate state prepped for tracing
+	index   int            // Transaction offset in the block
+}
+
+// TraceBlockByNumber returns the structured logs created during the execution of
+// EVM and returns them as a JSON object.
+func (api *API) TraceBlockByNumber(ctx context.Context, number rpc.BlockNumber, config *TraceConfig) ([]*txTraceResult, error) {
+	block, err := api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return api.traceBlock(ctx, block, config)
+}
+
+// TraceBlockByHash returns the structured logs created during the execution of
+// EVM and returns them as a JSON object.
+func (api *API) TraceBlockByHash(ctx context.Context, hash common.Hash, config *TraceConfig) ([]*txTraceResult, error) {
+	block, err := api.blockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return api.traceBlock(ctx, block, config)
+}
+
+// traceBlock configures a new tracer according to the provided configuration, and
+// executes all the Mive transactions contained within. The return value will be
+// one item per transaction, dependent on the requested tracer.
+func (api *API) traceBlock(ctx context.Context, block *mivetypes.Block, config *TraceConfig) ([]*txTraceResult, error) {
+	txs := block.Transactions()
+	if len(txs) == 0 {
+		return []*txTraceResult{}, nil
+	}
+	// Prepare base state, the one the first transaction was executed on top of
+	_, blockCtx, statedb, release, err := api.backend.StateAtTransaction(ctx, block, 0, api.reexec(config))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	// JS tracers have high overhead. In this case run a parallel
+	// process that generates states in one thread and traces txes
+	// in separate worker threads.
+	if config != nil && config.Tracer != nil && *config.Tracer != "" {
+		if isJS := tracers.DefaultDirectory.IsJS(*config.Tracer); isJS {
+			return api.traceBlockParallel(ctx, block, blockCtx, statedb, config)
+		}
+	}
+	// Native tracers have low overhead
+	var (
+		blockHash = block.Hash()
+		is158     = api.backend.ChainConfig().Eth.IsEIP158(block.Number())
+		results   = make([]*txTraceResult, len(txs))
+	)
+	for i, tx := range txs {
+		// Generate the next state snapshot fast without tracing
+		msg := mivecore.MiveTransactionToMessage(tx, blockCtx.BaseFee)
+		txctx := &tracers.Context{
+			BlockHash:   blockHash,
+			BlockNumber: block.Number(),
+			TxIndex:     i,
+			TxHash:      tx.Hash(),
+		}
+		mivecore.ApplyDepositMint(statedb, tx)
+		snapshot := statedb.Snapshot()
+		res, err := api.traceTx(ctx, msg, tx, txctx, blockCtx, statedb, config)
+		switch {
+		case err == nil:
+			results[i] = &txTraceResult{TxHash: tx.Hash(), Result: res}
+		case tx.IsDeposit():
+			// Deposits are included even if they fail, having only minted
+			statedb.RevertToSnapshot(snapshot)
+			results[i] = &txTraceResult{TxHash: tx.Hash(), Error: err.Error()}
+		default:
+			return nil, err
+		}
+		// Finalize the state so any modifications are written to the trie
+		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
+		statedb.Finalise(is158)
+	}
+	return results, nil
+}
+
+// traceBlockParallel is for tracers that have a high overhead (read JS tracers). One thread
+// runs along and executes txes without tracing enabled to generate their prestate.
+// Worker threads take the tasks and the prestate and trace them.
+func (api *API) traceBlockParallel(ctx context.Context, block *mivetypes.Block, blockCtx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) ([]*txTraceResult, error) {
+	// Execute all the transaction contained within the block concurrently
+	var (
+		txs       = block.Transactions()
+		blockHash = block.Hash()
+		results   = make([]*txTraceResult, len(txs))
+		pend      sync.WaitGroup
+	)
+	threads := runtime.NumCPU()
+	if threads > len(txs) {
+		threads = len(txs)
+	}
+	jobs := make(chan *txTraceTask, threads)
+	for th := 0; th < threads; th++ {
+		pend.Add(1)
+		go func() {
+			defer pend.Done()
+			// Fetch and execute the next transaction trace tasks
+			for task := range jobs {
+				msg := mivecore.MiveTransactionToMessage(txs[task.index], blockCtx.BaseFee)
+				txctx := &tracers.Context{
+					BlockHash:   blockHash,
+					BlockNumber: block.Number(),
+					TxIndex:     task.index,
+					TxHash:      txs[task.index].Hash(),
+				}
+				res, err := api.traceTx(ctx, msg, txs[task.index], txctx, blockCtx, task.statedb, config)
+				if err != nil {
+					results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Error: err.Error()}
+					continue
+				}
+				results[task.index] = &txTraceResult{TxHash: txs[task.index].Hash(), Result: res}
+			}
+		}()
+	}
+
+	// Feed the transactions into the tracers and return
+	var failed error
+txloop:
+	for i, tx := range txs {
+		// Send the trace task over for execution
+		mivecore.ApplyDepositMint(statedb, tx)
+		task := &txTraceTask{statedb: statedb.Copy(), index: i}
+		select {
+		case <-ctx.Done():
+			failed = ctx.Err()
+			break txloop
+		case jobs <- task:
+		}
+
+		// Generate the next state snapshot fast without tracing
+		msg := mivecore.MiveTransactionToMessage(tx, blockCtx.BaseFee)
+		statedb.SetTxContext(tx.Hash(), i)
+		vmenv := vm.NewEVM(blockCtx, core.NewEVMTxContext(msg), statedb, api.backend.ChainConfig().EVMConfig(), vm.Config{NoBaseFee: tx.IsDeposit()})
+		snapshot := statedb.Snapshot()
+		result, err := core.ApplyMessage(vmenv, msg, new(core.GasPool).AddGas(msg.GasLimit))
+		if err != nil {
+			if !tx.IsDeposit() {
+				failed = err
+				break txloop
+			}
+			statedb.RevertToSnapshot(snapshot)
+		} else {
+			mivecore.ApplyFeePolicy(statedb, blockCtx, msg, tx, result.UsedGas, api.backend.ChainConfig())
+		}
+		// Finalize the state so any modifications are written to the trie
+		// Only delete empty objects if EIP158/161 (a.k.a Spurious Dragon) is in effect
+		statedb.Finalise(vmenv.ChainConfig().IsEIP158(block.Number()))
+	}
+
+	close(jobs)
+	pend.Wait()
+
+	// If execution failed in between, abort
+	if failed != nil {
+		return nil, failed
+	}
+	return results, nil
+}
+
+// TraceTransaction returns the structured logs created during the execution of EVM
+// and returns them as a JSON object. The transaction may be identified either by
+// its Mive hash or by the hash of the wrapping L1 transaction.
+func (api *API) TraceTransaction(ctx context.Context, hash common.Hash, config *TraceConfig) (interface{}, error) {
+	tx, blockHash, _, index, err := api.transaction(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	// Only executed txes are supported
+	if tx == nil {
+		return nil, errTxNotFound
+	}
+	block, err := api.blockByHash(ctx, blockHash)
+	if err != nil {
+		return nil, err
+	}
+	msg, vmctx, statedb, release, err := api.backend.StateAtTransaction(ctx, block, int(index), api.reexec(config))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	mivecore.ApplyDepositMint(statedb, tx)
+	txctx := &tracers.Context{
+		BlockHash:   blockHash,
+		BlockNumber: block.Number(),
+		TxIndex:     int(index),
+		TxHash:      tx.Hash(),
+	}
+	return api.traceTx(ctx, msg, tx, txctx, vmctx, statedb, config)
+}
+
+// transaction resolves the given hash to an executed Mive transaction, falling
+// back to looking it up as the hash of the wrapping L1 transaction. Beacon
+// transactions that were rejected have nothing to trace.
+func (api *API) transaction(ctx context.Context, hash common.Hash) (*mivetypes.Transaction, common.Hash, uint64, uint64, error) {
+	tx, blockHash, blockNumber, index, err := api.backend.GetTransaction(ctx, hash)
+	if tx != nil || err != nil {
+		return tx, blockHash, blockNumber, index, err
+	}
+	tx, rejection, _, _, err := api.backend.GetTransactionByOrigin(ctx, hash)
+	if err != nil {
+		return nil, common.Hash{}, 0, 0, err
+	}
+	if rejection != nil {
+		return nil, common.Hash{}, 0, 0, fmt.Errorf("beacon transaction %#x was rejected: %s", hash, rejection.Reason)
+	}
+	if tx == nil {
+		return nil, common.Hash{}, 0, 0, nil
+	}
+	return api.backend.GetTransaction(ctx, tx.Hash())
+}
+
+// TraceCall lets you trace a given eth_call. It collects the structured logs
+// created during the execution of EVM if the given transaction was added on
+// top of the provided block and returns them as a JSON object. The message is
+// executed in the context of the L1 block the Mive block was derived from, as
+// if it was wrapped in one of its beacon transactions.
+// If no transaction index is specified, the trace will be conducted on the state
+// after executing the specified block. However, if a transaction index is provided,
+// the trace will be conducted on the state after executing the transactions
+// preceding the specified one within the specified block.
+func (api *API) TraceCall(ctx context.Context, args miveapi.TransactionArgs, blockNrOrHash rpc.BlockNumberOrHash, config *TraceCallConfig) (interface{}, error) {
+	// Try to retrieve the specified block
+	var (
+		err     error
+		block   *mivetypes.Block
+		statedb *state.StateDB
+		release StateReleaseFunc
+	)
+	if hash, ok := blockNrOrHash.Hash(); ok {
+		block, err = api.blockByHash(ctx, hash)
+	} else if number, ok := blockNrOrHash.Number(); ok {
+		block, err = api.blockByNumber(ctx, number)
+	} else {
+		return nil, errors.New("invalid arguments; neither block nor hash specified")
+	}
+	if err != nil {
+		return nil, err
+	}
+	// try to recompute the state
+	var traceConfig *TraceConfig
+	if config != nil {
+		traceConfig = &config.TraceConfig
+	}
+	reexec := api.reexec(traceConfig)
+	if config != nil && config.TxIndex != nil {
+		_, _, statedb, release, err = api.backend.StateAtTransaction(ctx, block, int(*config.TxIndex), reexec)
+	} else {
+		statedb, release, err = api.backend.StateAtBlock(ctx, block, reexec)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	vmctx, err := api.backend.BlockContext(ctx, block.Header())
+	if err != nil {
+		return nil, err
+	}
+	// Apply the customization rules if required.
+	if config != nil {
+		config.BlockOverrides.Apply(&vmctx)
+	}
+	// Execute the trace
+	msg, err := args.ToMessage(api.backend.RPCGasCap(), vmctx.BaseFee)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.traceTx(ctx, msg, nil, new(tracers.Context), vmctx, statedb, traceConfig)
+}
+
+// traceTx configures a new tracer according to the provided configuration, and
+// executes the given message in the provided environment. The fees paid by the
+// message are then split according to the fee policy if it executes the given
+// Mive transaction, nil for calls. The return value will be tracer dependent.
+func (api *API) traceTx(ctx context.Context, message *core.Message, tx *mivetypes.Transaction, txctx *tracers.Context, vmctx vm.BlockContext, statedb *state.StateDB, config *TraceConfig) (interface{}, error) {
+	var (
+		tracer    tracers.Tracer
+		err       error
+		timeout   = defaultTraceTimeout
+		txContext = core.NewEVMTxContext(message)
+	)
+	if config == nil {
+		config = &TraceConfig{}
+	}
+	// Default tracer is the struct logger
+	tracer = logger.NewStructLogger(config.Config)
+	if config.Tracer != nil {
+		tracer, err = tracers.DefaultDirectory.New(*config.Tracer, txctx, config.TracerConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	vmenv := vm.NewEVM(vmctx, txContext, statedb, api.backend.ChainConfig().EVMConfig(), vm.Config{Tracer: tracer, NoBaseFee: true})
+
+	// Define a meaningful timeout of a single transaction trace
+	if config.Timeout != nil {
+		if timeout, err = time.ParseDuration(*config.Timeout); err != nil {
+			return nil, err
+		}
+	}
+	deadlineCtx, cancel := context.WithTimeout(ctx, timeout)
+	go func() {
+		<-deadlineCtx.Done()
+		if errors.Is(deadlineCtx.Err(), context.DeadlineExceeded) {
+			tracer.Stop(errors.New("execution timeout"))
+			// Stop evm execution. Note cancellation is not necessarily immediate.
+			vmenv.Cancel()
+		}
+	}()
+	defer cancel()
+
+	// Call Prepare to clear out the statedb access list
+	statedb.SetTxContext(txctx.TxHash, txctx.TxIndex)
+	result, err := core.ApplyMessage(vmenv, message, new(core.GasPool).AddGas(message.GasLimit))
+	if err != nil {
+		return nil, fmt.Errorf("tracing failed: %w", err)
+	}
+	if tx != nil {
+		mivecore.ApplyFeePolicy(statedb, vmctx, message, tx, result.UsedGas, api.backend.ChainConfig())
+	}
+	return tracer.GetResult()
+}
+
+// APIs return the collection of RPC services the tracer package offers.
+func APIs(backend Backend) []rpc.API {
+	// Append all the local APIs and return
+	return []rpc.API{
+		{
+			Namespace: "debug",
+			Service:   NewAPI(backend),
+		}, {
+			Namespace: "trace",
+			Service:   NewTraceAPI(backend),
+		},
+	}
+}
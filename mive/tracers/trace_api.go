@@ -0,0 +1,244 @@
+package tracers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+
+	// The flat call tracer producing the traces is a native one
+	_ "github.com/ethereum/go-ethereum/eth/tracers/native"
+)
+
+var (
+	// flatCallTracer is the tracer producing the Parity-style traces, with the
+	// errors converted to their Parity equivalent for compatibility with the
+	// tools consuming them, e.g. block explorers.
+	flatCallTracer       = "flatCallTracer"
+	flatCallTracerConfig = json.RawMessage(`{"convertParityErrors":true}`)
+)
+
+// TraceAPI provides the Parity-style trace namespace over the Mive chain: the
+// call frames of the Mive transactions flattened into action/result records
+// identified by their trace address.
+type TraceAPI struct {
+	api *API
+}
+
+// NewTraceAPI creates a new API definition for the Parity-style tracing methods
+// of the Mive service.
+func NewTraceAPI(backend Backend) *TraceAPI {
+	return &TraceAPI{api: NewAPI(backend)}
+}
+
+// flatTraceConfig returns the configuration running the flat call tracer.
+func flatTraceConfig() *TraceConfig {
+	return &TraceConfig{Tracer: &flatCallTracer, TracerConfig: flatCallTracerConfig}
+}
+
+// Block returns the traces of all the Mive transactions of the given block.
+func (api *TraceAPI) Block(ctx context.Context, number rpc.BlockNumber) ([]json.RawMessage, error) {
+	block, err := api.api.blockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return api.traceBlock(ctx, block)
+}
+
+// Transaction returns the traces of the given Mive transaction, which may be
+// identified either by its Mive hash or by the hash of the wrapping L1
+// transaction.
+func (api *TraceAPI) Transaction(ctx context.Context, hash common.Hash) ([]json.RawMessage, error) {
+	result, err := api.api.TraceTransaction(ctx, hash, flatTraceConfig())
+	if err != nil {
+		return nil, err
+	}
+	return decodeFlatTraces(result)
+}
+
+// TraceFilterArgs represents the arguments to filter the traces of a range of
+// Mive blocks.
+type TraceFilterArgs struct {
+	FromBlock   *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock     *rpc.BlockNumber `json:"toBlock"`
+	FromAddress []common.Address `json:"fromAddress"`
+	ToAddress   []common.Address `json:"toAddress"`
+	After       *uint64          `json:"after"`
+	Count       *uint64          `json:"count"`
+}
+
+// Filter returns the traces of the given block range matching the given sender
+// and recipient addresses. A trace matches if its sender is one of the sender
+// addresses and its recipient one of the recipient addresses, an empty list
+// matching any address. The first After matching traces are skipped and at
+// most Count traces are returned.
+func (api *TraceAPI) Filter(ctx context.Context, args TraceFilterArgs) ([]json.RawMessage, error) {
+	from := api.api.backend.ChainConfig().Mive.GenesisBlock.Uint64()
+	if args.FromBlock != nil {
+		number, err := api.blockNumber(ctx, *args.FromBlock)
+		if err != nil {
+			return nil, err
+		}
+		from = number
+	}
+	toBlock := rpc.LatestBlockNumber
+	if args.ToBlock != nil {
+		toBlock = *args.ToBlock
+	}
+	to, err := api.blockNumber(ctx, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	if from > to {
+		return nil, fmt.Errorf("end block (#%d) needs to come after start block (#%d)", to, from)
+	}
+	var (
+		skip    uint64
+		results = []json.RawMessage{}
+	)
+	if args.After != nil {
+		skip = *args.After
+	}
+	for number := from; number <= to; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		block, err := api.api.backend.BlockByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		// Blocks below the checkpoint of checkpoint synced nodes are missing
+		if block == nil || len(block.Transactions()) == 0 {
+			continue
+		}
+		traces, err := api.traceBlock(ctx, block)
+		if err != nil {
+			return nil, err
+		}
+		for _, trace := range traces {
+			match, err := matchTrace(trace, args.FromAddress, args.ToAddress)
+			if err != nil {
+				return nil, err
+			}
+			if !match {
+				continue
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			results = append(results, trace)
+			if args.Count != nil && uint64(len(results)) >= *args.Count {
+				return results, nil
+			}
+		}
+	}
+	return results, nil
+}
+
+// blockNumber resolves the given block number, which may be a named one, to
+// the number of a Mive block.
+func (api *TraceAPI) blockNumber(ctx context.Context, number rpc.BlockNumber) (uint64, error) {
+	if number >= 0 {
+		return uint64(number), nil
+	}
+	header, err := api.api.backend.HeaderByNumber(ctx, number)
+	if err != nil {
+		return 0, err
+	}
+	if header == nil {
+		return 0, fmt.Errorf("block #%d not found", number)
+	}
+	return header.NumberU64(), nil
+}
+
+// traceBlock traces all the Mive transactions of the given block with the flat
+// call tracer and concatenates their traces.
+func (api *TraceAPI) traceBlock(ctx context.Context, block *mivetypes.Block) ([]json.RawMessage, error) {
+	results, err := api.api.traceBlock(ctx, block, flatTraceConfig())
+	if err != nil {
+		return nil, err
+	}
+	traces := []json.RawMessage{}
+	for _, result := range results {
+		if result.Error != "" {
+			return nil, fmt.Errorf("tracing transaction %#x failed: %s", result.TxHash, result.Error)
+		}
+		txTraces, err := decodeFlatTraces(result.Result)
+		if err != nil {
+			return nil, err
+		}
+		traces = append(traces, txTraces...)
+	}
+	return traces, nil
+}
+
+// decodeFlatTraces splits the output of the flat call tracer into the traces
+// of the individual call frames.
+func decodeFlatTraces(result interface{}) ([]json.RawMessage, error) {
+	raw, ok := result.(json.RawMessage)
+	if !ok {
+		return nil, errors.New("unexpected flat call tracer output")
+	}
+	var traces []json.RawMessage
+	if err := json.Unmarshal(raw, &traces); err != nil {
+		return nil, err
+	}
+	return traces, nil
+}
+
+// flatTraceAddresses holds the fields of a flat trace identifying its sender
+// and recipient, depending on the type of the trace.
+type flatTraceAddresses struct {
+	Action struct {
+		From           *common.Address `json:"from"`
+		To             *common.Address `json:"to"`
+		SelfDestructed *common.Address `json:"address"`
+		RefundAddress  *common.Address `json:"refundAddress"`
+	} `json:"action"`
+	Result *struct {
+		Address *common.Address `json:"address"`
+	} `json:"result"`
+}
+
+// matchTrace reports whether the sender and recipient of the given trace are
+// among the given addresses. The recipient of a contract creation is the
+// created contract, the one of a self-destruct the refunded beneficiary.
+func matchTrace(trace json.RawMessage, fromAddresses, toAddresses []common.Address) (bool, error) {
+	if len(fromAddresses) == 0 && len(toAddresses) == 0 {
+		return true, nil
+	}
+	var addrs flatTraceAddresses
+	if err := json.Unmarshal(trace, &addrs); err != nil {
+		return false, err
+	}
+	from, to := addrs.Action.From, addrs.Action.To
+	if addrs.Action.SelfDestructed != nil {
+		from, to = addrs.Action.SelfDestructed, addrs.Action.RefundAddress
+	} else if to == nil && addrs.Result != nil {
+		to = addrs.Result.Address
+	}
+	return matchAddress(from, fromAddresses) && matchAddress(to, toAddresses), nil
+}
+
+// matchAddress reports whether the given address is among the given ones, an
+// empty list matching any address.
+func matchAddress(addr *common.Address, addresses []common.Address) bool {
+	if len(addresses) == 0 {
+		return true
+	}
+	if addr == nil {
+		return false
+	}
+	for _, a := range addresses {
+		if a == *addr {
+			return true
+		}
+	}
+	return false
+}
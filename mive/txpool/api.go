@@ -0,0 +1,64 @@
+package txpool
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+
+	"github.com/ethereum-mive/mive/internal/miveapi"
+)
+
+// API offers the txpool namespace over the observed pool of pending Mive
+// transactions. The pool only holds pending transactions: a beacon transaction
+// is executed once it is included on L1, so no transaction is ever queued on
+// the Mive side.
+type API struct {
+	pool *TxPool
+}
+
+// NewAPI creates a new API definition for the observed transaction pool.
+func NewAPI(pool *TxPool) *API {
+	return &API{pool}
+}
+
+// Content returns the pending Mive transactions, grouped by sender and keyed by
+// the Mive nonces they will be executed with if included in order.
+func (api *API) Content() map[string]map[string]map[string]*miveapi.RPCTransaction {
+	content := map[string]map[string]map[string]*miveapi.RPCTransaction{
+		"pending": make(map[string]map[string]*miveapi.RPCTransaction),
+		"queued":  make(map[string]map[string]*miveapi.RPCTransaction),
+	}
+	for account, txs := range api.pool.Content() {
+		dump := make(map[string]*miveapi.RPCTransaction, len(txs))
+		for _, tx := range txs {
+			dump[fmt.Sprintf("%d", tx.Nonce)] = miveapi.NewRPCPendingTransaction(tx)
+		}
+		content["pending"][account.Hex()] = dump
+	}
+	return content
+}
+
+// ContentFrom returns the pending Mive transactions of the given sender, keyed
+// by the Mive nonces they will be executed with if included in order.
+func (api *API) ContentFrom(addr common.Address) map[string]map[string]*miveapi.RPCTransaction {
+	var (
+		txs  = api.pool.ContentFrom(addr)
+		dump = make(map[string]*miveapi.RPCTransaction, len(txs))
+	)
+	for _, tx := range txs {
+		dump[fmt.Sprintf("%d", tx.Nonce)] = miveapi.NewRPCPendingTransaction(tx)
+	}
+	return map[string]map[string]*miveapi.RPCTransaction{
+		"pending": dump,
+		"queued":  make(map[string]*miveapi.RPCTransaction),
+	}
+}
+
+// Status returns the number of pending and queued Mive transactions.
+func (api *API) Status() map[string]hexutil.Uint {
+	return map[string]hexutil.Uint{
+		"pending": hexutil.Uint(api.pool.Stats()),
+		"queued":  0,
+	}
+}
@@ -0,0 +1,206 @@
+package txpool
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+	"golang.org/x/exp/slices"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+)
+
+// Trust is the extent to which the view of the L1 pool is trusted to predict
+// the content of the next L1 block when building the pending Mive block.
+type Trust string
+
+const (
+	// TrustNone builds no pending block, the pending state is the one of the
+	// head of the chain.
+	TrustNone Trust = "none"
+
+	// TrustPriced only includes the pending transactions which the next L1
+	// block could include: the ones paying at least its base fee, with no
+	// nonce gap or underpriced transaction of the same sender before them.
+	TrustPriced Trust = "priced"
+
+	// TrustAll includes all the pending transactions, as if the next L1 block
+	// included the entire L1 pool.
+	TrustAll Trust = "all"
+)
+
+// l1BlockTime is the expected interval between two L1 blocks, used to predict
+// the timestamp of the next one.
+const l1BlockTime = 12
+
+// pendingBlock is a speculative Mive block, derived from a predicted L1 block
+// on top of the head of the chain which includes the pending transactions.
+type pendingBlock struct {
+	head    common.Hash // Head of the chain the block was built on
+	version uint64      // Version of the pending transactions the block was built from
+
+	l1       *types.Header // Predicted L1 block header
+	block    *mivetypes.Block
+	receipts types.Receipts
+	state    *state.StateDB
+}
+
+// Pending returns the pending Mive block along with its receipts and state, and
+// the header of the predicted L1 block it was derived from. The block is built
+// on demand, only if the chain head or the pending transactions changed since
+// the last build. Nil values are returned if the pool is configured not to
+// build any pending block.
+func (pool *TxPool) Pending() (*mivetypes.Block, types.Receipts, *state.StateDB, *types.Header, error) {
+	if pool.config.PendingTrust == TrustNone {
+		return nil, nil, nil, nil, nil
+	}
+	pool.buildMu.Lock()
+	defer pool.buildMu.Unlock()
+
+	head := pool.chain.CurrentBlock()
+
+	pool.mu.RLock()
+	version := pool.version
+	pool.mu.RUnlock()
+
+	if built := pool.built; built == nil || built.head != head.Hash || built.version != version {
+		built, err := pool.build(head, version)
+		if err != nil {
+			return nil, nil, nil, nil, err
+		}
+		pool.built = built
+	}
+	built := pool.built
+	return built.block, built.receipts, built.state.Copy(), built.l1, nil
+}
+
+// build derives the pending Mive block on top of the given head from the
+// pending transactions trusted to be included in the next L1 block.
+func (pool *TxPool) build(head *mivetypes.Header, version uint64) (*pendingBlock, error) {
+	var (
+		chain  = pool.chain
+		config = chain.Config()
+	)
+	parent, err := chain.EthGetHeader(head.Hash, head.NumberU64())
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve L1 header of block #%d: %w", head.NumberU64(), err)
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Coinbase:   parent.Coinbase,
+		Difficulty: new(big.Int),
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time + l1BlockTime,
+		MixDigest:  parent.MixDigest,
+	}
+	if parent.BaseFee != nil {
+		header.BaseFee = eip1559.CalcBaseFee(config.Eth, parent)
+	}
+	if parent.ExcessBlobGas != nil && parent.BlobGasUsed != nil {
+		excessBlobGas := eip4844.CalcExcessBlobGas(*parent.ExcessBlobGas, *parent.BlobGasUsed)
+		header.ExcessBlobGas = &excessBlobGas
+		header.BlobGasUsed = new(uint64)
+	}
+	statedb, err := chain.StateAt(head.Root)
+	if err != nil {
+		return nil, err
+	}
+	l1Block := types.NewBlockWithHeader(header).WithBody(pool.trusted(header.BaseFee), nil)
+	txs, _, receipts, _, usedGas, err := chain.Processor().Process(l1Block, statedb, *chain.GetVMConfig())
+	if err != nil {
+		return nil, err
+	}
+	baseFee, err := mivecore.CalcBaseFee(config, head, header)
+	if err != nil {
+		return nil, err
+	}
+	miveHeader := &mivetypes.Header{
+		ParentHash:      l1Block.ParentHash(),
+		Hash:            l1Block.Hash(),
+		Number:          l1Block.Number(),
+		Time:            l1Block.Time(),
+		Root:            statedb.IntermediateRoot(config.Eth.IsEIP158(l1Block.Number())),
+		ReceiptHash:     types.DeriveSha(receipts, trie.NewStackTrie(nil)),
+		Bloom:           types.CreateBloom(receipts),
+		GasUsed:         usedGas,
+		WithdrawalsHash: mivecore.WithdrawalsRoot(statedb, l1Block.Number(), l1Block.Time(), config),
+		BaseFee:         baseFee,
+	}
+	return &pendingBlock{
+		head:     head.Hash,
+		version:  version,
+		l1:       header,
+		block:    mivetypes.NewBlock(miveHeader, &mivetypes.Body{Transactions: txs}),
+		receipts: receipts,
+		state:    statedb,
+	}, nil
+}
+
+// trusted returns the L1 transactions of the pending Mive transactions trusted
+// to be included in an L1 block with the given base fee. The transactions of a
+// sender are ordered by nonce, the senders are interleaved in the order their
+// transactions were observed.
+func (pool *TxPool) trusted(baseFee *big.Int) []*types.Transaction {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	var included []*pendingTx
+	for _, ptxs := range pool.pending {
+		sorted := make([]*pendingTx, 0, len(ptxs))
+		for _, ptx := range ptxs {
+			sorted = append(sorted, ptx)
+		}
+		slices.SortFunc(sorted, byNonce)
+		for i, ptx := range sorted {
+			if pool.config.PendingTrust == TrustPriced {
+				if i > 0 && ptx.nonce != sorted[i-1].nonce+1 {
+					break
+				}
+				if baseFee != nil && ptx.l1.GasFeeCapIntCmp(baseFee) < 0 {
+					break
+				}
+			}
+			included = append(included, ptx)
+		}
+	}
+	// Order the transactions by observation time, then restore the nonce order
+	// of the transactions of each sender within the positions they occupy.
+	slices.SortStableFunc(included, func(a, b *pendingTx) int {
+		return a.time.Compare(b.time)
+	})
+	var (
+		positions = make(map[common.Address][]int)
+		bySender  = make(map[common.Address][]*pendingTx)
+	)
+	for i, ptx := range included {
+		positions[ptx.from] = append(positions[ptx.from], i)
+		bySender[ptx.from] = append(bySender[ptx.from], ptx)
+	}
+	txs := make([]*types.Transaction, len(included))
+	for from, ptxs := range bySender {
+		slices.SortFunc(ptxs, byNonce)
+		for i, pos := range positions[from] {
+			txs[pos] = ptxs[i].l1
+		}
+	}
+	return txs
+}
+
+// byNonce orders pending transactions by the nonce of their L1 transaction.
+func byNonce(a, b *pendingTx) int {
+	switch {
+	case a.nonce < b.nonce:
+		return -1
+	case a.nonce > b.nonce:
+		return 1
+	default:
+		return 0
+	}
+}
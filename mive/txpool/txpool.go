@@ -0,0 +1,489 @@
+// Package txpool implements an observed pool of the pending Mive transactions,
+// i.e. the beacon transactions waiting in the pending pool of L1.
+package txpool
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"golang.org/x/exp/slices"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	miveethclient "github.com/ethereum-mive/mive/ethclient"
+)
+
+const (
+	// chainEventChanSize is the size of channel listening to ChainEvent.
+	chainEventChanSize = 10
+
+	// pendingChanSize is the size of channel receiving the pending transactions
+	// of the L1 subscription.
+	pendingChanSize = 256
+)
+
+// Config contains the settings of the observed pool.
+type Config struct {
+	Lifetime     time.Duration // Maximum amount of time a pending transaction is kept
+	PollInterval time.Duration // Interval between polls of the L1 pool if subscriptions are unsupported
+	GlobalSlots  uint64        // Maximum number of pending transactions kept
+	PendingTrust Trust         // Trust in the L1 pool for building the pending block
+}
+
+// DefaultConfig contains the default settings of the observed pool.
+var DefaultConfig = Config{
+	Lifetime:     3 * time.Hour,
+	PollInterval: 4 * time.Second,
+	GlobalSlots:  4096,
+	PendingTrust: TrustPriced,
+}
+
+// sanitize checks the provided user configurations and changes anything that's
+// unreasonable or unworkable.
+func (config *Config) sanitize() Config {
+	conf := *config
+	if conf.Lifetime <= 0 {
+		log.Warn("Sanitizing invalid txpool lifetime", "provided", conf.Lifetime, "updated", DefaultConfig.Lifetime)
+		conf.Lifetime = DefaultConfig.Lifetime
+	}
+	if conf.PollInterval <= 0 {
+		log.Warn("Sanitizing invalid txpool poll interval", "provided", conf.PollInterval, "updated", DefaultConfig.PollInterval)
+		conf.PollInterval = DefaultConfig.PollInterval
+	}
+	if conf.GlobalSlots < 1 {
+		log.Warn("Sanitizing invalid txpool global slots", "provided", conf.GlobalSlots, "updated", DefaultConfig.GlobalSlots)
+		conf.GlobalSlots = DefaultConfig.GlobalSlots
+	}
+	switch conf.PendingTrust {
+	case TrustNone, TrustPriced, TrustAll:
+	default:
+		log.Warn("Sanitizing invalid txpool pending trust", "provided", conf.PendingTrust, "updated", DefaultConfig.PendingTrust)
+		conf.PendingTrust = DefaultConfig.PendingTrust
+	}
+	return conf
+}
+
+// pendingTx is a pending beacon transaction along with the Mive transactions it
+// carries, more than one for a batch.
+type pendingTx struct {
+	txs   []*mivetypes.Transaction // Pending Mive transactions, their nonces are assigned on retrieval
+	l1    *types.Transaction       // Wrapping L1 transaction
+	from  common.Address           // Sender of the wrapping L1 transaction
+	nonce uint64                   // Nonce of the wrapping L1 transaction
+	time  time.Time                // Time the transaction was first observed
+}
+
+// TxPool keeps track of the pending Mive transactions, decoded from the beacon
+// transactions in the pending pool of L1. The pool is only observed: the Mive
+// transactions are executed once their L1 transactions are included, in the
+// order of the L1 block, so the pool has no say in their inclusion.
+type TxPool struct {
+	config Config
+	chain  *mivecore.BlockChain
+	client *miveethclient.Client
+	signer types.Signer
+
+	mu      sync.RWMutex
+	pending map[common.Address]map[uint64]*pendingTx // Pending transactions by sender and L1 nonce
+	all     map[common.Hash]*pendingTx               // Pending transactions by L1 hash
+	version uint64                                   // Incremented on every change of the pending transactions
+
+	buildMu sync.Mutex    // Serializes the builds of the pending block
+	built   *pendingBlock // Last built pending block, nil if none
+
+	txFeed event.Feed
+	scope  event.SubscriptionScope
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// New creates a pool observing the pending pool of the given L1 endpoints.
+func New(config Config, chain *mivecore.BlockChain, client *miveethclient.Client) *TxPool {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &TxPool{
+		config:  config.sanitize(),
+		chain:   chain,
+		client:  client,
+		signer:  types.LatestSigner(chain.Config().Eth),
+		pending: make(map[common.Address]map[uint64]*pendingTx),
+		all:     make(map[common.Hash]*pendingTx),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Start launches the observation of the L1 pool and the maintenance of the
+// pending transactions.
+func (pool *TxPool) Start() {
+	pool.wg.Add(2)
+	go pool.observe()
+	go pool.loop()
+}
+
+// Stop terminates the observation of the L1 pool.
+func (pool *TxPool) Stop() {
+	pool.cancel()
+	pool.scope.Close()
+	pool.wg.Wait()
+	log.Info("Transaction pool stopped")
+}
+
+// observe follows the pending pool of L1, through a subscription if an
+// endpoint supports it, by polling it otherwise.
+func (pool *TxPool) observe() {
+	defer pool.wg.Done()
+
+	polling := false
+	for {
+		if err := pool.subscribe(); err != nil {
+			if !polling {
+				log.Info("Polling L1 pending transactions", "interval", pool.config.PollInterval, "reason", err)
+				polling = true
+			}
+			if err := pool.poll(); err != nil && pool.ctx.Err() == nil {
+				log.Debug("Failed to poll L1 pending transactions", "err", err)
+			}
+		} else {
+			polling = false
+		}
+		select {
+		case <-time.After(pool.config.PollInterval):
+		case <-pool.ctx.Done():
+			return
+		}
+	}
+}
+
+// subscribe subscribes to the pending transactions of L1 and adds them to the
+// pool until the subscription fails. An error is returned if the subscription
+// can't be established.
+func (pool *TxPool) subscribe() error {
+	ch := make(chan *types.Transaction, pendingChanSize)
+	sub, err := pool.client.SubscribePendingTransactions(pool.ctx, ch)
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	log.Info("Subscribed to L1 pending transactions")
+	for {
+		select {
+		case tx := <-ch:
+			pool.add([]*types.Transaction{tx})
+		case err := <-sub.Err():
+			log.Warn("L1 pending transactions subscription failed", "err", err)
+			return nil
+		case <-pool.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// poll retrieves the content of the pending pool of L1 and synchronizes the
+// pool with it: new transactions are added and the ones that left the L1 pool
+// (included or dropped) are removed.
+func (pool *TxPool) poll() error {
+	txs, err := pool.client.PendingTransactions(pool.ctx)
+	if err != nil {
+		return err
+	}
+	pool.add(txs)
+
+	current := make(map[common.Hash]bool, len(txs))
+	for _, tx := range txs {
+		current[tx.Hash()] = true
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for hash, ptx := range pool.all {
+		if !current[hash] {
+			pool.remove(ptx)
+		}
+	}
+	return nil
+}
+
+// loop removes the pending transactions included in the derived blocks, along
+// with the ones they replaced, and evicts the transactions that remained
+// pending for too long.
+func (pool *TxPool) loop() {
+	defer pool.wg.Done()
+
+	var (
+		chainCh = make(chan core.ChainEvent, chainEventChanSize)
+		sub     = pool.chain.SubscribeChainEvent(chainCh)
+		evict   = time.NewTicker(time.Minute)
+	)
+	defer sub.Unsubscribe()
+	defer evict.Stop()
+
+	for {
+		select {
+		case ev := <-chainCh:
+			pool.removeIncluded(ev.Block)
+
+		case <-evict.C:
+			pool.mu.Lock()
+			for _, ptx := range pool.all {
+				if time.Since(ptx.time) > pool.config.Lifetime {
+					pool.remove(ptx)
+				}
+			}
+			pool.mu.Unlock()
+
+		case <-sub.Err():
+			return
+		case <-pool.ctx.Done():
+			return
+		}
+	}
+}
+
+// add decodes the given L1 transactions into pending Mive transactions and adds
+// them to the pool, replacing the ones with the same sender and L1 nonce. The
+// L1 transactions not sent to the beacon address or with a malformed payload
+// are ignored.
+func (pool *TxPool) add(txs []*types.Transaction) {
+	var (
+		config = pool.chain.Config()
+		next   = new(big.Int).Add(pool.chain.CurrentBlock().Number, common.Big1)
+	)
+	pool.mu.Lock()
+	var added []*pendingTx
+	for _, tx := range txs {
+		if pool.all[tx.Hash()] != nil {
+			continue
+		}
+		msgs, err := mivecore.TransactionToMessages(tx, next, pool.signer, nil, config)
+		if err != nil || len(msgs) == 0 {
+			continue
+		}
+		from, _ := types.Sender(pool.signer, tx)
+		ptx := &pendingTx{
+			txs:   make([]*mivetypes.Transaction, len(msgs)),
+			l1:    tx,
+			from:  from,
+			nonce: tx.Nonce(),
+			time:  time.Now(),
+		}
+		for i, msg := range msgs {
+			ptx.txs[i] = &mivetypes.Transaction{
+				Tx: mivetypes.Tx{
+					Gas:        msg.GasLimit,
+					To:         msg.To,
+					Value:      msg.Value,
+					Data:       msg.Data,
+					AccessList: msg.AccessList,
+				},
+				Origin:     tx.Hash(),
+				From:       msg.From,
+				GasPrice:   msg.GasPrice,
+				GasTipCap:  msg.GasTipCap,
+				GasFeeCap:  msg.GasFeeCap,
+				BatchIndex: uint64(i),
+			}
+			if msg.From != from {
+				// Signed transactions carry their own Mive nonce
+				ptx.txs[i].Nonce = msg.Nonce
+				ptx.txs[i].Sponsor = from
+			}
+		}
+		if old := pool.pending[ptx.from][ptx.nonce]; old != nil {
+			pool.remove(old)
+		} else if uint64(len(pool.all)) >= pool.config.GlobalSlots {
+			log.Trace("Discarding pending transaction, pool is full", "hash", tx.Hash())
+			continue
+		}
+		if pool.pending[ptx.from] == nil {
+			pool.pending[ptx.from] = make(map[uint64]*pendingTx)
+		}
+		pool.pending[ptx.from][ptx.nonce] = ptx
+		pool.all[tx.Hash()] = ptx
+		pool.version++
+		added = append(added, ptx)
+	}
+	var event []*mivetypes.Transaction
+	if len(added) > 0 {
+		nonces := pool.nonces()
+		for _, ptx := range added {
+			event = append(event, pool.withNonces(ptx, nonces)...)
+		}
+	}
+	pool.mu.Unlock()
+
+	if len(event) > 0 {
+		pool.txFeed.Send(mivecore.NewPendingTxsEvent{Txs: event})
+	}
+}
+
+// removeIncluded removes the pending transactions included in the given L1
+// block, and the ones with the same sender and a lower or equal L1 nonce, which
+// can no longer be included.
+func (pool *TxPool) removeIncluded(block *types.Block) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	for _, tx := range block.Transactions() {
+		if ptx := pool.all[tx.Hash()]; ptx != nil {
+			pool.remove(ptx)
+		}
+		from, err := types.Sender(pool.signer, tx)
+		if err != nil {
+			continue
+		}
+		for nonce, ptx := range pool.pending[from] {
+			if nonce <= tx.Nonce() {
+				pool.remove(ptx)
+			}
+		}
+	}
+}
+
+// remove drops a pending transaction from the pool. The caller must hold the
+// pool lock.
+func (pool *TxPool) remove(ptx *pendingTx) {
+	delete(pool.all, ptx.l1.Hash())
+	pool.version++
+
+	delete(pool.pending[ptx.from], ptx.nonce)
+	if len(pool.pending[ptx.from]) == 0 {
+		delete(pool.pending, ptx.from)
+	}
+}
+
+// nonces returns a function assigning the pending transactions of a sender the
+// Mive nonce of the first transaction they carry, as executed if included in
+// the order of their L1 nonces, on top of the nonce of the sender at the head
+// of the chain. The caller must hold the pool lock.
+func (pool *TxPool) nonces() func(ptx *pendingTx) uint64 {
+	statedb, err := pool.chain.State()
+	if err != nil {
+		log.Debug("Failed to retrieve head state for pending nonces", "err", err)
+	}
+	return func(ptx *pendingTx) uint64 {
+		var nonce uint64
+		if statedb != nil {
+			nonce = statedb.GetNonce(ptx.from)
+		}
+		for l1Nonce, prev := range pool.pending[ptx.from] {
+			if l1Nonce < ptx.nonce {
+				nonce += uint64(unsigned(prev.txs))
+			}
+		}
+		return nonce
+	}
+}
+
+// withNonces returns copies of the Mive transactions of the given pending
+// transaction with their Mive nonces assigned. Signed transactions keep the
+// nonce they carry and don't consume one of the L1 sender.
+func (pool *TxPool) withNonces(ptx *pendingTx, nonces func(*pendingTx) uint64) []*mivetypes.Transaction {
+	var (
+		nonce = nonces(ptx)
+		txs   = make([]*mivetypes.Transaction, len(ptx.txs))
+	)
+	for i, tx := range ptx.txs {
+		cpy := *tx
+		if tx.Sponsor == (common.Address{}) {
+			cpy.Nonce = nonce
+			nonce++
+		}
+		txs[i] = &cpy
+	}
+	return txs
+}
+
+// unsigned returns the number of the given transactions executing as the sender
+// of their wrapping L1 transaction.
+func unsigned(txs []*mivetypes.Transaction) int {
+	var n int
+	for _, tx := range txs {
+		if tx.Sponsor == (common.Address{}) {
+			n++
+		}
+	}
+	return n
+}
+
+// Content retrieves the pending Mive transactions, grouped by sender and sorted
+// by the L1 nonce of the wrapping transactions.
+func (pool *TxPool) Content() map[common.Address][]*mivetypes.Transaction {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	content := make(map[common.Address][]*mivetypes.Transaction, len(pool.pending))
+	nonces := pool.nonces()
+	for from, ptxs := range pool.pending {
+		content[from] = pool.sorted(ptxs, nonces)
+	}
+	return content
+}
+
+// ContentFrom retrieves the pending Mive transactions of the given sender,
+// sorted by the L1 nonce of the wrapping transactions.
+func (pool *TxPool) ContentFrom(addr common.Address) []*mivetypes.Transaction {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	return pool.sorted(pool.pending[addr], pool.nonces())
+}
+
+// sorted returns the given pending transactions of a sender sorted by their L1
+// nonce, with their Mive nonce assigned. The caller must hold the pool lock.
+func (pool *TxPool) sorted(ptxs map[uint64]*pendingTx, nonces func(*pendingTx) uint64) []*mivetypes.Transaction {
+	txs := make([]*mivetypes.Transaction, 0, len(ptxs))
+	for _, ptx := range ptxs {
+		txs = append(txs, pool.withNonces(ptx, nonces)...)
+	}
+	slices.SortFunc(txs, func(a, b *mivetypes.Transaction) int {
+		switch {
+		case a.Nonce < b.Nonce:
+			return -1
+		case a.Nonce > b.Nonce:
+			return 1
+		default:
+			return 0
+		}
+	})
+	return txs
+}
+
+// Get returns the pending Mive transactions wrapped in the L1 transaction with
+// the given hash, or nil if it is not pending.
+func (pool *TxPool) Get(l1TxHash common.Hash) []*mivetypes.Transaction {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	ptx := pool.all[l1TxHash]
+	if ptx == nil {
+		return nil
+	}
+	return pool.withNonces(ptx, pool.nonces())
+}
+
+// Stats returns the number of pending Mive transactions.
+func (pool *TxPool) Stats() int {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+
+	var count int
+	for _, ptx := range pool.all {
+		count += len(ptx.txs)
+	}
+	return count
+}
+
+// SubscribeNewPendingTxsEvent registers a subscription of NewPendingTxsEvent.
+func (pool *TxPool) SubscribeNewPendingTxsEvent(ch chan<- mivecore.NewPendingTxsEvent) event.Subscription {
+	return pool.scope.Track(pool.txFeed.Subscribe(ch))
+}
@@ -0,0 +1,100 @@
+package mive
+
+import (
+	"context"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/accounts/usbwallet"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+
+	miveethclient "github.com/ethereum-mive/mive/ethclient"
+)
+
+// walletEventChanSize is the size of the channel receiving wallet events.
+const walletEventChanSize = 16
+
+// walletOpener opens the wallets of the account manager as they appear, and
+// discovers the L1 accounts of the hardware ones, so that they can sign the
+// beacon transactions of the relayer and of eth_sendTransaction.
+type walletOpener struct {
+	manager *accounts.Manager
+	client  *miveethclient.Client // L1 state reader to discover the used accounts with
+
+	events chan accounts.WalletEvent
+	sub    event.Subscription
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// newWalletOpener creates a wallet opener for the given account manager.
+func newWalletOpener(manager *accounts.Manager, client *miveethclient.Client) *walletOpener {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &walletOpener{
+		manager: manager,
+		client:  client,
+		events:  make(chan accounts.WalletEvent, walletEventChanSize),
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// start opens the wallets already attached and launches the event loop.
+func (o *walletOpener) start() {
+	o.sub = o.manager.Subscribe(o.events)
+
+	for _, wallet := range o.manager.Wallets() {
+		if err := wallet.Open(""); err != nil {
+			log.Warn("Failed to open wallet", "url", wallet.URL(), "err", err)
+		}
+	}
+	o.wg.Add(1)
+	go o.loop()
+}
+
+// stop terminates the event loop.
+func (o *walletOpener) stop() {
+	o.cancel()
+	o.wg.Wait()
+}
+
+// loop opens the arriving wallets and derives the accounts of the opened ones,
+// until stopped.
+func (o *walletOpener) loop() {
+	defer o.wg.Done()
+	defer o.sub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-o.events:
+			switch ev.Kind {
+			case accounts.WalletArrived:
+				if err := ev.Wallet.Open(""); err != nil {
+					log.Warn("New wallet appeared, failed to open", "url", ev.Wallet.URL(), "err", err)
+				}
+			case accounts.WalletOpened:
+				status, _ := ev.Wallet.Status()
+				log.Info("New wallet appeared", "url", ev.Wallet.URL(), "status", status)
+
+				var derivationPaths []accounts.DerivationPath
+				if ev.Wallet.URL().Scheme == usbwallet.LedgerScheme {
+					derivationPaths = append(derivationPaths, accounts.LegacyLedgerBaseDerivationPath)
+				}
+				derivationPaths = append(derivationPaths, accounts.DefaultBaseDerivationPath)
+
+				ev.Wallet.SelfDerive(derivationPaths, o.client)
+
+			case accounts.WalletDropped:
+				log.Info("Old wallet dropped", "url", ev.Wallet.URL())
+				ev.Wallet.Close()
+			}
+		case <-o.sub.Err():
+			return
+		case <-o.ctx.Done():
+			return
+		}
+	}
+}
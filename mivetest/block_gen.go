@@ -0,0 +1,118 @@
+package mivetest
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/mive/devnet"
+)
+
+// depositData is the layout of the data of the deposit events.
+var depositData abi.Arguments
+
+func init() {
+	for _, t := range []string{"uint256", "uint256", "uint64", "bool", "bytes"} {
+		typ, err := abi.NewType(t, "", nil)
+		if err != nil {
+			panic(err)
+		}
+		depositData = append(depositData, abi.Argument{Type: typ})
+	}
+}
+
+// BlockGen creates an L1 block, extending core.BlockGen with helpers to add the
+// beacon transactions carrying Mive transactions.
+type BlockGen struct {
+	*core.BlockGen
+	c *Chain
+}
+
+// AddMiveTxs adds a beacon transaction sent by the given key to the beacon
+// address, carrying the given Mive transactions. A single transaction is sent
+// as is, several ones as a batch. The Mive transactions execute as their signer
+// if signed, as the key's account otherwise.
+func (b *BlockGen) AddMiveTxs(key *ecdsa.PrivateKey, txs ...*mivetypes.Tx) *types.Transaction {
+	b.c.t.Helper()
+
+	payload, err := mivetypes.EncodePayload(txs...)
+	if err != nil {
+		b.c.t.Fatalf("failed to encode Mive payload: %v", err)
+	}
+	return b.AddPayload(key, b.c.chain.Config().Mive.BeaconAddress, payload)
+}
+
+// AddBeaconCall adds an L1 transaction sent by the given key to the beacon
+// contract of the development network, which emits the Mive transactions as
+// a beacon event.
+func (b *BlockGen) AddBeaconCall(key *ecdsa.PrivateKey, txs ...*mivetypes.Tx) *types.Transaction {
+	b.c.t.Helper()
+
+	payload, err := mivetypes.EncodePayload(txs...)
+	if err != nil {
+		b.c.t.Fatalf("failed to encode Mive payload: %v", err)
+	}
+	return b.AddPayload(key, devnet.BeaconContract, payload)
+}
+
+// AddDeposit adds an L1 transaction sent by the given key to PortalContract,
+// emitting the given deposit. The sender of the deposit is the key's account,
+// its From field is ignored.
+func (b *BlockGen) AddDeposit(key *ecdsa.PrivateKey, deposit *mivetypes.Deposit) *types.Transaction {
+	b.c.t.Helper()
+
+	var to common.Address
+	if deposit.To != nil {
+		to = *deposit.To
+	}
+	mint, value := deposit.Mint, deposit.Value
+	if mint == nil {
+		mint = new(big.Int)
+	}
+	if value == nil {
+		value = new(big.Int)
+	}
+	data, err := depositData.Pack(mint, value, deposit.Gas, deposit.To == nil, deposit.Data)
+	if err != nil {
+		b.c.t.Fatalf("failed to encode deposit: %v", err)
+	}
+	return b.AddPayload(key, PortalContract, append(common.LeftPadBytes(to[:], 32), data...))
+}
+
+// AddPayload adds an L1 transaction sent by the given key to the given address
+// with the given payload as data, e.g. to include malformed payloads. The gas
+// limit covers the execution of the beacon and portal contracts.
+func (b *BlockGen) AddPayload(key *ecdsa.PrivateKey, to common.Address, payload []byte) *types.Transaction {
+	b.c.t.Helper()
+
+	gas, err := core.IntrinsicGas(payload, nil, false, true, true, true)
+	if err != nil {
+		b.c.t.Fatalf("failed to compute intrinsic gas: %v", err)
+	}
+	if to == devnet.BeaconContract || to == PortalContract {
+		// Copying the payload to memory and logging it
+		words := uint64(len(payload)+64+31) / 32
+		gas += 10_000 + 270*words + words*words/512
+	}
+	feeCap := new(big.Int).Add(new(big.Int).Mul(b.BaseFee(), common.Big2), l1GasTipCap)
+	tx, err := types.SignNewTx(key, types.LatestSigner(b.c.l1Config), &types.DynamicFeeTx{
+		ChainID:   b.c.l1Config.ChainID,
+		Nonce:     b.TxNonce(crypto.PubkeyToAddress(key.PublicKey)),
+		GasTipCap: l1GasTipCap,
+		GasFeeCap: feeCap,
+		Gas:       gas,
+		To:        &to,
+		Data:      payload,
+	})
+	if err != nil {
+		b.c.t.Fatalf("failed to sign L1 transaction: %v", err)
+	}
+	b.AddTx(tx)
+	return tx
+}
@@ -0,0 +1,268 @@
+// Package mivetest builds L1 chains carrying Mive transactions in memory and
+// derives the Mive chain from them, so that the derivation pipeline can be
+// exercised end-to-end in Go tests, without any L1 endpoint or node.
+package mivetest
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+
+	mivebeacon "github.com/ethereum-mive/mive/consensus/beacon"
+	mivecore "github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/mive/devnet"
+)
+
+// l1GasLimit is the gas limit of the generated L1 blocks.
+const l1GasLimit = 30_000_000
+
+var (
+	// Key is the key of the account funded on L1 and, with the default genesis,
+	// on Mive.
+	Key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+
+	// Address is the address of Key.
+	Address = crypto.PubkeyToAddress(Key.PublicKey)
+
+	// PortalContract is the address of a portal contract deployed on the L1
+	// chain, emitting the deposits sent to it by BlockGen.AddDeposit. It has to
+	// be configured in the Mive genesis for the deposits to be executed.
+	PortalContract = common.HexToAddress("0x000000000000000000000000000000000000d3b0")
+
+	// portalCode is the code of the portal contract, emitting a deposit event
+	// from the caller to the recipient in the first word of the call data, with
+	// the rest of the call data as the event data.
+	portalCode = bytes.Join([][]byte{
+		common.FromHex("0x60203603602060003760003533"),          // calldatacopy(0, 32, calldatasize-32), push to and caller
+		append([]byte{0x7f}, mivetypes.DepositEventTopic[:]...), // push32 topic
+		common.FromHex("0x602036036000a300"),                    // log3(0, calldatasize-32, topic, caller, to)
+	}, nil)
+
+	// l1GasTipCap is the priority fee per gas of the generated beacon
+	// transactions.
+	l1GasTipCap = big.NewInt(params.GWei)
+)
+
+// Chain is an L1 chain built in memory along with the Mive chain derived from
+// it. The L1 genesis funds Key and deploys the beacon contract of the
+// development network, devnet.BeaconContract, and the portal contract.
+type Chain struct {
+	t testing.TB
+
+	l1       *L1
+	l1Config *params.ChainConfig
+	l1Engine consensus.Engine
+	l1DB     ethdb.Database // Database holding the L1 states blocks are generated on
+
+	db     ethdb.Database
+	chain  *mivecore.BlockChain
+	signer mivetypes.Signer
+}
+
+// New creates an L1 chain and the Mive chain derived from it with the given
+// genesis, or devnet.Genesis(Address) if nil. If the Mive chain doesn't start
+// at the L1 genesis, empty L1 blocks are generated up to its genesis block. The
+// chains are stopped once the test finishes.
+func New(t testing.TB, genesis *mivecore.Genesis) *Chain {
+	t.Helper()
+
+	if genesis == nil {
+		genesis = devnet.Genesis(Address)
+	}
+	l1Genesis := devnet.L1Genesis(devnet.Config{Developer: Address, GasLimit: l1GasLimit})
+	l1Genesis.Alloc[PortalContract] = core.GenesisAccount{Code: portalCode, Balance: new(big.Int)}
+	l1DB := rawdb.NewMemoryDatabase()
+	triedb := trie.NewDatabase(l1DB, trie.HashDefaults)
+	defer triedb.Close()
+
+	c := &Chain{
+		t:        t,
+		l1:       newL1(l1Genesis.MustCommit(l1DB, triedb)),
+		l1Config: l1Genesis.Config,
+		l1Engine: beacon.New(ethash.NewFaker()),
+		l1DB:     l1DB,
+		db:       rawdb.NewMemoryDatabase(),
+		signer:   mivetypes.NewSigner(l1Genesis.Config.ChainID, genesis.Config.Mive.BeaconAddress),
+	}
+	if n := genesis.Config.Mive.GenesisBlock.Uint64(); n > 0 {
+		c.Generate(int(n), nil)
+	}
+	cacheConfig := core.DefaultCacheConfigWithScheme(rawdb.HashScheme)
+	chain, err := mivecore.NewBlockChain(c.db, cacheConfig, genesis, nil, mivebeacon.New(c.l1, false), vm.Config{}, c.l1, nil)
+	if err != nil {
+		t.Fatalf("failed to create Mive chain: %v", err)
+	}
+	c.chain = chain
+	t.Cleanup(chain.Stop)
+
+	return c
+}
+
+// L1 returns the L1 chain.
+func (c *Chain) L1() *L1 {
+	return c.l1
+}
+
+// BlockChain returns the Mive chain.
+func (c *Chain) BlockChain() *mivecore.BlockChain {
+	return c.chain
+}
+
+// Database returns the database of the Mive chain.
+func (c *Chain) Database() ethdb.Database {
+	return c.db
+}
+
+// Generate generates n L1 blocks on top of the L1 head, making them canonical
+// without deriving them. The gen function is called for every block with its
+// index to fill it, it may be nil.
+func (c *Chain) Generate(n int, gen func(int, *BlockGen)) []*types.Block {
+	return c.GenerateFrom(c.l1.Head(), n, gen)
+}
+
+// GenerateFrom generates n L1 blocks on top of the given parent like Generate,
+// reorganising the L1 chain if the parent isn't the head.
+func (c *Chain) GenerateFrom(parent *types.Block, n int, gen func(int, *BlockGen)) []*types.Block {
+	blocks, receipts := core.GenerateChain(c.l1Config, parent, c.l1Engine, c.l1DB, n, func(i int, b *core.BlockGen) {
+		if gen != nil {
+			gen(i, &BlockGen{BlockGen: b, c: c})
+		}
+	})
+	if n > 0 {
+		c.l1.insert(blocks, receipts)
+	}
+	return blocks
+}
+
+// Derive derives the Mive blocks of the given L1 blocks, returning the error
+// the insertion failed with, if any.
+func (c *Chain) Derive(blocks ...*types.Block) error {
+	_, err := c.chain.InsertChain(blocks)
+	return err
+}
+
+// Commit generates an L1 block on top of the L1 head, filled by the given gen
+// function if not nil, and derives it. It returns the derived Mive block and
+// fails the test if the derivation fails.
+func (c *Chain) Commit(gen func(*BlockGen)) *mivetypes.Block {
+	c.t.Helper()
+
+	blocks := c.Generate(1, func(_ int, b *BlockGen) {
+		if gen != nil {
+			gen(b)
+		}
+	})
+	if err := c.Derive(blocks...); err != nil {
+		c.t.Fatalf("failed to derive block %d: %v", blocks[0].NumberU64(), err)
+	}
+	return c.chain.GetMiveBlock(blocks[0].Hash(), blocks[0].NumberU64())
+}
+
+// SignTx signs the given Mive transaction with the given key, so that it
+// executes as the key's account whoever includes it. The nonce and fee cap of
+// its authorization have to be set.
+func (c *Chain) SignTx(key *ecdsa.PrivateKey, tx *mivetypes.Tx) *mivetypes.Tx {
+	c.t.Helper()
+
+	signed, err := mivetypes.SignTx(tx, c.signer, key)
+	if err != nil {
+		c.t.Fatalf("failed to sign Mive transaction: %v", err)
+	}
+	return signed
+}
+
+// State returns the state at the head of the Mive chain.
+func (c *Chain) State() *state.StateDB {
+	c.t.Helper()
+
+	statedb, err := c.chain.State()
+	if err != nil {
+		c.t.Fatalf("failed to retrieve Mive state: %v", err)
+	}
+	return statedb
+}
+
+// Receipts returns the receipts of the Mive transactions executed in the Mive
+// block with the given hash, which is the hash of its L1 block.
+func (c *Chain) Receipts(hash common.Hash) types.Receipts {
+	return c.chain.GetReceiptsByHash(hash)
+}
+
+// Rejections returns the records of the beacon transactions rejected in the
+// Mive block with the given hash.
+func (c *Chain) Rejections(hash common.Hash) mivetypes.Rejections {
+	return c.chain.GetRejections(hash)
+}
+
+// AssertBalance checks the balance of the given account at the head of the
+// Mive chain.
+func (c *Chain) AssertBalance(addr common.Address, want *big.Int) {
+	c.t.Helper()
+
+	if have := c.State().GetBalance(addr); have.Cmp(want) != 0 {
+		c.t.Errorf("balance mismatch for %v: have %v, want %v", addr, have, want)
+	}
+}
+
+// AssertNonce checks the nonce of the given account at the head of the Mive
+// chain.
+func (c *Chain) AssertNonce(addr common.Address, want uint64) {
+	c.t.Helper()
+
+	if have := c.State().GetNonce(addr); have != want {
+		c.t.Errorf("nonce mismatch for %v: have %d, want %d", addr, have, want)
+	}
+}
+
+// AssertStorage checks a storage slot of the given account at the head of the
+// Mive chain.
+func (c *Chain) AssertStorage(addr common.Address, key common.Hash, want common.Hash) {
+	c.t.Helper()
+
+	if have := c.State().GetState(addr, key); have != want {
+		c.t.Errorf("storage mismatch for %v at %v: have %v, want %v", addr, key, have, want)
+	}
+}
+
+// AssertReceipts checks the statuses of the receipts of the Mive block with the
+// given hash, in order.
+func (c *Chain) AssertReceipts(hash common.Hash, want ...uint64) {
+	c.t.Helper()
+
+	receipts := c.Receipts(hash)
+	if len(receipts) != len(want) {
+		c.t.Errorf("receipt count mismatch for block %v: have %d, want %d", hash, len(receipts), len(want))
+		return
+	}
+	for i, receipt := range receipts {
+		if receipt.Status != want[i] {
+			c.t.Errorf("receipt %d status mismatch for block %v: have %d, want %d", i, hash, receipt.Status, want[i])
+		}
+	}
+}
+
+// AssertRejections checks the number of beacon transactions rejected in the
+// Mive block with the given hash.
+func (c *Chain) AssertRejections(hash common.Hash, want int) {
+	c.t.Helper()
+
+	if have := c.Rejections(hash); len(have) != want {
+		c.t.Errorf("rejection count mismatch for block %v: have %d, want %d", hash, len(have), want)
+	}
+}
@@ -0,0 +1,180 @@
+package mivetest
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+
+	mivecore "github.com/ethereum-mive/mive/core"
+	mivetypes "github.com/ethereum-mive/mive/core/types"
+	"github.com/ethereum-mive/mive/mive/devnet"
+	miveparams "github.com/ethereum-mive/mive/params"
+)
+
+// Tests that an L1 block carrying every kind of Mive transaction derives the
+// expected Mive block: the deposit first, then the beacon transactions in
+// order, with the signed transaction included ahead of its nonce deferred
+// after the one catching its signer up, and the malformed payload rejected.
+func TestDerive(t *testing.T) {
+	var (
+		otherKey, _ = crypto.HexToECDSA("8a1f9a8f95be41cd7ccb6168179afb4504aefe388d1e14474d32c45c72ce7b7a")
+		other       = crypto.PubkeyToAddress(otherKey.PublicKey)
+
+		depositTo = common.HexToAddress("0x000000000000000000000000000000000000d0d0")
+		a         = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+		b         = common.HexToAddress("0x000000000000000000000000000000000000bbbb")
+
+		feeCap = big.NewInt(100 * params.GWei)
+	)
+	genesis := devnet.Genesis(Address)
+	genesis.Config.Mive.PortalContract = PortalContract
+	genesis.Config.Mive.PortalBlock = new(big.Int)
+	genesis.Config.Mive.NonceOrderingBlock = new(big.Int)
+
+	c := New(t, genesis)
+	block := c.Commit(func(gen *BlockGen) {
+		gen.AddDeposit(Key, &mivetypes.Deposit{
+			To:    &depositTo,
+			Mint:  big.NewInt(5),
+			Value: big.NewInt(5),
+			Gas:   params.TxGas,
+		})
+		gen.AddMiveTxs(Key, &mivetypes.Tx{Gas: params.TxGas, To: &other, Value: big.NewInt(params.Ether)})
+		gen.AddBeaconCall(Key,
+			&mivetypes.Tx{Gas: params.TxGas, To: &a, Value: big.NewInt(100)},
+			&mivetypes.Tx{Gas: params.TxGas, To: &b, Value: big.NewInt(200)},
+		)
+		gen.AddMiveTxs(Key, c.SignTx(otherKey, &mivetypes.Tx{
+			Gas:   params.TxGas,
+			To:    &a,
+			Value: big.NewInt(7),
+			Auth:  &mivetypes.TxAuth{Nonce: 1, GasFeeCap: feeCap},
+		}))
+		gen.AddMiveTxs(Key, c.SignTx(otherKey, &mivetypes.Tx{
+			Gas:   params.TxGas,
+			To:    &b,
+			Value: big.NewInt(3),
+			Auth:  &mivetypes.TxAuth{Nonce: 0, GasFeeCap: feeCap},
+		}))
+		gen.AddPayload(Key, c.BlockChain().Config().Mive.BeaconAddress, []byte{0x7f, 0x01, 0x02})
+	})
+	if block == nil {
+		t.Fatal("derived block not found")
+	}
+	hash := block.Hash()
+
+	c.AssertReceipts(hash, 1, 1, 1, 1, 1, 1)
+	c.AssertRejections(hash, 1)
+
+	c.AssertBalance(depositTo, big.NewInt(5))
+	c.AssertBalance(a, big.NewInt(107))
+	c.AssertBalance(b, big.NewInt(203))
+	c.AssertNonce(other, 2)
+}
+
+// Tests that a block is not derived from L1 receipts that don't match the
+// receipt root of its L1 header, e.g. minting more than was deposited, nor are
+// the receipts kept for the next attempt.
+func TestDeriveForgedDeposit(t *testing.T) {
+	genesis := devnet.Genesis(Address)
+	genesis.Config.Mive.PortalContract = PortalContract
+	genesis.Config.Mive.PortalBlock = new(big.Int)
+
+	var (
+		c      = New(t, genesis)
+		to     = common.HexToAddress("0x000000000000000000000000000000000000d0d0")
+		blocks = c.Generate(1, func(_ int, gen *BlockGen) {
+			gen.AddDeposit(Key, &mivetypes.Deposit{To: &to, Mint: big.NewInt(5), Gas: params.TxGas})
+		})
+		hash     = blocks[0].Hash()
+		receipts = c.l1.receipts[hash]
+	)
+	c.l1.receipts[hash] = forgeLog(receipts, func(data []byte) {
+		data[31] = 0xff // Mint
+	})
+	if err := c.Derive(blocks...); !errors.Is(err, mivecore.ErrInvalidL1Receipts) {
+		t.Fatalf("derivation error mismatch: have %v, want %v", err, mivecore.ErrInvalidL1Receipts)
+	}
+	c.l1.receipts[hash] = receipts
+	if err := c.Derive(blocks...); err != nil {
+		t.Fatalf("failed to derive block: %v", err)
+	}
+	c.AssertBalance(Address, new(big.Int).Add(genesis.Alloc[Address].Balance, big.NewInt(5)))
+}
+
+// Tests that a block is not derived from L1 receipts carrying beacon events that
+// don't match the receipt root of its L1 header.
+func TestDeriveForgedBeaconEvent(t *testing.T) {
+	var (
+		c      = New(t, nil)
+		to     = common.HexToAddress("0x000000000000000000000000000000000000aaaa")
+		blocks = c.Generate(1, func(_ int, gen *BlockGen) {
+			gen.AddBeaconCall(Key, &mivetypes.Tx{Gas: params.TxGas, To: &to, Value: big.NewInt(100)})
+		})
+		hash     = blocks[0].Hash()
+		receipts = c.l1.receipts[hash]
+	)
+	c.l1.receipts[hash] = forgeLog(receipts, func(data []byte) {
+		data[len(data)-1] ^= 0xff
+	})
+	if err := c.Derive(blocks...); !errors.Is(err, mivecore.ErrInvalidL1Receipts) {
+		t.Fatalf("derivation error mismatch: have %v, want %v", err, mivecore.ErrInvalidL1Receipts)
+	}
+	c.l1.receipts[hash] = receipts
+	if err := c.Derive(blocks...); err != nil {
+		t.Fatalf("failed to derive block: %v", err)
+	}
+	c.AssertBalance(to, big.NewInt(100))
+}
+
+// Tests that the Mive precompiles are run as scheduled by the chain of the EVM
+// calling them, whatever the other chains of the process schedule.
+func TestPrecompileSchedules(t *testing.T) {
+	scheduled := devnet.Genesis(Address)
+	scheduled.Config.Mive.Precompiles = map[string]*big.Int{mivecore.L1StatePrecompile: new(big.Int)}
+
+	for _, test := range []struct {
+		genesis *mivecore.Genesis
+		status  uint64
+	}{
+		{scheduled, types.ReceiptStatusFailed},                   // Malformed input
+		{devnet.Genesis(Address), types.ReceiptStatusSuccessful}, // Account without code
+	} {
+		c := New(t, test.genesis)
+		block := c.Commit(func(gen *BlockGen) {
+			gen.AddMiveTxs(Key, &mivetypes.Tx{Gas: 200_000, To: &miveparams.L1StateAddress})
+		})
+		c.AssertReceipts(block.Hash(), test.status)
+	}
+}
+
+// Tests that the Mive precompiles can't be scheduled after the genesis block,
+// as the EVM would reserve their address before their activation.
+func TestPrecompileScheduledAfterGenesis(t *testing.T) {
+	genesis := devnet.Genesis(Address)
+	genesis.Config.Mive.Precompiles = map[string]*big.Int{mivecore.L1StatePrecompile: big.NewInt(1)}
+
+	if err := mivecore.InstallPrecompiles(genesis.Config); err == nil {
+		t.Fatal("precompile scheduled after the genesis block accepted")
+	}
+}
+
+// forgeLog returns a copy of the given receipts with the data of the first log
+// of the first receipt modified by the given function.
+func forgeLog(receipts types.Receipts, forge func([]byte)) types.Receipts {
+	forged := append(types.Receipts{}, receipts...)
+
+	receipt := *forged[0]
+	log := *receipt.Logs[0]
+	log.Data = common.CopyBytes(log.Data)
+	forge(log.Data)
+	receipt.Logs = append([]*types.Log{&log}, receipt.Logs[1:]...)
+	forged[0] = &receipt
+
+	return forged
+}
@@ -0,0 +1,124 @@
+package mivetest
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// L1 is an L1 chain held in memory, serving its blocks and receipts to the Mive
+// chain derived from it in place of the L1 endpoints. It implements
+// mivecore.L1Reader.
+type L1 struct {
+	blocks   map[common.Hash]*types.Block
+	receipts map[common.Hash]types.Receipts
+	canon    []*types.Block // Canonical blocks, indexed by number
+
+	lock sync.RWMutex
+}
+
+// newL1 creates an in-memory L1 chain starting at the given genesis block.
+func newL1(genesis *types.Block) *L1 {
+	return &L1{
+		blocks:   map[common.Hash]*types.Block{genesis.Hash(): genesis},
+		receipts: map[common.Hash]types.Receipts{genesis.Hash(): nil},
+		canon:    []*types.Block{genesis},
+	}
+}
+
+// insert adds the given consecutive blocks and their receipts to the chain,
+// making them canonical. The canonical blocks past the parent of the first one
+// are dropped, so inserting blocks on top of an older block reorganises the
+// chain.
+func (l *L1) insert(blocks []*types.Block, receipts []types.Receipts) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.canon = l.canon[:blocks[0].NumberU64()]
+	for i, block := range blocks {
+		l.blocks[block.Hash()] = block
+		l.receipts[block.Hash()] = receipts[i]
+		l.canon = append(l.canon, block)
+	}
+}
+
+// Head returns the head block of the canonical chain.
+func (l *L1) Head() *types.Block {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.canon[len(l.canon)-1]
+}
+
+// canonical returns the canonical block with the given number, or the head
+// block if number is nil or a negative block tag (latest, safe, finalized).
+func (l *L1) canonical(number *big.Int) *types.Block {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	if number == nil || number.Sign() < 0 {
+		return l.canon[len(l.canon)-1]
+	}
+	if !number.IsUint64() || number.Uint64() >= uint64(len(l.canon)) {
+		return nil
+	}
+	return l.canon[number.Uint64()]
+}
+
+// HeaderByHash returns the header of the block with the given hash.
+func (l *L1) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	block, err := l.BlockByHash(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+// HeaderByNumber returns the header of the canonical block with the given
+// number, or the head header if number is nil.
+func (l *L1) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	block, err := l.BlockByNumber(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	return block.Header(), nil
+}
+
+// BlockByHash returns the block with the given hash, canonical or not.
+func (l *L1) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	block, ok := l.blocks[hash]
+	if !ok {
+		return nil, ethereum.NotFound
+	}
+	return block, nil
+}
+
+// BlockByNumber returns the canonical block with the given number, or the head
+// block if number is nil.
+func (l *L1) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	block := l.canonical(number)
+	if block == nil {
+		return nil, ethereum.NotFound
+	}
+	return block, nil
+}
+
+// BlockReceipts returns the receipts of the block with the given hash and
+// number.
+func (l *L1) BlockReceipts(ctx context.Context, hash common.Hash, number uint64) ([]*types.Receipt, error) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	block, ok := l.blocks[hash]
+	if !ok || block.NumberU64() != number {
+		return nil, ethereum.NotFound
+	}
+	return l.receipts[hash], nil
+}
@@ -36,8 +36,11 @@ import (
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/gofrs/flock"
+
+	miverawdb "github.com/ethereum-mive/mive/core/rawdb"
 )
 
 // Node is a container on which services can be registered.
@@ -52,6 +55,7 @@ type Node struct {
 	stop          chan struct{} // Channel to wait for termination notifications
 	startStopLock sync.Mutex    // Start/Stop are protected by an additional lock
 	state         int           // Tracks state of node lifecycle
+	server        *p2p.Server   // Currently running P2P networking layer
 
 	lock          sync.Mutex
 	lifecycles    []node.Lifecycle // All registered backends, services, and auxiliary services that have a lifecycle
@@ -108,6 +112,7 @@ func New(conf *Config) (*Node, error) {
 		eventmux:      new(event.TypeMux),
 		log:           conf.Logger,
 		stop:          make(chan struct{}),
+		server:        &p2p.Server{Config: conf.P2P},
 		databases:     make(map[*closeTrackingDB]struct{}),
 	}
 
@@ -128,6 +133,14 @@ func New(conf *Config) (*Node, error) {
 	// are required to add the backends later on.
 	node.accman = accounts.NewManager(&accounts.Config{InsecureUnlockAllowed: conf.InsecureUnlockAllowed})
 
+	// Initialize the p2p server. This creates the node key and discovery databases.
+	node.server.Config.PrivateKey = node.config.NodeKey()
+	node.server.Config.Name = node.config.NodeName()
+	node.server.Config.Logger = node.log
+	if node.server.Config.NodeDatabase == "" {
+		node.server.Config.NodeDatabase = node.config.NodeDB()
+	}
+
 	// Check HTTP/WS prefixes are valid.
 	if err := validatePrefix("HTTP", conf.HTTPPathPrefix); err != nil {
 		return nil, err
@@ -253,11 +266,16 @@ func (n *Node) doClose(errs []error) error {
 
 // openEndpoints starts all network and RPC endpoints.
 func (n *Node) openEndpoints() error {
-	n.log.Info("Starting node")
+	// start networking endpoints
+	n.log.Info("Starting peer-to-peer node", "instance", n.server.Name)
+	if err := n.server.Start(); err != nil {
+		return err
+	}
 	// start RPC endpoints
 	err := n.startRPC()
 	if err != nil {
 		n.stopRPC()
+		n.server.Stop()
 	}
 	return err
 }
@@ -272,7 +290,7 @@ func containsLifecycle(lfs []node.Lifecycle, l node.Lifecycle) bool {
 	return false
 }
 
-// stopServices terminates running services, RPC and indexer.
+// stopServices terminates running services, RPC and p2p networking.
 // It is the inverse of Start.
 func (n *Node) stopServices(running []node.Lifecycle) error {
 	n.stopRPC()
@@ -285,6 +303,9 @@ func (n *Node) stopServices(running []node.Lifecycle) error {
 		}
 	}
 
+	// Stop p2p networking.
+	n.server.Stop()
+
 	if len(failure.Services) > 0 {
 		return failure
 	}
@@ -543,6 +564,17 @@ func (n *Node) RegisterLifecycle(lifecycle node.Lifecycle) {
 	n.lifecycles = append(n.lifecycles, lifecycle)
 }
 
+// RegisterProtocols adds backend's protocols to the node's p2p server.
+func (n *Node) RegisterProtocols(protocols []p2p.Protocol) {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	if n.state != initializingState {
+		panic("can't register protocols on running/stopped node")
+	}
+	n.server.Protocols = append(n.server.Protocols, protocols...)
+}
+
 // RegisterAPIs registers the APIs a service provides on the node.
 func (n *Node) RegisterAPIs(apis []rpc.API) {
 	n.lock.Lock()
@@ -601,6 +633,16 @@ func (n *Node) Config() *Config {
 	return n.config
 }
 
+// Server retrieves the currently running P2P network layer. This method is meant
+// only to inspect fields of the currently running server. Callers should not
+// start or stop the returned server.
+func (n *Node) Server() *p2p.Server {
+	n.lock.Lock()
+	defer n.lock.Unlock()
+
+	return n.server
+}
+
 // DataDir retrieves the current datadir used by the protocol stack.
 func (n *Node) DataDir() string {
 	return n.config.DataDir
@@ -692,7 +734,7 @@ func (n *Node) OpenDatabase(name string, cache, handles int, namespace string, r
 
 // OpenDatabaseWithFreezer opens an existing database with the given name (or
 // creates one if no previous can be found) from within the node's data directory,
-// also attaching a chain freezer to it that moves ancient chain data from the
+// also attaching a Mive chain freezer to it that moves ancient chain data from the
 // database to immutable append-only files. If the node is an ephemeral one, a
 // memory database is returned.
 func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient string, namespace string, readonly bool) (ethdb.Database, error) {
@@ -706,15 +748,22 @@ func (n *Node) OpenDatabaseWithFreezer(name string, cache, handles int, ancient
 	if n.config.DataDir == "" {
 		db = rawdb.NewMemoryDatabase()
 	} else {
-		db, err = rawdb.Open(rawdb.OpenOptions{
-			Type:              n.config.DBEngine,
-			Directory:         n.ResolvePath(name),
-			AncientsDirectory: n.ResolveAncient(name, ancient),
-			Namespace:         namespace,
-			Cache:             cache,
-			Handles:           handles,
-			ReadOnly:          readonly,
+		var kvdb ethdb.Database
+		kvdb, err = rawdb.Open(rawdb.OpenOptions{
+			Type:      n.config.DBEngine,
+			Directory: n.ResolvePath(name),
+			Namespace: namespace,
+			Cache:     cache,
+			Handles:   handles,
+			ReadOnly:  readonly,
 		})
+		if err == nil {
+			// The go-ethereum chain freezer can't handle Mive blocks, use the Mive one
+			db, err = miverawdb.NewDatabaseWithFreezer(kvdb, n.ResolveAncient(name, ancient), namespace, readonly)
+			if err != nil {
+				kvdb.Close()
+			}
+		}
 	}
 
 	if err == nil {
@@ -0,0 +1,53 @@
+package params
+
+import (
+	"bytes"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+var (
+	// badHashes is the set of Mive blocks known to be invalid, e.g. derived by a
+	// faulty release, which are never imported nor kept in the canonical chain.
+	// The set is extended from the node configuration, so that an emergency
+	// invalidation doesn't have to wait for a new release.
+	badHashes = map[common.Hash]bool{}
+
+	badHashesLock sync.RWMutex
+)
+
+// AddBadHashes adds the given Mive block hashes to the set of known bad blocks.
+func AddBadHashes(hashes ...common.Hash) {
+	badHashesLock.Lock()
+	defer badHashesLock.Unlock()
+
+	for _, hash := range hashes {
+		badHashes[hash] = true
+	}
+}
+
+// IsBadHash returns whether the Mive block with the given hash is a known bad
+// block.
+func IsBadHash(hash common.Hash) bool {
+	badHashesLock.RLock()
+	defer badHashesLock.RUnlock()
+
+	return badHashes[hash]
+}
+
+// BadHashes returns the hashes of the known bad Mive blocks, sorted.
+func BadHashes() []common.Hash {
+	badHashesLock.RLock()
+	defer badHashesLock.RUnlock()
+
+	hashes := make([]common.Hash, 0, len(badHashes))
+	for hash := range badHashes {
+		hashes = append(hashes, hash)
+	}
+	sort.Slice(hashes, func(i, j int) bool {
+		return bytes.Compare(hashes[i][:], hashes[j][:]) < 0
+	})
+	return hashes
+}
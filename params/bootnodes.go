@@ -0,0 +1,14 @@
+package params
+
+// MainnetBootnodes are the enode URLs of the P2P bootstrap nodes running on
+// the Mive main network. None are run yet, the peers of the main network are
+// found from the --bootnodes and --discovery.dns lists of the operators.
+var MainnetBootnodes = []string{}
+
+// SepoliaBootnodes are the enode URLs of the P2P bootstrap nodes running on the
+// Mive Sepolia test network. None are run yet.
+var SepoliaBootnodes = []string{}
+
+// HoleskyBootnodes are the enode URLs of the P2P bootstrap nodes running on the
+// Mive Holesky test network. None are run yet.
+var HoleskyBootnodes = []string{}
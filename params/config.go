@@ -1,8 +1,11 @@
 package params
 
 import (
+	"errors"
 	"fmt"
 	"math/big"
+	"sort"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/params"
@@ -17,48 +20,581 @@ var (
 			BeaconAddress: DefaultBeaconAddress,
 		},
 	}
+
+	// SepoliaChainConfig contains the chain parameters to run a node on the
+	// Sepolia test network.
+	SepoliaChainConfig = &ChainConfig{
+		Eth: params.SepoliaChainConfig,
+		Mive: &MiveChainConfig{
+			GenesisBlock:  new(big.Int), // TODO
+			BeaconAddress: DefaultBeaconAddress,
+		},
+	}
+
+	// HoleskyChainConfig contains the chain parameters to run a node on the
+	// Holesky test network.
+	HoleskyChainConfig = &ChainConfig{
+		Eth: params.HoleskyChainConfig,
+		Mive: &MiveChainConfig{
+			GenesisBlock:  new(big.Int), // TODO
+			BeaconAddress: DefaultBeaconAddress,
+		},
+	}
 )
 
 type ChainConfig struct {
 	Eth  *params.ChainConfig `json:"eth,omitempty"`
 	Mive *MiveChainConfig    `json:"mive,omitempty"`
+
+	evm *params.ChainConfig // Configuration of the EVM, cached by EVMConfig
 }
 
+var (
+	// evmConfigs maps the EVM configurations handed out by EVMConfig back to
+	// their chain configuration, see ChainConfigOf.
+	evmConfigs   = make(map[*params.ChainConfig]*ChainConfig)
+	evmConfigsMu sync.Mutex
+)
+
 type MiveChainConfig struct {
 	// Genesis block at which Mive starts indexing and executing.
 	// For any specific network, it should not be changed after Mive launched.
 	GenesisBlock *big.Int `json:"genesisBlock,omitempty"`
 
+	// Chain ID of the Mive network, distinct from the L1 one, nil to share the
+	// L1 chain ID. It's returned by the CHAINID opcode and eth_chainId, and is
+	// the domain of the signatures of the signed transactions, so Mive ones
+	// can't be replayed on L1 and the other way around. It applies from the
+	// genesis block on, so it can't be changed once Mive launched.
+	ChainID *big.Int `json:"chainId,omitempty"`
+
 	// Beacon address that will be observed by Mive for transactions sent to it.
 	// These transactions will be interpreted and executed by the Mive EVM.
 	// For any specific network, it should not be changed after Mive launched.
 	BeaconAddress common.Address `json:"beaconAddress"`
+
+	// Block from which the initial beacon address is no longer observed, nil
+	// if it never retires. It keeps identifying the network in the signatures
+	// of signed transactions even once retired.
+	BeaconRetireBlock *big.Int `json:"beaconRetireBlock,omitempty"`
+
+	// Beacon addresses observed in addition to the initial one, in order of
+	// activation. Scheduling addresses rotates or adds beacon addresses at the
+	// configured blocks without forking the client code.
+	BeaconSchedule []*BeaconFork `json:"beaconSchedule,omitempty"`
+
+	// Block from which the beacon transactions carrying their payload in blobs
+	// (EIP-4844) are executed, nil if they never are. Before it, blob-carrying
+	// transactions sent to the beacon address are ignored. Executing them
+	// requires a beacon node to retrieve the blobs from.
+	BlobBlock *big.Int `json:"blobBlock,omitempty"`
+
+	// Block from which the signed transactions of an L1 block are executed in
+	// the order of their Mive nonces per signer, nil if never. Signed
+	// transactions always have their nonce checked, but before it, the ones
+	// included ahead of their nonce are rejected. From it, they are deferred
+	// until their signer catches up within the block, and only rejected if it
+	// doesn't.
+	NonceOrderingBlock *big.Int `json:"nonceOrderingBlock,omitempty"`
+
+	// Beacon contract whose MiveTransaction events are executed as Mive
+	// transactions from BeaconContractBlock on, letting contracts originate
+	// Mive transactions. Executing them requires the receipts of the L1
+	// blocks emitting the events.
+	BeaconContract      common.Address `json:"beaconContract,omitempty"`
+	BeaconContractBlock *big.Int       `json:"beaconContractBlock,omitempty"`
+
+	// Portal contract whose deposit events mint the value locked on L1 into
+	// Mive from PortalBlock on. Deposits are forced into the Mive blocks ahead
+	// of the beacon transactions, and require the receipts of the L1 blocks
+	// emitting them.
+	PortalContract common.Address `json:"portalContract,omitempty"`
+	PortalBlock    *big.Int       `json:"portalBlock,omitempty"`
+
+	// Block from which the Mive headers commit to the withdrawals initiated
+	// through the withdrawal contract, nil if never. The contract has to be
+	// deployed by then.
+	WithdrawalBlock *big.Int `json:"withdrawalBlock,omitempty"`
+
+	// Block from which the attributes of the L1 block a Mive block is derived
+	// from are stored in the L1 block contract at the start of the block, nil
+	// if never. The contract code is deployed at the fork block.
+	L1BlockAttributesBlock *big.Int `json:"l1BlockAttributesBlock,omitempty"`
+
+	// Mive network upgrades, bundling the features above. From M1 on, the
+	// signed transactions are executed in nonce order and the blob-carrying
+	// beacon transactions are executed. From M2 on, the headers commit to the
+	// withdrawals. The feature blocks above may activate them earlier. M1 is
+	// activated at an L1 block number and M2 at an L1 timestamp, nil if never.
+	M1Block *big.Int `json:"m1Block,omitempty"`
+	M2Time  *uint64  `json:"m2Time,omitempty"`
+
+	// Block from which the Mive blocks have a base fee of their own, nil if
+	// never. It's adjusted per block from the Mive gas used like EIP-1559,
+	// targeting half the Mive block gas limit, instead of following the L1
+	// base fee. It starts from the reduced L1 base fee.
+	MiveBaseFeeBlock *big.Int `json:"miveBaseFeeBlock,omitempty"`
+
+	// Blocks from which the Mive precompiles are active, by name, in addition
+	// to the precompiles of the Ethereum forks. A precompile missing or nil is
+	// never active. The precompiles themselves are registered by the client.
+	// They can't be activated after GenesisBlock yet, the EVM reserving their
+	// address from the start.
+	Precompiles map[string]*big.Int `json:"precompiles,omitempty"`
+
+	// Fee parameters of the network, nil for the defaults. They apply from
+	// the genesis block on, so they can't be changed once Mive launched.
+	FeeReductionDenominator *uint64 `json:"feeReductionDenominator,omitempty"` // Denominator the L1 fees are reduced by
+	BlockGasLimitMultiplier *uint64 `json:"blockGasLimitMultiplier,omitempty"` // Multiplier of the L1 block gas limit
+	MinBlockGasLimit        *uint64 `json:"minBlockGasLimit,omitempty"`        // Minimum gas limit of a Mive block
+
+	// Account credited with the fees of the Mive transactions, as the
+	// coinbase of the Mive blocks, and the policy splitting the fees, nil to
+	// credit the tips and burn the base fees like L1. They apply from the
+	// genesis block on as well.
+	Beneficiary common.Address `json:"beneficiary,omitempty"`
+	FeePolicy   *FeePolicy     `json:"feePolicy,omitempty"`
+}
+
+// FeePolicy splits the fees paid by the Mive transactions, base fee and tip
+// alike, in percentages. The remainder is credited to the beneficiary.
+type FeePolicy struct {
+	BurnPercentage     uint64         `json:"burnPercentage,omitempty"`     // Share of the fees burned
+	TreasuryPercentage uint64         `json:"treasuryPercentage,omitempty"` // Share of the fees credited to the treasury
+	Treasury           common.Address `json:"treasury,omitempty"`
+	RefundPercentage   uint64         `json:"refundPercentage,omitempty"` // Share of the fees refunded to the sender of the wrapping L1 transaction
+}
+
+// BeaconFork schedules a beacon address observed by Mive from the given block
+// on, until it's retired.
+type BeaconFork struct {
+	Address     common.Address `json:"address"`
+	Block       *big.Int       `json:"block"`                 // First block the address is observed at
+	RetireBlock *big.Int       `json:"retireBlock,omitempty"` // First block the address is no longer observed at, nil if never
+}
+
+// active returns whether the beacon address is observed at the given block.
+func (f *BeaconFork) active(num *big.Int) bool {
+	return (f.Block == nil || f.Block.Cmp(num) <= 0) && (f.RetireBlock == nil || f.RetireBlock.Cmp(num) > 0)
+}
+
+// beacons returns all the scheduled beacon addresses, the initial one first.
+func (c *ChainConfig) beacons() []*BeaconFork {
+	initial := &BeaconFork{Address: c.Mive.BeaconAddress, RetireBlock: c.Mive.BeaconRetireBlock}
+	return append([]*BeaconFork{initial}, c.Mive.BeaconSchedule...)
+}
+
+// IsBeacon returns whether the transactions sent to the given address are beacon
+// transactions at the given block.
+func (c *ChainConfig) IsBeacon(addr common.Address, num *big.Int) bool {
+	for _, beacon := range c.beacons() {
+		if beacon.Address == addr && beacon.active(num) {
+			return true
+		}
+	}
+	return false
+}
+
+// Beacon returns the beacon address new beacon transactions are sent to at the
+// given block: the most recently activated address still observed, or the
+// initial one if no other is.
+func (c *ChainConfig) Beacon(num *big.Int) common.Address {
+	beacons := c.beacons()
+	for i := len(beacons) - 1; i > 0; i-- {
+		if beacons[i].active(num) {
+			return beacons[i].Address
+		}
+	}
+	return c.Mive.BeaconAddress
+}
+
+// IsM1 returns whether the M1 upgrade is active at the given block.
+func (c *ChainConfig) IsM1(num *big.Int) bool {
+	return isBlockForked(c.Mive.M1Block, num)
+}
+
+// IsM2 returns whether the M2 upgrade is active at the given block and time.
+func (c *ChainConfig) IsM2(num *big.Int, time uint64) bool {
+	return c.IsM1(num) && isTimestampForked(c.Mive.M2Time, time)
+}
+
+// IsBlobCarrier returns whether the beacon transactions carrying their payload
+// in blobs are executed at the given block.
+func (c *ChainConfig) IsBlobCarrier(num *big.Int) bool {
+	return isBlockForked(c.Mive.BlobBlock, num) || c.IsM1(num)
+}
+
+// IsNonceOrdering returns whether the signed transactions of the given block are
+// executed in nonce order.
+func (c *ChainConfig) IsNonceOrdering(num *big.Int) bool {
+	return isBlockForked(c.Mive.NonceOrderingBlock, num) || c.IsM1(num)
+}
+
+// IsBeaconContract returns whether the events of the beacon contract are executed
+// at the given block.
+func (c *ChainConfig) IsBeaconContract(num *big.Int) bool {
+	return c.Mive.BeaconContractBlock != nil && c.Mive.BeaconContractBlock.Cmp(num) <= 0
+}
+
+// IsPortal returns whether the deposits made through the portal contract are
+// executed at the given block.
+func (c *ChainConfig) IsPortal(num *big.Int) bool {
+	return c.Mive.PortalBlock != nil && c.Mive.PortalBlock.Cmp(num) <= 0
+}
+
+// IsWithdrawals returns whether the header of the given block commits to the
+// withdrawals.
+func (c *ChainConfig) IsWithdrawals(num *big.Int, time uint64) bool {
+	return isBlockForked(c.Mive.WithdrawalBlock, num) || c.IsM2(num, time)
+}
+
+// ChainID returns the chain ID of the Mive network, the L1 one if not set.
+func (c *ChainConfig) ChainID() *big.Int {
+	if c.Mive == nil || c.Mive.ChainID == nil {
+		return c.Eth.ChainID
+	}
+	return c.Mive.ChainID
+}
+
+// EVMConfig returns the chain configuration the Mive EVM runs with, a copy of
+// the L1 one with the chain ID replaced by the Mive one. The copy is returned
+// again until the configuration changes, so that an EVM can be traced back to
+// its chain configuration by ChainConfigOf.
+func (c *ChainConfig) EVMConfig() *params.ChainConfig {
+	cpy := *c.Eth
+	cpy.ChainID = c.ChainID()
+
+	evmConfigsMu.Lock()
+	defer evmConfigsMu.Unlock()
+
+	if c.evm == nil || *c.evm != cpy || evmConfigs[c.evm] != c {
+		c.evm = &cpy
+		evmConfigs[c.evm] = c
+	}
+	return c.evm
+}
+
+// ChainConfigOf returns the chain configuration the given EVM configuration was
+// returned by EVMConfig for, or nil if it's not the one of a Mive EVM.
+func ChainConfigOf(evm *params.ChainConfig) *ChainConfig {
+	evmConfigsMu.Lock()
+	defer evmConfigsMu.Unlock()
+
+	return evmConfigs[evm]
+}
+
+// IsL1BlockAttributes returns whether the attributes of the given L1 block are
+// exposed through the L1 block contract.
+func (c *ChainConfig) IsL1BlockAttributes(num *big.Int) bool {
+	return isBlockForked(c.Mive.L1BlockAttributesBlock, num)
+}
+
+// IsPrecompile returns whether the Mive precompile with the given name is active
+// at the given block.
+func (c *ChainConfig) IsPrecompile(name string, num *big.Int) bool {
+	return isBlockForked(c.Mive.Precompiles[name], num)
+}
+
+// IsMiveBaseFee returns whether the given block has a Mive base fee of its own.
+func (c *ChainConfig) IsMiveBaseFee(num *big.Int) bool {
+	return isBlockForked(c.Mive.MiveBaseFeeBlock, num)
 }
 
 // FeeReductionDenominator bounds the reduction amount the various fees may have in Mive.
 func (c *ChainConfig) FeeReductionDenominator() uint64 {
-	return DefaultFeeReductionDenominator
+	return configValue(c.Mive.FeeReductionDenominator, DefaultFeeReductionDenominator)
 }
 
 // BlockGasLimitMultiplier bounds the maximum gas limit a Mive block may have.
 func (c *ChainConfig) BlockGasLimitMultiplier() uint64 {
-	return DefaultBlockGasLimitMultiplier
+	return configValue(c.Mive.BlockGasLimitMultiplier, DefaultBlockGasLimitMultiplier)
 }
 
 // MinBlockGasLimit is the minimum gas limit for a Mive block.
 func (c *ChainConfig) MinBlockGasLimit() uint64 {
-	return DefaultMinBlockGasLimit
+	return configValue(c.Mive.MinBlockGasLimit, DefaultMinBlockGasLimit)
+}
+
+// configValue returns the configured value, or the given default if not set.
+func configValue(v *uint64, def uint64) uint64 {
+	if v == nil {
+		return def
+	}
+	return *v
 }
 
 // CheckCompatible checks whether scheduled fork transitions have been imported
 // with a mismatching chain configuration.
 func (c *ChainConfig) CheckCompatible(newcfg *ChainConfig, height uint64, time uint64) *params.ConfigCompatError {
-	return c.Eth.CheckCompatible(newcfg.Eth, height, time)
+	if err := c.Eth.CheckCompatible(newcfg.Eth, height, time); err != nil {
+		return err
+	}
+	if c.Mive == nil || newcfg.Mive == nil {
+		return nil
+	}
+	return c.Mive.checkCompatible(newcfg.Mive, new(big.Int).SetUint64(height), time)
+}
+
+// checkCompatible checks whether the Mive specific transitions scheduled before
+// the given head block and time have been changed.
+func (c *MiveChainConfig) checkCompatible(newcfg *MiveChainConfig, head *big.Int, headTime uint64) *params.ConfigCompatError {
+	// The chain ID and the fee parameters apply from the genesis block on, so
+	// the blocks after it have to be processed again if they changed.
+	if !configBlockEqual(c.ChainID, newcfg.ChainID) && isBlockForked(c.GenesisBlock, head) {
+		return c.newGenesisCompatError("chain ID", newcfg)
+	}
+	for _, param := range []struct {
+		what            string
+		stored, updated *uint64
+		def             uint64
+	}{
+		{"fee reduction denominator", c.FeeReductionDenominator, newcfg.FeeReductionDenominator, DefaultFeeReductionDenominator},
+		{"block gas limit multiplier", c.BlockGasLimitMultiplier, newcfg.BlockGasLimitMultiplier, DefaultBlockGasLimitMultiplier},
+		{"minimum block gas limit", c.MinBlockGasLimit, newcfg.MinBlockGasLimit, DefaultMinBlockGasLimit},
+	} {
+		if configValue(param.stored, param.def) != configValue(param.updated, param.def) && isBlockForked(c.GenesisBlock, head) {
+			return c.newGenesisCompatError(param.what, newcfg)
+		}
+	}
+	if (c.Beneficiary != newcfg.Beneficiary || !feePolicyEqual(c.FeePolicy, newcfg.FeePolicy)) && isBlockForked(c.GenesisBlock, head) {
+		return c.newGenesisCompatError("fee policy", newcfg)
+	}
+	if isForkBlockIncompatible(c.BeaconRetireBlock, newcfg.BeaconRetireBlock, head) {
+		return newBlockCompatError("beacon address retirement", c.BeaconRetireBlock, newcfg.BeaconRetireBlock)
+	}
+	for i := 0; i < len(c.BeaconSchedule) || i < len(newcfg.BeaconSchedule); i++ {
+		stored, updated := new(BeaconFork), new(BeaconFork)
+		if i < len(c.BeaconSchedule) {
+			stored = c.BeaconSchedule[i]
+		}
+		if i < len(newcfg.BeaconSchedule) {
+			updated = newcfg.BeaconSchedule[i]
+		}
+		what := fmt.Sprintf("beacon schedule entry %d", i)
+		if stored.Address != updated.Address || isForkBlockIncompatible(stored.Block, updated.Block, head) {
+			if isBlockForked(stored.Block, head) || isBlockForked(updated.Block, head) {
+				return newBlockCompatError(what, stored.Block, updated.Block)
+			}
+		}
+		if isForkBlockIncompatible(stored.RetireBlock, updated.RetireBlock, head) {
+			return newBlockCompatError(what+" retirement", stored.RetireBlock, updated.RetireBlock)
+		}
+	}
+	if isForkBlockIncompatible(c.BlobBlock, newcfg.BlobBlock, head) {
+		return newBlockCompatError("blob carrier fork block", c.BlobBlock, newcfg.BlobBlock)
+	}
+	if isForkBlockIncompatible(c.NonceOrderingBlock, newcfg.NonceOrderingBlock, head) {
+		return newBlockCompatError("nonce ordering fork block", c.NonceOrderingBlock, newcfg.NonceOrderingBlock)
+	}
+	if isForkBlockIncompatible(c.BeaconContractBlock, newcfg.BeaconContractBlock, head) ||
+		(c.BeaconContract != newcfg.BeaconContract && isBlockForked(c.BeaconContractBlock, head)) {
+		return newBlockCompatError("beacon contract fork block", c.BeaconContractBlock, newcfg.BeaconContractBlock)
+	}
+	if isForkBlockIncompatible(c.PortalBlock, newcfg.PortalBlock, head) ||
+		(c.PortalContract != newcfg.PortalContract && isBlockForked(c.PortalBlock, head)) {
+		return newBlockCompatError("portal fork block", c.PortalBlock, newcfg.PortalBlock)
+	}
+	if isForkBlockIncompatible(c.WithdrawalBlock, newcfg.WithdrawalBlock, head) {
+		return newBlockCompatError("withdrawals fork block", c.WithdrawalBlock, newcfg.WithdrawalBlock)
+	}
+	if isForkBlockIncompatible(c.L1BlockAttributesBlock, newcfg.L1BlockAttributesBlock, head) {
+		return newBlockCompatError("L1 block attributes fork block", c.L1BlockAttributesBlock, newcfg.L1BlockAttributesBlock)
+	}
+	if isForkBlockIncompatible(c.MiveBaseFeeBlock, newcfg.MiveBaseFeeBlock, head) {
+		return newBlockCompatError("Mive base fee fork block", c.MiveBaseFeeBlock, newcfg.MiveBaseFeeBlock)
+	}
+	names := make([]string, 0, len(c.Precompiles)+len(newcfg.Precompiles))
+	for name := range c.Precompiles {
+		names = append(names, name)
+	}
+	for name := range newcfg.Precompiles {
+		if _, ok := c.Precompiles[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if isForkBlockIncompatible(c.Precompiles[name], newcfg.Precompiles[name], head) {
+			return newBlockCompatError(fmt.Sprintf("precompile %s fork block", name), c.Precompiles[name], newcfg.Precompiles[name])
+		}
+	}
+	if isForkBlockIncompatible(c.M1Block, newcfg.M1Block, head) {
+		return newBlockCompatError("M1 fork block", c.M1Block, newcfg.M1Block)
+	}
+	if isForkTimestampIncompatible(c.M2Time, newcfg.M2Time, headTime) {
+		return newTimestampCompatError("M2 fork timestamp", c.M2Time, newcfg.M2Time)
+	}
+	return nil
 }
 
-// CheckConfigForkOrder checks that we don't "skip" any forks.
+// CheckConfigForkOrder checks that we don't "skip" any forks, and that the Mive
+// parameters are valid.
 func (c *ChainConfig) CheckConfigForkOrder() error {
-	return c.Eth.CheckConfigForkOrder()
+	if err := c.Eth.CheckConfigForkOrder(); err != nil {
+		return err
+	}
+	if c.Mive == nil {
+		return nil
+	}
+	var last *big.Int
+	for i, beacon := range c.Mive.BeaconSchedule {
+		if beacon.Block == nil {
+			return fmt.Errorf("beacon schedule entry %d has no activation block", i)
+		}
+		if last != nil && beacon.Block.Cmp(last) < 0 {
+			return fmt.Errorf("beacon schedule entry %d activated at block %v, before the previous one at %v", i, beacon.Block, last)
+		}
+		if beacon.RetireBlock != nil && beacon.RetireBlock.Cmp(beacon.Block) <= 0 {
+			return fmt.Errorf("beacon schedule entry %d retired at block %v, not after its activation at %v", i, beacon.RetireBlock, beacon.Block)
+		}
+		last = beacon.Block
+	}
+	if id := c.Mive.ChainID; id != nil {
+		if id.Sign() <= 0 {
+			return fmt.Errorf("invalid chain ID: %v", id)
+		}
+		if c.Eth.ChainID != nil && id.Cmp(c.Eth.ChainID) == 0 {
+			return fmt.Errorf("invalid chain ID: %v, same as the L1 chain ID", id)
+		}
+	}
+	if c.Mive.FeeReductionDenominator != nil && *c.Mive.FeeReductionDenominator == 0 {
+		return errors.New("invalid fee reduction denominator: zero")
+	}
+	if c.Mive.BlockGasLimitMultiplier != nil && *c.Mive.BlockGasLimitMultiplier == 0 {
+		return errors.New("invalid block gas limit multiplier: zero")
+	}
+	if c.Mive.MinBlockGasLimit != nil && *c.Mive.MinBlockGasLimit < params.TxGas {
+		return fmt.Errorf("invalid minimum block gas limit: %d, below the transaction gas of %d", *c.Mive.MinBlockGasLimit, params.TxGas)
+	}
+	if policy := c.Mive.FeePolicy; policy != nil {
+		if sum := policy.BurnPercentage + policy.TreasuryPercentage + policy.RefundPercentage; sum > 100 {
+			return fmt.Errorf("invalid fee policy: percentages sum up to %d", sum)
+		}
+		if policy.TreasuryPercentage > 0 && policy.Treasury == (common.Address{}) {
+			return errors.New("invalid fee policy: treasury share without treasury")
+		}
+	}
+	// The upgrades are cumulative, M2 can't be scheduled without M1
+	if c.Mive.M2Time != nil && c.Mive.M1Block == nil {
+		return fmt.Errorf("unsupported fork ordering: M2 enabled at timestamp %d, but M1 not enabled", *c.Mive.M2Time)
+	}
+	return nil
+}
+
+// isForkBlockIncompatible returns true if a fork scheduled at block s1 cannot be
+// rescheduled to block s2 because head is already past the fork.
+func isForkBlockIncompatible(s1, s2, head *big.Int) bool {
+	return (isBlockForked(s1, head) || isBlockForked(s2, head)) && !configBlockEqual(s1, s2)
+}
+
+// isBlockForked returns whether a fork scheduled at block s is active at the
+// given head block.
+func isBlockForked(s, head *big.Int) bool {
+	if s == nil || head == nil {
+		return false
+	}
+	return s.Cmp(head) <= 0
+}
+
+// newGenesisCompatError creates an error for a parameter applying from the
+// genesis block on which was changed, rewinding to the genesis block.
+func (c *MiveChainConfig) newGenesisCompatError(what string, newcfg *MiveChainConfig) *params.ConfigCompatError {
+	return &params.ConfigCompatError{
+		What:          what,
+		StoredBlock:   c.GenesisBlock,
+		NewBlock:      newcfg.GenesisBlock,
+		RewindToBlock: c.GenesisBlock.Uint64(),
+	}
+}
+
+func feePolicyEqual(x, y *FeePolicy) bool {
+	if x == nil || y == nil {
+		return x == y
+	}
+	return *x == *y
+}
+
+// isForkTimestampIncompatible returns true if a fork scheduled at timestamp s1
+// cannot be rescheduled to timestamp s2 because head is already past the fork.
+func isForkTimestampIncompatible(s1, s2 *uint64, head uint64) bool {
+	return (isTimestampForked(s1, head) || isTimestampForked(s2, head)) && !configTimestampEqual(s1, s2)
+}
+
+// isTimestampForked returns whether a fork scheduled at timestamp s is active
+// at the given head timestamp.
+func isTimestampForked(s *uint64, head uint64) bool {
+	if s == nil {
+		return false
+	}
+	return *s <= head
+}
+
+func configBlockEqual(x, y *big.Int) bool {
+	if x == nil {
+		return y == nil
+	}
+	if y == nil {
+		return x == nil
+	}
+	return x.Cmp(y) == 0
+}
+
+// newBlockCompatError creates an error for a fork whose block was changed,
+// rewinding to the block before the earliest of the stored and new ones.
+func newBlockCompatError(what string, storedblock, newblock *big.Int) *params.ConfigCompatError {
+	var rew *big.Int
+	switch {
+	case storedblock == nil:
+		rew = newblock
+	case newblock == nil || storedblock.Cmp(newblock) < 0:
+		rew = storedblock
+	default:
+		rew = newblock
+	}
+	err := &params.ConfigCompatError{
+		What:          what,
+		StoredBlock:   storedblock,
+		NewBlock:      newblock,
+		RewindToBlock: 0,
+	}
+	if rew != nil && rew.Sign() > 0 {
+		err.RewindToBlock = rew.Uint64() - 1
+	}
+	return err
+}
+
+func configTimestampEqual(x, y *uint64) bool {
+	if x == nil {
+		return y == nil
+	}
+	if y == nil {
+		return x == nil
+	}
+	return *x == *y
+}
+
+// newTimestampCompatError creates an error for a fork whose timestamp was
+// changed, rewinding to the time before the earliest of the stored and new ones.
+func newTimestampCompatError(what string, storedtime, newtime *uint64) *params.ConfigCompatError {
+	var rew *uint64
+	switch {
+	case storedtime == nil:
+		rew = newtime
+	case newtime == nil || *storedtime < *newtime:
+		rew = storedtime
+	default:
+		rew = newtime
+	}
+	err := &params.ConfigCompatError{
+		What:         what,
+		StoredTime:   storedtime,
+		NewTime:      newtime,
+		RewindToTime: 0,
+	}
+	if rew != nil && *rew > 0 {
+		err.RewindToTime = *rew - 1
+	}
+	return err
 }
 
 // Description returns a human-readable description of ChainConfig.
@@ -70,6 +606,17 @@ func (c *ChainConfig) Description() string {
 		network = "unknown"
 	}
 	banner += fmt.Sprintf("Master Chain ID:  %v (%s)\n", c.Eth.ChainID, network)
+	if c.Mive != nil {
+		if c.Mive.ChainID != nil {
+			banner += fmt.Sprintf("Mive Chain ID:    %v\n", c.Mive.ChainID)
+		}
+		if c.Mive.M1Block != nil {
+			banner += fmt.Sprintf(" - M1: #%v\n", c.Mive.M1Block)
+		}
+		if c.Mive.M2Time != nil {
+			banner += fmt.Sprintf(" - M2: @%v\n", *c.Mive.M2Time)
+		}
+	}
 
 	return banner
 }
@@ -1,18 +1,37 @@
 package params
 
-import "github.com/ethereum/go-ethereum/common"
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
 
 const (
 	DefaultFeeReductionDenominator = 50       // Bounds the reduction amount the various fees may have in Mive.
 	DefaultBlockGasLimitMultiplier = 100      // Bounds the maximum gas limit a Mive block may have.
 	DefaultMinBlockGasLimit        = 30000000 // Minimum gas limit for a Mive block.
+
+	L1StateRootHistory = 8191 // Number of recent L1 state roots kept by the L1 block contract.
+	L1StateGas         = 5000 // Base gas of the L1 state precompile.
+	L1StateWordGas     = 24   // Gas per word of input of the L1 state precompile, hashed and decoded as trie nodes.
 )
 
 var (
 	// DefaultBeaconAddress is the default beacon address, which has suffix "315e" (a variant of "mive").
 	DefaultBeaconAddress = common.HexToAddress("0x000000000000000000000000000000000000315e")
 
-	// BeneficiaryAddress is the address that will receive tx fees.
-	// TODO
-	BeneficiaryAddress = common.HexToAddress("0x0000000000000000000000000000000000000000")
+	// WithdrawalContractAddress is the address of the predeployed contract the
+	// withdrawals from Mive to L1 are initiated through.
+	WithdrawalContractAddress = common.HexToAddress("0x000000000000000000000000000000000000315f")
+
+	// L1BlockAddress is the address of the system contract exposing the
+	// attributes of the L1 block a Mive block is derived from.
+	L1BlockAddress = common.HexToAddress("0x0000000000000000000000000000000000003160")
+
+	// L1StateAddress is the address of the precompile proving the L1 state
+	// against the recent L1 state roots.
+	L1StateAddress = common.HexToAddress("0x0000000000000000000000000000000000003170")
+
+	// L1BlockCode is the code of the L1 block contract, returning the storage
+	// slot matching the selector of the getter called, reverting otherwise.
+	L1BlockCode = hexutil.MustDecode("0x60003560e01c80638381f58a14603c57806309bd5a60146042578063b80777ea1460485780635cf2496914604e578063f820614014605457600080fd5b6000605a565b6001605a565b6002605a565b6003605a565b6004605a565b5460005260206000f3")
 )